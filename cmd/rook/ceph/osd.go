@@ -105,6 +105,8 @@ func addOSDFlags(command *cobra.Command) {
 	provisionCmd.Flags().StringVar(&osdDataDeviceFilter, "data-device-filter", "", "a regex filter for the device names to use, or \"all\"")
 	provisionCmd.Flags().StringVar(&osdDataDevicePathFilter, "data-device-path-filter", "", "a regex filter for the device path names to use")
 	provisionCmd.Flags().StringVar(&cfg.metadataDevice, "metadata-device", "", "device to use for metadata (e.g. a high performance SSD/NVMe device)")
+	provisionCmd.Flags().StringSliceVar(&cfg.storeConfig.MetadataDevices, "metadata-devices", nil, "comma separated list of devices to spread the metadata of the OSDs across")
+	provisionCmd.Flags().IntVar(&cfg.storeConfig.MetadataDeviceRatio, "metadata-device-ratio", 0, "the maximum number of OSDs per metadata device, or 0 for no limit")
 	provisionCmd.Flags().BoolVar(&cfg.forceFormat, "force-format", false,
 		"true to force the format of any specified devices, even if they already have a filesystem.  BE CAREFUL!")
 	provisionCmd.Flags().BoolVar(&cfg.pvcBacked, "pvc-backed-osd", false, "true to specify a block mode pvc is backing the OSD")
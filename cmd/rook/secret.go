@@ -20,6 +20,7 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"path"
 
 	"github.com/pkg/errors"
 	"github.com/rook/rook/cmd/rook/rook"
@@ -28,6 +29,8 @@ import (
 	"github.com/rook/rook/pkg/daemon/ceph/osd"
 	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
 	operator "github.com/rook/rook/pkg/operator/ceph"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -125,6 +128,9 @@ func getSecret(cmd *cobra.Command, args []string) {
 	}
 }
 
+// hostOSDID is the ID of the host-based OSD whose key is rotated, or -1 for an OSD on a PVC
+var hostOSDID int
+
 // cliRotateSecret is the Cobra CLI call
 func cliRotateSecret() *cobra.Command {
 	cmd := &cobra.Command{
@@ -133,9 +139,37 @@ func cliRotateSecret() *cobra.Command {
 		Args:  cobra.RangeArgs(2, 4),
 		Run:   rotateSecret,
 	}
+	cmd.Flags().IntVar(&hostOSDID, "osd-id", -1,
+		"the ID of a host-based OSD, whose key is stored in the mon config-key store instead of the KMS")
 	return cmd
 }
 
+// startMonKeyStore connects to the mons to rotate the key of a host-based OSD, which ceph-volume
+// keeps in the mon config-key store
+func startMonKeyStore(ctx context.Context) (osd.KeyStore, *clusterd.Context) {
+	namespace := os.Getenv(k8sutil.PodNamespaceEnvVar)
+	if namespace == "" {
+		rook.TerminateFatal(errors.New("failed to find pod namespace"))
+	}
+
+	clusterInfo := client.AdminClusterInfo(ctx, namespace, os.Getenv("ROOK_CLUSTER_NAME"))
+	clusterInfo.FSID = os.Getenv("ROOK_FSID")
+	clusterInfo.CephCred.Username = os.Getenv("ROOK_CEPH_USERNAME")
+	clusterInfo.InternalMonitors = opcontroller.ParseMonEndpoints(os.Getenv("ROOK_MON_ENDPOINTS"))
+	secret, err := os.ReadFile(path.Join(mon.CephSecretMountPath, mon.CephSecretFilename))
+	if err != nil {
+		rook.TerminateFatal(errors.Wrapf(err, "failed to read ceph secret file from %q", mon.CephSecretMountPath))
+	}
+	clusterInfo.CephCred.Secret = string(secret)
+
+	context := rook.NewContext()
+	if err := client.WriteCephConfig(context, clusterInfo); err != nil {
+		rook.TerminateFatal(errors.Wrap(err, "failed to write the ceph config"))
+	}
+
+	return osd.NewMonKeyStore(context, clusterInfo), context
+}
+
 // rotateSecret rotates the Key Encryption Key of a given OSD.
 // It accepts the name of the secret to rotate, and the path to the
 // encrypted devices.
@@ -146,6 +180,19 @@ func rotateSecret(cmd *cobra.Command, args []string) {
 	defer cancel()
 	secretName := args[0]
 	devicePaths := args[1:]
+
+	if hostOSDID >= 0 {
+		keyStore, context := startMonKeyStore(ctx)
+		devices, err := osd.HostEncryptedDevices(context, hostOSDID, devicePaths)
+		if err != nil {
+			rook.TerminateFatal(errors.Wrapf(err, "failed to find the encrypted devices of osd.%d", hostOSDID))
+		}
+		if err := osd.RotateKeyEncryptionKey(context, keyStore, secretName, devices); err != nil {
+			rook.TerminateFatal(errors.Wrapf(err, "failed to rotate the key of osd.%d", hostOSDID))
+		}
+		return
+	}
+
 	keyManagementService, context := startSecret()
 	keyManagementService.ClusterInfo.Context = ctx
 
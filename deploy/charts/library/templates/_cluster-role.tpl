@@ -78,6 +78,11 @@ rules:
       - cephfilesystemsubvolumegroups
       - cephblockpoolradosnamespaces
       - cephcosidrivers
+      - cephmgrmodules
+      - cephdashboardusers
+      - cephosdremovals
+      - cepherasurecodeprofiles
+      - cephcrushrules
     verbs:
       - get
       - list
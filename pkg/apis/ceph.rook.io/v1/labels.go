@@ -45,6 +45,33 @@ const (
 	// ReadyForSwapOSDAnnotationKey is set by Rook on the OSD Deployment once the OSD is destroyed and
 	// the disk may be physically swapped. E.g. "osd.rook.io/replace-ready-for-swap": "true".
 	ReadyForSwapOSDAnnotationKey = "osd.rook.io/replace-ready-for-swap"
+
+	// RestoreMonBackupAnnotationKey is set by a user on the CephCluster to restore a mon backup after
+	// all mons are lost. The value is the name of the backup, e.g. "mon-backup-20260101-020000". The
+	// annotation is removed by Rook after the restore, whether it succeeded or failed, so a failed
+	// restore is only retried when the user sets it again.
+	RestoreMonBackupAnnotationKey = "mon.rook.io/restore-from-backup"
+
+	// RecoverMonQuorumAnnotationKey is set by a user on the CephCluster to rebuild the mon store from
+	// the OSDs after all mons are lost. The annotation is removed by Rook after the recovery, whether it
+	// succeeded or failed, so a failed recovery is only retried when the user sets it again.
+	RecoverMonQuorumAnnotationKey = "mon.rook.io/recover-quorum-from-osds"
+
+	// RecoverMonQuorumAnnotationValue is the required value of RecoverMonQuorumAnnotationKey, as a
+	// confirmation guard against an accidental recovery.
+	RecoverMonQuorumAnnotationValue = "yes-really-recover-mon-quorum"
+
+	// ApproveOSDUpgradeAnnotationKey is set by a user on the CephCluster to continue an OSD upgrade with
+	// the Canary strategy after the canary failure domain is updated. The value is the approved Ceph
+	// image, so that the approval does not apply to later upgrades.
+	// E.g. "osd.rook.io/approve-upgrade": "quay.io/ceph/ceph:v20.2.4".
+	ApproveOSDUpgradeAnnotationKey = "osd.rook.io/approve-upgrade"
+
+	// ForceFastRecoveryAnnotationKey is set by a user on the CephCluster to apply the fastest recovery
+	// profile to the OSDs whatever the recovery schedule, for example after a failure that must be
+	// recovered at once. The recovery schedule is applied again when the annotation is removed.
+	// E.g. "osd.rook.io/force-fast-recovery": "true".
+	ForceFastRecoveryAnnotationKey = "osd.rook.io/force-fast-recovery"
 )
 
 // LabelsSpec is the main spec label for all daemons
@@ -25,7 +25,7 @@ func (p *PoolSpec) IsReplicated() bool {
 }
 
 func (p *PoolSpec) IsErasureCoded() bool {
-	return p.ErasureCoded.CodingChunks > 0 || p.ErasureCoded.DataChunks > 0
+	return p.ErasureCoded.CodingChunks > 0 || p.ErasureCoded.DataChunks > 0 || p.ErasureCoded.Profile != ""
 }
 
 func (p *PoolSpec) IsHybridStoragePool() bool {
@@ -40,17 +40,48 @@ func ValidateCephBlockPool(p *CephBlockPool) error {
 		}
 	}
 
+	if p.Spec.Migration != nil {
+		if err := validatePoolMigration(p); err != nil {
+			return err
+		}
+	}
+
 	return validatePoolSpec(p.ToNamedPoolSpec())
 }
 
+// validatePoolMigration checks that the images of the pool can be moved to the migration target
+func validatePoolMigration(p *CephBlockPool) error {
+	if p.Spec.Name != "" {
+		return errors.New("invalid CephBlockPool spec: migration is not supported for pools with spec.name")
+	}
+	if p.Spec.Mirroring.Enabled {
+		return errors.New("invalid CephBlockPool spec: migration is not supported for mirrored pools")
+	}
+	// the images of an erasure coded pool have their metadata in another pool
+	if p.Spec.IsErasureCoded() {
+		return errors.New("invalid CephBlockPool spec: migration is not supported for erasure coded pools")
+	}
+	target := p.Spec.Migration.Target
+	if target == nil {
+		return nil
+	}
+	if target.IsErasureCoded() {
+		return errors.New("invalid CephBlockPool spec: migration target cannot be erasure coded, use migration.dataPool for the data")
+	}
+	if err := validatePoolSpec(NamedPoolSpec{Name: p.Name, PoolSpec: *target}); err != nil {
+		return errors.Wrap(err, "invalid CephBlockPool migration target")
+	}
+	return nil
+}
+
 // validate any NamedPoolSpec
 func validatePoolSpec(ps NamedPoolSpec) error {
 	// Checks if either ErasureCoded or Replicated fields are set
-	if ps.ErasureCoded.CodingChunks <= 0 && ps.ErasureCoded.DataChunks <= 0 && ps.Replicated.TargetSizeRatio <= 0 && ps.Replicated.Size <= 0 {
+	if ps.ErasureCoded.CodingChunks <= 0 && ps.ErasureCoded.DataChunks <= 0 && ps.ErasureCoded.Profile == "" && ps.Replicated.TargetSizeRatio <= 0 && ps.Replicated.Size <= 0 {
 		return errors.New("invalid pool spec: either of erasurecoded or replicated fields should be set")
 	}
 	// Check if any of the ErasureCoded fields are populated. Then check if replicated is populated. Both can't be populated at same time.
-	if ps.ErasureCoded.CodingChunks > 0 || ps.ErasureCoded.DataChunks > 0 || ps.ErasureCoded.Algorithm != "" {
+	if ps.ErasureCoded.CodingChunks > 0 || ps.ErasureCoded.DataChunks > 0 || ps.ErasureCoded.Algorithm != "" || ps.ErasureCoded.Profile != "" {
 		if ps.Replicated.Size > 0 || ps.Replicated.TargetSizeRatio > 0 {
 			return errors.New("invalid pool spec: both erasurecoded and replicated fields cannot be set at the same time")
 		}
@@ -67,6 +98,27 @@ func validatePoolSpec(ps NamedPoolSpec) error {
 			return errors.New("invalid pool spec: erasurecoded.codingchunks needs minimum value of 1")
 		}
 	}
+
+	// The profile defines the chunks and the crush placement of the pool
+	if ps.ErasureCoded.Profile != "" {
+		ec := ps.ErasureCoded
+		if ec.CodingChunks > 0 || ec.DataChunks > 0 || ec.Algorithm != "" || ec.StripeUnit != nil || ec.CrushNumFailureDomains > 0 || ec.CrushOSDsPerFailureDomain > 0 {
+			return errors.New("invalid pool spec: erasurecoded.profile cannot be set with the other erasurecoded fields")
+		}
+		if ps.FailureDomain != "" || ps.CrushRoot != "" || ps.DeviceClass != "" {
+			return errors.New("invalid pool spec: failureDomain, crushRoot and deviceClass cannot be set with erasurecoded.profile")
+		}
+	}
+
+	// The crush rule defines the placement of the pool
+	if ps.CrushRule != "" {
+		if ps.FailureDomain != "" || ps.CrushRoot != "" || ps.DeviceClass != "" {
+			return errors.New("invalid pool spec: failureDomain, crushRoot and deviceClass cannot be set with crushRule")
+		}
+		if ps.Replicated.ReplicasPerFailureDomain > 1 || ps.Replicated.SubFailureDomain != "" || ps.Replicated.HybridStorage != nil {
+			return errors.New("invalid pool spec: replicasPerFailureDomain, subFailureDomain and hybridStorage cannot be set with crushRule")
+		}
+	}
 	return nil
 }
 
@@ -83,6 +135,14 @@ func (p *CephBlockPool) ToNamedPoolSpec() NamedPoolSpec {
 	}
 }
 
+// RuleName returns the name of the rule in the CRUSH map
+func (r *CephCrushRule) RuleName() string {
+	if r.Spec.Name != "" {
+		return r.Spec.Name
+	}
+	return r.Name
+}
+
 func (p *CephBlockPool) GetStatusConditions() *[]Condition {
 	return &p.Status.Conditions
 }
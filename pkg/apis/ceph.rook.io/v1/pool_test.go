@@ -43,6 +43,40 @@ func TestValidatePoolSpec(t *testing.T) {
 	p.Spec.ErasureCoded.DataChunks = 1
 	err = validatePoolSpec(p.ToNamedPoolSpec())
 	assert.Error(t, err)
+
+	t.Run("erasure code profile", func(t *testing.T) {
+		p.Spec.PoolSpec = PoolSpec{ErasureCoded: ErasureCodedSpec{Profile: "archive"}}
+		assert.True(t, p.Spec.IsErasureCoded())
+		assert.NoError(t, validatePoolSpec(p.ToNamedPoolSpec()))
+
+		p.Spec.ErasureCoded.DataChunks = 4
+		assert.Error(t, validatePoolSpec(p.ToNamedPoolSpec()))
+
+		p.Spec.ErasureCoded.DataChunks = 0
+		p.Spec.FailureDomain = "rack"
+		assert.Error(t, validatePoolSpec(p.ToNamedPoolSpec()))
+
+		p.Spec.FailureDomain = ""
+		p.Spec.Replicated.Size = 3
+		assert.Error(t, validatePoolSpec(p.ToNamedPoolSpec()))
+	})
+
+	t.Run("crush rule", func(t *testing.T) {
+		p.Spec.PoolSpec = PoolSpec{CrushRule: "two-dc", Replicated: ReplicatedSpec{Size: 3}}
+		assert.NoError(t, validatePoolSpec(p.ToNamedPoolSpec()))
+
+		p.Spec.FailureDomain = "host"
+		assert.Error(t, validatePoolSpec(p.ToNamedPoolSpec()))
+
+		p.Spec.FailureDomain = ""
+		p.Spec.Replicated.SubFailureDomain = "host"
+		assert.Error(t, validatePoolSpec(p.ToNamedPoolSpec()))
+
+		p.Spec.Replicated.SubFailureDomain = ""
+		p.Spec.PoolSpec.Replicated = ReplicatedSpec{}
+		p.Spec.ErasureCoded = ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}
+		assert.NoError(t, validatePoolSpec(p.ToNamedPoolSpec()))
+	})
 }
 
 func TestValidateCephBlockPoolBuiltInPool(t *testing.T) {
@@ -60,6 +94,40 @@ func TestValidateCephBlockPoolBuiltInPool(t *testing.T) {
 	assert.EqualError(t, err, `invalid CephBlockPool spec: ceph built-in pool ".mgr" cannot be erasure coded`)
 }
 
+func TestValidateCephBlockPoolMigration(t *testing.T) {
+	replicated := PoolSpec{Replicated: ReplicatedSpec{Size: 3}}
+	tests := []struct {
+		name string
+		spec NamedBlockPoolSpec
+		err  string
+	}{
+		{"default target", NamedBlockPoolSpec{PoolSpec: replicated, Migration: &PoolMigrationSpec{DataPool: "ec-data"}}, ""},
+		{"replicated target", NamedBlockPoolSpec{PoolSpec: replicated, Migration: &PoolMigrationSpec{
+			Target: &PoolSpec{FailureDomain: "rack", Replicated: ReplicatedSpec{Size: 2}},
+		}}, ""},
+		{"spec name", NamedBlockPoolSpec{Name: ".mgr", PoolSpec: replicated, Migration: &PoolMigrationSpec{}}, "pools with spec.name"},
+		{"mirrored", NamedBlockPoolSpec{PoolSpec: PoolSpec{Replicated: ReplicatedSpec{Size: 3}, Mirroring: MirroringSpec{Enabled: true}},
+			Migration: &PoolMigrationSpec{}}, "mirrored pools"},
+		{"erasure coded pool", NamedBlockPoolSpec{PoolSpec: PoolSpec{ErasureCoded: ErasureCodedSpec{CodingChunks: 1, DataChunks: 2}},
+			Migration: &PoolMigrationSpec{Target: &replicated}}, "erasure coded pools"},
+		{"erasure coded target", NamedBlockPoolSpec{PoolSpec: replicated, Migration: &PoolMigrationSpec{
+			Target: &PoolSpec{ErasureCoded: ErasureCodedSpec{CodingChunks: 1, DataChunks: 2}},
+		}}, "migration target cannot be erasure coded"},
+		{"invalid target", NamedBlockPoolSpec{PoolSpec: replicated, Migration: &PoolMigrationSpec{Target: &PoolSpec{}}}, "invalid CephBlockPool migration target"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool"}, Spec: tt.spec}
+			err := ValidateCephBlockPool(p)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
 func TestMirroringSpec_SnapshotSchedulesEnabled(t *testing.T) {
 	type fields struct {
 		Enabled           bool
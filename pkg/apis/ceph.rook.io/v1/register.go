@@ -63,6 +63,16 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CephBlockPoolList{},
 		&CephFilesystem{},
 		&CephFilesystemList{},
+		&CephMgrModule{},
+		&CephMgrModuleList{},
+		&CephDashboardUser{},
+		&CephDashboardUserList{},
+		&CephOSDRemoval{},
+		&CephOSDRemovalList{},
+		&CephErasureCodeProfile{},
+		&CephErasureCodeProfileList{},
+		&CephCrushRule{},
+		&CephCrushRuleList{},
 		&CephNFS{},
 		&CephNFSList{},
 		&CephNVMeOFGateway{},
@@ -260,6 +260,110 @@ type ClusterSpec struct {
 	// +optional
 	// +nullable
 	CephConfigFromSecret map[string]map[string]v1.SecretKeySelector `json:"cephConfigFromSecret,omitempty"`
+
+	// CephConfigDrift configures the periodic check of the central Ceph config for options that
+	// differ from the cephConfig and cephConfigFromSecret settings, and from the Rook defaults
+	// +optional
+	CephConfigDrift CephConfigDriftSpec `json:"cephConfigDrift,omitempty"`
+
+	// RecoverySchedule defines the time windows in which the recovery and backfill of the OSDs are
+	// throttled with different settings, for example to favor the client IO during business hours
+	// +optional
+	RecoverySchedule RecoveryScheduleSpec `json:"recoverySchedule,omitempty"`
+}
+
+// RecoveryScheduleSpec represents the recovery profiles applied to the OSDs over time
+type RecoveryScheduleSpec struct {
+	// TimeZone is the IANA name of the time zone of the windows, for example "Europe/Paris".
+	// Default is UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// Windows are the time windows with their recovery profile. When windows overlap, the first one
+	// in the list is applied.
+	// +optional
+	Windows []RecoveryWindow `json:"windows,omitempty"`
+	// Default is the recovery profile applied outside of the windows. If not set, the Ceph defaults
+	// are used outside of the windows.
+	// +optional
+	Default *RecoveryProfile `json:"default,omitempty"`
+}
+
+// RecoveryWindowDay is a day of the week of a recovery window
+// +kubebuilder:validation:Enum=Mon;Tue;Wed;Thu;Fri;Sat;Sun
+type RecoveryWindowDay string
+
+// RecoveryWindow is a time window with a recovery profile
+type RecoveryWindow struct {
+	// Name of the window, shown in the status when the window is active
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Days are the days of the week when the window starts. Default is every day.
+	// +optional
+	Days []RecoveryWindowDay `json:"days,omitempty"`
+	// Start is the time of the day when the window starts, in the "HH:MM" format
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+	// End is the time of the day when the window ends, in the "HH:MM" format. A window ending
+	// before its start ends on the next day.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+	// Profile is the recovery profile applied during the window
+	Profile RecoveryProfile `json:"profile"`
+}
+
+// RecoveryMClockProfile is the mclock scheduler profile of the OSDs
+// +kubebuilder:validation:Enum=high_client_ops;balanced;high_recovery_ops
+type RecoveryMClockProfile string
+
+const (
+	// MClockProfileHighClientOps favors the client IO over the recovery
+	MClockProfileHighClientOps RecoveryMClockProfile = "high_client_ops"
+	// MClockProfileBalanced shares the IO evenly between the client and the recovery
+	MClockProfileBalanced RecoveryMClockProfile = "balanced"
+	// MClockProfileHighRecoveryOps favors the recovery over the client IO
+	MClockProfileHighRecoveryOps RecoveryMClockProfile = "high_recovery_ops"
+)
+
+// RecoveryProfile represents the recovery and backfill settings of the OSDs
+type RecoveryProfile struct {
+	// MClockProfile sets osd_mclock_profile
+	// +optional
+	MClockProfile RecoveryMClockProfile `json:"mclockProfile,omitempty"`
+	// MaxBackfills sets osd_max_backfills. The mclock recovery settings are overridden when set.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxBackfills *int `json:"maxBackfills,omitempty"`
+	// RecoveryMaxActive sets osd_recovery_max_active. The mclock recovery settings are overridden
+	// when set.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RecoveryMaxActive *int `json:"recoveryMaxActive,omitempty"`
+}
+
+// CephConfigDriftPolicy is the action taken when the central Ceph config drifts from the CephCluster
+// +kubebuilder:validation:Enum=Detect;Enforce
+type CephConfigDriftPolicy string
+
+const (
+	// CephConfigDriftDetect reports the drifted options in the CephCluster status
+	CephConfigDriftDetect CephConfigDriftPolicy = "Detect"
+	// CephConfigDriftEnforce reports the drifted options and sets them back to their expected values
+	CephConfigDriftEnforce CephConfigDriftPolicy = "Enforce"
+)
+
+// CephConfigDriftSpec represents the settings of the Ceph config drift check
+type CephConfigDriftSpec struct {
+	// Disabled disables the drift check
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+	// Interval is the interval between two drift checks. Default is 10 minutes.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// DriftPolicy is the action taken on the drifted options. With "Detect" (the default), the drifted
+	// options are only reported in the CephCluster status. With "Enforce", they are also set back to
+	// their expected values.
+	// +optional
+	DriftPolicy CephConfigDriftPolicy `json:"driftPolicy,omitempty"`
 }
 
 // CSIDriverSpec defines CSI Driver settings applied per cluster.
@@ -554,6 +658,12 @@ type CephVersionSpec struct {
 	// +kubebuilder:validation:Enum=IfNotPresent;Always;Never;""
 	// +optional
 	ImagePullPolicy v1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// PreflightOnly runs the upgrade checks when the image is changed to a new Ceph version, and
+	// reports them in the upgradePreflight status without updating any daemon. The upgrade starts
+	// when this setting is removed.
+	// +optional
+	PreflightOnly bool `json:"preflightOnly,omitempty"`
 }
 
 // DashboardSpec represents the settings for the Ceph dashboard
@@ -578,8 +688,85 @@ type DashboardSpec struct {
 	// Whether to verify the ssl endpoint for prometheus. Set to false for a self-signed cert.
 	// +optional
 	PrometheusEndpointSSLVerify bool `json:"prometheusEndpointSSLVerify,omitempty"`
+	// SSLCertificateRef is the name of the secret that stores the certificate (tls.crt), the private
+	// key (tls.key), and optionally the CA certificate (ca.crt) for the dashboard, such as a secret
+	// issued by cert-manager. The certificate is applied again when the secret is renewed. If not set,
+	// a self-signed certificate is created.
+	// +optional
+	SSLCertificateRef string `json:"sslCertificateRef,omitempty"`
+	// SSO configures the single sign-on of the dashboard
+	// +optional
+	SSO *DashboardSSOSpec `json:"sso,omitempty"`
+	// Roles are custom dashboard roles managed by the operator, in addition to the system roles
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Roles []DashboardRoleSpec `json:"roles,omitempty"`
+}
+
+// DashboardSSOSpec represents the single sign-on settings of the dashboard
+type DashboardSSOSpec struct {
+	// SAML2 configures the single sign-on with a SAML2 identity provider
+	SAML2 DashboardSAML2Spec `json:"saml2"`
+	// Users are the users authenticated by the identity provider and their dashboard roles. A user
+	// is matched by the value of the username attribute returned by the identity provider.
+	// +listType=map
+	// +listMapKey=username
+	// +optional
+	Users []DashboardSSOUserSpec `json:"users,omitempty"`
 }
 
+// DashboardSAML2Spec represents the SAML2 identity provider of the dashboard single sign-on
+// +kubebuilder:validation:XValidation:message="exactly one of idpMetadataURL or idpMetadataSecret must be set",rule="has(self.idpMetadataURL) != has(self.idpMetadataSecret)"
+type DashboardSAML2Spec struct {
+	// BaseURL is the URL where the dashboard is reached by the users, for example
+	// "https://dashboard.example.com/"
+	// +kubebuilder:validation:Pattern=`^https?://`
+	BaseURL string `json:"baseURL"`
+	// IdPMetadataURL is the URL of the metadata of the identity provider
+	// +optional
+	IdPMetadataURL string `json:"idpMetadataURL,omitempty"`
+	// IdPMetadataSecret is the secret key that stores the XML metadata of the identity provider
+	// +optional
+	IdPMetadataSecret *v1.SecretKeySelector `json:"idpMetadataSecret,omitempty"`
+	// UsernameAttribute is the attribute of the identity provider response used as the dashboard
+	// username. Default is "uid".
+	// +optional
+	UsernameAttribute string `json:"usernameAttribute,omitempty"`
+	// IdPEntityID is the entity ID of the identity provider, needed if the metadata describes
+	// more than one identity provider
+	// +optional
+	IdPEntityID string `json:"idpEntityID,omitempty"`
+}
+
+// DashboardSSOUserSpec maps a user of the identity provider to dashboard roles
+type DashboardSSOUserSpec struct {
+	// Username is the value of the username attribute of the user
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:message="the admin user is managed by the operator",rule="self != 'admin'"
+	Username string `json:"username"`
+	// Roles are the dashboard roles of the user, either system roles like "read-only" or roles from
+	// the dashboard roles
+	// +kubebuilder:validation:MinItems=1
+	Roles []string `json:"roles"`
+}
+
+// DashboardRoleSpec represents a custom dashboard role
+// +kubebuilder:validation:XValidation:message="system dashboard roles cannot be redefined",rule="!(self.name in ['administrator', 'read-only', 'block-manager', 'rgw-manager', 'cluster-manager', 'pool-manager', 'cephfs-manager', 'ganesha-manager'])"
+type DashboardRoleSpec struct {
+	// Name is the name of the role
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// ScopePermissions are the permissions of the role on each dashboard scope, for example
+	// "pool: [read, update]". See the Ceph docs for the list of scopes.
+	// +kubebuilder:validation:MinProperties=1
+	ScopePermissions map[string][]DashboardPermission `json:"scopePermissions"`
+}
+
+// DashboardPermission is a permission on a dashboard scope
+// +kubebuilder:validation:Enum=read;create;update;delete
+type DashboardPermission string
+
 // MonitoringSpec represents the settings for Prometheus based Ceph monitoring
 type MonitoringSpec struct {
 	// Enabled determines whether to create the prometheus rules for the ceph cluster. If true, the prometheus
@@ -649,11 +836,263 @@ type ClusterStatus struct {
 	Cephx       ClusterCephxStatus `json:"cephx,omitempty"`
 	CephStorage *CephStorage       `json:"storage,omitempty"`
 	CephVersion *ClusterVersion    `json:"version,omitempty"`
+	// MonBackup shows the status of the periodic mon backups
+	// +optional
+	MonBackup *MonBackupStatus `json:"monBackup,omitempty"`
+	// MonHealth shows the health score of each mon if the mon health scoring is enabled
+	// +optional
+	MonHealth []MonHealthStatus `json:"monHealth,omitempty"`
+	// Dashboard shows the status of the dashboard certificate and access control
+	// +optional
+	Dashboard *DashboardStatus `json:"dashboard,omitempty"`
+	// CephConfigDrift shows the options of the central Ceph config that differ from the CephCluster
+	// +optional
+	CephConfigDrift *CephConfigDriftStatus `json:"cephConfigDrift,omitempty"`
+	// UpgradePreflight shows the result of the last upgrade preflight checks
+	// +optional
+	UpgradePreflight *UpgradePreflightStatus `json:"upgradePreflight,omitempty"`
+	// UpgradeHistory is the history of the Ceph upgrades of the cluster, the most recent last. Only the
+	// most recent upgrades are kept.
+	// +optional
+	UpgradeHistory []UpgradeHistoryEntry `json:"upgradeHistory,omitempty"`
+	// Recovery shows the recovery profile currently applied by the recovery schedule
+	// +optional
+	Recovery *RecoveryStatus `json:"recovery,omitempty"`
 	// ObservedGeneration is the latest generation observed by the controller.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// RecoveryStatus represents the recovery profile applied to the OSDs
+type RecoveryStatus struct {
+	// Window is the name of the active window, "default" outside of the windows, or
+	// "force-fast-recovery" when the fast recovery is forced with an annotation
+	// +optional
+	Window string `json:"window,omitempty"`
+	// Profile is the applied recovery profile
+	// +optional
+	Profile *RecoveryProfile `json:"profile,omitempty"`
+	// LastTransitionTime is the time when the profile was applied
+	// +optional
+	// +nullable
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// DashboardStatus represents the status of the dashboard
+type DashboardStatus struct {
+	// CertificateExpiry is the time when the dashboard certificate expires
+	// +optional
+	// +nullable
+	CertificateExpiry *metav1.Time `json:"certificateExpiry,omitempty"`
+	// CertificateSource is the source of the dashboard certificate, either the name of the secret
+	// from the sslCertificateRef or "self-signed"
+	// +optional
+	CertificateSource string `json:"certificateSource,omitempty"`
+	// SSO is the single sign-on protocol enabled by the operator
+	// +optional
+	SSO string `json:"sso,omitempty"`
+	// SSOUsers are the single sign-on users created by the operator
+	// +optional
+	SSOUsers []string `json:"ssoUsers,omitempty"`
+	// Roles are the custom dashboard roles created by the operator
+	// +optional
+	Roles []string `json:"roles,omitempty"`
+}
+
+// CephConfigDriftStatus represents the result of the last Ceph config drift check
+type CephConfigDriftStatus struct {
+	// LastChecked is the time of the last drift check
+	// +optional
+	// +nullable
+	LastChecked *metav1.Time `json:"lastChecked,omitempty"`
+	// Options are the options that differed from their expected values at the last check
+	// +optional
+	Options []CephConfigDriftedOption `json:"options,omitempty"`
+}
+
+// CephConfigDriftedOption is an option of the central Ceph config that differs from its expected value
+type CephConfigDriftedOption struct {
+	// Who is the target of the option, for example "global" or "osd"
+	Who string `json:"who"`
+	// Option is the name of the option
+	Option string `json:"option"`
+	// Expected is the value from the CephCluster or the Rook defaults. The values from secrets are not shown.
+	// +optional
+	Expected string `json:"expected,omitempty"`
+	// Actual is the value in the central Ceph config. The values from secrets are not shown.
+	// +optional
+	Actual string `json:"actual,omitempty"`
+	// Missing is true if the option was removed from the central Ceph config
+	// +optional
+	Missing bool `json:"missing,omitempty"`
+	// Reverted is true if the option was set back to its expected value
+	// +optional
+	Reverted bool `json:"reverted,omitempty"`
+}
+
+// UpgradePreflightStatus represents the report of the checks run before upgrading to a new Ceph image
+type UpgradePreflightStatus struct {
+	// Image is the Ceph image of the upgrade
+	// +optional
+	Image string `json:"image,omitempty"`
+	// TargetVersion is the Ceph version of the image
+	// +optional
+	TargetVersion string `json:"targetVersion,omitempty"`
+	// RunningVersion is the oldest Ceph version running in the cluster
+	// +optional
+	RunningVersion string `json:"runningVersion,omitempty"`
+	// LastChecked is the time of the checks
+	// +optional
+	// +nullable
+	LastChecked *metav1.Time `json:"lastChecked,omitempty"`
+	// Result is the worst result of the checks
+	// +optional
+	Result UpgradePreflightResult `json:"result,omitempty"`
+	// Checks are the results of each check
+	// +optional
+	Checks []UpgradePreflightCheck `json:"checks,omitempty"`
+}
+
+// UpgradePreflightCheck represents the result of an upgrade preflight check
+type UpgradePreflightCheck struct {
+	// Name is the name of the check, such as "PGHealth" or "OkToStop/host/node-a"
+	Name string `json:"name"`
+	// Result is the result of the check
+	Result UpgradePreflightResult `json:"result"`
+	// Message describes the result of the check
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// UpgradePreflightResult is the result of an upgrade preflight check
+// +kubebuilder:validation:Enum=Passed;Warning;Failed
+type UpgradePreflightResult string
+
+const (
+	// UpgradePreflightPassed means that the check does not prevent the upgrade
+	UpgradePreflightPassed UpgradePreflightResult = "Passed"
+	// UpgradePreflightWarning means that the upgrade can proceed but needs attention
+	UpgradePreflightWarning UpgradePreflightResult = "Warning"
+	// UpgradePreflightFailed means that the upgrade would fail or harm the cluster
+	UpgradePreflightFailed UpgradePreflightResult = "Failed"
+)
+
+// UpgradeHistoryEntry records a Ceph upgrade of the cluster
+type UpgradeHistoryEntry struct {
+	// Image is the Ceph image of the upgrade
+	// +optional
+	Image string `json:"image,omitempty"`
+	// FromCephVersion is the least recent Ceph version running when the upgrade started
+	// +optional
+	FromCephVersion string `json:"fromCephVersion,omitempty"`
+	// ToCephVersion is the Ceph version of the image
+	// +optional
+	ToCephVersion string `json:"toCephVersion,omitempty"`
+	// FromRookVersion is the Rook version that last updated the daemons before the upgrade
+	// +optional
+	FromRookVersion string `json:"fromRookVersion,omitempty"`
+	// ToRookVersion is the Rook version of the operator running the upgrade
+	// +optional
+	ToRookVersion string `json:"toRookVersion,omitempty"`
+	// StartTime is the time the upgrade started
+	// +optional
+	// +nullable
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// EndTime is the time the upgrade ended
+	// +optional
+	// +nullable
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+	// Daemons is the time spent updating each daemon type. The times are observed at the interval of
+	// the Ceph health checks.
+	// +optional
+	Daemons []UpgradeDaemonTiming `json:"daemons,omitempty"`
+	// HealthWarnings are the Ceph health checks raised during the upgrade
+	// +optional
+	HealthWarnings []string `json:"healthWarnings,omitempty"`
+	// Result is the result of the upgrade
+	// +optional
+	Result UpgradeResult `json:"result,omitempty"`
+	// Message is the reason the upgrade failed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// UpgradeDaemonTiming is the time spent updating the daemons of a type
+type UpgradeDaemonTiming struct {
+	// DaemonType is the type of the daemons, such as "mon" or "osd"
+	DaemonType string `json:"daemonType"`
+	// StartTime is the time the first daemon of the type was seen running the new version
+	// +optional
+	// +nullable
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// EndTime is the time all the daemons of the type were seen running the new version
+	// +optional
+	// +nullable
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+	// Duration is the time from the end of the previous daemon type, or from the start of the upgrade,
+	// until all the daemons of the type were seen running the new version
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// UpgradeResult is the result of a Ceph upgrade
+// +kubebuilder:validation:Enum=InProgress;Succeeded;Superseded;Failed
+type UpgradeResult string
+
+const (
+	// UpgradeInProgress means that some daemons do not run the new version yet
+	UpgradeInProgress UpgradeResult = "InProgress"
+	// UpgradeSucceeded means that all the daemons run the new version
+	UpgradeSucceeded UpgradeResult = "Succeeded"
+	// UpgradeSuperseded means that the image was changed again before all the daemons were updated
+	UpgradeSuperseded UpgradeResult = "Superseded"
+	// UpgradeFailed means that the upgrade was refused or stopped by the upgrade checks
+	UpgradeFailed UpgradeResult = "Failed"
+)
+
+// MonBackupStatus represents the status of the periodic mon backups
+type MonBackupStatus struct {
+	// LastScheduleTime is the last time a mon backup was started
+	// +optional
+	// +nullable
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastSuccessfulTime is the last time a mon backup completed successfully
+	// +optional
+	// +nullable
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+}
+
+// MonHealthStatus represents the health score of a mon
+type MonHealthStatus struct {
+	// Name is the name of the mon
+	Name string `json:"name"`
+	// Score is the health score of the mon, from 0 (unhealthy) to 100 (healthy)
+	Score int `json:"score"`
+	// Signals are the signals that lowered the score of the mon
+	// +optional
+	Signals []MonHealthSignal `json:"signals,omitempty"`
+	// DegradedSince is the time since when the score of the mon is below the failover threshold
+	// +optional
+	// +nullable
+	DegradedSince *metav1.Time `json:"degradedSince,omitempty"`
+}
+
+// MonHealthSignal is a signal that lowers the health score of a mon
+type MonHealthSignal string
+
+const (
+	// MonHealthSignalOutOfQuorum is set when the mon is not in quorum
+	MonHealthSignalOutOfQuorum MonHealthSignal = "OutOfQuorum"
+	// MonHealthSignalClockSkew is set when the clock of the mon is skewed from the other mons
+	MonHealthSignalClockSkew MonHealthSignal = "ClockSkew"
+	// MonHealthSignalStoreSize is set when the store of the mon has grown large or its disk is low on space
+	MonHealthSignalStoreSize MonHealthSignal = "StoreSize"
+	// MonHealthSignalElectionChurn is set when the mon repeatedly left and joined the quorum
+	MonHealthSignalElectionChurn MonHealthSignal = "ElectionChurn"
+	// MonHealthSignalSlowOps is set when the mon has slow ops
+	MonHealthSignalSlowOps MonHealthSignal = "SlowOps"
+)
+
 // CephDaemonsVersions show the current ceph version for different ceph daemons
 type CephDaemonsVersions struct {
 	// Mon shows Mon Ceph version
@@ -708,8 +1147,73 @@ type CephStorage struct {
 	DeviceClasses  []DeviceClasses  `json:"deviceClasses,omitempty"`
 	OSD            OSDStatus        `json:"osd,omitempty"`
 	DeprecatedOSDs map[string][]int `json:"deprecatedOSDs,omitempty"`
+	// DeviceHealth is the health of the OSD devices when the device health checks are enabled
+	// +optional
+	DeviceHealth *DeviceHealthStatus `json:"deviceHealth,omitempty"`
+	// CrushHierarchy is the state of the declared CRUSH hierarchy, if any
+	// +optional
+	CrushHierarchy *CrushHierarchyStatus `json:"crushHierarchy,omitempty"`
+}
+
+// CrushHierarchyStatus is the state of the declared CRUSH hierarchy in the CRUSH map
+type CrushHierarchyStatus struct {
+	// InSync is true when the CRUSH map matches the declared hierarchy
+	InSync bool `json:"inSync"`
+	// Differences are the differences between the declared hierarchy and the CRUSH map that were
+	// not reconciled, for example a host without OSDs yet
+	// +optional
+	Differences []string `json:"differences,omitempty"`
+}
+
+// DeviceHealthStatus is the health of the OSD devices
+type DeviceHealthStatus struct {
+	// LastChecked is the time of the last device health check
+	// +optional
+	// +nullable
+	LastChecked *metav1.Time `json:"lastChecked,omitempty"`
+	// Devices is the health of each device used by the OSDs
+	// +optional
+	Devices []DeviceHealth `json:"devices,omitempty"`
 }
 
+// DeviceHealth is the health of a device used by OSDs
+type DeviceHealth struct {
+	// DeviceID is the ID of the device in Ceph, made of its vendor, model and serial number
+	DeviceID string `json:"deviceID"`
+	// Host is the host of the device
+	// +optional
+	Host string `json:"host,omitempty"`
+	// OSDs are the IDs of the OSDs using the device
+	// +optional
+	OSDs []int `json:"osds,omitempty"`
+	// Health is the health of the device
+	// +optional
+	Health DeviceHealthState `json:"health,omitempty"`
+	// LifeExpectancy is the earliest predicted failure time of the device, if a failure is predicted
+	// +optional
+	LifeExpectancy string `json:"lifeExpectancy,omitempty"`
+	// Message describes why the device is not healthy
+	// +optional
+	Message string `json:"message,omitempty"`
+	// MarkedOut is true if Rook marked out the OSDs of the device with the MarkOut failure policy
+	// +optional
+	MarkedOut bool `json:"markedOut,omitempty"`
+}
+
+// DeviceHealthState is the health of a device
+type DeviceHealthState string
+
+const (
+	// DeviceHealthGood means that the SMART data of the device shows no problem
+	DeviceHealthGood DeviceHealthState = "Good"
+	// DeviceHealthWarning means that the device shows errors or wear but is not predicted to fail soon
+	DeviceHealthWarning DeviceHealthState = "Warning"
+	// DeviceHealthFailing means that the device failed its SMART checks or is predicted to fail soon
+	DeviceHealthFailing DeviceHealthState = "Failing"
+	// DeviceHealthUnknown means that no SMART data was scraped for the device
+	DeviceHealthUnknown DeviceHealthState = "Unknown"
+)
+
 // DeviceClasses represents device classes of a Ceph Cluster
 type DeviceClasses struct {
 	Name string `json:"name,omitempty"`
@@ -720,11 +1224,153 @@ type OSDStatus struct {
 	// StoreType is a mapping between the OSD backend stores and number of OSDs using these stores
 	StoreType       map[string]int  `json:"storeType,omitempty"`
 	MigrationStatus MigrationStatus `json:"migrationStatus,omitempty"`
+	// Upgrade is the position of the OSD upgrade with the Canary upgrade strategy
+	// +optional
+	Upgrade *OSDUpgradeStatus `json:"upgrade,omitempty"`
+	// Replacements are the OSD replacements in progress and the most recent completed replacements
+	// +optional
+	Replacements []OSDReplacementStatus `json:"replacements,omitempty"`
+	// KeyRotations are the results of the encryption key rotation of each encrypted OSD
+	// +optional
+	KeyRotations []OSDKeyRotationStatus `json:"keyRotations,omitempty"`
+}
+
+// OSDKeyRotationStatus is the result of the encryption key rotation of an OSD
+type OSDKeyRotationStatus struct {
+	// ID is the ID of the OSD
+	ID int `json:"id"`
+	// Host is the node of a host-based OSD, or the PVC of an OSD on a PVC
+	// +optional
+	Host string `json:"host,omitempty"`
+	// LastScheduleTime is the last time the key rotation started
+	// +optional
+	// +nullable
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastSuccessfulTime is the last time the key was rotated successfully
+	// +optional
+	// +nullable
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// Result is the result of the last key rotation
+	// +optional
+	Result OSDKeyRotationResult `json:"result,omitempty"`
 }
 
+// OSDKeyRotationResult is the result of the last key rotation of an OSD
+type OSDKeyRotationResult string
+
+const (
+	// OSDKeyRotationPending means that the key was not rotated yet
+	OSDKeyRotationPending OSDKeyRotationResult = "Pending"
+	// OSDKeyRotationRunning means that the key rotation is in progress
+	OSDKeyRotationRunning OSDKeyRotationResult = "Running"
+	// OSDKeyRotationSucceeded means that the last key rotation succeeded
+	OSDKeyRotationSucceeded OSDKeyRotationResult = "Succeeded"
+	// OSDKeyRotationFailed means that the last key rotation failed
+	OSDKeyRotationFailed OSDKeyRotationResult = "Failed"
+)
+
+// OSDReplacementStatus represents the replacement of an OSD that keeps its ID
+type OSDReplacementStatus struct {
+	// ID is the ID of the OSD being replaced
+	ID int `json:"id"`
+	// Host is the host of the OSD
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Devices are the backing devices of the OSD that were missing when the replacement was requested
+	// +optional
+	Devices []string `json:"devices,omitempty"`
+	// Automatic is true if the replacement was requested by Rook for a failed disk
+	// +optional
+	Automatic bool `json:"automatic,omitempty"`
+	// Phase is the phase of the replacement
+	// +optional
+	Phase OSDReplacementPhase `json:"phase,omitempty"`
+	// StartTime is the time the replacement was requested
+	// +optional
+	// +nullable
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// EndTime is the time the replacement completed, was cancelled, or failed
+	// +optional
+	// +nullable
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+	// Message describes the phase of the replacement
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// OSDReplacementPhase is the phase of an OSD replacement
+type OSDReplacementPhase string
+
+const (
+	// OSDReplacementRequested means that the replacement is waiting to be validated
+	OSDReplacementRequested OSDReplacementPhase = "Requested"
+	// OSDReplacementDraining means that the OSD is out and waits to be safe to destroy
+	OSDReplacementDraining OSDReplacementPhase = "Draining"
+	// OSDReplacementReadyForSwap means that the OSD is destroyed and waits for a new disk
+	OSDReplacementReadyForSwap OSDReplacementPhase = "ReadyForSwap"
+	// OSDReplacementCompleted means that the OSD is up on the new disk
+	OSDReplacementCompleted OSDReplacementPhase = "Completed"
+	// OSDReplacementCancelled means that the replacement was cancelled before the OSD was destroyed
+	OSDReplacementCancelled OSDReplacementPhase = "Cancelled"
+	// OSDReplacementFailed means that the OSD was removed before the replacement completed
+	OSDReplacementFailed OSDReplacementPhase = "Failed"
+)
+
+// OSDUpgradeStatus represents the position of an OSD upgrade by failure domain
+type OSDUpgradeStatus struct {
+	// Image is the Ceph image the OSDs are updated to
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Phase is the phase of the upgrade
+	// +optional
+	Phase OSDUpgradePhase `json:"phase,omitempty"`
+	// FailureDomainType is the CRUSH bucket type updated at a time
+	// +optional
+	FailureDomainType string `json:"failureDomainType,omitempty"`
+	// CanaryFailureDomain is the failure domain updated first
+	// +optional
+	CanaryFailureDomain string `json:"canaryFailureDomain,omitempty"`
+	// CurrentFailureDomain is the failure domain being updated or soaking
+	// +optional
+	CurrentFailureDomain string `json:"currentFailureDomain,omitempty"`
+	// UpdatedFailureDomains are the failure domains whose OSDs run the image
+	// +optional
+	UpdatedFailureDomains []string `json:"updatedFailureDomains,omitempty"`
+	// PendingFailureDomains are the failure domains whose OSDs are not updated yet
+	// +optional
+	PendingFailureDomains []string `json:"pendingFailureDomains,omitempty"`
+	// SoakStartTime is the time the soak checks of the current failure domain started
+	// +optional
+	// +nullable
+	SoakStartTime *metav1.Time `json:"soakStartTime,omitempty"`
+	// Message describes the phase of the upgrade
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// OSDUpgradePhase is the phase of an OSD upgrade by failure domain
+type OSDUpgradePhase string
+
+const (
+	// OSDUpgradeUpdating means that the OSDs of the current failure domain are being updated
+	OSDUpgradeUpdating OSDUpgradePhase = "Updating"
+	// OSDUpgradeSoaking means that the cluster health is checked after the current failure domain was updated
+	OSDUpgradeSoaking OSDUpgradePhase = "Soaking"
+	// OSDUpgradeAwaitingApproval means that the canary failure domain is updated and the upgrade waits for the approval
+	OSDUpgradeAwaitingApproval OSDUpgradePhase = "AwaitingApproval"
+	// OSDUpgradeHalted means that the cluster became unhealthy during the soak checks
+	OSDUpgradeHalted OSDUpgradePhase = "Halted"
+	// OSDUpgradeCompleted means that all the OSDs run the image
+	OSDUpgradeCompleted OSDUpgradePhase = "Completed"
+)
+
 // MigrationStatus status represents the current status of any OSD migration.
 type MigrationStatus struct {
 	Pending int `json:"pending,omitempty"`
+	// PendingByReason is the number of OSDs pending migration for each reason: encryption, storeType
+	// or lvmToRaw
+	// +optional
+	PendingByReason map[string]int `json:"pendingByReason,omitempty"`
 }
 
 // ClusterVersion represents the version of a Ceph Cluster
@@ -792,6 +1438,32 @@ const (
 	// RadosNamespaceEmptyReason represents when a rados namespace does not contain images or snapshots that are blocking
 	// deletion.
 	RadosNamespaceEmptyReason ConditionReason = "RadosNamespaceEmpty"
+	// MonBackupRestoreInProgressReason represents when a mon backup is being restored.
+	MonBackupRestoreInProgressReason ConditionReason = "MonBackupRestoreInProgress"
+	// MonBackupRestoreCompletedReason represents when a mon was restarted with the store of a mon backup.
+	MonBackupRestoreCompletedReason ConditionReason = "MonBackupRestoreCompleted"
+	// MonBackupRestoreFailedReason represents when a mon backup could not be restored.
+	MonBackupRestoreFailedReason ConditionReason = "MonBackupRestoreFailed"
+	// MonQuorumRecoveryInProgressReason represents when the mon store is being rebuilt from the OSDs.
+	MonQuorumRecoveryInProgressReason ConditionReason = "MonQuorumRecoveryInProgress"
+	// MonQuorumRecoveryCompletedReason represents when a mon was restarted with the rebuilt mon store.
+	MonQuorumRecoveryCompletedReason ConditionReason = "MonQuorumRecoveryCompleted"
+	// MonQuorumRecoveryFailedReason represents when the mon store could not be rebuilt from the OSDs.
+	MonQuorumRecoveryFailedReason ConditionReason = "MonQuorumRecoveryFailed"
+	// CephConfigDriftDetectedReason represents when options of the central Ceph config differ from the CephCluster.
+	CephConfigDriftDetectedReason ConditionReason = "CephConfigDriftDetected"
+	// CephConfigDriftRevertedReason represents when the drifted options were set back to their expected values.
+	CephConfigDriftRevertedReason ConditionReason = "CephConfigDriftReverted"
+	// CephConfigInSyncReason represents when the central Ceph config matches the CephCluster.
+	CephConfigInSyncReason ConditionReason = "CephConfigInSync"
+	// CephConfigOptionsRejectedReason represents when ceph config options do not match the option schema of the cluster.
+	CephConfigOptionsRejectedReason ConditionReason = "CephConfigOptionsRejected"
+	// CephConfigOptionsValidReason represents when all the ceph config options match the option schema of the cluster.
+	CephConfigOptionsValidReason ConditionReason = "CephConfigOptionsValid"
+	// UpgradePreflightPassedReason represents the reason the upgrade preflight checks did not find any failure
+	UpgradePreflightPassedReason ConditionReason = "UpgradePreflightPassed"
+	// UpgradePreflightFailedReason represents the reason some upgrade preflight checks failed
+	UpgradePreflightFailedReason ConditionReason = "UpgradePreflightFailed"
 )
 
 // ConditionType represent a resource's status
@@ -817,6 +1489,16 @@ const (
 	ConditionPoolDeletionIsBlocked ConditionType = "PoolDeletionIsBlocked"
 	// ConditionRadosNSDeletionIsBlocked represents when deletion of the object is blocked.
 	ConditionRadosNSDeletionIsBlocked ConditionType = "RadosNamespaceDeletionIsBlocked"
+	// ConditionMonBackupRestore represents the progress of the restore of a mon backup.
+	ConditionMonBackupRestore ConditionType = "MonBackupRestore"
+	// ConditionMonQuorumRecovery represents the progress of a mon quorum recovery from the OSDs.
+	ConditionMonQuorumRecovery ConditionType = "MonQuorumRecovery"
+	// ConditionCephConfigDrift represents whether the central Ceph config differs from the CephCluster.
+	ConditionCephConfigDrift ConditionType = "CephConfigDrift"
+	// ConditionCephConfigInvalid represents whether options of the ceph config of the CephCluster were rejected.
+	ConditionCephConfigInvalid ConditionType = "CephConfigInvalid"
+	// ConditionUpgradePreflight represents whether the upgrade preflight checks found a failure.
+	ConditionUpgradePreflight ConditionType = "UpgradePreflight"
 )
 
 // ClusterState represents the state of a Ceph Cluster
@@ -933,6 +1615,109 @@ type MonSpec struct {
 	// and can be scheduled on either node. Template variables are supplied via a ConfigMap.
 	// +optional
 	FloatingMon FloatingMonSpec `json:"floatingMon,omitempty,omitzero"`
+
+	// Backup is the specification of the periodic mon backups
+	// +optional
+	Backup MonBackupSpec `json:"backup,omitempty"`
+
+	// HealthScoring is the specification of the mon health scoring, which scores each mon from
+	// several health signals in addition to its quorum membership
+	// +optional
+	HealthScoring MonHealthScoringSpec `json:"healthScoring,omitempty"`
+
+	// Relocations are the mons to move to a chosen node. A new mon is started on the target node and
+	// the relocated mon is removed once the new mon is in quorum, so the mon count never drops.
+	// Relocations of mons that no longer exist are ignored.
+	// +kubebuilder:validation:MaxItems=9
+	// +listType=map
+	// +listMapKey=mon
+	// +optional
+	Relocations []MonRelocation `json:"relocations,omitempty"`
+}
+
+// MonRelocation is a request to move a mon to a chosen node
+type MonRelocation struct {
+	// Mon is the name of the mon to relocate, for example "b"
+	// +kubebuilder:validation:MinLength=1
+	Mon string `json:"mon"`
+	// Node is the name of the node where the mon is moved
+	// +kubebuilder:validation:MinLength=1
+	Node string `json:"node"`
+}
+
+// MonHealthScoringSpec represents the settings of the mon health scoring. Each mon is scored from
+// its quorum membership, clock skew, store size, election churn, and slow ops.
+type MonHealthScoringSpec struct {
+	// Enabled turns on the mon health scoring. The score of each mon is shown in the CephCluster status.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// FailoverPolicy is the action taken when the score of a mon stays below the failover threshold.
+	// With "None", the score is only reported. With "Proactive", the mon is failed over even while it
+	// is still in quorum, as long as all the other mons are in quorum. The default is "None".
+	// +kubebuilder:validation:Enum=None;Proactive
+	// +optional
+	FailoverPolicy MonHealthFailoverPolicy `json:"failoverPolicy,omitempty"`
+	// FailoverThreshold is the score below which a mon is degraded. The default is 50.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	FailoverThreshold int `json:"failoverThreshold,omitempty"`
+	// FailoverDelay is how long the score of a mon must stay below the failover threshold before
+	// the mon is failed over with the "Proactive" policy. The default is 10m.
+	// +optional
+	FailoverDelay *metav1.Duration `json:"failoverDelay,omitempty"`
+}
+
+// MonHealthFailoverPolicy is the action taken for a mon with a degraded health score
+type MonHealthFailoverPolicy string
+
+const (
+	// MonHealthFailoverPolicyNone only reports the health score of the mons
+	MonHealthFailoverPolicyNone MonHealthFailoverPolicy = "None"
+	// MonHealthFailoverPolicyProactive fails over the mons with a degraded health score
+	MonHealthFailoverPolicyProactive MonHealthFailoverPolicy = "Proactive"
+)
+
+// MonBackupSpec represents the settings for periodic mon backups. A backup is a copy of the store of
+// a mon, taken while the mon is briefly stopped so that the copy is consistent. Either a PVC or an S3
+// bucket must be set as the backup target.
+// +kubebuilder:validation:XValidation:message="exactly one of persistentVolumeClaim or s3 must be set when a schedule is set",rule="!has(self.schedule) || size(self.schedule) == 0 || (has(self.persistentVolumeClaim) && size(self.persistentVolumeClaim) > 0) != has(self.s3)"
+type MonBackupSpec struct {
+	// Schedule is the cron schedule of the mon backups, e.g. "@daily" or "0 2 * * *".
+	// Backups are disabled if not set.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+	// Retention is the number of backups to keep. Older backups are deleted after each successful backup.
+	// If not set, 7 backups are kept.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Retention int `json:"retention,omitempty"`
+	// PersistentVolumeClaim is the name of an existing PVC in the cluster namespace where the backups are stored
+	// +optional
+	PersistentVolumeClaim string `json:"persistentVolumeClaim,omitempty"`
+	// S3 is the S3 bucket where the backups are stored
+	// +optional
+	S3 *MonBackupS3Spec `json:"s3,omitempty"`
+}
+
+// MonBackupS3Spec represents an S3 bucket used as a mon backup target
+type MonBackupS3Spec struct {
+	// Endpoint is the URL of the S3 endpoint
+	// +kubebuilder:validation:MinLength=1
+	Endpoint string `json:"endpoint"`
+	// Bucket is the name of the bucket where the backups are stored
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+	// Prefix is the object key prefix of the backups in the bucket. A trailing "/" is added if missing.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+	// Region is the region of the bucket
+	// +optional
+	Region string `json:"region,omitempty"`
+	// CredentialsSecretName is the name of a secret in the cluster namespace with the
+	// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY keys used to access the bucket
+	// +kubebuilder:validation:MinLength=1
+	CredentialsSecretName string `json:"credentialsSecretName"`
 }
 
 // +kubebuilder:validation:MinProperties=2
@@ -1058,6 +1843,7 @@ type CrashCollectorSpec struct {
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
 // +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.status.info.type`
 // +kubebuilder:printcolumn:name="FailureDomain",type=string,JSONPath=`.status.info.failureDomain`
+// +kubebuilder:printcolumn:name="Used",type=integer,JSONPath=`.status.capacity.percentUsed`,description="Percentage of the capacity of the pool that is used",priority=1
 // +kubebuilder:printcolumn:name="Replication",type=integer,JSONPath=`.spec.replicated.size`,priority=1
 // +kubebuilder:printcolumn:name="EC-CodingChunks",type=integer,JSONPath=`.spec.erasureCoded.codingChunks`,priority=1
 // +kubebuilder:printcolumn:name="EC-DataChunks",type=integer,JSONPath=`.spec.erasureCoded.dataChunks`,priority=1
@@ -1103,6 +1889,11 @@ type PoolSpec struct {
 	// +nullable
 	DeviceClass string `json:"deviceClass,omitempty"`
 
+	// CrushRule is the name of a rule in the CRUSH map, usually defined by a CephCrushRule, that places
+	// the data of the pool instead of a rule generated from the failureDomain, crushRoot and deviceClass
+	// +optional
+	CrushRule string `json:"crushRule,omitempty"`
+
 	// Allow rook operator to change the pool CRUSH tunables once the pool is created
 	// +nullable
 	// +optional
@@ -1160,6 +1951,29 @@ type NamedBlockPoolSpec struct {
 	Name string `json:"name,omitempty"`
 	// The core pool configuration
 	PoolSpec `json:",inline"`
+	// Migration moves the RBD images of the pool to a new pool with another layout
+	// +optional
+	// +nullable
+	Migration *PoolMigrationSpec `json:"migration,omitempty"`
+}
+
+// PoolMigrationSpec moves the RBD images of a pool to a new pool. Once all the images are moved, the
+// new pool takes the name of the pool.
+type PoolMigrationSpec struct {
+	// Target is the layout of the new pool. The default is the layout of the pool. The new pool must be
+	// replicated since the RBD images keep their metadata in it, the data can be stored in an erasure
+	// coded pool with dataPool.
+	// +optional
+	// +nullable
+	Target *PoolSpec `json:"target,omitempty"`
+	// DataPool is the name of an existing pool, usually erasure coded, that stores the data of the
+	// migrated images
+	// +optional
+	DataPool string `json:"dataPool,omitempty"`
+	// MaxConcurrentImages is the number of images migrated at the same time. The default is 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrentImages int `json:"maxConcurrentImages,omitempty"`
 }
 
 // NamedPoolSpec represents the named ceph pool spec
@@ -1175,6 +1989,10 @@ type MirrorHealthCheckSpec struct {
 	// +optional
 	// +nullable
 	Mirror HealthCheckSpec `json:"mirror,omitempty"`
+	// Capacity is the refresh of the capacity and placement groups of a CephBlockPool in its status
+	// +optional
+	// +nullable
+	Capacity HealthCheckSpec `json:"capacity,omitempty"`
 }
 
 // CephBlockPoolStatus represents the mirroring status of Ceph Storage Pool
@@ -1189,6 +2007,13 @@ type CephBlockPoolStatus struct {
 	MirroringInfo *MirroringInfoSpec `json:"mirroringInfo,omitempty"`
 	// optional
 	PoolID int `json:"poolID,omitempty"`
+	// Capacity is the usage and the placement groups of the pool, refreshed at the
+	// statusCheck.capacity interval
+	// +optional
+	Capacity *PoolCapacityStatus `json:"capacity,omitempty"`
+	// Migration is the progress of the migration of the images to a new pool
+	// +optional
+	Migration *PoolMigrationStatus `json:"migration,omitempty"`
 	// +optional
 	SnapshotScheduleStatus *SnapshotScheduleStatusSpec `json:"snapshotScheduleStatus,omitempty"`
 	// +optional
@@ -1200,6 +2025,142 @@ type CephBlockPoolStatus struct {
 	Conditions         []Condition `json:"conditions,omitempty"`
 }
 
+// PoolMigrationPhase is the phase of the migration of a pool
+type PoolMigrationPhase string
+
+const (
+	// PoolMigrationMigrating means that the images are being moved to the new pool
+	PoolMigrationMigrating PoolMigrationPhase = "Migrating"
+	// PoolMigrationSwapping means that all the images are moved and the new pool is taking the name of the pool
+	PoolMigrationSwapping PoolMigrationPhase = "Swapping"
+	// PoolMigrationCompleted means that the new pool has the name of the pool
+	PoolMigrationCompleted PoolMigrationPhase = "Completed"
+)
+
+// PoolMigrationImageState is the state of the migration of an image
+type PoolMigrationImageState string
+
+const (
+	// PoolMigrationImageInUse means that the image is not migrated until it is released by its clients
+	PoolMigrationImageInUse PoolMigrationImageState = "InUse"
+	// PoolMigrationImageMigrating means that the data of the image is being copied to the new pool
+	PoolMigrationImageMigrating PoolMigrationImageState = "Migrating"
+	// PoolMigrationImageCompleted means that the image is in the new pool
+	PoolMigrationImageCompleted PoolMigrationImageState = "Completed"
+	// PoolMigrationImageFailed means that the migration of the image failed and is retried
+	PoolMigrationImageFailed PoolMigrationImageState = "Failed"
+)
+
+// PoolMigrationStatus is the progress of the migration of the images of a pool
+type PoolMigrationStatus struct {
+	// Phase is the phase of the migration
+	// +optional
+	Phase PoolMigrationPhase `json:"phase,omitempty"`
+	// TargetPool is the name of the new pool while the images are moved
+	// +optional
+	TargetPool string `json:"targetPool,omitempty"`
+	// SourcePoolID is the ID of the pool the images are moved from
+	// +optional
+	SourcePoolID int `json:"sourcePoolID,omitempty"`
+	// TargetPoolID is the ID of the pool the images are moved to
+	// +optional
+	TargetPoolID int `json:"targetPoolID,omitempty"`
+	// CompletedImages is the number of images moved to the new pool
+	// +optional
+	CompletedImages int `json:"completedImages,omitempty"`
+	// TotalImages is the number of images to move
+	// +optional
+	TotalImages int `json:"totalImages,omitempty"`
+	// Images is the migration state of the images being moved, waiting for their clients, or failed.
+	// The images waiting for their turn are only counted in totalImages.
+	// +optional
+	Images []PoolMigrationImageStatus `json:"images,omitempty"`
+	// Message is the error of the last migration step, if any
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PoolMigrationImageStatus is the migration state of an image
+type PoolMigrationImageStatus struct {
+	// Name is the name of the image
+	Name string `json:"name"`
+	// State is the migration state of the image
+	State PoolMigrationImageState `json:"state"`
+	// Progress is the percentage of the data of the image copied to the new pool
+	// +optional
+	Progress int `json:"progress,omitempty"`
+	// Message is the reason of the state, if any
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PoolCapacityStatus is the usage of a pool and its placement groups as reported by Ceph
+type PoolCapacityStatus struct {
+	// StoredBytes is the size of the data stored in the pool, before replication or erasure coding
+	// +optional
+	StoredBytes int64 `json:"storedBytes,omitempty"`
+	// UsedRawBytes is the capacity used by the pool on the OSDs, including the copies or coding chunks
+	// +optional
+	UsedRawBytes int64 `json:"usedRawBytes,omitempty"`
+	// PercentUsed is the percentage of the capacity available to the pool that is used
+	// +optional
+	PercentUsed int `json:"percentUsed,omitempty"`
+	// MaxAvailableBytes is the size of the data that can still be stored in the pool
+	// +optional
+	MaxAvailableBytes int64 `json:"maxAvailableBytes,omitempty"`
+	// Objects is the number of objects in the pool
+	// +optional
+	Objects int64 `json:"objects,omitempty"`
+	// Quota is the usage of the quotas set on the pool
+	// +optional
+	Quota *PoolQuotaStatus `json:"quota,omitempty"`
+	// CompressionRatio is the size of the compressed data before compression divided by its size
+	// after compression
+	// +optional
+	CompressionRatio *float64 `json:"compressionRatio,omitempty"`
+	// PlacementGroups is the number of placement groups of the pool
+	// +optional
+	PlacementGroups PoolPlacementGroupsStatus `json:"placementGroups,omitempty"`
+	// LastChecked is the time the capacity was last refreshed
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+	// Message is the error of the last refresh, if any
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PoolQuotaStatus is the usage of the quotas of a pool
+type PoolQuotaStatus struct {
+	// MaxBytes is the quota on the size of the data of the pool
+	// +optional
+	MaxBytes int64 `json:"maxBytes,omitempty"`
+	// BytesPercentUsed is the percentage of MaxBytes that is used
+	// +optional
+	BytesPercentUsed int `json:"bytesPercentUsed,omitempty"`
+	// MaxObjects is the quota on the number of objects of the pool
+	// +optional
+	MaxObjects int64 `json:"maxObjects,omitempty"`
+	// ObjectsPercentUsed is the percentage of MaxObjects that is used
+	// +optional
+	ObjectsPercentUsed int `json:"objectsPercentUsed,omitempty"`
+}
+
+// PoolPlacementGroupsStatus is the current and target number of placement groups of a pool
+type PoolPlacementGroupsStatus struct {
+	// PGNum is the current number of placement groups of the pool
+	// +optional
+	PGNum int `json:"pgNum,omitempty"`
+	// TargetPGNum is the number of placement groups the pool is changing to
+	// +optional
+	TargetPGNum int `json:"targetPGNum,omitempty"`
+	// AutoscaleMode is the mode of the placement group autoscaler for the pool: on, warn or off
+	// +optional
+	AutoscaleMode string `json:"autoscaleMode,omitempty"`
+	// RecommendedPGNum is the number of placement groups recommended by the autoscaler
+	// +optional
+	RecommendedPGNum int `json:"recommendedPGNum,omitempty"`
+}
+
 // PeerTokenCephxStatus represents the cephx key rotation status for peer tokens
 type PeerTokenCephxStatus struct {
 	// PeerToken shows the rotation status of the peer token associated with the `rbd-mirror-peer` user.
@@ -1483,14 +2444,22 @@ type ErasureCodedSpec struct {
 	// Number of coding chunks per object in an erasure coded storage pool (required for erasure-coded pool type).
 	// This is the number of OSDs that can be lost simultaneously before data cannot be recovered.
 	// +kubebuilder:validation:Minimum=0
+	// +optional
 	CodingChunks uint `json:"codingChunks"`
 
 	// Number of data chunks per object in an erasure coded storage pool (required for erasure-coded pool type).
 	// The number of chunks required to recover an object when any single OSD is lost is the same
 	// as dataChunks so be aware that the larger the number of data chunks, the higher the cost of recovery.
 	// +kubebuilder:validation:Minimum=0
+	// +optional
 	DataChunks uint `json:"dataChunks"`
 
+	// Profile is the name of a CephErasureCodeProfile in the namespace of the cluster to create the
+	// pool with, instead of a profile generated from the other erasure coded settings. The crush
+	// placement of the pool is then defined by the profile.
+	// +optional
+	Profile string `json:"profile,omitempty"`
+
 	// The algorithm for erasure coding.
 	// If absent, defaults to the plugin specified in osd_pool_default_erasure_code_profile.
 	// +kubebuilder:validation:Enum=isa;jerasure
@@ -3574,6 +4543,443 @@ type CephClientStatus struct {
 	Cephx CephxStatus `json:"cephx,omitempty"`
 }
 
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephMgrModule represents a Ceph mgr module enabled and configured by the operator
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Health",type=string,JSONPath=`.status.health`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:subresource:status
+type CephMgrModule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// Spec represents the specification of a Ceph mgr module
+	Spec MgrModuleSpec `json:"spec"`
+	// Status represents the status of a Ceph mgr module
+	// +optional
+	Status *CephMgrModuleStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephMgrModuleList represents a list of Ceph mgr modules
+type CephMgrModuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephMgrModule `json:"items"`
+}
+
+// MgrModuleSpec represents the specification of a Ceph mgr module
+type MgrModuleSpec struct {
+	// Name is the name of the mgr module, for example "pg_autoscaler". If not set, the name of the
+	// CR is used.
+	// +kubebuilder:validation:XValidation:message="name is immutable",rule="self == oldSelf"
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Settings are the options of the mgr module to set in the centralized Ceph configuration, for
+	// example "min_score" for the balancer module. Each setting is validated against the options
+	// reported by the module.
+	// +optional
+	Settings map[string]string `json:"settings,omitempty"`
+}
+
+// CephMgrModuleStatus represents the status of a Ceph mgr module
+type CephMgrModuleStatus struct {
+	// +optional
+	Phase ConditionType `json:"phase,omitempty"`
+	// ObservedGeneration is the latest generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Health is the health of the module reported by the mgr
+	// +optional
+	Health MgrModuleHealth `json:"health,omitempty"`
+	// Message explains the health or the phase of the module
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Settings are the names of the module options set by the operator
+	// +optional
+	Settings []string `json:"settings,omitempty"`
+}
+
+// MgrModuleHealth is the health of a mgr module
+type MgrModuleHealth string
+
+const (
+	// MgrModuleHealthy is set when the module is enabled and running
+	MgrModuleHealthy MgrModuleHealth = "Healthy"
+	// MgrModuleUnhealthy is set when the module cannot run or has failed
+	MgrModuleUnhealthy MgrModuleHealth = "Unhealthy"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephDashboardUser represents a user of the Ceph dashboard
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:subresource:status
+// +kubebuilder:validation:XValidation:message=`the admin user is managed by the operator`,rule=`(has(self.spec.username) && self.spec.username != "" ? self.spec.username : self.metadata.name) != "admin"`
+type CephDashboardUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// Spec represents the specification of a Ceph dashboard user
+	Spec DashboardUserSpec `json:"spec"`
+	// Status represents the status of a Ceph dashboard user
+	// +optional
+	Status *CephDashboardUserStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephDashboardUserList represents a list of Ceph dashboard users
+type CephDashboardUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephDashboardUser `json:"items"`
+}
+
+// DashboardUserSpec represents the specification of a Ceph dashboard user
+type DashboardUserSpec struct {
+	// Username is the name of the dashboard user. If not set, the name of the CR is used.
+	// +kubebuilder:validation:XValidation:message="username is immutable",rule="self == oldSelf"
+	// +optional
+	Username string `json:"username,omitempty"`
+	// PasswordSecret is the secret key that stores the password of the user
+	PasswordSecret v1.SecretKeySelector `json:"passwordSecret"`
+	// Roles are the dashboard roles of the user, either system roles like "read-only" or custom
+	// roles from the CephCluster dashboard settings
+	// +kubebuilder:validation:MinItems=1
+	Roles []string `json:"roles"`
+	// Enabled determines whether the user can log in. Default is true.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// PasswordExpirationDate is the time when the password of the user expires. After the password
+	// expires, the user must set a new password at the next login.
+	// +optional
+	// +nullable
+	PasswordExpirationDate *metav1.Time `json:"passwordExpirationDate,omitempty"`
+}
+
+// CephDashboardUserStatus represents the status of a Ceph dashboard user
+type CephDashboardUserStatus struct {
+	// +optional
+	Phase ConditionType `json:"phase,omitempty"`
+	// ObservedGeneration is the latest generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Message explains the phase of the user
+	// +optional
+	Message string `json:"message,omitempty"`
+	// PasswordSecretVersion is the resource version of the password secret applied to the user
+	// +optional
+	PasswordSecretVersion string `json:"passwordSecretVersion,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephOSDRemoval represents the removal of OSDs from a Ceph cluster
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Clean PGs",type=integer,JSONPath=`.status.cleanPGsPercent`,description="Percentage of the PGs that are active+clean"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:subresource:status
+type CephOSDRemoval struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// Spec represents the specification of the OSD removal
+	Spec OSDRemovalSpec `json:"spec"`
+	// Status represents the status of the OSD removal
+	// +optional
+	Status *CephOSDRemovalStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephOSDRemovalList represents a list of OSD removals
+type CephOSDRemovalList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephOSDRemoval `json:"items"`
+}
+
+// OSDRemovalSpec represents the specification of an OSD removal
+type OSDRemovalSpec struct {
+	// OSDIDs are the IDs of the OSDs to remove
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:XValidation:message="osdIDs are immutable",rule="self == oldSelf"
+	// +listType=set
+	OSDIDs []int `json:"osdIDs"`
+	// ForceRemoval purges the OSDs even if Ceph does not report them as safe to destroy, for
+	// example when the data of a failed disk cannot be recovered from the other replicas.
+	// +optional
+	ForceRemoval bool `json:"forceRemoval,omitempty"`
+	// PreservePVC keeps the PVCs of OSDs running on PVCs and only detaches them from Rook,
+	// instead of deleting them.
+	// +optional
+	PreservePVC bool `json:"preservePVC,omitempty"`
+	// WaitForRebalance waits for all the PGs in the cluster to be clean before an OSD is purged,
+	// in addition to the OSD being safe to destroy. Ignored when forceRemoval is set. Default is true.
+	// +optional
+	WaitForRebalance *bool `json:"waitForRebalance,omitempty"`
+}
+
+// CephOSDRemovalStatus represents the status of an OSD removal
+type CephOSDRemovalStatus struct {
+	// Phase is the phase of the removal: Draining, Completed or Failed
+	// +optional
+	Phase OSDRemovalPhase `json:"phase,omitempty"`
+	// ObservedGeneration is the latest generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Message explains the phase of the removal
+	// +optional
+	Message string `json:"message,omitempty"`
+	// StartTime is the time when the OSDs were first marked out
+	// +optional
+	// +nullable
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time when all the OSDs were removed or failed
+	// +optional
+	// +nullable
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// CleanPGsPercent is the percentage of the PGs in the cluster that are clean
+	// +optional
+	CleanPGsPercent int `json:"cleanPGsPercent,omitempty"`
+	// OSDs is the removal progress of each OSD
+	// +optional
+	OSDs []OSDRemovalOSDStatus `json:"osds,omitempty"`
+}
+
+// OSDRemovalOSDStatus represents the removal progress of a single OSD
+type OSDRemovalOSDStatus struct {
+	// ID is the ID of the OSD
+	ID int `json:"id"`
+	// Phase is the phase of the removal of the OSD: Draining, Completed or Failed
+	Phase OSDRemovalPhase `json:"phase"`
+	// InitialPGs is the number of PGs on the OSD when it was marked out
+	// +optional
+	InitialPGs int `json:"initialPGs,omitempty"`
+	// PGs is the number of PGs still on the OSD
+	// +optional
+	PGs int `json:"pgs,omitempty"`
+	// MigratedPercent is the percentage of the initial PGs that were moved off the OSD
+	// +optional
+	MigratedPercent int `json:"migratedPercent,omitempty"`
+	// Message explains the phase of the removal of the OSD
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// OSDRemovalPhase is the phase of an OSD removal
+type OSDRemovalPhase string
+
+const (
+	// OSDRemovalDraining is set while the data is moved off the OSDs and before they are purged
+	OSDRemovalDraining OSDRemovalPhase = "Draining"
+	// OSDRemovalCompleted is set when the OSDs were purged and their resources deleted
+	OSDRemovalCompleted OSDRemovalPhase = "Completed"
+	// OSDRemovalFailed is set when an OSD cannot be removed
+	OSDRemovalFailed OSDRemovalPhase = "Failed"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephCrushRule represents a CRUSH rule described by its steps that pools reference by name
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Rule ID",type=integer,JSONPath=`.status.ruleID`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:subresource:status
+type CephCrushRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// Spec represents the specification of the CRUSH rule
+	Spec CrushRuleSpec `json:"spec"`
+	// Status represents the status of the CRUSH rule
+	// +optional
+	Status *CephCrushRuleStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephCrushRuleList represents a list of CRUSH rules
+type CephCrushRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephCrushRule `json:"items"`
+}
+
+// CrushRuleSpec represents the specification of a CRUSH rule
+type CrushRuleSpec struct {
+	// Name is the name of the rule in the CRUSH map. If not set, the name of the CR is used.
+	// +kubebuilder:validation:XValidation:message="name is immutable",rule="self == oldSelf"
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Type is the type of the pools the rule can be used by: replicated or erasure. Default is replicated.
+	// +kubebuilder:validation:Enum=replicated;erasure
+	// +kubebuilder:default=replicated
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Steps are the steps of the rule in order. Each group of steps starts with a take step,
+	// selects buckets with choose and chooseleaf steps, and ends with an emit step.
+	// +kubebuilder:validation:MinItems=3
+	Steps []CrushRuleStep `json:"steps"`
+	// TestSize is the number of replicas or chunks the rule is tested with by crushtool before it
+	// is added to the CRUSH map. If not set, the number of OSDs selected by the steps is used when
+	// all the counts are positive, and 3 otherwise.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TestSize int `json:"testSize,omitempty"`
+}
+
+// CrushRuleStep represents a step of a CRUSH rule
+type CrushRuleStep struct {
+	// Op is the operation of the step: take, choose, chooseleaf or emit
+	// +kubebuilder:validation:Enum=take;choose;chooseleaf;emit
+	Op CrushRuleStepOp `json:"op"`
+	// Item is the name of the bucket a take step starts from, for example "default" or "dc1"
+	// +optional
+	Item string `json:"item,omitempty"`
+	// DeviceClass restricts a take step to the OSDs of the device class
+	// +optional
+	DeviceClass string `json:"deviceClass,omitempty"`
+	// Mode is the selection mode of a choose or chooseleaf step: firstn or indep. Default is firstn
+	// for replicated rules and indep for erasure rules.
+	// +kubebuilder:validation:Enum=firstn;indep
+	// +optional
+	Mode string `json:"mode,omitempty"`
+	// Count is the number of buckets a choose or chooseleaf step selects. 0 selects as many buckets
+	// as the size of the pool, and a negative count selects the size of the pool minus the count.
+	// +optional
+	Count int `json:"count,omitempty"`
+	// Type is the type of the buckets a choose or chooseleaf step selects, for example "host" or "rack"
+	// +optional
+	Type string `json:"type,omitempty"`
+}
+
+// CrushRuleStepOp is the operation of a CRUSH rule step
+type CrushRuleStepOp string
+
+const (
+	// CrushRuleStepTake starts a group of steps from a bucket
+	CrushRuleStepTake CrushRuleStepOp = "take"
+	// CrushRuleStepChoose selects buckets of a type
+	CrushRuleStepChoose CrushRuleStepOp = "choose"
+	// CrushRuleStepChooseLeaf selects buckets of a type and an OSD in each of them
+	CrushRuleStepChooseLeaf CrushRuleStepOp = "chooseleaf"
+	// CrushRuleStepEmit outputs the selected OSDs and ends a group of steps
+	CrushRuleStepEmit CrushRuleStepOp = "emit"
+)
+
+// CephCrushRuleStatus represents the status of a CRUSH rule
+type CephCrushRuleStatus struct {
+	// +optional
+	Phase ConditionType `json:"phase,omitempty"`
+	// ObservedGeneration is the latest generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Message explains the phase of the rule
+	// +optional
+	Message string `json:"message,omitempty"`
+	// RuleID is the ID of the rule in the CRUSH map
+	// +optional
+	// +nullable
+	RuleID *int `json:"ruleID,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephErasureCodeProfile represents a Ceph erasure code profile that erasure coded pools reference by name
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Plugin",type=string,JSONPath=`.spec.plugin`
+// +kubebuilder:printcolumn:name="K",type=integer,JSONPath=`.spec.dataChunks`
+// +kubebuilder:printcolumn:name="M",type=integer,JSONPath=`.spec.codingChunks`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:subresource:status
+// +kubebuilder:validation:XValidation:message="the name is reserved for the profiles created by ceph and rook",rule="self.metadata.name != 'default' && !self.metadata.name.endsWith('_ecprofile')"
+type CephErasureCodeProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// Spec represents the specification of the erasure code profile
+	Spec ErasureCodeProfileSpec `json:"spec"`
+	// Status represents the status of the erasure code profile
+	// +optional
+	Status *CephErasureCodeProfileStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephErasureCodeProfileList represents a list of Ceph erasure code profiles
+type CephErasureCodeProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephErasureCodeProfile `json:"items"`
+}
+
+// ErasureCodeProfileSpec represents the specification of a Ceph erasure code profile. The name of
+// the profile in Ceph is the name of the CR.
+// +kubebuilder:validation:XValidation:message="locality is only supported by the lrc plugin",rule="!has(self.locality) || (has(self.plugin) && self.plugin == 'lrc')"
+// +kubebuilder:validation:XValidation:message="the lrc plugin requires locality",rule="!has(self.plugin) || self.plugin != 'lrc' || has(self.locality)"
+type ErasureCodeProfileSpec struct {
+	// Plugin is the erasure code plugin: jerasure, isa, lrc, shec or clay. Default is jerasure.
+	// +kubebuilder:validation:Enum=jerasure;isa;lrc;shec;clay
+	// +kubebuilder:default=jerasure
+	// +optional
+	Plugin string `json:"plugin,omitempty"`
+	// Technique is the erasure code technique of the plugin, for example "reed_sol_van" or "cauchy"
+	// for jerasure and isa, or "single" or "multiple" for shec. The lrc and clay plugins do not
+	// support a technique. If not set, the default technique of the plugin is used.
+	// +optional
+	Technique string `json:"technique,omitempty"`
+	// DataChunks is the number of data chunks (k) each object is split into
+	// +kubebuilder:validation:Minimum=2
+	DataChunks uint `json:"dataChunks"`
+	// CodingChunks is the number of coding chunks (m) computed for each object. This is the number
+	// of OSDs that can be lost simultaneously before data cannot be recovered.
+	// +kubebuilder:validation:Minimum=1
+	CodingChunks uint `json:"codingChunks"`
+	// Locality (l) groups the data and coding chunks into sets of this size with an additional
+	// local parity chunk each, so that a lost chunk is recovered from its set only. Required by the
+	// lrc plugin and not supported by the other plugins. dataChunks + codingChunks must be a multiple
+	// of the locality.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Locality *uint `json:"locality,omitempty"`
+	// FailureDomain is the CRUSH bucket type the chunks are spread across (crush-failure-domain),
+	// for example "host" or "rack". Default is host.
+	// +optional
+	FailureDomain string `json:"failureDomain,omitempty"`
+	// DeviceClass restricts the placement of the chunks to the OSDs of this device class (crush-device-class)
+	// +optional
+	DeviceClass string `json:"deviceClass,omitempty"`
+	// StripeUnit is the size of the data of a stripe in a chunk. Ceph default is 4096 bytes (4 KiB).
+	// +kubebuilder:validation:Enum={"4Ki","16Ki","64Ki","256Ki","1Mi"}
+	// +optional
+	StripeUnit *resource.Quantity `json:"stripeUnit,omitempty"`
+}
+
+// CephErasureCodeProfileStatus represents the status of a Ceph erasure code profile
+type CephErasureCodeProfileStatus struct {
+	// +optional
+	Phase ConditionType `json:"phase,omitempty"`
+	// ObservedGeneration is the latest generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Message explains the phase of the profile, for example why a change of the spec was refused
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Pools are the names of the pools that use the profile
+	// +optional
+	Pools []string `json:"pools,omitempty"`
+}
+
 // CleanupPolicySpec represents a Ceph Cluster cleanup policy
 type CleanupPolicySpec struct {
 	// Confirmation represents the cleanup confirmation
@@ -3829,8 +5235,109 @@ type StorageScopeSpec struct {
 	// +kubebuilder:validation:Minimum=1
 	// +optional
 	OSDMaxUpdatesInParallel uint32 `json:"osdMaxUpdatesInParallel,omitempty"`
+	// UpgradeStrategy controls how far the OSDs are updated when the Ceph image changes
+	// +optional
+	UpgradeStrategy OSDUpgradeStrategy `json:"upgradeStrategy,omitempty"`
+	// Whether Rook will automatically replace the host-based OSDs that are down and out with a backing
+	// device missing from the devices reported by the discovery daemon. The OSD is destroyed while
+	// keeping its ID, and a new disk is provisioned with the same ID once it is found on the host.
+	// The default is false.
+	// +optional
+	AutoReplaceFailedOSDs bool `json:"autoReplaceFailedOSDs,omitempty"`
+	// DeviceHealth configures the monitoring of the SMART health of the OSD devices
+	// +optional
+	DeviceHealth DeviceHealthSpec `json:"deviceHealth,omitempty"`
+	// CrushHierarchy declares the CRUSH buckets above the hosts and the hosts in each bucket. The
+	// hosts are moved to their declared buckets regardless of the topology labels of their nodes.
+	// +optional
+	CrushHierarchy CrushHierarchySpec `json:"crushHierarchy,omitempty"`
+}
+
+// CrushHierarchySpec declares the buckets of the CRUSH map above the hosts
+type CrushHierarchySpec struct {
+	// Buckets are the buckets of the hierarchy. A bucket is added to the CRUSH map if it does not
+	// exist, and moved to its parent if it is in another bucket.
+	// +optional
+	Buckets []CrushBucketSpec `json:"buckets,omitempty"`
+}
+
+// CrushBucketSpec declares a bucket of the CRUSH map and the hosts in it
+type CrushBucketSpec struct {
+	// Name is the name of the bucket in the CRUSH map
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_.-]+$`
+	Name string `json:"name"`
+	// Type is the type of the bucket
+	// +kubebuilder:validation:Enum=chassis;rack;row;pdu;pod;room;datacenter;zone;region
+	Type string `json:"type"`
+	// Parent is the name of the bucket containing this bucket, either another bucket of the
+	// hierarchy of a higher type or a bucket of the CRUSH map such as a root. The default is the
+	// CRUSH root of the cluster.
+	// +optional
+	Parent string `json:"parent,omitempty"`
+	// Hosts are the names of the hosts in the bucket, as in the hostname label of their nodes
+	// +optional
+	Hosts []string `json:"hosts,omitempty"`
+}
+
+// DeviceHealthSpec configures the monitoring of the OSD devices health from the SMART data scraped
+// by the Ceph devicehealth mgr module
+type DeviceHealthSpec struct {
+	// Enabled turns on the device health checks. The health of each device is reported in the
+	// CephCluster status, and an event is raised when a device is failing.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Interval is the time between two device health checks. The default is 1 hour.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// FailurePolicy is the action taken on the OSDs of a failing device. With "Report", the default,
+	// the failure is only reported. With "MarkOut", the OSDs are marked out so that their data is
+	// moved away before the device fails, unless too few OSDs would be left in.
+	// +kubebuilder:validation:Enum=Report;MarkOut;""
+	// +optional
+	FailurePolicy DeviceFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// DeviceFailurePolicy is the action taken on the OSDs of a failing device
+type DeviceFailurePolicy string
+
+const (
+	// DeviceFailurePolicyReport only reports the failing devices
+	DeviceFailurePolicyReport DeviceFailurePolicy = "Report"
+	// DeviceFailurePolicyMarkOut marks out the OSDs of the failing devices
+	DeviceFailurePolicyMarkOut DeviceFailurePolicy = "MarkOut"
+)
+
+// OSDUpgradeStrategy controls how the OSDs are updated to a new Ceph image
+type OSDUpgradeStrategy struct {
+	// Type is the strategy of the OSD upgrades. With "All", the default, all the OSDs are updated as
+	// soon as they are ok to stop. With "Canary", the OSDs of a single failure domain are updated first,
+	// the cluster health is checked for the soak duration, and the upgrade pauses until it is approved
+	// with the "osd.rook.io/approve-upgrade" annotation on the CephCluster set to the Ceph image. The
+	// other failure domains are then updated one at a time, each followed by the soak checks.
+	// +kubebuilder:validation:Enum=All;Canary;""
+	// +optional
+	Type OSDUpgradeStrategyType `json:"type,omitempty"`
+	// FailureDomain is the CRUSH bucket type updated at a time with the Canary strategy, such as
+	// "host", "rack", or "zone". The default is "host".
+	// +optional
+	FailureDomain string `json:"failureDomain,omitempty"`
+	// SoakDuration is how long the cluster must stay healthy after a failure domain is updated before
+	// the upgrade continues. The default is 10 minutes.
+	// +optional
+	SoakDuration *metav1.Duration `json:"soakDuration,omitempty"`
 }
 
+// OSDUpgradeStrategyType is the strategy of the OSD upgrades
+type OSDUpgradeStrategyType string
+
+const (
+	// OSDUpgradeStrategyAll updates all the OSDs as soon as they are ok to stop
+	OSDUpgradeStrategyAll OSDUpgradeStrategyType = "All"
+	// OSDUpgradeStrategyCanary updates a canary failure domain first, then the other failure domains
+	// one at a time after the upgrade is approved
+	OSDUpgradeStrategyCanary OSDUpgradeStrategyType = "Canary"
+)
+
 // Migration handles the OSD migration
 type Migration struct {
 	// A user confirmation to migrate the OSDs. It destroys each OSD one at a time, cleans up the backing disk
@@ -3838,6 +5345,11 @@ type Migration struct {
 	// +optional
 	// +kubebuilder:validation:Pattern=`^$|^yes-really-migrate-osds$`
 	Confirmation string `json:"confirmation,omitempty"`
+	// LVMToRaw migrates the OSDs created by ceph-volume in lvm mode on host devices to raw mode. Only
+	// the OSDs supported by the raw mode are migrated: the OSDs that are not encrypted, without a
+	// metadata device and alone on their device. Requires the confirmation.
+	// +optional
+	LVMToRaw bool `json:"lvmToRaw,omitempty"`
 }
 
 // OSDStore is the backend storage type used for creating the OSDs
@@ -557,6 +557,16 @@ func (in *CephBlockPoolStatus) DeepCopyInto(out *CephBlockPoolStatus) {
 		*out = new(MirroringInfoSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = new(PoolCapacityStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(PoolMigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SnapshotScheduleStatus != nil {
 		in, out := &in.SnapshotScheduleStatus, &out.SnapshotScheduleStatus
 		*out = new(SnapshotScheduleStatusSpec)
@@ -1001,6 +1011,154 @@ func (in *CephClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephConfigDriftSpec) DeepCopyInto(out *CephConfigDriftSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephConfigDriftSpec.
+func (in *CephConfigDriftSpec) DeepCopy() *CephConfigDriftSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CephConfigDriftSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephConfigDriftStatus) DeepCopyInto(out *CephConfigDriftStatus) {
+	*out = *in
+	if in.LastChecked != nil {
+		in, out := &in.LastChecked, &out.LastChecked
+		*out = (*in).DeepCopy()
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]CephConfigDriftedOption, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephConfigDriftStatus.
+func (in *CephConfigDriftStatus) DeepCopy() *CephConfigDriftStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephConfigDriftStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephConfigDriftedOption) DeepCopyInto(out *CephConfigDriftedOption) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephConfigDriftedOption.
+func (in *CephConfigDriftedOption) DeepCopy() *CephConfigDriftedOption {
+	if in == nil {
+		return nil
+	}
+	out := new(CephConfigDriftedOption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephCrushRule) DeepCopyInto(out *CephCrushRule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(CephCrushRuleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephCrushRule.
+func (in *CephCrushRule) DeepCopy() *CephCrushRule {
+	if in == nil {
+		return nil
+	}
+	out := new(CephCrushRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephCrushRule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephCrushRuleList) DeepCopyInto(out *CephCrushRuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephCrushRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephCrushRuleList.
+func (in *CephCrushRuleList) DeepCopy() *CephCrushRuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephCrushRuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephCrushRuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephCrushRuleStatus) DeepCopyInto(out *CephCrushRuleStatus) {
+	*out = *in
+	if in.RuleID != nil {
+		in, out := &in.RuleID, &out.RuleID
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephCrushRuleStatus.
+func (in *CephCrushRuleStatus) DeepCopy() *CephCrushRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephCrushRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CephDaemonsVersions) DeepCopyInto(out *CephDaemonsVersions) {
 	*out = *in
@@ -1073,6 +1231,173 @@ func (in *CephDaemonsVersions) DeepCopy() *CephDaemonsVersions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephDashboardUser) DeepCopyInto(out *CephDashboardUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(CephDashboardUserStatus)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephDashboardUser.
+func (in *CephDashboardUser) DeepCopy() *CephDashboardUser {
+	if in == nil {
+		return nil
+	}
+	out := new(CephDashboardUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephDashboardUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephDashboardUserList) DeepCopyInto(out *CephDashboardUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephDashboardUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephDashboardUserList.
+func (in *CephDashboardUserList) DeepCopy() *CephDashboardUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephDashboardUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephDashboardUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephDashboardUserStatus) DeepCopyInto(out *CephDashboardUserStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephDashboardUserStatus.
+func (in *CephDashboardUserStatus) DeepCopy() *CephDashboardUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephDashboardUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephErasureCodeProfile) DeepCopyInto(out *CephErasureCodeProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(CephErasureCodeProfileStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephErasureCodeProfile.
+func (in *CephErasureCodeProfile) DeepCopy() *CephErasureCodeProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(CephErasureCodeProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephErasureCodeProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephErasureCodeProfileList) DeepCopyInto(out *CephErasureCodeProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephErasureCodeProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephErasureCodeProfileList.
+func (in *CephErasureCodeProfileList) DeepCopy() *CephErasureCodeProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephErasureCodeProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephErasureCodeProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephErasureCodeProfileStatus) DeepCopyInto(out *CephErasureCodeProfileStatus) {
+	*out = *in
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephErasureCodeProfileStatus.
+func (in *CephErasureCodeProfileStatus) DeepCopy() *CephErasureCodeProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephErasureCodeProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CephExporterSpec) DeepCopyInto(out *CephExporterSpec) {
 	*out = *in
@@ -1422,6 +1747,92 @@ func (in *CephHealthMessage) DeepCopy() *CephHealthMessage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephMgrModule) DeepCopyInto(out *CephMgrModule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(CephMgrModuleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephMgrModule.
+func (in *CephMgrModule) DeepCopy() *CephMgrModule {
+	if in == nil {
+		return nil
+	}
+	out := new(CephMgrModule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephMgrModule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephMgrModuleList) DeepCopyInto(out *CephMgrModuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephMgrModule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephMgrModuleList.
+func (in *CephMgrModuleList) DeepCopy() *CephMgrModuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephMgrModuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephMgrModuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephMgrModuleStatus) DeepCopyInto(out *CephMgrModuleStatus) {
+	*out = *in
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephMgrModuleStatus.
+func (in *CephMgrModuleStatus) DeepCopy() *CephMgrModuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephMgrModuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CephNFS) DeepCopyInto(out *CephNFS) {
 	*out = *in
@@ -1549,7 +1960,101 @@ func (in *CephNVMeOFGatewayList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
-	return nil
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephOSDRemoval) DeepCopyInto(out *CephOSDRemoval) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(CephOSDRemovalStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephOSDRemoval.
+func (in *CephOSDRemoval) DeepCopy() *CephOSDRemoval {
+	if in == nil {
+		return nil
+	}
+	out := new(CephOSDRemoval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephOSDRemoval) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephOSDRemovalList) DeepCopyInto(out *CephOSDRemovalList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephOSDRemoval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephOSDRemovalList.
+func (in *CephOSDRemovalList) DeepCopy() *CephOSDRemovalList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephOSDRemovalList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephOSDRemovalList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephOSDRemovalStatus) DeepCopyInto(out *CephOSDRemovalStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.OSDs != nil {
+		in, out := &in.OSDs, &out.OSDs
+		*out = make([]OSDRemovalOSDStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephOSDRemovalStatus.
+func (in *CephOSDRemovalStatus) DeepCopy() *CephOSDRemovalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephOSDRemovalStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -2060,6 +2565,16 @@ func (in *CephStorage) DeepCopyInto(out *CephStorage) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.DeviceHealth != nil {
+		in, out := &in.DeviceHealth, &out.DeviceHealth
+		*out = new(DeviceHealthStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CrushHierarchy != nil {
+		in, out := &in.CrushHierarchy, &out.CrushHierarchy
+		*out = new(CrushHierarchyStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2344,7 +2859,7 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	out.DisruptionManagement = in.DisruptionManagement
 	in.Mon.DeepCopyInto(&out.Mon)
 	out.CrashCollector = in.CrashCollector
-	out.Dashboard = in.Dashboard
+	in.Dashboard.DeepCopyInto(&out.Dashboard)
 	in.Monitoring.DeepCopyInto(&out.Monitoring)
 	out.External = in.External
 	in.Mgr.DeepCopyInto(&out.Mgr)
@@ -2387,6 +2902,8 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 			(*out)[key] = outVal
 		}
 	}
+	in.CephConfigDrift.DeepCopyInto(&out.CephConfigDrift)
+	in.RecoverySchedule.DeepCopyInto(&out.RecoverySchedule)
 	return
 }
 
@@ -2426,6 +2943,45 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = new(ClusterVersion)
 		**out = **in
 	}
+	if in.MonBackup != nil {
+		in, out := &in.MonBackup, &out.MonBackup
+		*out = new(MonBackupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MonHealth != nil {
+		in, out := &in.MonHealth, &out.MonHealth
+		*out = make([]MonHealthStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Dashboard != nil {
+		in, out := &in.Dashboard, &out.Dashboard
+		*out = new(DashboardStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CephConfigDrift != nil {
+		in, out := &in.CephConfigDrift, &out.CephConfigDrift
+		*out = new(CephConfigDriftStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradePreflight != nil {
+		in, out := &in.UpgradePreflight, &out.UpgradePreflight
+		*out = new(UpgradePreflightStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradeHistory != nil {
+		in, out := &in.UpgradeHistory, &out.UpgradeHistory
+		*out = make([]UpgradeHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Recovery != nil {
+		in, out := &in.Recovery, &out.Recovery
+		*out = new(RecoveryStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2577,6 +3133,108 @@ func (in *CrashCollectorSpec) DeepCopy() *CrashCollectorSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrushBucketSpec) DeepCopyInto(out *CrushBucketSpec) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrushBucketSpec.
+func (in *CrushBucketSpec) DeepCopy() *CrushBucketSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CrushBucketSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrushHierarchySpec) DeepCopyInto(out *CrushHierarchySpec) {
+	*out = *in
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]CrushBucketSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrushHierarchySpec.
+func (in *CrushHierarchySpec) DeepCopy() *CrushHierarchySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CrushHierarchySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrushHierarchyStatus) DeepCopyInto(out *CrushHierarchyStatus) {
+	*out = *in
+	if in.Differences != nil {
+		in, out := &in.Differences, &out.Differences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrushHierarchyStatus.
+func (in *CrushHierarchyStatus) DeepCopy() *CrushHierarchyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CrushHierarchyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrushRuleSpec) DeepCopyInto(out *CrushRuleSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]CrushRuleStep, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrushRuleSpec.
+func (in *CrushRuleSpec) DeepCopy() *CrushRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CrushRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrushRuleStep) DeepCopyInto(out *CrushRuleStep) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrushRuleStep.
+func (in *CrushRuleStep) DeepCopy() *CrushRuleStep {
+	if in == nil {
+		return nil
+	}
+	out := new(CrushRuleStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DaemonHealthSpec) DeepCopyInto(out *DaemonHealthSpec) {
 	*out = *in
@@ -2596,9 +3254,118 @@ func (in *DaemonHealthSpec) DeepCopy() *DaemonHealthSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardRoleSpec) DeepCopyInto(out *DashboardRoleSpec) {
+	*out = *in
+	if in.ScopePermissions != nil {
+		in, out := &in.ScopePermissions, &out.ScopePermissions
+		*out = make(map[string][]DashboardPermission, len(*in))
+		for key, val := range *in {
+			var outVal []DashboardPermission
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]DashboardPermission, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardRoleSpec.
+func (in *DashboardRoleSpec) DeepCopy() *DashboardRoleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardRoleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardSAML2Spec) DeepCopyInto(out *DashboardSAML2Spec) {
+	*out = *in
+	if in.IdPMetadataSecret != nil {
+		in, out := &in.IdPMetadataSecret, &out.IdPMetadataSecret
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardSAML2Spec.
+func (in *DashboardSAML2Spec) DeepCopy() *DashboardSAML2Spec {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardSAML2Spec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardSSOSpec) DeepCopyInto(out *DashboardSSOSpec) {
+	*out = *in
+	in.SAML2.DeepCopyInto(&out.SAML2)
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]DashboardSSOUserSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardSSOSpec.
+func (in *DashboardSSOSpec) DeepCopy() *DashboardSSOSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardSSOSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardSSOUserSpec) DeepCopyInto(out *DashboardSSOUserSpec) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardSSOUserSpec.
+func (in *DashboardSSOUserSpec) DeepCopy() *DashboardSSOUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardSSOUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DashboardSpec) DeepCopyInto(out *DashboardSpec) {
 	*out = *in
+	if in.SSO != nil {
+		in, out := &in.SSO, &out.SSO
+		*out = new(DashboardSSOSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]DashboardRoleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -2612,6 +3379,67 @@ func (in *DashboardSpec) DeepCopy() *DashboardSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardStatus) DeepCopyInto(out *DashboardStatus) {
+	*out = *in
+	if in.CertificateExpiry != nil {
+		in, out := &in.CertificateExpiry, &out.CertificateExpiry
+		*out = (*in).DeepCopy()
+	}
+	if in.SSOUsers != nil {
+		in, out := &in.SSOUsers, &out.SSOUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardStatus.
+func (in *DashboardStatus) DeepCopy() *DashboardStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardUserSpec) DeepCopyInto(out *DashboardUserSpec) {
+	*out = *in
+	in.PasswordSecret.DeepCopyInto(&out.PasswordSecret)
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PasswordExpirationDate != nil {
+		in, out := &in.PasswordExpirationDate, &out.PasswordExpirationDate
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardUserSpec.
+func (in *DashboardUserSpec) DeepCopy() *DashboardUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Device) DeepCopyInto(out *Device) {
 	*out = *in
@@ -2625,28 +3453,97 @@ func (in *Device) DeepCopyInto(out *Device) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Device.
-func (in *Device) DeepCopy() *Device {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Device.
+func (in *Device) DeepCopy() *Device {
+	if in == nil {
+		return nil
+	}
+	out := new(Device)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClasses) DeepCopyInto(out *DeviceClasses) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClasses.
+func (in *DeviceClasses) DeepCopy() *DeviceClasses {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClasses)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceHealth) DeepCopyInto(out *DeviceHealth) {
+	*out = *in
+	if in.OSDs != nil {
+		in, out := &in.OSDs, &out.OSDs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceHealth.
+func (in *DeviceHealth) DeepCopy() *DeviceHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceHealthSpec) DeepCopyInto(out *DeviceHealthSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceHealthSpec.
+func (in *DeviceHealthSpec) DeepCopy() *DeviceHealthSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Device)
+	out := new(DeviceHealthSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DeviceClasses) DeepCopyInto(out *DeviceClasses) {
+func (in *DeviceHealthStatus) DeepCopyInto(out *DeviceHealthStatus) {
 	*out = *in
+	if in.LastChecked != nil {
+		in, out := &in.LastChecked, &out.LastChecked
+		*out = (*in).DeepCopy()
+	}
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]DeviceHealth, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClasses.
-func (in *DeviceClasses) DeepCopy() *DeviceClasses {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceHealthStatus.
+func (in *DeviceHealthStatus) DeepCopy() *DeviceHealthStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DeviceClasses)
+	out := new(DeviceHealthStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2699,6 +3596,32 @@ func (in *EndpointAddress) DeepCopy() *EndpointAddress {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErasureCodeProfileSpec) DeepCopyInto(out *ErasureCodeProfileSpec) {
+	*out = *in
+	if in.Locality != nil {
+		in, out := &in.Locality, &out.Locality
+		*out = new(uint)
+		**out = **in
+	}
+	if in.StripeUnit != nil {
+		in, out := &in.StripeUnit, &out.StripeUnit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErasureCodeProfileSpec.
+func (in *ErasureCodeProfileSpec) DeepCopy() *ErasureCodeProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ErasureCodeProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ErasureCodedSpec) DeepCopyInto(out *ErasureCodedSpec) {
 	*out = *in
@@ -3514,6 +4437,29 @@ func (in *MetadataServerSpec) DeepCopy() *MetadataServerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MgrModuleSpec) DeepCopyInto(out *MgrModuleSpec) {
+	*out = *in
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MgrModuleSpec.
+func (in *MgrModuleSpec) DeepCopy() *MgrModuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MgrModuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MgrSpec) DeepCopyInto(out *MgrSpec) {
 	*out = *in
@@ -3559,6 +4505,13 @@ func (in *Migration) DeepCopy() *Migration {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MigrationStatus) DeepCopyInto(out *MigrationStatus) {
 	*out = *in
+	if in.PendingByReason != nil {
+		in, out := &in.PendingByReason, &out.PendingByReason
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -3576,6 +4529,7 @@ func (in *MigrationStatus) DeepCopy() *MigrationStatus {
 func (in *MirrorHealthCheckSpec) DeepCopyInto(out *MirrorHealthCheckSpec) {
 	*out = *in
 	in.Mirror.DeepCopyInto(&out.Mirror)
+	in.Capacity.DeepCopyInto(&out.Capacity)
 	return
 }
 
@@ -3772,6 +4726,129 @@ func (in *ModuleSettings) DeepCopy() *ModuleSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonBackupS3Spec) DeepCopyInto(out *MonBackupS3Spec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonBackupS3Spec.
+func (in *MonBackupS3Spec) DeepCopy() *MonBackupS3Spec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonBackupS3Spec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonBackupSpec) DeepCopyInto(out *MonBackupSpec) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(MonBackupS3Spec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonBackupSpec.
+func (in *MonBackupSpec) DeepCopy() *MonBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonBackupStatus) DeepCopyInto(out *MonBackupStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonBackupStatus.
+func (in *MonBackupStatus) DeepCopy() *MonBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MonBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonHealthScoringSpec) DeepCopyInto(out *MonHealthScoringSpec) {
+	*out = *in
+	if in.FailoverDelay != nil {
+		in, out := &in.FailoverDelay, &out.FailoverDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonHealthScoringSpec.
+func (in *MonHealthScoringSpec) DeepCopy() *MonHealthScoringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonHealthScoringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonHealthStatus) DeepCopyInto(out *MonHealthStatus) {
+	*out = *in
+	if in.Signals != nil {
+		in, out := &in.Signals, &out.Signals
+		*out = make([]MonHealthSignal, len(*in))
+		copy(*out, *in)
+	}
+	if in.DegradedSince != nil {
+		in, out := &in.DegradedSince, &out.DegradedSince
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonHealthStatus.
+func (in *MonHealthStatus) DeepCopy() *MonHealthStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MonHealthStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonRelocation) DeepCopyInto(out *MonRelocation) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonRelocation.
+func (in *MonRelocation) DeepCopy() *MonRelocation {
+	if in == nil {
+		return nil
+	}
+	out := new(MonRelocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MonSpec) DeepCopyInto(out *MonSpec) {
 	*out = *in
@@ -3798,6 +4875,13 @@ func (in *MonSpec) DeepCopyInto(out *MonSpec) {
 		copy(*out, *in)
 	}
 	out.FloatingMon = in.FloatingMon
+	in.Backup.DeepCopyInto(&out.Backup)
+	in.HealthScoring.DeepCopyInto(&out.HealthScoring)
+	if in.Relocations != nil {
+		in, out := &in.Relocations, &out.Relocations
+		*out = make([]MonRelocation, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -4066,6 +5150,11 @@ func (in *NVMeOFGatewayStatus) DeepCopy() *NVMeOFGatewayStatus {
 func (in *NamedBlockPoolSpec) DeepCopyInto(out *NamedBlockPoolSpec) {
 	*out = *in
 	in.PoolSpec.DeepCopyInto(&out.PoolSpec)
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(PoolMigrationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -4214,68 +5303,233 @@ func (in *NotificationFilterSpec) DeepCopyInto(out *NotificationFilterSpec) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationFilterSpec.
-func (in *NotificationFilterSpec) DeepCopy() *NotificationFilterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationFilterSpec.
+func (in *NotificationFilterSpec) DeepCopy() *NotificationFilterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationFilterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationKeyFilterRule) DeepCopyInto(out *NotificationKeyFilterRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationKeyFilterRule.
+func (in *NotificationKeyFilterRule) DeepCopy() *NotificationKeyFilterRule {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationKeyFilterRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSDKeyRotationStatus) DeepCopyInto(out *OSDKeyRotationStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDKeyRotationStatus.
+func (in *OSDKeyRotationStatus) DeepCopy() *OSDKeyRotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OSDKeyRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSDRemovalOSDStatus) DeepCopyInto(out *OSDRemovalOSDStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDRemovalOSDStatus.
+func (in *OSDRemovalOSDStatus) DeepCopy() *OSDRemovalOSDStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OSDRemovalOSDStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSDRemovalSpec) DeepCopyInto(out *OSDRemovalSpec) {
+	*out = *in
+	if in.OSDIDs != nil {
+		in, out := &in.OSDIDs, &out.OSDIDs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.WaitForRebalance != nil {
+		in, out := &in.WaitForRebalance, &out.WaitForRebalance
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDRemovalSpec.
+func (in *OSDRemovalSpec) DeepCopy() *OSDRemovalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OSDRemovalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSDReplacementStatus) DeepCopyInto(out *OSDReplacementStatus) {
+	*out = *in
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDReplacementStatus.
+func (in *OSDReplacementStatus) DeepCopy() *OSDReplacementStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OSDReplacementStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSDStatus) DeepCopyInto(out *OSDStatus) {
+	*out = *in
+	if in.StoreType != nil {
+		in, out := &in.StoreType, &out.StoreType
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.MigrationStatus.DeepCopyInto(&out.MigrationStatus)
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(OSDUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replacements != nil {
+		in, out := &in.Replacements, &out.Replacements
+		*out = make([]OSDReplacementStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.KeyRotations != nil {
+		in, out := &in.KeyRotations, &out.KeyRotations
+		*out = make([]OSDKeyRotationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDStatus.
+func (in *OSDStatus) DeepCopy() *OSDStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NotificationFilterSpec)
+	out := new(OSDStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NotificationKeyFilterRule) DeepCopyInto(out *NotificationKeyFilterRule) {
+func (in *OSDStore) DeepCopyInto(out *OSDStore) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationKeyFilterRule.
-func (in *NotificationKeyFilterRule) DeepCopy() *NotificationKeyFilterRule {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDStore.
+func (in *OSDStore) DeepCopy() *OSDStore {
 	if in == nil {
 		return nil
 	}
-	out := new(NotificationKeyFilterRule)
+	out := new(OSDStore)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OSDStatus) DeepCopyInto(out *OSDStatus) {
+func (in *OSDUpgradeStatus) DeepCopyInto(out *OSDUpgradeStatus) {
 	*out = *in
-	if in.StoreType != nil {
-		in, out := &in.StoreType, &out.StoreType
-		*out = make(map[string]int, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.UpdatedFailureDomains != nil {
+		in, out := &in.UpdatedFailureDomains, &out.UpdatedFailureDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingFailureDomains != nil {
+		in, out := &in.PendingFailureDomains, &out.PendingFailureDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SoakStartTime != nil {
+		in, out := &in.SoakStartTime, &out.SoakStartTime
+		*out = (*in).DeepCopy()
 	}
-	out.MigrationStatus = in.MigrationStatus
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDStatus.
-func (in *OSDStatus) DeepCopy() *OSDStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDUpgradeStatus.
+func (in *OSDUpgradeStatus) DeepCopy() *OSDUpgradeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(OSDStatus)
+	out := new(OSDUpgradeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OSDStore) DeepCopyInto(out *OSDStore) {
+func (in *OSDUpgradeStrategy) DeepCopyInto(out *OSDUpgradeStrategy) {
 	*out = *in
+	if in.SoakDuration != nil {
+		in, out := &in.SoakDuration, &out.SoakDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDStore.
-func (in *OSDStore) DeepCopy() *OSDStore {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDUpgradeStrategy.
+func (in *OSDUpgradeStrategy) DeepCopy() *OSDUpgradeStrategy {
 	if in == nil {
 		return nil
 	}
-	out := new(OSDStore)
+	out := new(OSDUpgradeStrategy)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -4930,6 +6184,107 @@ func (in *PlacementStorageClassSpec) DeepCopy() *PlacementStorageClassSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolCapacityStatus) DeepCopyInto(out *PoolCapacityStatus) {
+	*out = *in
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(PoolQuotaStatus)
+		**out = **in
+	}
+	if in.CompressionRatio != nil {
+		in, out := &in.CompressionRatio, &out.CompressionRatio
+		*out = new(float64)
+		**out = **in
+	}
+	out.PlacementGroups = in.PlacementGroups
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolCapacityStatus.
+func (in *PoolCapacityStatus) DeepCopy() *PoolCapacityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolCapacityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolMigrationImageStatus) DeepCopyInto(out *PoolMigrationImageStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolMigrationImageStatus.
+func (in *PoolMigrationImageStatus) DeepCopy() *PoolMigrationImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolMigrationImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolMigrationSpec) DeepCopyInto(out *PoolMigrationSpec) {
+	*out = *in
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(PoolSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolMigrationSpec.
+func (in *PoolMigrationSpec) DeepCopy() *PoolMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolMigrationStatus) DeepCopyInto(out *PoolMigrationStatus) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]PoolMigrationImageStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolMigrationStatus.
+func (in *PoolMigrationStatus) DeepCopy() *PoolMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolPlacementGroupsStatus) DeepCopyInto(out *PoolPlacementGroupsStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolPlacementGroupsStatus.
+func (in *PoolPlacementGroupsStatus) DeepCopy() *PoolPlacementGroupsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolPlacementGroupsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolPlacementSpec) DeepCopyInto(out *PoolPlacementSpec) {
 	*out = *in
@@ -4951,6 +6306,22 @@ func (in *PoolPlacementSpec) DeepCopy() *PoolPlacementSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolQuotaStatus) DeepCopyInto(out *PoolQuotaStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolQuotaStatus.
+func (in *PoolQuotaStatus) DeepCopy() *PoolQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolSpec) DeepCopyInto(out *PoolSpec) {
 	*out = *in
@@ -5233,6 +6604,107 @@ func (in *ReadAffinitySpec) DeepCopy() *ReadAffinitySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoveryProfile) DeepCopyInto(out *RecoveryProfile) {
+	*out = *in
+	if in.MaxBackfills != nil {
+		in, out := &in.MaxBackfills, &out.MaxBackfills
+		*out = new(int)
+		**out = **in
+	}
+	if in.RecoveryMaxActive != nil {
+		in, out := &in.RecoveryMaxActive, &out.RecoveryMaxActive
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryProfile.
+func (in *RecoveryProfile) DeepCopy() *RecoveryProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoveryProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoveryScheduleSpec) DeepCopyInto(out *RecoveryScheduleSpec) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]RecoveryWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(RecoveryProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryScheduleSpec.
+func (in *RecoveryScheduleSpec) DeepCopy() *RecoveryScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoveryScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoveryStatus) DeepCopyInto(out *RecoveryStatus) {
+	*out = *in
+	if in.Profile != nil {
+		in, out := &in.Profile, &out.Profile
+		*out = new(RecoveryProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryStatus.
+func (in *RecoveryStatus) DeepCopy() *RecoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoveryWindow) DeepCopyInto(out *RecoveryWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]RecoveryWindowDay, len(*in))
+		copy(*out, *in)
+	}
+	in.Profile.DeepCopyInto(&out.Profile)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryWindow.
+func (in *RecoveryWindow) DeepCopy() *RecoveryWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoveryWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReplicatedSpec) DeepCopyInto(out *ReplicatedSpec) {
 	*out = *in
@@ -5737,6 +7209,9 @@ func (in *StorageScopeSpec) DeepCopyInto(out *StorageScopeSpec) {
 		*out = new(float64)
 		**out = **in
 	}
+	in.UpgradeStrategy.DeepCopyInto(&out.UpgradeStrategy)
+	in.DeviceHealth.DeepCopyInto(&out.DeviceHealth)
+	in.CrushHierarchy.DeepCopyInto(&out.CrushHierarchy)
 	return
 }
 
@@ -5835,6 +7310,112 @@ func (in *TopicEndpointSpec) DeepCopy() *TopicEndpointSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeDaemonTiming) DeepCopyInto(out *UpgradeDaemonTiming) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeDaemonTiming.
+func (in *UpgradeDaemonTiming) DeepCopy() *UpgradeDaemonTiming {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeDaemonTiming)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeHistoryEntry) DeepCopyInto(out *UpgradeHistoryEntry) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Daemons != nil {
+		in, out := &in.Daemons, &out.Daemons
+		*out = make([]UpgradeDaemonTiming, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HealthWarnings != nil {
+		in, out := &in.HealthWarnings, &out.HealthWarnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeHistoryEntry.
+func (in *UpgradeHistoryEntry) DeepCopy() *UpgradeHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePreflightCheck) DeepCopyInto(out *UpgradePreflightCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePreflightCheck.
+func (in *UpgradePreflightCheck) DeepCopy() *UpgradePreflightCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePreflightCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePreflightStatus) DeepCopyInto(out *UpgradePreflightStatus) {
+	*out = *in
+	if in.LastChecked != nil {
+		in, out := &in.LastChecked, &out.LastChecked
+		*out = (*in).DeepCopy()
+	}
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]UpgradePreflightCheck, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePreflightStatus.
+func (in *UpgradePreflightStatus) DeepCopy() *UpgradePreflightStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePreflightStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeClaimTemplate) DeepCopyInto(out *VolumeClaimTemplate) {
 	*out = *in
@@ -35,11 +35,16 @@ type CephV1Interface interface {
 	CephCOSIDriversGetter
 	CephClientsGetter
 	CephClustersGetter
+	CephCrushRulesGetter
+	CephDashboardUsersGetter
+	CephErasureCodeProfilesGetter
 	CephFilesystemsGetter
 	CephFilesystemMirrorsGetter
 	CephFilesystemSubVolumeGroupsGetter
+	CephMgrModulesGetter
 	CephNFSesGetter
 	CephNVMeOFGatewaysGetter
+	CephOSDRemovalsGetter
 	CephObjectRealmsGetter
 	CephObjectStoresGetter
 	CephObjectStoreAccountsGetter
@@ -82,6 +87,18 @@ func (c *CephV1Client) CephClusters(namespace string) CephClusterInterface {
 	return newCephClusters(c, namespace)
 }
 
+func (c *CephV1Client) CephCrushRules(namespace string) CephCrushRuleInterface {
+	return newCephCrushRules(c, namespace)
+}
+
+func (c *CephV1Client) CephDashboardUsers(namespace string) CephDashboardUserInterface {
+	return newCephDashboardUsers(c, namespace)
+}
+
+func (c *CephV1Client) CephErasureCodeProfiles(namespace string) CephErasureCodeProfileInterface {
+	return newCephErasureCodeProfiles(c, namespace)
+}
+
 func (c *CephV1Client) CephFilesystems(namespace string) CephFilesystemInterface {
 	return newCephFilesystems(c, namespace)
 }
@@ -94,6 +111,10 @@ func (c *CephV1Client) CephFilesystemSubVolumeGroups(namespace string) CephFiles
 	return newCephFilesystemSubVolumeGroups(c, namespace)
 }
 
+func (c *CephV1Client) CephMgrModules(namespace string) CephMgrModuleInterface {
+	return newCephMgrModules(c, namespace)
+}
+
 func (c *CephV1Client) CephNFSes(namespace string) CephNFSInterface {
 	return newCephNFSes(c, namespace)
 }
@@ -102,6 +123,10 @@ func (c *CephV1Client) CephNVMeOFGateways(namespace string) CephNVMeOFGatewayInt
 	return newCephNVMeOFGateways(c, namespace)
 }
 
+func (c *CephV1Client) CephOSDRemovals(namespace string) CephOSDRemovalInterface {
+	return newCephOSDRemovals(c, namespace)
+}
+
 func (c *CephV1Client) CephObjectRealms(namespace string) CephObjectRealmInterface {
 	return newCephObjectRealms(c, namespace)
 }
@@ -56,6 +56,18 @@ func (c *FakeCephV1) CephClusters(namespace string) v1.CephClusterInterface {
 	return newFakeCephClusters(c, namespace)
 }
 
+func (c *FakeCephV1) CephCrushRules(namespace string) v1.CephCrushRuleInterface {
+	return newFakeCephCrushRules(c, namespace)
+}
+
+func (c *FakeCephV1) CephDashboardUsers(namespace string) v1.CephDashboardUserInterface {
+	return newFakeCephDashboardUsers(c, namespace)
+}
+
+func (c *FakeCephV1) CephErasureCodeProfiles(namespace string) v1.CephErasureCodeProfileInterface {
+	return newFakeCephErasureCodeProfiles(c, namespace)
+}
+
 func (c *FakeCephV1) CephFilesystems(namespace string) v1.CephFilesystemInterface {
 	return newFakeCephFilesystems(c, namespace)
 }
@@ -68,6 +80,10 @@ func (c *FakeCephV1) CephFilesystemSubVolumeGroups(namespace string) v1.CephFile
 	return newFakeCephFilesystemSubVolumeGroups(c, namespace)
 }
 
+func (c *FakeCephV1) CephMgrModules(namespace string) v1.CephMgrModuleInterface {
+	return newFakeCephMgrModules(c, namespace)
+}
+
 func (c *FakeCephV1) CephNFSes(namespace string) v1.CephNFSInterface {
 	return newFakeCephNFSes(c, namespace)
 }
@@ -76,6 +92,10 @@ func (c *FakeCephV1) CephNVMeOFGateways(namespace string) v1.CephNVMeOFGatewayIn
 	return newFakeCephNVMeOFGateways(c, namespace)
 }
 
+func (c *FakeCephV1) CephOSDRemovals(namespace string) v1.CephOSDRemovalInterface {
+	return newFakeCephOSDRemovals(c, namespace)
+}
+
 func (c *FakeCephV1) CephObjectRealms(namespace string) v1.CephObjectRealmInterface {
 	return newFakeCephObjectRealms(c, namespace)
 }
@@ -32,16 +32,26 @@ type CephClientExpansion interface{}
 
 type CephClusterExpansion interface{}
 
+type CephCrushRuleExpansion interface{}
+
+type CephDashboardUserExpansion interface{}
+
+type CephErasureCodeProfileExpansion interface{}
+
 type CephFilesystemExpansion interface{}
 
 type CephFilesystemMirrorExpansion interface{}
 
 type CephFilesystemSubVolumeGroupExpansion interface{}
 
+type CephMgrModuleExpansion interface{}
+
 type CephNFSExpansion interface{}
 
 type CephNVMeOFGatewayExpansion interface{}
 
+type CephOSDRemovalExpansion interface{}
+
 type CephObjectRealmExpansion interface{}
 
 type CephObjectStoreExpansion interface{}
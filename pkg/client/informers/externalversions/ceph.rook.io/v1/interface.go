@@ -38,16 +38,26 @@ type Interface interface {
 	CephClients() CephClientInformer
 	// CephClusters returns a CephClusterInformer.
 	CephClusters() CephClusterInformer
+	// CephCrushRules returns a CephCrushRuleInformer.
+	CephCrushRules() CephCrushRuleInformer
+	// CephDashboardUsers returns a CephDashboardUserInformer.
+	CephDashboardUsers() CephDashboardUserInformer
+	// CephErasureCodeProfiles returns a CephErasureCodeProfileInformer.
+	CephErasureCodeProfiles() CephErasureCodeProfileInformer
 	// CephFilesystems returns a CephFilesystemInformer.
 	CephFilesystems() CephFilesystemInformer
 	// CephFilesystemMirrors returns a CephFilesystemMirrorInformer.
 	CephFilesystemMirrors() CephFilesystemMirrorInformer
 	// CephFilesystemSubVolumeGroups returns a CephFilesystemSubVolumeGroupInformer.
 	CephFilesystemSubVolumeGroups() CephFilesystemSubVolumeGroupInformer
+	// CephMgrModules returns a CephMgrModuleInformer.
+	CephMgrModules() CephMgrModuleInformer
 	// CephNFSes returns a CephNFSInformer.
 	CephNFSes() CephNFSInformer
 	// CephNVMeOFGateways returns a CephNVMeOFGatewayInformer.
 	CephNVMeOFGateways() CephNVMeOFGatewayInformer
+	// CephOSDRemovals returns a CephOSDRemovalInformer.
+	CephOSDRemovals() CephOSDRemovalInformer
 	// CephObjectRealms returns a CephObjectRealmInformer.
 	CephObjectRealms() CephObjectRealmInformer
 	// CephObjectStores returns a CephObjectStoreInformer.
@@ -110,6 +120,21 @@ func (v *version) CephClusters() CephClusterInformer {
 	return &cephClusterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CephCrushRules returns a CephCrushRuleInformer.
+func (v *version) CephCrushRules() CephCrushRuleInformer {
+	return &cephCrushRuleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// CephDashboardUsers returns a CephDashboardUserInformer.
+func (v *version) CephDashboardUsers() CephDashboardUserInformer {
+	return &cephDashboardUserInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// CephErasureCodeProfiles returns a CephErasureCodeProfileInformer.
+func (v *version) CephErasureCodeProfiles() CephErasureCodeProfileInformer {
+	return &cephErasureCodeProfileInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CephFilesystems returns a CephFilesystemInformer.
 func (v *version) CephFilesystems() CephFilesystemInformer {
 	return &cephFilesystemInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -125,6 +150,11 @@ func (v *version) CephFilesystemSubVolumeGroups() CephFilesystemSubVolumeGroupIn
 	return &cephFilesystemSubVolumeGroupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CephMgrModules returns a CephMgrModuleInformer.
+func (v *version) CephMgrModules() CephMgrModuleInformer {
+	return &cephMgrModuleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CephNFSes returns a CephNFSInformer.
 func (v *version) CephNFSes() CephNFSInformer {
 	return &cephNFSInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -135,6 +165,11 @@ func (v *version) CephNVMeOFGateways() CephNVMeOFGatewayInformer {
 	return &cephNVMeOFGatewayInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CephOSDRemovals returns a CephOSDRemovalInformer.
+func (v *version) CephOSDRemovals() CephOSDRemovalInformer {
+	return &cephOSDRemovalInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CephObjectRealms returns a CephObjectRealmInformer.
 func (v *version) CephObjectRealms() CephObjectRealmInformer {
 	return &cephObjectRealmInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
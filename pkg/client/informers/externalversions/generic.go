@@ -67,16 +67,26 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephClients().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cephclusters"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephClusters().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("cephcrushrules"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephCrushRules().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("cephdashboardusers"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephDashboardUsers().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("cepherasurecodeprofiles"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephErasureCodeProfiles().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cephfilesystems"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephFilesystems().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cephfilesystemmirrors"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephFilesystemMirrors().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cephfilesystemsubvolumegroups"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephFilesystemSubVolumeGroups().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("cephmgrmodules"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephMgrModules().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cephnfses"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephNFSes().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cephnvmeofgateways"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephNVMeOFGateways().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("cephosdremovals"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephOSDRemovals().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cephobjectrealms"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephObjectRealms().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cephobjectstores"):
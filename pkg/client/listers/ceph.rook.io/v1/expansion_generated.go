@@ -74,6 +74,30 @@ type CephClusterListerExpansion interface{}
 // CephClusterNamespaceLister.
 type CephClusterNamespaceListerExpansion interface{}
 
+// CephCrushRuleListerExpansion allows custom methods to be added to
+// CephCrushRuleLister.
+type CephCrushRuleListerExpansion interface{}
+
+// CephCrushRuleNamespaceListerExpansion allows custom methods to be added to
+// CephCrushRuleNamespaceLister.
+type CephCrushRuleNamespaceListerExpansion interface{}
+
+// CephDashboardUserListerExpansion allows custom methods to be added to
+// CephDashboardUserLister.
+type CephDashboardUserListerExpansion interface{}
+
+// CephDashboardUserNamespaceListerExpansion allows custom methods to be added to
+// CephDashboardUserNamespaceLister.
+type CephDashboardUserNamespaceListerExpansion interface{}
+
+// CephErasureCodeProfileListerExpansion allows custom methods to be added to
+// CephErasureCodeProfileLister.
+type CephErasureCodeProfileListerExpansion interface{}
+
+// CephErasureCodeProfileNamespaceListerExpansion allows custom methods to be added to
+// CephErasureCodeProfileNamespaceLister.
+type CephErasureCodeProfileNamespaceListerExpansion interface{}
+
 // CephFilesystemListerExpansion allows custom methods to be added to
 // CephFilesystemLister.
 type CephFilesystemListerExpansion interface{}
@@ -98,6 +122,14 @@ type CephFilesystemSubVolumeGroupListerExpansion interface{}
 // CephFilesystemSubVolumeGroupNamespaceLister.
 type CephFilesystemSubVolumeGroupNamespaceListerExpansion interface{}
 
+// CephMgrModuleListerExpansion allows custom methods to be added to
+// CephMgrModuleLister.
+type CephMgrModuleListerExpansion interface{}
+
+// CephMgrModuleNamespaceListerExpansion allows custom methods to be added to
+// CephMgrModuleNamespaceLister.
+type CephMgrModuleNamespaceListerExpansion interface{}
+
 // CephNFSListerExpansion allows custom methods to be added to
 // CephNFSLister.
 type CephNFSListerExpansion interface{}
@@ -114,6 +146,14 @@ type CephNVMeOFGatewayListerExpansion interface{}
 // CephNVMeOFGatewayNamespaceLister.
 type CephNVMeOFGatewayNamespaceListerExpansion interface{}
 
+// CephOSDRemovalListerExpansion allows custom methods to be added to
+// CephOSDRemovalLister.
+type CephOSDRemovalListerExpansion interface{}
+
+// CephOSDRemovalNamespaceListerExpansion allows custom methods to be added to
+// CephOSDRemovalNamespaceLister.
+type CephOSDRemovalNamespaceListerExpansion interface{}
+
 // CephObjectRealmListerExpansion allows custom methods to be added to
 // CephObjectRealmLister.
 type CephObjectRealmListerExpansion interface{}
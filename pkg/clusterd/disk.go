@@ -22,6 +22,7 @@ import (
 	"os"
 	"path"
 	"regexp"
+	"slices"
 	"strconv"
 
 	"github.com/coreos/pkg/capnslog"
@@ -59,7 +60,7 @@ func ignoreDevice(d string) bool {
 	return isRBD.MatchString(d)
 }
 
-func DiscoverDevicesWithFilter(executor exec.Executor, deviceFilter, metaDevice string) ([]*sys.LocalDisk, error) {
+func DiscoverDevicesWithFilter(executor exec.Executor, deviceFilter string, metaDevices ...string) ([]*sys.LocalDisk, error) {
 	var disks []*sys.LocalDisk
 	devices, err := sys.ListDevices(executor)
 	if err != nil {
@@ -74,7 +75,7 @@ func DiscoverDevicesWithFilter(executor exec.Executor, deviceFilter, metaDevice
 			continue
 		}
 
-		if deviceFilter != "" && !deviceMatchWithFilter(d, deviceFilter, metaDevice) {
+		if deviceFilter != "" && !deviceMatchWithFilter(d, deviceFilter, metaDevices...) {
 			logger.Warningf("device %q skipped due to filter %q", d, deviceFilter)
 			continue
 		}
@@ -121,8 +122,8 @@ func DiscoverDevicesWithFilter(executor exec.Executor, deviceFilter, metaDevice
 	return disks, nil
 }
 
-func deviceMatchWithFilter(device string, filter, metaDevice string) bool {
-	if filter == listAllDevices || device == metaDevice {
+func deviceMatchWithFilter(device string, filter string, metaDevices ...string) bool {
+	if filter == listAllDevices || slices.Contains(metaDevices, device) {
 		return true
 	}
 
@@ -143,7 +144,7 @@ func deviceMatchWithFilter(device string, filter, metaDevice string) bool {
 
 // DiscoverDevices returns all the details of devices available on the local node
 func DiscoverDevices(executor exec.Executor) ([]*sys.LocalDisk, error) {
-	disks, err := DiscoverDevicesWithFilter(executor, "", "")
+	disks, err := DiscoverDevicesWithFilter(executor, "")
 	if err != nil {
 		return nil, err
 	}
@@ -51,6 +51,10 @@ func TestDeviceMatchWithFilter(t *testing.T) {
 	assert.True(t, result)
 	result = deviceMatchWithFilter("dm-1", "nvme[0-1np]+", "/dev/test-rook-vg/test-rook-lv")
 	assert.True(t, result)
+	result = deviceMatchWithFilter("nvme1n1", "sd[a-z]+", "nvme0n1", "nvme1n1")
+	assert.True(t, result)
+	result = deviceMatchWithFilter("nvme2n1", "sd[a-z]+", "nvme0n1", "nvme1n1")
+	assert.False(t, result)
 }
 
 func TestIgnoreDevice(t *testing.T) {
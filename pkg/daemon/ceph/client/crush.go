@@ -140,6 +140,27 @@ func FindOSDInCrushMap(context *clusterd.Context, clusterInfo *ClusterInfo, osdI
 	return &result, nil
 }
 
+// AddCrushBucket adds a bucket of the type to the CRUSH map, outside of any other bucket. Adding a
+// bucket that already exists succeeds.
+func AddCrushBucket(context *clusterd.Context, clusterInfo *ClusterInfo, name, bucketType string) error {
+	args := []string{"osd", "crush", "add-bucket", name, bucketType}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to add crush bucket %q of type %q. %s", name, bucketType, string(buf))
+	}
+	return nil
+}
+
+// MoveCrushBucket moves the bucket and everything in it into the parent bucket
+func MoveCrushBucket(context *clusterd.Context, clusterInfo *ClusterInfo, name, parentType, parentName string) error {
+	args := []string{"osd", "crush", "move", name, formatProperty(parentType, parentName)}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to move crush bucket %q to %s %q. %s", name, parentType, parentName, string(buf))
+	}
+	return nil
+}
+
 // GetCrushHostName gets the hostname where an OSD is running on
 func GetCrushHostName(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int) (string, error) {
 	result, err := FindOSDInCrushMap(context, clusterInfo, osdID)
@@ -230,6 +251,27 @@ func decompileCRUSHMap(context *clusterd.Context, crushMapPath string) error {
 	return nil
 }
 
+// testCRUSHRule maps sample inputs with the rule of the compiled crush map and fails if the rule
+// cannot select numRep OSDs for any of them
+func testCRUSHRule(context *clusterd.Context, crushMapPath string, ruleID, numRep int) error {
+	args := []string{"-i", crushMapPath, "--test", "--rule", strconv.Itoa(ruleID), "--num-rep", strconv.Itoa(numRep), "--show-bad-mappings"}
+	// bad mappings are reported on stderr
+	output, err := context.Executor.ExecuteCommandWithCombinedOutput("crushtool", args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to test crush rule %d. %s", ruleID, output)
+	}
+	if strings.Contains(output, "bad mapping") {
+		return errors.Errorf("crush rule %d cannot place %d replicas. %s", ruleID, numRep, firstLine(output))
+	}
+
+	return nil
+}
+
+func firstLine(output string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	return line
+}
+
 func injectCRUSHMap(context *clusterd.Context, clusterInfo *ClusterInfo, crushMapPath string) error {
 	args := []string{"osd", "setcrushmap", "--in-file", crushMapPath}
 	exec := NewCephCommand(context, clusterInfo, args)
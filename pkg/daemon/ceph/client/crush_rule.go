@@ -19,14 +19,22 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	crushReplicatedType      = 1
+	crushErasureType         = 3
+	defaultCrushRuleTestSize = 3
 	ruleMinSizeDefault       = 1
 	ruleMaxSizeDefault       = 10
 	twoStepCRUSHRuleTemplate = `
@@ -198,3 +206,219 @@ func getCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, name stri
 
 	return rule, nil
 }
+
+// ApplyCrushRule adds a rule with the given steps to the CRUSH map, or replaces the steps of the rule
+// with the same name. The new CRUSH map is tested with crushtool for testSize replicas before it is
+// injected. If testSize is not set, the rule is tested with the number of OSDs its steps select.
+// It returns the ID of the rule.
+func ApplyCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, name, ruleType string, steps []cephv1.CrushRuleStep, testSize int) (int, error) {
+	// the whole crush map is replaced, so no pool reconcile may change it at the same time
+	crushRuleMutex.Lock()
+	defer crushRuleMutex.Unlock()
+
+	if testSize <= 0 {
+		testSize = CrushRuleSize(steps)
+	}
+	if testSize <= 0 {
+		testSize = defaultCrushRuleTestSize
+	}
+
+	crushMap, err := GetCrushMap(context, clusterInfo)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get crush map")
+	}
+
+	desiredType := crushRuleType(ruleType)
+	desiredSteps := buildCrushRuleSteps(ruleType, steps)
+	ruleID := -1
+	for _, rule := range crushMap.Rules {
+		if rule.Name != name {
+			continue
+		}
+		if rule.Type == desiredType && crushStepsEqual(rule.Steps, desiredSteps) {
+			logger.Debugf("crush rule %q is up to date", name)
+			return rule.ID, nil
+		}
+		ruleID = rule.ID
+	}
+	if ruleID < 0 {
+		ruleID = generateRuleID(crushMap.Rules)
+	}
+
+	ruleText := buildCrushRuleText(name, ruleID, ruleType, steps)
+	logger.Infof("applying crush rule %q with id %d", name, ruleID)
+	err = editCrushMap(context, clusterInfo,
+		func(decompiledCRUSHMapFilePath string) error {
+			crushMapText, err := os.ReadFile(filepath.Clean(decompiledCRUSHMapFilePath))
+			if err != nil {
+				return errors.Wrapf(err, "failed to read decompiled crush map %q", decompiledCRUSHMapFilePath)
+			}
+			crushMapText = []byte(replaceCrushRule(string(crushMapText), name, ruleText))
+			if err := os.WriteFile(decompiledCRUSHMapFilePath, crushMapText, 0o600); err != nil {
+				return errors.Wrapf(err, "failed to write decompiled crush map %q", decompiledCRUSHMapFilePath)
+			}
+			return nil
+		},
+		func(compiledCRUSHMapFilePath string) error {
+			return testCRUSHRule(context, compiledCRUSHMapFilePath, ruleID, testSize)
+		})
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to apply crush rule %q", name)
+	}
+	return ruleID, nil
+}
+
+// IsCrushRuleDefinedByCR returns whether a CephCrushRule in the namespace of the cluster defines the
+// rule with the given name
+func IsCrushRuleDefinedByCR(context *clusterd.Context, clusterInfo *ClusterInfo, name string) (bool, error) {
+	crushRules := &cephv1.CephCrushRuleList{}
+	err := context.Client.List(clusterInfo.Context, crushRules, k8sClient.InNamespace(clusterInfo.Namespace))
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to list crush rules in namespace %q", clusterInfo.Namespace)
+	}
+	for i := range crushRules.Items {
+		if crushRules.Items[i].RuleName() == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetPoolsUsingCrushRule returns the sorted names of the pools that place their data with the rule
+func GetPoolsUsingCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, ruleID int) ([]string, error) {
+	args := []string{"osd", "pool", "ls", "detail"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pool details")
+	}
+
+	var pools []struct {
+		Name      string `json:"pool_name"`
+		CrushRule int    `json:"crush_rule"`
+	}
+	err = json.Unmarshal(buf, &pools)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unmarshal failed raw buffer response %s", string(buf))
+	}
+
+	names := []string{}
+	for _, pool := range pools {
+		if pool.CrushRule == ruleID {
+			names = append(names, pool.Name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// CrushRuleSize returns the number of OSDs the steps select, or 0 if a count depends on the size
+// of the pool
+func CrushRuleSize(steps []cephv1.CrushRuleStep) int {
+	size := 0
+	groupSize := 0
+	for _, step := range steps {
+		switch step.Op {
+		case cephv1.CrushRuleStepTake:
+			groupSize = 1
+		case cephv1.CrushRuleStepChoose, cephv1.CrushRuleStepChooseLeaf:
+			if step.Count <= 0 {
+				return 0
+			}
+			groupSize *= step.Count
+		case cephv1.CrushRuleStepEmit:
+			size += groupSize
+			groupSize = 0
+		}
+	}
+	return size
+}
+
+func crushRuleType(ruleType string) int {
+	if ruleType == "erasure" {
+		return crushErasureType
+	}
+	return crushReplicatedType
+}
+
+func crushRuleStepMode(ruleType string, step cephv1.CrushRuleStep) string {
+	if step.Mode != "" {
+		return step.Mode
+	}
+	if ruleType == "erasure" {
+		return "indep"
+	}
+	return "firstn"
+}
+
+// buildCrushRuleSteps returns the steps of the rule as they are reported by the crush map dump
+func buildCrushRuleSteps(ruleType string, steps []cephv1.CrushRuleStep) []stepSpec {
+	crushSteps := []stepSpec{}
+	for _, step := range steps {
+		switch step.Op {
+		case cephv1.CrushRuleStepTake:
+			itemName := step.Item
+			if step.DeviceClass != "" {
+				// the crush map dump reports the shadow bucket of the device class
+				itemName = fmt.Sprintf("%s~%s", step.Item, step.DeviceClass)
+			}
+			crushSteps = append(crushSteps, stepSpec{Operation: "take", ItemName: itemName})
+		case cephv1.CrushRuleStepChoose, cephv1.CrushRuleStepChooseLeaf:
+			operation := fmt.Sprintf("%s_%s", step.Op, crushRuleStepMode(ruleType, step))
+			crushSteps = append(crushSteps, stepSpec{Operation: operation, Number: step.Count, Type: step.Type})
+		case cephv1.CrushRuleStepEmit:
+			crushSteps = append(crushSteps, *stepEmit)
+		}
+	}
+	return crushSteps
+}
+
+// buildCrushRuleText returns the rule in the text format of a decompiled crush map
+func buildCrushRuleText(name string, id int, ruleType string, steps []cephv1.CrushRuleStep) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\nrule %s {\n", name)
+	fmt.Fprintf(&b, "        id %d\n", id)
+	if ruleType == "erasure" {
+		fmt.Fprintf(&b, "        type erasure\n")
+	} else {
+		fmt.Fprintf(&b, "        type replicated\n")
+	}
+	for _, step := range steps {
+		switch step.Op {
+		case cephv1.CrushRuleStepTake:
+			if step.DeviceClass != "" {
+				fmt.Fprintf(&b, "        step take %s class %s\n", step.Item, step.DeviceClass)
+			} else {
+				fmt.Fprintf(&b, "        step take %s\n", step.Item)
+			}
+		case cephv1.CrushRuleStepChoose, cephv1.CrushRuleStepChooseLeaf:
+			fmt.Fprintf(&b, "        step %s %s %d type %s\n", step.Op, crushRuleStepMode(ruleType, step), step.Count, step.Type)
+		case cephv1.CrushRuleStepEmit:
+			fmt.Fprintf(&b, "        step emit\n")
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// replaceCrushRule replaces the rule with the same name in the decompiled crush map, or appends the
+// rule if it is not in the crush map
+func replaceCrushRule(crushMapText, name, ruleText string) string {
+	ruleRegex := regexp.MustCompile(`(?ms)^rule ` + regexp.QuoteMeta(name) + ` \{.*?^\}\n`)
+	if ruleRegex.MatchString(crushMapText) {
+		return ruleRegex.ReplaceAllLiteralString(crushMapText, strings.TrimPrefix(ruleText, "\n"))
+	}
+	return crushMapText + ruleText
+}
+
+func crushStepsEqual(current, desired []stepSpec) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	for i := range current {
+		if current[i].Operation != desired[i].Operation || current[i].Number != desired[i].Number ||
+			current[i].ItemName != desired[i].ItemName || current[i].Type != desired[i].Type {
+			return false
+		}
+	}
+	return true
+}
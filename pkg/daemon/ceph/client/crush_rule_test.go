@@ -18,6 +18,8 @@ package client
 
 import (
 	"encoding/json"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -25,6 +27,11 @@ import (
 	"github.com/rook/rook/pkg/clusterd"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestBuildStretchClusterCrushRule(t *testing.T) {
@@ -135,3 +142,233 @@ func Test_generateRuleID(t *testing.T) {
 		})
 	}
 }
+
+// twoDCSteps places 2 copies in dc1 and 1 copy in dc2
+var twoDCSteps = []cephv1.CrushRuleStep{
+	{Op: cephv1.CrushRuleStepTake, Item: "dc1", DeviceClass: "ssd"},
+	{Op: cephv1.CrushRuleStepChooseLeaf, Count: 2, Type: "host"},
+	{Op: cephv1.CrushRuleStepEmit},
+	{Op: cephv1.CrushRuleStepTake, Item: "dc2"},
+	{Op: cephv1.CrushRuleStepChooseLeaf, Count: 1, Type: "host"},
+	{Op: cephv1.CrushRuleStepEmit},
+}
+
+func TestBuildCrushRuleText(t *testing.T) {
+	expected := `
+rule two-dc {
+        id 4
+        type replicated
+        step take dc1 class ssd
+        step chooseleaf firstn 2 type host
+        step emit
+        step take dc2
+        step chooseleaf firstn 1 type host
+        step emit
+}
+`
+	assert.Equal(t, expected, buildCrushRuleText("two-dc", 4, "replicated", twoDCSteps))
+
+	ecSteps := []cephv1.CrushRuleStep{
+		{Op: cephv1.CrushRuleStepTake, Item: "default"},
+		{Op: cephv1.CrushRuleStepChoose, Count: 3, Type: "rack"},
+		{Op: cephv1.CrushRuleStepChooseLeaf, Count: 2, Type: "host", Mode: "firstn"},
+		{Op: cephv1.CrushRuleStepEmit},
+	}
+	text := buildCrushRuleText("ec-racks", 5, "erasure", ecSteps)
+	assert.Contains(t, text, "type erasure\n")
+	assert.Contains(t, text, "step choose indep 3 type rack\n")
+	assert.Contains(t, text, "step chooseleaf firstn 2 type host\n")
+}
+
+func TestBuildCrushRuleSteps(t *testing.T) {
+	steps := buildCrushRuleSteps("replicated", twoDCSteps)
+	expected := []stepSpec{
+		{Operation: "take", ItemName: "dc1~ssd"},
+		{Operation: "chooseleaf_firstn", Number: 2, Type: "host"},
+		{Operation: "emit"},
+		{Operation: "take", ItemName: "dc2"},
+		{Operation: "chooseleaf_firstn", Number: 1, Type: "host"},
+		{Operation: "emit"},
+	}
+	assert.Equal(t, expected, steps)
+
+	// the item IDs of the crush map dump are ignored
+	current := append([]stepSpec{}, expected...)
+	current[0].Item = -12
+	assert.True(t, crushStepsEqual(current, steps))
+	current[1].Number = 3
+	assert.False(t, crushStepsEqual(current, steps))
+	assert.False(t, crushStepsEqual(current[:3], steps))
+}
+
+func TestCrushRuleSize(t *testing.T) {
+	assert.Equal(t, 3, CrushRuleSize(twoDCSteps))
+	assert.Equal(t, 6, CrushRuleSize([]cephv1.CrushRuleStep{
+		{Op: cephv1.CrushRuleStepTake, Item: "default"},
+		{Op: cephv1.CrushRuleStepChoose, Count: 3, Type: "rack"},
+		{Op: cephv1.CrushRuleStepChooseLeaf, Count: 2, Type: "host"},
+		{Op: cephv1.CrushRuleStepEmit},
+	}))
+	assert.Equal(t, 0, CrushRuleSize([]cephv1.CrushRuleStep{
+		{Op: cephv1.CrushRuleStepTake, Item: "default"},
+		{Op: cephv1.CrushRuleStepChooseLeaf, Count: 0, Type: "host"},
+		{Op: cephv1.CrushRuleStepEmit},
+	}))
+}
+
+func TestReplaceCrushRule(t *testing.T) {
+	crushMapText := `# rules
+rule replicated_rule {
+	id 0
+	type replicated
+	step take default
+	step chooseleaf firstn 0 type host
+	step emit
+}
+rule two-dc {
+	id 1
+	type replicated
+	step take dc1
+	step chooseleaf firstn 3 type host
+	step emit
+}
+
+# end crush map
+`
+	newRule := buildCrushRuleText("two-dc", 1, "replicated", twoDCSteps)
+	replaced := replaceCrushRule(crushMapText, "two-dc", newRule)
+	assert.Equal(t, 1, strings.Count(replaced, "rule two-dc {"))
+	assert.Contains(t, replaced, "step take dc2\n")
+	assert.NotContains(t, replaced, "chooseleaf firstn 3")
+	assert.Contains(t, replaced, "rule replicated_rule {")
+	assert.True(t, strings.HasSuffix(replaced, "# end crush map\n"))
+
+	appended := replaceCrushRule(crushMapText, "other", buildCrushRuleText("other", 2, "replicated", twoDCSteps))
+	assert.True(t, strings.HasPrefix(appended, crushMapText))
+	assert.Contains(t, appended, "rule other {")
+}
+
+func TestApplyCrushRule(t *testing.T) {
+	crushDump := `{"rules":[{"rule_id":0,"rule_name":"replicated_rule","type":1},
+{"rule_id":3,"rule_name":"two-dc","type":1,"steps":[
+{"op":"take","item":-5,"item_name":"dc1~ssd"},{"op":"chooseleaf_firstn","num":2,"type":"host"},{"op":"emit"},
+{"op":"take","item":-6,"item_name":"dc2"},{"op":"chooseleaf_firstn","num":1,"type":"host"},{"op":"emit"}]}]}`
+
+	newExecutor := func(t *testing.T, testOutput string, injected *bool) *exectest.MockExecutor {
+		executor := &exectest.MockExecutor{}
+		executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			if command == "ceph" && args[0] == "osd" && args[1] == "crush" && args[2] == "dump" {
+				return crushDump, nil
+			}
+			if command == "ceph" && args[0] == "osd" && args[1] == "getcrushmap" {
+				return "", nil
+			}
+			if command == "crushtool" && args[0] == "--decompile" {
+				return "", os.WriteFile(args[3], []byte("# begin crush map\n"), 0o600)
+			}
+			if command == "crushtool" && args[0] == "--compile" {
+				decompiled, err := os.ReadFile(args[1])
+				assert.NoError(t, err)
+				assert.Contains(t, string(decompiled), "rule rack-rule {\n        id 4\n")
+				return "", nil
+			}
+			if command == "ceph" && args[0] == "osd" && args[1] == "setcrushmap" {
+				*injected = true
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %q %v", command, args)
+		}
+		executor.MockExecuteCommandWithCombinedOutput = func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			if command == "crushtool" && args[2] == "--test" {
+				assert.Equal(t, []string{"--rule", "4", "--num-rep", "3", "--show-bad-mappings"}, args[3:])
+				return testOutput, nil
+			}
+			return "", errors.Errorf("unexpected command %q %v", command, args)
+		}
+		return executor
+	}
+
+	rackSteps := []cephv1.CrushRuleStep{
+		{Op: cephv1.CrushRuleStepTake, Item: "default"},
+		{Op: cephv1.CrushRuleStepChooseLeaf, Count: 0, Type: "rack"},
+		{Op: cephv1.CrushRuleStepEmit},
+	}
+
+	t.Run("unchanged rule", func(t *testing.T) {
+		injected := false
+		context := &clusterd.Context{Executor: newExecutor(t, "", &injected)}
+		ruleID, err := ApplyCrushRule(context, AdminTestClusterInfo("mycluster"), "two-dc", "replicated", twoDCSteps, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, ruleID)
+		assert.False(t, injected)
+	})
+
+	t.Run("new rule", func(t *testing.T) {
+		injected := false
+		context := &clusterd.Context{Executor: newExecutor(t, "", &injected)}
+		ruleID, err := ApplyCrushRule(context, AdminTestClusterInfo("mycluster"), "rack-rule", "replicated", rackSteps, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, ruleID)
+		assert.True(t, injected)
+	})
+
+	t.Run("bad mappings", func(t *testing.T) {
+		injected := false
+		context := &clusterd.Context{Executor: newExecutor(t, "bad mapping rule 4 x 0 num_rep 3 result [2,0]\nbad mapping rule 4 x 1 num_rep 3 result [1]", &injected)}
+		_, err := ApplyCrushRule(context, AdminTestClusterInfo("mycluster"), "rack-rule", "replicated", rackSteps, 0)
+		assert.ErrorContains(t, err, "cannot place 3 replicas. bad mapping rule 4 x 0 num_rep 3 result [2,0]")
+		assert.False(t, injected)
+	})
+}
+
+func TestGetPoolsUsingCrushRule(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] == "osd" && args[1] == "pool" && args[2] == "ls" && args[3] == "detail" {
+			return `[{"pool_name":"rbd","crush_rule":3},{"pool_name":".mgr","crush_rule":0},{"pool_name":"images","crush_rule":3}]`, nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+
+	pools, err := GetPoolsUsingCrushRule(context, AdminTestClusterInfo("mycluster"), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"images", "rbd"}, pools)
+
+	pools, err = GetPoolsUsingCrushRule(context, AdminTestClusterInfo("mycluster"), 7)
+	assert.NoError(t, err)
+	assert.Empty(t, pools)
+}
+
+func TestIsCrushRuleDefinedByCR(t *testing.T) {
+	crushRule := &cephv1.CephCrushRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "stretched", Namespace: "mycluster"},
+		Spec:       cephv1.CrushRuleSpec{Name: "two-dc"},
+	}
+	otherNamespace := &cephv1.CephCrushRule{ObjectMeta: metav1.ObjectMeta{Name: "racks", Namespace: "other"}}
+	context := &clusterd.Context{Client: newTestCrushRuleClient(crushRule, otherNamespace)}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+
+	defined, err := IsCrushRuleDefinedByCR(context, clusterInfo, "two-dc")
+	assert.NoError(t, err)
+	assert.True(t, defined)
+
+	// the name of the CR is not the rule name when the spec overrides it
+	defined, err = IsCrushRuleDefinedByCR(context, clusterInfo, "stretched")
+	assert.NoError(t, err)
+	assert.False(t, defined)
+
+	defined, err = IsCrushRuleDefinedByCR(context, clusterInfo, "racks")
+	assert.NoError(t, err)
+	assert.False(t, defined)
+}
+
+// newTestCrushRuleClient returns a fake client with the CephCrushRules
+func newTestCrushRuleClient(crushRules ...runtime.Object) k8sClient.Client {
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCrushRule{}, &cephv1.CephCrushRuleList{})
+	return fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(crushRules...).Build()
+}
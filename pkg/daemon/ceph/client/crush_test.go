@@ -295,6 +295,29 @@ func TestGetOSDOnHost(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestAddAndMoveCrushBucket(t *testing.T) {
+	commands := [][]string{}
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[1] == "crush" && (args[2] == "add-bucket" || args[2] == "move") {
+			commands = append(commands, args[:5])
+			return "", nil
+		}
+		return "", errors.Errorf("unexpected ceph command '%v'", args)
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := AddCrushBucket(context, AdminTestClusterInfo("mycluster"), "rack1", "rack")
+	assert.NoError(t, err)
+	err = MoveCrushBucket(context, AdminTestClusterInfo("mycluster"), "rack1", "datacenter", "dc1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"osd", "crush", "add-bucket", "rack1", "rack"},
+		{"osd", "crush", "move", "rack1", "datacenter=dc1"},
+	}, commands)
+}
+
 func TestCrushName(t *testing.T) {
 	// each is slightly different than the last
 	crushNames := []string{
@@ -0,0 +1,262 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/util"
+	"github.com/rook/rook/pkg/util/exec"
+)
+
+// DashboardRole is a role of the dashboard access control
+type DashboardRole struct {
+	Name              string              `json:"name"`
+	Description       string              `json:"description"`
+	ScopesPermissions map[string][]string `json:"scopes_permissions"`
+}
+
+// DashboardUser is a user of the dashboard access control
+type DashboardUser struct {
+	Username          string   `json:"username"`
+	Name              string   `json:"name"`
+	Email             string   `json:"email"`
+	Roles             []string `json:"roles"`
+	Enabled           bool     `json:"enabled"`
+	PwdExpirationDate *int64   `json:"pwdExpirationDate"`
+	PwdUpdateRequired bool     `json:"pwdUpdateRequired"`
+}
+
+// DashboardSAML2Config is the SAML2 single sign-on configuration of the dashboard
+type DashboardSAML2Config struct {
+	BaseURL           string
+	IdPMetadata       string
+	UsernameAttribute string
+	IdPEntityID       string
+}
+
+func runDashboardCommand(context *clusterd.Context, clusterInfo *ClusterInfo, args []string) ([]byte, error) {
+	args = append([]string{"dashboard"}, args...)
+	buf, err := NewCephCommand(context, clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		if len(buf) > 0 {
+			return buf, errors.Wrapf(err, "%s", string(buf))
+		}
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ListDashboardRoles returns the names of the dashboard roles, including the system roles
+func ListDashboardRoles(context *clusterd.Context, clusterInfo *ClusterInfo) ([]string, error) {
+	buf, err := runDashboardCommand(context, clusterInfo, []string{"ac-role-show"})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list dashboard roles")
+	}
+	var roles []string
+	if err := json.Unmarshal(buf, &roles); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal dashboard roles")
+	}
+	return roles, nil
+}
+
+// GetDashboardRole returns the dashboard role with the given name
+func GetDashboardRole(context *clusterd.Context, clusterInfo *ClusterInfo, name string) (*DashboardRole, error) {
+	buf, err := runDashboardCommand(context, clusterInfo, []string{"ac-role-show", name})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get dashboard role %q", name)
+	}
+	var role DashboardRole
+	if err := json.Unmarshal(buf, &role); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal dashboard role %q", name)
+	}
+	return &role, nil
+}
+
+// CreateDashboardRole creates a dashboard role without permissions
+func CreateDashboardRole(context *clusterd.Context, clusterInfo *ClusterInfo, name string) error {
+	if _, err := runDashboardCommand(context, clusterInfo, []string{"ac-role-create", name}); err != nil {
+		return errors.Wrapf(err, "failed to create dashboard role %q", name)
+	}
+	return nil
+}
+
+// AddDashboardRoleScopePermissions adds the permissions on a scope to a dashboard role
+func AddDashboardRoleScopePermissions(context *clusterd.Context, clusterInfo *ClusterInfo, name, scope string, permissions []string) error {
+	args := append([]string{"ac-role-add-scope-perms", name, scope}, permissions...)
+	if _, err := runDashboardCommand(context, clusterInfo, args); err != nil {
+		return errors.Wrapf(err, "failed to add permissions %v on scope %q to dashboard role %q", permissions, scope, name)
+	}
+	return nil
+}
+
+// DeleteDashboardRoleScopePermissions removes all the permissions on a scope from a dashboard role
+func DeleteDashboardRoleScopePermissions(context *clusterd.Context, clusterInfo *ClusterInfo, name, scope string) error {
+	if _, err := runDashboardCommand(context, clusterInfo, []string{"ac-role-del-scope-perms", name, scope}); err != nil {
+		return errors.Wrapf(err, "failed to remove the permissions on scope %q from dashboard role %q", scope, name)
+	}
+	return nil
+}
+
+// DeleteDashboardRole deletes a dashboard role. A role assigned to a user cannot be deleted.
+func DeleteDashboardRole(context *clusterd.Context, clusterInfo *ClusterInfo, name string) error {
+	if _, err := runDashboardCommand(context, clusterInfo, []string{"ac-role-delete", name}); err != nil {
+		return errors.Wrapf(err, "failed to delete dashboard role %q", name)
+	}
+	return nil
+}
+
+// ListDashboardUsers returns the names of the dashboard users
+func ListDashboardUsers(context *clusterd.Context, clusterInfo *ClusterInfo) ([]string, error) {
+	buf, err := runDashboardCommand(context, clusterInfo, []string{"ac-user-show"})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list dashboard users")
+	}
+	var users []string
+	if err := json.Unmarshal(buf, &users); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal dashboard users")
+	}
+	return users, nil
+}
+
+// GetDashboardUser returns the dashboard user with the given name
+func GetDashboardUser(context *clusterd.Context, clusterInfo *ClusterInfo, username string) (*DashboardUser, error) {
+	buf, err := runDashboardCommand(context, clusterInfo, []string{"ac-user-show", username})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get dashboard user %q", username)
+	}
+	var user DashboardUser
+	if err := json.Unmarshal(buf, &user); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal dashboard user %q", username)
+	}
+	return &user, nil
+}
+
+// CreateDashboardUser creates a dashboard user with the given password and roles. The password
+// expires at the expiration date if it is not nil.
+func CreateDashboardUser(context *clusterd.Context, clusterInfo *ClusterInfo, username, password string, roles []string, pwdExpirationDate *time.Time) error {
+	file, err := createPasswordFile(password)
+	if err != nil {
+		return err
+	}
+	defer removePasswordFile(file)
+
+	// > ceph dashboard ac-user-create <username> -i <path-to-password-file> [--pwd_expiration_date=<timestamp>]
+	args := []string{"ac-user-create", username, "-i", file.Name()}
+	if pwdExpirationDate != nil {
+		args = append(args, fmt.Sprintf("--pwd_expiration_date=%d", pwdExpirationDate.Unix()))
+	}
+	if _, err := runDashboardCommand(context, clusterInfo, args); err != nil {
+		return errors.Wrapf(err, "failed to create dashboard user %q", username)
+	}
+	if len(roles) > 0 {
+		return SetDashboardUserRoles(context, clusterInfo, username, roles)
+	}
+	return nil
+}
+
+// SetDashboardUserPassword sets the password of a dashboard user
+func SetDashboardUserPassword(context *clusterd.Context, clusterInfo *ClusterInfo, username, password string) error {
+	file, err := createPasswordFile(password)
+	if err != nil {
+		return err
+	}
+	defer removePasswordFile(file)
+
+	// > ceph dashboard ac-user-set-password <username> -i <path-to-password-file>
+	if _, err := runDashboardCommand(context, clusterInfo, []string{"ac-user-set-password", username, "-i", file.Name()}); err != nil {
+		return errors.Wrapf(err, "failed to set the password of dashboard user %q", username)
+	}
+	return nil
+}
+
+// EnableDashboardUser enables or disables the login of a dashboard user
+func EnableDashboardUser(context *clusterd.Context, clusterInfo *ClusterInfo, username string, enabled bool) error {
+	command := "ac-user-enable"
+	if !enabled {
+		command = "ac-user-disable"
+	}
+	if _, err := runDashboardCommand(context, clusterInfo, []string{command, username}); err != nil {
+		return errors.Wrapf(err, "failed to run %q for dashboard user %q", command, username)
+	}
+	return nil
+}
+
+func createPasswordFile(password string) (*os.File, error) {
+	file, err := util.CreateTempFile(password)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create a temporary dashboard password file")
+	}
+	return file, nil
+}
+
+func removePasswordFile(file *os.File) {
+	if err := os.Remove(file.Name()); err != nil {
+		logger.Errorf("failed to clean up dashboard password file %q. %v", file.Name(), err)
+	}
+}
+
+// SetDashboardUserRoles replaces the roles of a dashboard user
+func SetDashboardUserRoles(context *clusterd.Context, clusterInfo *ClusterInfo, username string, roles []string) error {
+	args := append([]string{"ac-user-set-roles", username}, roles...)
+	if _, err := runDashboardCommand(context, clusterInfo, args); err != nil {
+		return errors.Wrapf(err, "failed to set the roles %v of dashboard user %q", roles, username)
+	}
+	return nil
+}
+
+// DeleteDashboardUser deletes a dashboard user
+func DeleteDashboardUser(context *clusterd.Context, clusterInfo *ClusterInfo, username string) error {
+	if _, err := runDashboardCommand(context, clusterInfo, []string{"ac-user-delete", username}); err != nil {
+		return errors.Wrapf(err, "failed to delete dashboard user %q", username)
+	}
+	return nil
+}
+
+// SetupDashboardSAML2 configures the SAML2 single sign-on of the dashboard
+func SetupDashboardSAML2(context *clusterd.Context, clusterInfo *ClusterInfo, config DashboardSAML2Config) error {
+	// > ceph dashboard sso setup saml2 <ceph_dashboard_base_url> <idp_metadata> [<idp_username_attribute>] [<idp_entity_id>]
+	args := []string{"sso", "setup", "saml2", config.BaseURL, config.IdPMetadata, config.UsernameAttribute}
+	if config.IdPEntityID != "" {
+		args = append(args, config.IdPEntityID)
+	}
+	if _, err := runDashboardCommand(context, clusterInfo, args); err != nil {
+		return errors.Wrap(err, "failed to set up the dashboard saml2 single sign-on")
+	}
+	return nil
+}
+
+// EnableDashboardSAML2 enables the SAML2 single sign-on of the dashboard
+func EnableDashboardSAML2(context *clusterd.Context, clusterInfo *ClusterInfo) error {
+	if _, err := runDashboardCommand(context, clusterInfo, []string{"sso", "enable", "saml2"}); err != nil {
+		return errors.Wrap(err, "failed to enable the dashboard saml2 single sign-on")
+	}
+	return nil
+}
+
+// DisableDashboardSSO disables the single sign-on of the dashboard
+func DisableDashboardSSO(context *clusterd.Context, clusterInfo *ClusterInfo) error {
+	if _, err := runDashboardCommand(context, clusterInfo, []string{"sso", "disable"}); err != nil {
+		return errors.Wrap(err, "failed to disable the dashboard single sign-on")
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+// lifeExpectancyLayouts are the layouts of the life expectancy of a device reported by ceph
+var lifeExpectancyLayouts = []string{"2006-01-02 15:04:05.000000", "2006-01-02T15:04:05.000000", "2006-01-02 15:04:05", time.RFC3339}
+
+// Device is a device of the "ceph device ls" command output
+type Device struct {
+	DevID             string           `json:"devid"`
+	Location          []DeviceLocation `json:"location"`
+	Daemons           []string         `json:"daemons"`
+	LifeExpectancyMin string           `json:"life_expectancy_min,omitempty"`
+	LifeExpectancyMax string           `json:"life_expectancy_max,omitempty"`
+	WearLevel         *float64         `json:"wear_level,omitempty"`
+}
+
+// DeviceLocation is the host and the name of a device
+type DeviceLocation struct {
+	Host string `json:"host"`
+	Dev  string `json:"dev"`
+	Path string `json:"path"`
+}
+
+// DeviceHealthMetrics is the SMART data of a device scraped by the devicehealth mgr module, in the
+// smartctl JSON format
+type DeviceHealthMetrics struct {
+	SmartStatus *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status,omitempty"`
+	ATASmartAttributes struct {
+		Table []ATASmartAttribute `json:"table"`
+	} `json:"ata_smart_attributes"`
+	NVMeSmartHealthInformationLog *NVMeSmartHealthInformationLog `json:"nvme_smart_health_information_log,omitempty"`
+}
+
+// ATASmartAttribute is a SMART attribute of an ATA device
+type ATASmartAttribute struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Raw  struct {
+		Value int64 `json:"value"`
+	} `json:"raw"`
+}
+
+// NVMeSmartHealthInformationLog is the SMART health log of an NVMe device
+type NVMeSmartHealthInformationLog struct {
+	CriticalWarning int   `json:"critical_warning"`
+	PercentageUsed  int   `json:"percentage_used"`
+	MediaErrors     int64 `json:"media_errors"`
+}
+
+// ListDevices lists the devices known by ceph with the daemons using them
+func ListDevices(context *clusterd.Context, clusterInfo *ClusterInfo) ([]Device, error) {
+	args := []string{"device", "ls"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the devices")
+	}
+
+	var devices []Device
+	if err := json.Unmarshal(buf, &devices); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal device ls response. %s", string(buf))
+	}
+	return devices, nil
+}
+
+// GetLatestDeviceHealthMetrics returns the most recent SMART data scraped for the device, or nil if
+// no SMART data was scraped
+func GetLatestDeviceHealthMetrics(context *clusterd.Context, clusterInfo *ClusterInfo, devID string) (*DeviceHealthMetrics, error) {
+	args := []string{"device", "get-health-metrics", devID}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the health metrics of device %q", devID)
+	}
+
+	// the metrics are keyed by the time they were scraped, like "20261017-000000"
+	var metrics map[string]DeviceHealthMetrics
+	if err := json.Unmarshal(buf, &metrics); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the health metrics of device %q", devID)
+	}
+	if len(metrics) == 0 {
+		return nil, nil
+	}
+	stamps := make([]string, 0, len(metrics))
+	for stamp := range metrics {
+		stamps = append(stamps, stamp)
+	}
+	sort.Strings(stamps)
+	latest := metrics[stamps[len(stamps)-1]]
+	return &latest, nil
+}
+
+// ParseLifeExpectancy parses the life expectancy of a device, which is empty if the failure of the
+// device is not predicted
+func ParseLifeExpectancy(lifeExpectancy string) (*time.Time, error) {
+	if lifeExpectancy == "" {
+		return nil, nil
+	}
+	for _, layout := range lifeExpectancyLayouts {
+		if t, err := time.Parse(layout, lifeExpectancy); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, errors.Errorf("invalid life expectancy %q", lifeExpectancy)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fakeDeviceLs = `[
+	{
+		"devid": "SEAGATE_ST4000NM_ZC1234",
+		"location": [{"host": "node1", "dev": "sdb", "path": "/dev/disk/by-path/pci-0000:00:10.0-scsi-0:0:1:0"}],
+		"daemons": ["osd.0"],
+		"life_expectancy_min": "2026-11-01 00:00:00.000000",
+		"life_expectancy_max": "2026-12-01 00:00:00.000000"
+	},
+	{
+		"devid": "SAMSUNG_MZ7LH_S4GE",
+		"location": [{"host": "node2", "dev": "nvme0n1", "path": ""}],
+		"daemons": ["osd.1", "osd.2"],
+		"wear_level": 0.12
+	}
+]`
+
+var fakeDeviceHealthMetrics = `{
+	"20261016-000000": {"smart_status": {"passed": true}, "ata_smart_attributes": {"table": [{"id": 5, "name": "Reallocated_Sector_Ct", "raw": {"value": 0}}]}},
+	"20261017-000000": {"smart_status": {"passed": true}, "ata_smart_attributes": {"table": [{"id": 5, "name": "Reallocated_Sector_Ct", "raw": {"value": 12}}]}}
+}`
+
+func TestDevices(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("ExecuteCommandWithOutput: %s %v", command, args)
+		if args[0] == "device" && args[1] == "ls" {
+			return fakeDeviceLs, nil
+		}
+		if args[0] == "device" && args[1] == "get-health-metrics" {
+			switch args[2] {
+			case "SEAGATE_ST4000NM_ZC1234":
+				return fakeDeviceHealthMetrics, nil
+			case "SAMSUNG_MZ7LH_S4GE":
+				return "{}", nil
+			}
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+
+	devices, err := ListDevices(context, clusterInfo)
+	assert.NoError(t, err)
+	require.Len(t, devices, 2)
+	assert.Equal(t, "node1", devices[0].Location[0].Host)
+	assert.Equal(t, []string{"osd.1", "osd.2"}, devices[1].Daemons)
+	assert.Equal(t, 0.12, *devices[1].WearLevel)
+
+	metrics, err := GetLatestDeviceHealthMetrics(context, clusterInfo, "SEAGATE_ST4000NM_ZC1234")
+	assert.NoError(t, err)
+	require.NotNil(t, metrics)
+	assert.Equal(t, int64(12), metrics.ATASmartAttributes.Table[0].Raw.Value)
+
+	metrics, err = GetLatestDeviceHealthMetrics(context, clusterInfo, "SAMSUNG_MZ7LH_S4GE")
+	assert.NoError(t, err)
+	assert.Nil(t, metrics)
+
+	_, err = GetLatestDeviceHealthMetrics(context, clusterInfo, "unknown")
+	assert.Error(t, err)
+}
+
+func TestParseLifeExpectancy(t *testing.T) {
+	lifeExpectancy, err := ParseLifeExpectancy("2026-11-01 00:00:00.000000")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), *lifeExpectancy)
+
+	lifeExpectancy, err = ParseLifeExpectancy("")
+	assert.NoError(t, err)
+	assert.Nil(t, lifeExpectancy)
+
+	_, err = ParseLifeExpectancy("soon")
+	assert.Error(t, err)
+}
@@ -18,6 +18,7 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
@@ -65,6 +66,81 @@ func GetErasureCodeProfileDetails(context *clusterd.Context, clusterInfo *Cluste
 	return ecProfileDetails, nil
 }
 
+// GetErasureCodeProfile returns the key/value pairs of the profile, or nil if the profile does not exist
+func GetErasureCodeProfile(context *clusterd.Context, clusterInfo *ClusterInfo, name string) (map[string]string, error) {
+	profiles, err := ListErasureCodeProfiles(context, clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(profiles, name) {
+		return nil, nil
+	}
+
+	args := []string{"osd", "erasure-code-profile", "get", name}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get erasure-code-profile for %q", name)
+	}
+
+	profile := map[string]string{}
+	err = json.Unmarshal(buf, &profile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unmarshal failed raw buffer response %s", string(buf))
+	}
+
+	return profile, nil
+}
+
+// SetErasureCodeProfile creates the profile with the given key/value pairs, or overwrites the profile
+// if it exists
+func SetErasureCodeProfile(context *clusterd.Context, clusterInfo *ClusterInfo, name string, profile map[string]string) error {
+	keys := make([]string, 0, len(profile))
+	for key := range profile {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	args := []string{"osd", "erasure-code-profile", "set", name, "--force"}
+	for _, key := range keys {
+		args = append(args, fmt.Sprintf("%s=%s", key, profile[key]))
+	}
+	cmd := NewCephCommand(context, clusterInfo, args)
+	cmd.JsonOutput = false
+	buf, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set erasure-code-profile %q. output: %q", name, string(buf))
+	}
+
+	return nil
+}
+
+// GetPoolsUsingErasureCodeProfile returns the sorted names of the pools created with the profile
+func GetPoolsUsingErasureCodeProfile(context *clusterd.Context, clusterInfo *ClusterInfo, name string) ([]string, error) {
+	args := []string{"osd", "pool", "ls", "detail"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pool details")
+	}
+
+	var pools []struct {
+		Name               string `json:"pool_name"`
+		ErasureCodeProfile string `json:"erasure_code_profile"`
+	}
+	err = json.Unmarshal(buf, &pools)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unmarshal failed raw buffer response %s", string(buf))
+	}
+
+	names := []string{}
+	for _, pool := range pools {
+		if pool.ErasureCodeProfile == name {
+			names = append(names, pool.Name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
 func CreateErasureCodeProfile(context *clusterd.Context, clusterInfo *ClusterInfo, profileName string, pool cephv1.PoolSpec) error {
 	// look up the default profile so we can use the default plugin/technique
 	defaultProfile, err := GetErasureCodeProfileDetails(context, clusterInfo, "default")
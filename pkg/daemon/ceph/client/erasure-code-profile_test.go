@@ -111,3 +111,55 @@ func testCreateProfile(t *testing.T, failureDomain, crushRoot, deviceClass strin
 	err := CreateErasureCodeProfile(context, AdminTestClusterInfo("mycluster"), "myapp", spec)
 	assert.Nil(t, err)
 }
+
+func TestSetErasureCodeProfile(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	var setArgs []string
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		switch {
+		case args[2] == "ls":
+			return `["default","archive"]`, nil
+		case args[2] == "get":
+			assert.Equal(t, "archive", args[3])
+			return `{"plugin":"lrc","k":"4","m":"2","l":"3","crush-failure-domain":"host"}`, nil
+		case args[2] == "set":
+			setArgs = args
+			return "", nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+
+	profile, err := GetErasureCodeProfile(context, clusterInfo, "archive")
+	assert.NoError(t, err)
+	assert.Equal(t, "lrc", profile["plugin"])
+	assert.Equal(t, "3", profile["l"])
+
+	profile, err = GetErasureCodeProfile(context, clusterInfo, "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, profile)
+
+	err = SetErasureCodeProfile(context, clusterInfo, "archive", map[string]string{"plugin": "lrc", "k": "4", "m": "2", "l": "3"})
+	assert.NoError(t, err)
+	// the pairs are sorted for a stable command
+	assert.Equal(t, []string{"osd", "erasure-code-profile", "set", "archive", "--force", "k=4", "l=3", "m=2", "plugin=lrc"}, setArgs[:9])
+}
+
+func TestGetPoolsUsingErasureCodeProfile(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		assert.Equal(t, []string{"osd", "pool", "ls", "detail"}, args[:4])
+		return `[{"pool_name":"logs","erasure_code_profile":"archive"},{"pool_name":"replicapool","erasure_code_profile":""},
+{"pool_name":"backups","erasure_code_profile":"archive"},{"pool_name":"data","erasure_code_profile":"data_ecprofile"}]`, nil
+	}
+
+	pools, err := GetPoolsUsingErasureCodeProfile(context, AdminTestClusterInfo("mycluster"), "archive")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"backups", "logs"}, pools)
+
+	pools, err = GetPoolsUsingErasureCodeProfile(context, AdminTestClusterInfo("mycluster"), "unused")
+	assert.NoError(t, err)
+	assert.Empty(t, pools)
+}
@@ -96,7 +96,7 @@ func TestFilesystemRemove(t *testing.T) {
 	metadataDeleted := false
 	deletedPools := map[string]bool{}
 	executor := &exectest.MockExecutor{}
-	context := &clusterd.Context{Executor: executor}
+	context := &clusterd.Context{Executor: executor, Client: newTestCrushRuleClient()}
 	fs := CephFilesystemDetails{
 		ID: 1,
 		MDSMap: MDSMap{
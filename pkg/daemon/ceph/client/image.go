@@ -169,6 +169,8 @@ type RBDStatus struct {
 	Watchers []struct {
 		Address string `json:"address"`
 	} `json:"watchers"`
+	// Migration is set while the image is migrated to another pool
+	Migration *RBDMigrationStatus `json:"migration,omitempty"`
 }
 
 // GetWatchers returns a list of watchers of the RBD image.
@@ -0,0 +1,189 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+const (
+	// RBDMigrationPrepared is the state of a migration after `rbd migration prepare`
+	RBDMigrationPrepared = "prepared"
+	// RBDMigrationExecuting is the state of a migration while the data is copied
+	RBDMigrationExecuting = "executing"
+	// RBDMigrationExecuted is the state of a migration that can be committed
+	RBDMigrationExecuted = "executed"
+	// RBDMigrationError is the state of a migration that failed to execute
+	RBDMigrationError = "error"
+
+	rbdTaskMigrationExecute = "migrate execute"
+)
+
+// RBDMigrationStatus is the migration of an image as reported by `rbd status`
+type RBDMigrationStatus struct {
+	SourcePoolName   string `json:"source_pool_name"`
+	SourceImageName  string `json:"source_image_name"`
+	DestPoolName     string `json:"dest_pool_name"`
+	DestImageName    string `json:"dest_image_name"`
+	DestImageID      string `json:"dest_image_id"`
+	State            string `json:"state"`
+	StateDescription string `json:"state_description"`
+}
+
+// RBDTask is a background task of the rbd mgr module
+type RBDTask struct {
+	ID         string  `json:"id"`
+	Message    string  `json:"message"`
+	InProgress bool    `json:"in_progress"`
+	Progress   float64 `json:"progress"`
+	Refs       struct {
+		Action    string `json:"action"`
+		PoolName  string `json:"pool_name"`
+		ImageName string `json:"image_name"`
+	} `json:"refs"`
+}
+
+// IsMigrationExecute returns whether the task copies the data of the image to the pool
+func (t RBDTask) IsMigrationExecute(poolName, imageName string) bool {
+	return t.Refs.Action == rbdTaskMigrationExecute && t.Refs.PoolName == poolName && t.Refs.ImageName == imageName
+}
+
+// the hexdump line of a value in the output of `rados listomapvals`, e.g.
+// 00000000  31 30 65 35 36 62 38 62  34 35 36 37              |10e56b8b4567|
+var omapValueLine = regexp.MustCompile(`^[0-9a-f]{8}  ((?:[0-9a-f]{2} {1,2})+)`)
+
+// PrepareImageMigration links the image to a new image with the same name in the target pool. The
+// image is then used from the target pool while the data is copied by ExecuteImageMigration.
+func PrepareImageMigration(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, imageName, targetPoolName, dataPoolName string) error {
+	args := []string{"migration", "prepare", getImageSpec(imageName, poolName), getImageSpec(imageName, targetPoolName)}
+	if dataPoolName != "" {
+		args = append(args, "--data-pool", dataPoolName)
+	}
+	output, err := NewRBDCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to prepare the migration of image %q from pool %q to pool %q. %s", imageName, poolName, targetPoolName, output)
+	}
+	return nil
+}
+
+// ExecuteImageMigration adds a task to the rbd mgr module to copy the data of a prepared image
+func ExecuteImageMigration(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, imageName string) error {
+	args := []string{"rbd", "task", "add", "migration", "execute", getImageSpec(imageName, poolName)}
+	if _, err := NewCephCommand(context, clusterInfo, args).Run(); err != nil {
+		return errors.Wrapf(err, "failed to execute the migration of image %q in pool %q", imageName, poolName)
+	}
+	return nil
+}
+
+// CommitImageMigration removes the source of an executed image migration
+func CommitImageMigration(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, imageName string) error {
+	args := []string{"migration", "commit", getImageSpec(imageName, poolName)}
+	output, err := NewRBDCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to commit the migration of image %q in pool %q. %s", imageName, poolName, output)
+	}
+	return nil
+}
+
+// ListRBDTasks returns the background tasks of the rbd mgr module
+func ListRBDTasks(context *clusterd.Context, clusterInfo *ClusterInfo) ([]RBDTask, error) {
+	buf, err := NewCephCommand(context, clusterInfo, []string{"rbd", "task", "list"}).Run()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list rbd tasks")
+	}
+	var tasks []RBDTask
+	if err := json.Unmarshal(buf, &tasks); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal rbd task list response. %s", string(buf))
+	}
+	return tasks, nil
+}
+
+// GetOmapValues returns the omap of a rados object, or nil if the object does not exist
+func GetOmapValues(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, objectName string) (map[string]string, error) {
+	args := []string{"--pool", poolName, "listomapvals", objectName}
+	buf, err := NewRadosCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		// the error is in the output, or in the error when the command runs in the proxy container
+		if strings.Contains(string(buf)+err.Error(), "No such file or directory") {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to list the omap values of object %q in pool %q. %s", objectName, poolName, string(buf))
+	}
+	values, err := parseOmapValues(string(buf))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the omap values of object %q in pool %q", objectName, poolName)
+	}
+	return values, nil
+}
+
+// parseOmapValues parses the output of `rados listomapvals`, where each key is followed by the
+// size and the hexdump of its value
+func parseOmapValues(output string) (map[string]string, error) {
+	values := map[string]string{}
+	key := ""
+	var value []byte
+	inValue := false
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if inValue {
+				values[key] = string(value)
+			}
+			key, value, inValue = "", nil, false
+		case !inValue && key == "":
+			key = line
+		case !inValue && strings.HasPrefix(line, "value ("):
+			inValue = true
+		case inValue:
+			match := omapValueLine.FindStringSubmatch(line)
+			if match == nil {
+				// the last line of the hexdump is the size of the value
+				continue
+			}
+			b, err := hex.DecodeString(strings.ReplaceAll(match[1], " ", ""))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to decode the value of key %q", key)
+			}
+			value = append(value, b...)
+		default:
+			return nil, errors.Errorf("unexpected line %q", line)
+		}
+	}
+	if inValue {
+		values[key] = string(value)
+	}
+	return values, nil
+}
+
+// SetOmapValue sets a key in the omap of a rados object, creating the object if needed
+func SetOmapValue(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, objectName, key, value string) error {
+	args := []string{"--pool", poolName, "setomapval", objectName, key, value}
+	output, err := NewRadosCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set omap key %q of object %q in pool %q. %s", key, objectName, poolName, string(output))
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageMigrationCommands(t *testing.T) {
+	var commands [][]string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			commands = append(commands, append([]string{command}, args...))
+			return "", nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+
+	assert.NoError(t, PrepareImageMigration(context, clusterInfo, "replicapool", "csi-vol-1", "replicapool-migration", ""))
+	assert.NoError(t, PrepareImageMigration(context, clusterInfo, "replicapool", "csi-vol-2", "replicapool-migration", "ecpool"))
+	assert.NoError(t, ExecuteImageMigration(context, clusterInfo, "replicapool-migration", "csi-vol-1"))
+	assert.NoError(t, CommitImageMigration(context, clusterInfo, "replicapool-migration", "csi-vol-1"))
+	require.Len(t, commands, 4)
+	assert.Equal(t, []string{"rbd", "migration", "prepare", "replicapool/csi-vol-1", "replicapool-migration/csi-vol-1", "--cluster=mycluster"}, commands[0][:6])
+	assert.Equal(t, []string{"rbd", "migration", "prepare", "replicapool/csi-vol-2", "replicapool-migration/csi-vol-2", "--data-pool", "ecpool"}, commands[1][:7])
+	assert.Equal(t, []string{"ceph", "rbd", "task", "add", "migration", "execute", "replicapool-migration/csi-vol-1"}, commands[2][:7])
+	assert.Equal(t, []string{"rbd", "migration", "commit", "replicapool-migration/csi-vol-1"}, commands[3][:4])
+
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		return "rbd: the image is in use", errors.New("exit status 16")
+	}
+	err := PrepareImageMigration(context, clusterInfo, "replicapool", "csi-vol-1", "replicapool-migration", "")
+	assert.ErrorContains(t, err, "the image is in use")
+}
+
+func TestGetRBDImageStatusMigration(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			assert.Equal(t, []string{"status", "replicapool-migration/csi-vol-1"}, args[:2])
+			return `{"watchers":[],"migration":{"source_pool_name":"replicapool","source_image_name":"csi-vol-1",
+"dest_pool_name":"replicapool-migration","dest_image_name":"csi-vol-1","dest_image_id":"1a2b3c","state":"executed","state_description":""}}`, nil
+		},
+	}
+	status, err := GetRBDImageStatus(&clusterd.Context{Executor: executor}, AdminTestClusterInfo("mycluster"), "replicapool-migration", "csi-vol-1", "")
+	assert.NoError(t, err)
+	require.NotNil(t, status.Migration)
+	assert.Equal(t, RBDMigrationExecuted, status.Migration.State)
+	assert.Equal(t, "1a2b3c", status.Migration.DestImageID)
+}
+
+func TestListRBDTasks(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			assert.Equal(t, []string{"rbd", "task", "list"}, args[:3])
+			return `[{"sequence":1,"id":"f4a2","message":"Migrating image replicapool/csi-vol-1 to replicapool-migration/csi-vol-1",
+"refs":{"action":"migrate execute","pool_name":"replicapool-migration","pool_namespace":"","image_name":"csi-vol-1","image_id":"1a2b3c"},
+"in_progress":true,"progress":0.42}]`, nil
+		},
+	}
+	tasks, err := ListRBDTasks(&clusterd.Context{Executor: executor}, AdminTestClusterInfo("mycluster"))
+	assert.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.True(t, tasks[0].InProgress)
+	assert.Equal(t, 0.42, tasks[0].Progress)
+	assert.True(t, tasks[0].IsMigrationExecute("replicapool-migration", "csi-vol-1"))
+	assert.False(t, tasks[0].IsMigrationExecute("replicapool", "csi-vol-1"))
+}
+
+func TestGetOmapValues(t *testing.T) {
+	output := `csi.imageid
+value (12 bytes) :
+00000000  31 30 65 35 36 62 38 62  34 35 36 37              |10e56b8b4567|
+0000000c
+
+csi.volname
+value (21 bytes) :
+00000000  70 76 63 2d 30 31 32 33  34 35 36 37 38 39 61 62  |pvc-0123456789ab|
+00000010  63 64 65 66 30                                    |cdef0|
+00000015
+
+`
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			assert.Equal(t, "rados", command)
+			assert.Equal(t, []string{"--pool", "replicapool", "listomapvals"}, args[:3])
+			if args[3] == "missing" {
+				return "error getting omap key set replicapool/missing: (2) No such file or directory", errors.New("exit status 1")
+			}
+			return output, nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+
+	values, err := GetOmapValues(context, clusterInfo, "replicapool", "csi.volume.0123")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"csi.imageid": "10e56b8b4567", "csi.volname": "pvc-0123456789abcdef0"}, values)
+
+	values, err = GetOmapValues(context, clusterInfo, "replicapool", "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, values)
+}
+
+func TestSetOmapValue(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			assert.Equal(t, "rados", command)
+			assert.Equal(t, []string{"--pool", "replicapool", "setomapval", "csi.volumes.default", "csi.volume.pvc-1", "0123"}, args[:6])
+			return "", nil
+		},
+	}
+	err := SetOmapValue(&clusterd.Context{Executor: executor}, AdminTestClusterInfo("mycluster"), "replicapool", "csi.volumes.default", "csi.volume.pvc-1", "0123")
+	assert.NoError(t, err)
+}
@@ -18,6 +18,7 @@ package client
 
 import (
 	"encoding/json"
+	"slices"
 	"time"
 
 	"github.com/pkg/errors"
@@ -68,6 +69,85 @@ func CephMgrStat(context *clusterd.Context, clusterInfo *ClusterInfo) (*MgrStat,
 	return &mgrStat, nil
 }
 
+// MgrModules is the mgr module information of the mgr map
+type MgrModules struct {
+	// EnabledModules are the names of the enabled modules that are not always on
+	EnabledModules []string `json:"modules"`
+	// AlwaysOnModules are the names of the modules that are always on, by ceph release
+	AlwaysOnModules  map[string][]string `json:"always_on_modules"`
+	AvailableModules []MgrModuleInfo     `json:"available_modules"`
+
+	// release is the ceph release of the running mgrs, which selects their always on modules
+	release string
+}
+
+// MgrModuleInfo is the information of a mgr module
+type MgrModuleInfo struct {
+	Name          string                     `json:"name"`
+	CanRun        bool                       `json:"can_run"`
+	ErrorString   string                     `json:"error_string"`
+	ModuleOptions map[string]MgrModuleOption `json:"module_options"`
+}
+
+// MgrModuleOption is an option of a mgr module
+type MgrModuleOption struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Min         any      `json:"min"`
+	Max         any      `json:"max"`
+	EnumAllowed []string `json:"enum_allowed"`
+}
+
+// GetMgrModules returns the mgr modules of the mgr map
+func GetMgrModules(context *clusterd.Context, clusterInfo *ClusterInfo) (*MgrModules, error) {
+	args := []string{"mgr", "dump"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get mgr dump")
+	}
+
+	var modules MgrModules
+	if err := json.Unmarshal(buf, &modules); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal mgr modules")
+	}
+
+	version, err := LeastUptodateDaemonVersion(context, clusterInfo, "mgr")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the version of the running mgrs")
+	}
+	modules.release = version.ReleaseName()
+	return &modules, nil
+}
+
+// Module returns the information of the module with the given name, or nil if the module is not available
+func (m *MgrModules) Module(name string) *MgrModuleInfo {
+	for i := range m.AvailableModules {
+		if m.AvailableModules[i].Name == name {
+			return &m.AvailableModules[i]
+		}
+	}
+	return nil
+}
+
+// IsAlwaysOn returns whether the module with the given name is always on in the release of the
+// running mgrs. If the release is not known, the module is always on if it is in any release.
+func (m *MgrModules) IsAlwaysOn(name string) bool {
+	if modules, ok := m.AlwaysOnModules[m.release]; ok {
+		return slices.Contains(modules, name)
+	}
+	for _, modules := range m.AlwaysOnModules {
+		if slices.Contains(modules, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEnabled returns whether the module with the given name is enabled
+func (m *MgrModules) IsEnabled(name string) bool {
+	return slices.Contains(m.EnabledModules, name) || m.IsAlwaysOn(name)
+}
+
 // MgrEnableModule enables a mgr module
 func MgrEnableModule(context *clusterd.Context, clusterInfo *ClusterInfo, name string, force bool) error {
 	retryCount := 5
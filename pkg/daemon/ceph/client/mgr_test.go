@@ -169,3 +169,45 @@ func TestGetMinCompatClientVersion(t *testing.T) {
 		assert.Equal(t, "luminous", result)
 	})
 }
+
+func TestGetMgrModules(t *testing.T) {
+	var mgrVersions string
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mgr" && args[1] == "dump" {
+			return `{"modules":["iostat","prometheus"],"always_on_modules":{"squid":["balancer","pg_autoscaler","volumes"],"tentacle":["balancer","pg_autoscaler"]},
+"available_modules":[{"name":"balancer","can_run":true,"error_string":"","module_options":{"min_score":{"name":"min_score","type":"float","min":"","max":"","enum_allowed":[]}}},
+{"name":"telemetry","can_run":false,"error_string":"missing dependency","module_options":{}}]}`, nil
+		}
+		if args[0] == "versions" {
+			return mgrVersions, nil
+		}
+		return "", errors.New("unexpected command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	mgrVersions = `{"mgr":{"ceph version 20.2.0 (0000000000000000000000000000000000000000) tentacle (stable)":2}}`
+	modules, err := GetMgrModules(context, AdminTestClusterInfo("mycluster"))
+	assert.NoError(t, err)
+	assert.True(t, modules.IsEnabled("prometheus"))
+	assert.True(t, modules.IsEnabled("balancer"))
+	assert.True(t, modules.IsAlwaysOn("pg_autoscaler"))
+	assert.False(t, modules.IsAlwaysOn("prometheus"))
+	// the modules that are always on in another release are not always on in the running release
+	assert.False(t, modules.IsAlwaysOn("volumes"))
+	assert.False(t, modules.IsEnabled("telemetry"))
+
+	balancer := modules.Module("balancer")
+	assert.NotNil(t, balancer)
+	assert.Equal(t, "float", balancer.ModuleOptions["min_score"].Type)
+	telemetry := modules.Module("telemetry")
+	assert.False(t, telemetry.CanRun)
+	assert.Equal(t, "missing dependency", telemetry.ErrorString)
+	assert.Nil(t, modules.Module("unknown"))
+
+	// the modules that are always on in any release are always on if the mgr release is not known
+	mgrVersions = `{}`
+	modules, err = GetMgrModules(context, AdminTestClusterInfo("mycluster"))
+	assert.NoError(t, err)
+	assert.True(t, modules.IsAlwaysOn("volumes"))
+}
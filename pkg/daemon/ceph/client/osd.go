@@ -62,6 +62,7 @@ type OSDDump struct {
 	FullRatio         float64             `json:"full_ratio"`
 	BackfillFullRatio float64             `json:"backfillfull_ratio"`
 	NearFullRatio     float64             `json:"nearfull_ratio"`
+	RequireOSDRelease string              `json:"require_osd_release"`
 }
 
 // IsFlagSetOnCrushUnit checks if an OSD flag is set on specified Crush unit
@@ -471,6 +472,11 @@ type OSDMetadata struct {
 	// Devices is the sorted, comma-separated set of physical block devices backing the OSD, resolved
 	// past any LVM/dm layer, e.g. "vdb" or "nvme0n1,vdb" when the DB is on a separate device.
 	Devices string `json:"devices"`
+	// DeviceIDs is the comma-separated list of the stable IDs of the devices, built by ceph from the
+	// vendor, model and serial of the device, e.g. "vdb=QEMU_HARDDISK_QM00002"
+	DeviceIDs string `json:"device_ids"`
+	// ObjectStore is the backend of the OSD, such as "bluestore"
+	ObjectStore string `json:"osd_objectstore"`
 }
 
 // GetOSDMetadata returns the output of `ceph osd metadata`
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -68,19 +69,41 @@ type CephStoragePoolStats struct {
 		Name  string `json:"name"`
 		ID    int    `json:"id"`
 		Stats struct {
-			BytesUsed    float64 `json:"bytes_used"`
-			RawBytesUsed float64 `json:"raw_bytes_used"`
-			MaxAvail     float64 `json:"max_avail"`
-			Objects      float64 `json:"objects"`
-			DirtyObjects float64 `json:"dirty"`
-			ReadIO       float64 `json:"rd"`
-			ReadBytes    float64 `json:"rd_bytes"`
-			WriteIO      float64 `json:"wr"`
-			WriteBytes   float64 `json:"wr_bytes"`
+			Stored             float64 `json:"stored"`
+			BytesUsed          float64 `json:"bytes_used"`
+			RawBytesUsed       float64 `json:"raw_bytes_used"`
+			PercentUsed        float64 `json:"percent_used"`
+			MaxAvail           float64 `json:"max_avail"`
+			Objects            float64 `json:"objects"`
+			QuotaObjects       float64 `json:"quota_objects"`
+			QuotaBytes         float64 `json:"quota_bytes"`
+			DirtyObjects       float64 `json:"dirty"`
+			ReadIO             float64 `json:"rd"`
+			ReadBytes          float64 `json:"rd_bytes"`
+			WriteIO            float64 `json:"wr"`
+			WriteBytes         float64 `json:"wr_bytes"`
+			CompressBytesUsed  float64 `json:"compress_bytes_used"`
+			CompressUnderBytes float64 `json:"compress_under_bytes"`
 		} `json:"stats"`
 	} `json:"pools"`
 }
 
+// CephStoragePoolPlacementGroups is the number of placement groups of a pool in the osd map
+type CephStoragePoolPlacementGroups struct {
+	Name        string `json:"pool_name"`
+	PGNum       int    `json:"pg_num"`
+	PGNumTarget int    `json:"pg_num_target"`
+}
+
+// PoolAutoscaleStatus is the status of the placement group autoscaler for a pool
+type PoolAutoscaleStatus struct {
+	Name          string `json:"pool_name"`
+	AutoscaleMode string `json:"pg_autoscale_mode"`
+	PGNumTarget   int    `json:"pg_num_target"`
+	PGNumFinal    int    `json:"pg_num_final"`
+	WouldAdjust   bool   `json:"would_adjust"`
+}
+
 type PoolStatistics struct {
 	Images struct {
 		Count            int `json:"count"`
@@ -213,10 +236,22 @@ func CreatePoolWithPGs(context *clusterd.Context, clusterInfo *ClusterInfo, clus
 		return errors.Errorf("pool %q type is not defined as replicated or erasure coded", pool.Name)
 	}
 
-	// create a new erasure code profile for the new pool
-	ecProfileName := GetErasureCodeProfileForPool(pool.Name)
-	if err := CreateErasureCodeProfile(context, clusterInfo, ecProfileName, pool.PoolSpec); err != nil {
-		return errors.Wrapf(err, "failed to create erasure code profile for pool %q", pool.Name)
+	ecProfileName := pool.ErasureCoded.Profile
+	if ecProfileName != "" {
+		// the profile is created by the CephErasureCodeProfile with the same name
+		profiles, err := ListErasureCodeProfiles(context, clusterInfo)
+		if err != nil {
+			return errors.Wrapf(err, "failed to list erasure code profiles for pool %q", pool.Name)
+		}
+		if !slices.Contains(profiles, ecProfileName) {
+			return errors.Errorf("erasure code profile %q of pool %q not found", ecProfileName, pool.Name)
+		}
+	} else {
+		// create a new erasure code profile for the new pool
+		ecProfileName = GetErasureCodeProfileForPool(pool.Name)
+		if err := CreateErasureCodeProfile(context, clusterInfo, ecProfileName, pool.PoolSpec); err != nil {
+			return errors.Wrapf(err, "failed to create erasure code profile for pool %q", pool.Name)
+		}
 	}
 
 	// If the pool is not a replicated pool, then the only other option is an erasure coded pool.
@@ -314,15 +349,41 @@ func DeletePool(context *clusterd.Context, clusterInfo *ClusterInfo, name string
 	}
 
 	if pool.CrushRule != "" {
-		if err := deleteCrushRule(context, clusterInfo, pool.CrushRule); err != nil {
-			logger.Warningf("failed to delete crush rule %q after deleting pool %q. %v", pool.CrushRule, name, err)
-		}
+		deleteCrushRulesOfPool(context, clusterInfo, name, pool.CrushRule)
 	}
 
 	logger.Infof("purge completed for pool %q", name)
 	return nil
 }
 
+// deleteCrushRulesOfPool deletes the crush rule of a deleted pool unless a CephCrushRule defines it.
+// Failures are only logged since the unused rule is removed again by the crush rule cleanup.
+func deleteCrushRulesOfPool(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, crushRuleName string) {
+	definedByCR, err := IsCrushRuleDefinedByCR(context, clusterInfo, crushRuleName)
+	if err != nil {
+		logger.Warningf("failed to check if crush rule %q is defined by a CephCrushRule, keeping the rule after deleting pool %q. %v", crushRuleName, poolName, err)
+		return
+	}
+	if definedByCR {
+		logger.Infof("keeping crush rule %q after deleting pool %q since it is defined by a CephCrushRule", crushRuleName, poolName)
+		return
+	}
+	if err := DeleteCrushRule(context, clusterInfo, crushRuleName); err != nil {
+		logger.Warningf("failed to delete crush rule %q after deleting pool %q. %v", crushRuleName, poolName, err)
+	}
+}
+
+// RenamePool changes the name of a pool, the pool ID is kept
+func RenamePool(context *clusterd.Context, clusterInfo *ClusterInfo, name, newName string) error {
+	logger.Infof("renaming pool %q to %q", name, newName)
+	args := []string{"osd", "pool", "rename", name, newName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to rename pool %q to %q. %s", name, newName, string(output))
+	}
+	return nil
+}
+
 func givePoolAppTag(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, appName string) error {
 	currentAppName, err := getPoolApplication(context, clusterInfo, poolName)
 	if err != nil {
@@ -427,11 +488,19 @@ func GetErasureCodeProfileForPool(baseName string) string {
 
 func createECPoolForApp(context *clusterd.Context, clusterInfo *ClusterInfo, ecProfileName string, pool cephv1.NamedPoolSpec, pgCount string, enableECOverwrite bool) error {
 	args := []string{"osd", "pool", "create", pool.Name, pgCount, "erasure", ecProfileName}
+	if pool.CrushRule != "" {
+		args = append(args, pool.CrushRule)
+	}
 	output, err := NewCephCommand(context, clusterInfo, args).Run()
 	if err != nil {
 		return errors.Wrapf(err, "failed to create EC pool %s. %s", pool.Name, string(output))
 	}
 
+	// the rule is only applied by the create command if the pool does not exist yet
+	if err := updatePoolToCrushRule(context, clusterInfo, pool); err != nil {
+		return err
+	}
+
 	if enableECOverwrite {
 		if err = SetPoolProperty(context, clusterInfo, pool.Name, "allow_ec_overwrites", "true"); err != nil {
 			return errors.Wrapf(err, "failed to allow EC overwrite for pool %s", pool.Name)
@@ -470,6 +539,9 @@ func createReplicatedPoolForApp(context *clusterd.Context, clusterInfo *ClusterI
 		// The stretch cluster rule is created initially by the operator when the stretch cluster is configured
 		// so there is no need to create a new crush rule for the pools here.
 		crushRuleName = defaultStretchCrushRuleName
+	} else if pool.CrushRule != "" {
+		// The rule is created by the CephCrushRule that defines it
+		crushRuleName = pool.CrushRule
 	} else if pool.IsHybridStoragePool() {
 		// Create hybrid crush rule
 		err := createHybridCrushRule(context, clusterInfo, clusterSpec, crushRuleName, pool.PoolSpec)
@@ -515,6 +587,12 @@ func createReplicatedPoolForApp(context *clusterd.Context, clusterInfo *ClusterI
 				return errors.Wrapf(err, "failed to set size property to replicated pool %q to %d", pool.Name, pool.Replicated.Size)
 			}
 		}
+		if !clusterSpec.IsStretchCluster() && pool.CrushRule != "" && poolDetails.CrushRule != pool.CrushRule {
+			logger.Infof("updating crush rule of pool %q from %q to %q", pool.Name, poolDetails.CrushRule, pool.CrushRule)
+			if err := setCrushRule(context, clusterInfo, pool.Name, pool.CrushRule); err != nil {
+				return errors.Wrapf(err, "failed to set crush rule on pool %q", pool.Name)
+			}
+		}
 	}
 
 	// update the common pool properties
@@ -607,7 +685,9 @@ func updatePoolCrushRuleLocked(context *clusterd.Context, clusterInfo *ClusterIn
 	return nil
 }
 
-func CleanupUnusedCrushRules(context *clusterd.Context, clusterInfo *ClusterInfo) error {
+// CleanupUnusedCrushRules deletes the crush rules that no pool uses, except the default rule and the
+// rules in keepRules
+func CleanupUnusedCrushRules(context *clusterd.Context, clusterInfo *ClusterInfo, keepRules []string) error {
 	log.NamespacedInfo(clusterInfo.Namespace, logger, "attempting to acquire crush cleanup lock")
 	crushRuleMutex.Lock()
 	defer crushRuleMutex.Unlock()
@@ -642,8 +722,12 @@ func CleanupUnusedCrushRules(context *clusterd.Context, clusterInfo *ClusterInfo
 			logger.Debugf("skipping deletion of default crush rule %q", rule.Name)
 			continue
 		}
+		if slices.Contains(keepRules, rule.Name) {
+			logger.Debugf("skipping deletion of crush rule %q that is defined by a CephCrushRule", rule.Name)
+			continue
+		}
 		logger.Infof("crush rule %q is unused and will be deleted", rule.Name)
-		if err := deleteCrushRule(context, clusterInfo, rule.Name); err != nil {
+		if err := DeleteCrushRule(context, clusterInfo, rule.Name); err != nil {
 			logger.Warningf("failed to delete unused crush rule %q, continuing with remaining rules. %v", rule.Name, err)
 			lastErr = err
 		}
@@ -673,14 +757,15 @@ func getUsedCrushRules(context *clusterd.Context, clusterInfo *ClusterInfo) (map
 	return usedRules, nil
 }
 
-func deleteCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, crushRuleName string) error {
+// DeleteCrushRule removes the rule from the crush map
+func DeleteCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, crushRuleName string) error {
 	args := []string{"osd", "crush", "rule", "rm", crushRuleName}
 	output, err := NewCephCommand(context, clusterInfo, args).Run()
 	if err != nil {
 		return errors.Wrapf(err, "failed to delete crush rule %q. %s", crushRuleName, string(output))
 	}
 
-	logger.Infof("deleted unused crush rule %q", crushRuleName)
+	logger.Infof("deleted crush rule %q", crushRuleName)
 	return nil
 }
 
@@ -713,6 +798,26 @@ func extractPoolDetails(rule ruleSpec) (string, string) {
 	return failureDomain, deviceClass
 }
 
+// updatePoolToCrushRule sets the crush rule of the pool spec on the pool if it uses another rule
+func updatePoolToCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, pool cephv1.NamedPoolSpec) error {
+	if pool.CrushRule == "" {
+		return nil
+	}
+	details, err := GetPoolDetails(context, clusterInfo, pool.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get pool %q details", pool.Name)
+	}
+	if details.CrushRule == pool.CrushRule {
+		return nil
+	}
+
+	logger.Infof("updating crush rule of pool %q from %q to %q", pool.Name, details.CrushRule, pool.CrushRule)
+	if err := setCrushRule(context, clusterInfo, pool.Name, pool.CrushRule); err != nil {
+		return errors.Wrapf(err, "failed to set crush rule on pool %q", pool.Name)
+	}
+	return nil
+}
+
 func setCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, crushRule string) error {
 	args := []string{"osd", "pool", "set", poolName, "crush_rule", crushRule}
 
@@ -786,6 +891,30 @@ func createHybridCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo,
 }
 
 func updateCrushMap(context *clusterd.Context, clusterInfo *ClusterInfo, ruleset string) error {
+	return editCrushMap(context, clusterInfo, func(decompiledCRUSHMapFilePath string) error {
+		// Append plain rule to the decompiled crush map
+		f, err := os.OpenFile(filepath.Clean(decompiledCRUSHMapFilePath), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o400)
+		if err != nil {
+			return errors.Wrapf(err, "failed to open decompiled crush map %q", decompiledCRUSHMapFilePath)
+		}
+		defer func() {
+			err := f.Close()
+			if err != nil {
+				logger.Errorf("failed to close file %q. %v", f.Name(), err)
+			}
+		}()
+
+		// Append the new crush rule into the crush map
+		if _, err := f.WriteString(ruleset); err != nil {
+			return errors.Wrapf(err, "failed to append replicated plain crush rule to decompiled crush map %q", decompiledCRUSHMapFilePath)
+		}
+		return nil
+	}, nil)
+}
+
+// editCrushMap decompiles the crush map, lets edit change the decompiled file, and injects the
+// compiled result. If test is set, it is called with the compiled crush map before it is injected.
+func editCrushMap(context *clusterd.Context, clusterInfo *ClusterInfo, edit func(decompiledCRUSHMapFilePath string) error, test func(compiledCRUSHMapFilePath string) error) error {
 	// Fetch the compiled crush map
 	compiledCRUSHMapFilePath, err := GetCompiledCrushMap(context, clusterInfo)
 	if err != nil {
@@ -811,21 +940,8 @@ func updateCrushMap(context *clusterd.Context, clusterInfo *ClusterInfo, ruleset
 		}
 	}()
 
-	// Append plain rule to the decompiled crush map
-	f, err := os.OpenFile(filepath.Clean(decompiledCRUSHMapFilePath), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o400)
-	if err != nil {
-		return errors.Wrapf(err, "failed to open decompiled crush map %q", decompiledCRUSHMapFilePath)
-	}
-	defer func() {
-		err := f.Close()
-		if err != nil {
-			logger.Errorf("failed to close file %q. %v", f.Name(), err)
-		}
-	}()
-
-	// Append the new crush rule into the crush map
-	if _, err := f.WriteString(ruleset); err != nil {
-		return errors.Wrapf(err, "failed to append replicated plain crush rule to decompiled crush map %q", decompiledCRUSHMapFilePath)
+	if err := edit(decompiledCRUSHMapFilePath); err != nil {
+		return err
 	}
 
 	// Compile the plain text to CRUSH binary format
@@ -840,6 +956,12 @@ func updateCrushMap(context *clusterd.Context, clusterInfo *ClusterInfo, ruleset
 		}
 	}()
 
+	if test != nil {
+		if err := test(buildCompileCRUSHFileName(decompiledCRUSHMapFilePath)); err != nil {
+			return err
+		}
+	}
+
 	// Inject the new CRUSH Map
 	err = injectCRUSHMap(context, clusterInfo, buildCompileCRUSHFileName(decompiledCRUSHMapFilePath))
 	if err != nil {
@@ -929,6 +1051,44 @@ func GetPoolStats(context *clusterd.Context, clusterInfo *ClusterInfo) (*CephSto
 	return &poolStats, nil
 }
 
+// GetPoolPlacementGroups returns the current and target number of placement groups of the pool
+func GetPoolPlacementGroups(context *clusterd.Context, clusterInfo *ClusterInfo, name string) (CephStoragePoolPlacementGroups, error) {
+	args := []string{"osd", "pool", "ls", "detail"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return CephStoragePoolPlacementGroups{}, errors.Wrap(err, "failed to list pool details")
+	}
+
+	var pools []CephStoragePoolPlacementGroups
+	err = json.Unmarshal(buf, &pools)
+	if err != nil {
+		return CephStoragePoolPlacementGroups{}, errors.Wrapf(err, "unmarshal failed raw buffer response %s", string(buf))
+	}
+
+	for _, pool := range pools {
+		if pool.Name == name {
+			return pool, nil
+		}
+	}
+	return CephStoragePoolPlacementGroups{}, errors.Errorf("pool %q not found", name)
+}
+
+// GetPoolAutoscaleStatus returns the status of the placement group autoscaler for all the pools
+func GetPoolAutoscaleStatus(context *clusterd.Context, clusterInfo *ClusterInfo) ([]PoolAutoscaleStatus, error) {
+	args := []string{"osd", "pool", "autoscale-status"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get pool autoscale status. %s", string(buf))
+	}
+
+	var status []PoolAutoscaleStatus
+	err = json.Unmarshal(buf, &status)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unmarshal failed raw buffer response %s", string(buf))
+	}
+	return status, nil
+}
+
 func GetPoolStatistics(context *clusterd.Context, clusterInfo *ClusterInfo, name string) (*PoolStatistics, error) {
 	args := []string{"pool", "stats", name}
 	cmd := NewRBDCommand(context, clusterInfo, args)
@@ -28,6 +28,7 @@ import (
 	"github.com/rook/rook/pkg/clusterd"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const emptyApplicationName = `{"":{}}`
@@ -103,6 +104,126 @@ func testCreateECPool(t *testing.T, overwrite bool, compressionMode string) {
 	}
 }
 
+func TestCreateECPoolWithProfile(t *testing.T) {
+	p := cephv1.NamedPoolSpec{
+		Name: "mypool",
+		PoolSpec: cephv1.PoolSpec{
+			ErasureCoded: cephv1.ErasureCodedSpec{Profile: "archive"},
+			Application:  "myapp",
+		},
+	}
+	profiles := `["default"]`
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[1] == "erasure-code-profile" && args[2] == "ls" {
+			return profiles, nil
+		}
+		if args[1] == "pool" {
+			if args[2] == "create" {
+				// the pool is created with the referenced profile instead of a generated one
+				assert.Equal(t, "archive", args[6])
+				return "", nil
+			}
+			if args[2] == "application" && args[3] == "get" {
+				return emptyApplicationName, nil
+			}
+			return "", nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+
+	err := CreatePool(context, AdminTestClusterInfo("mycluster"), &cephv1.ClusterSpec{}, &p)
+	assert.ErrorContains(t, err, `erasure code profile "archive" of pool "mypool" not found`)
+
+	profiles = `["default","archive"]`
+	err = CreatePool(context, AdminTestClusterInfo("mycluster"), &cephv1.ClusterSpec{}, &p)
+	assert.NoError(t, err)
+}
+
+func TestCreatePoolWithCrushRule(t *testing.T) {
+	poolExists := false
+	currentRule := "mypool"
+	createdWithRule := ""
+	setRule := ""
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[1] == "erasure-code-profile" {
+			if args[2] == "ls" {
+				return `["default","archive"]`, nil
+			}
+		}
+		if args[1] == "pool" {
+			if args[2] == "get" {
+				if !poolExists {
+					return "", errors.New("pool does not exist")
+				}
+				return fmt.Sprintf(`{"pool":"mypool","size":3,"crush_rule":%q}`, currentRule), nil
+			}
+			if args[2] == "create" {
+				if args[5] == "replicated" {
+					createdWithRule = args[6]
+				} else {
+					createdWithRule = args[7]
+				}
+				return "", nil
+			}
+			if args[2] == "set" && args[4] == "crush_rule" {
+				setRule = args[5]
+				return "", nil
+			}
+			if args[2] == "application" && args[3] == "get" {
+				return emptyApplicationName, nil
+			}
+			return "", nil
+		}
+		if args[1] == "crush" {
+			assert.Fail(t, "no crush rule is created for a pool with a crush rule")
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+
+	p := cephv1.NamedPoolSpec{
+		Name: "mypool",
+		PoolSpec: cephv1.PoolSpec{
+			CrushRule:  "two-dc",
+			Replicated: cephv1.ReplicatedSpec{Size: 3},
+		},
+	}
+	t.Run("new replicated pool", func(t *testing.T) {
+		err := CreatePool(context, AdminTestClusterInfo("mycluster"), &cephv1.ClusterSpec{}, &p)
+		assert.NoError(t, err)
+		assert.Equal(t, "two-dc", createdWithRule)
+		assert.Equal(t, "", setRule)
+	})
+
+	t.Run("existing replicated pool", func(t *testing.T) {
+		poolExists = true
+		err := CreatePool(context, AdminTestClusterInfo("mycluster"), &cephv1.ClusterSpec{}, &p)
+		assert.NoError(t, err)
+		assert.Equal(t, "two-dc", setRule)
+
+		setRule = ""
+		currentRule = "two-dc"
+		err = CreatePool(context, AdminTestClusterInfo("mycluster"), &cephv1.ClusterSpec{}, &p)
+		assert.NoError(t, err)
+		assert.Equal(t, "", setRule)
+	})
+
+	t.Run("erasure coded pool", func(t *testing.T) {
+		createdWithRule = ""
+		currentRule = "mypool"
+		p.PoolSpec = cephv1.PoolSpec{CrushRule: "ec-racks", ErasureCoded: cephv1.ErasureCodedSpec{Profile: "archive"}}
+		err := CreatePool(context, AdminTestClusterInfo("mycluster"), &cephv1.ClusterSpec{}, &p)
+		assert.NoError(t, err)
+		assert.Equal(t, "ec-racks", createdWithRule)
+		assert.Equal(t, "ec-racks", setRule)
+	})
+}
+
 func TestSetPoolApplication(t *testing.T) {
 	poolName := "testpool"
 	appName := "testapp"
@@ -410,12 +531,55 @@ func TestCleanupUnusedCrushRules(t *testing.T) {
 		return "", errors.Errorf("unexpected ceph command %q", args)
 	}
 
-	err := CleanupUnusedCrushRules(context, AdminTestClusterInfo("mycluster"))
+	err := CleanupUnusedCrushRules(context, AdminTestClusterInfo("mycluster"), nil)
 	assert.NoError(t, err)
 	slices.Sort(removedRules)
 	expected := []string{"custom_rule", "mypool", "mypool_zone"}
 	assert.Equal(t, expected, removedRules)
 	assert.NotContains(t, removedRules, "replicated_rule")
+
+	// the rules of the CephCrushRules are kept
+	removedRules = []string{}
+	err = CleanupUnusedCrushRules(context, AdminTestClusterInfo("mycluster"), []string{"custom_rule"})
+	assert.NoError(t, err)
+	slices.Sort(removedRules)
+	assert.Equal(t, []string{"mypool", "mypool_zone"}, removedRules)
+}
+
+func TestDeletePoolKeepsCrushRuleOfCephCrushRule(t *testing.T) {
+	removedRules := []string{}
+	poolRule := "mypool"
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if command == "ceph" && args[1] == "pool" && args[2] == "get" {
+			return fmt.Sprintf(`{"pool":"mypool","pool_id":1,"crush_rule":%q}`, poolRule), nil
+		}
+		if command == "ceph" && args[1] == "pool" && args[2] == "delete" {
+			return "", nil
+		}
+		if command == "ceph" && args[1] == "crush" && args[2] == "rule" && args[3] == "rm" {
+			removedRules = append(removedRules, args[4])
+			return "", nil
+		}
+		if command == "rbd" && args[0] == "pool" && args[1] == "stats" {
+			return `{"images":{"count":0,"provisioned_bytes":0,"snap_count":0},"trash":{"count":0,"provisioned_bytes":0,"snap_count":0}}`, nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+	crushRule := &cephv1.CephCrushRule{ObjectMeta: metav1.ObjectMeta{Name: "two-dc", Namespace: "mycluster"}}
+	context := &clusterd.Context{Executor: executor, Client: newTestCrushRuleClient(crushRule)}
+
+	err := DeletePool(context, AdminTestClusterInfo("mycluster"), "mypool")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mypool"}, removedRules)
+
+	// the rule of a CephCrushRule is kept
+	removedRules = []string{}
+	poolRule = "two-dc"
+	err = DeletePool(context, AdminTestClusterInfo("mycluster"), "mypool")
+	assert.NoError(t, err)
+	assert.Empty(t, removedRules)
 }
 
 func TestCleanupUnusedCrushRulesNoPools(t *testing.T) {
@@ -434,7 +598,7 @@ func TestCleanupUnusedCrushRulesNoPools(t *testing.T) {
 		return "", errors.Errorf("unexpected ceph command %q", args)
 	}
 
-	err := CleanupUnusedCrushRules(context, AdminTestClusterInfo("mycluster"))
+	err := CleanupUnusedCrushRules(context, AdminTestClusterInfo("mycluster"), nil)
 	assert.NoError(t, err)
 	assert.Empty(t, removedRules)
 }
@@ -547,6 +711,50 @@ func TestGetPoolStatistics(t *testing.T) {
 	assert.Nil(t, stats)
 }
 
+func TestGetPoolPlacementGroups(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		assert.Equal(t, []string{"osd", "pool", "ls", "detail"}, args[:4])
+		return `[{"pool_name":".mgr","pg_num":1,"pg_num_target":1},{"pool_name":"replicapool","pg_num":24,"pg_num_target":32}]`, nil
+	}
+
+	pgs, err := GetPoolPlacementGroups(context, AdminTestClusterInfo("mycluster"), "replicapool")
+	assert.NoError(t, err)
+	assert.Equal(t, CephStoragePoolPlacementGroups{Name: "replicapool", PGNum: 24, PGNumTarget: 32}, pgs)
+
+	_, err = GetPoolPlacementGroups(context, AdminTestClusterInfo("mycluster"), "missing")
+	assert.ErrorContains(t, err, `pool "missing" not found`)
+}
+
+func TestGetPoolAutoscaleStatus(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		assert.Equal(t, []string{"osd", "pool", "autoscale-status"}, args[:3])
+		return `[{"pool_name":"replicapool","pg_autoscale_mode":"warn","pg_num_target":32,"pg_num_final":128,"would_adjust":true}]`, nil
+	}
+
+	status, err := GetPoolAutoscaleStatus(context, AdminTestClusterInfo("mycluster"))
+	assert.NoError(t, err)
+	assert.Equal(t, []PoolAutoscaleStatus{{Name: "replicapool", AutoscaleMode: "warn", PGNumTarget: 32, PGNumFinal: 128, WouldAdjust: true}}, status)
+}
+
+func TestRenamePool(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		assert.Equal(t, []string{"osd", "pool", "rename", "replicapool", "replicapool-old"}, args[:5])
+		return "", nil
+	}
+	assert.NoError(t, RenamePool(context, AdminTestClusterInfo("mycluster"), "replicapool", "replicapool-old"))
+
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		return "", errors.New("pool exists")
+	}
+	assert.ErrorContains(t, RenamePool(context, AdminTestClusterInfo("mycluster"), "replicapool", "replicapool-old"), "failed to rename pool")
+}
+
 func TestSetPoolReplicatedSizeProperty(t *testing.T) {
 	poolName := "mypool"
 	executor := &exectest.MockExecutor{}
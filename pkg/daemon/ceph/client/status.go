@@ -186,12 +186,9 @@ func IsClusterClean(context *clusterd.Context, clusterInfo *ClusterInfo, pgHealt
 		return "unable to get PG health", false, err
 	}
 
-	pgHealthyRegexCompiled := defaultPgHealthyRegexCompiled
-	if pgHealthyRegex != "" {
-		pgHealthyRegexCompiled, err = regexp.Compile(pgHealthyRegex)
-		if err != nil {
-			return "unable to compile pgHealthyRegex", false, err
-		}
+	pgHealthyRegexCompiled, err := compilePGHealthyRegex(pgHealthyRegex)
+	if err != nil {
+		return "unable to compile pgHealthyRegex", false, err
 	}
 
 	msg, clean := isClusterClean(status, pgHealthyRegexCompiled)
@@ -201,19 +198,55 @@ func IsClusterClean(context *clusterd.Context, clusterInfo *ClusterInfo, pgHealt
 	return msg, true, nil
 }
 
-func isClusterClean(status CephStatus, pgHealthyRegex *regexp.Regexp) (string, bool) {
+// CleanPGsPercent returns the percentage of the PGs in the cluster that are in a clean state, as
+// determined by pgHealthyRegex or the default healthy states if empty. A cluster without PGs is
+// fully clean.
+func CleanPGsPercent(context *clusterd.Context, clusterInfo *ClusterInfo, pgHealthyRegex string) (int, error) {
+	status, err := Status(context, clusterInfo)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get ceph status")
+	}
+
+	pgHealthyRegexCompiled, err := compilePGHealthyRegex(pgHealthyRegex)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to compile pgHealthyRegex")
+	}
+
+	return cleanPGsPercent(status, pgHealthyRegexCompiled), nil
+}
+
+func compilePGHealthyRegex(pgHealthyRegex string) (*regexp.Regexp, error) {
+	if pgHealthyRegex == "" {
+		return defaultPgHealthyRegexCompiled, nil
+	}
+	return regexp.Compile(pgHealthyRegex)
+}
+
+func cleanPGsPercent(status CephStatus, pgHealthyRegex *regexp.Regexp) int {
 	if status.PgMap.NumPgs == 0 {
-		// there are no PGs yet, that still counts as clean
-		return "cluster has no PGs", true
+		return 100
 	}
+	// rounded down so that the cluster is only 100% clean when every PG is clean
+	return countCleanPGs(status, pgHealthyRegex) * 100 / status.PgMap.NumPgs
+}
 
+func countCleanPGs(status CephStatus, pgHealthyRegex *regexp.Regexp) int {
 	cleanPGs := 0
 	for _, pg := range status.PgMap.PgsByState {
 		if pgHealthyRegex.MatchString(pg.StateName) {
 			cleanPGs += pg.Count
 		}
 	}
-	if cleanPGs == status.PgMap.NumPgs {
+	return cleanPGs
+}
+
+func isClusterClean(status CephStatus, pgHealthyRegex *regexp.Regexp) (string, bool) {
+	if status.PgMap.NumPgs == 0 {
+		// there are no PGs yet, that still counts as clean
+		return "cluster has no PGs", true
+	}
+
+	if countCleanPGs(status, pgHealthyRegex) == status.PgMap.NumPgs {
 		// all PGs in the cluster are in a clean state
 		logger.Debugf("all placement groups have reached a clean state: %+v", status.PgMap.PgsByState)
 		return "all PGs in cluster are clean", true
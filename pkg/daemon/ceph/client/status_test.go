@@ -127,6 +127,28 @@ func TestIsClusterClean(t *testing.T) {
 	assert.False(t, clean)
 }
 
+func TestCleanPGsPercent(t *testing.T) {
+	status := CephStatus{
+		PgMap: PgMap{
+			PgsByState: []PgStateEntry{
+				{StateName: activeClean, Count: 6},
+				{StateName: activeCleanScrubbing, Count: 1},
+				{StateName: "active+undersized+degraded", Count: 3},
+			},
+			NumPgs: 10,
+		},
+	}
+	assert.Equal(t, 70, cleanPGsPercent(status, defaultPgHealthyRegexCompiled))
+
+	// a single unclean PG is never rounded up to a clean cluster
+	status.PgMap.PgsByState = []PgStateEntry{{StateName: activeClean, Count: 999}, {StateName: "active+remapped", Count: 1}}
+	status.PgMap.NumPgs = 1000
+	assert.Equal(t, 99, cleanPGsPercent(status, defaultPgHealthyRegexCompiled))
+
+	// no PGs is a clean cluster
+	assert.Equal(t, 100, cleanPGsPercent(CephStatus{}, defaultPgHealthyRegexCompiled))
+}
+
 func TestGetMDSRank(t *testing.T) {
 	var statusFake CephStatus
 	err := json.Unmarshal(statusFakeRaw, &statusFake)
@@ -194,6 +194,17 @@ func okToStopDaemon(context *clusterd.Context, clusterInfo *ClusterInfo, deploym
 	return nil
 }
 
+// OkToStopDaemons returns an error if the daemons of the given type cannot be stopped all together
+func OkToStopDaemons(context *clusterd.Context, clusterInfo *ClusterInfo, daemonType string, daemonNames []string) error {
+	args := append([]string{daemonType, "ok-to-stop"}, daemonNames...)
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "%s %v cannot be stopped. %s", daemonType, daemonNames, string(buf))
+	}
+	logger.Debugf("%s %v are ok to stop. %s", daemonType, daemonNames, string(buf))
+	return nil
+}
+
 // okToContinueMDSDaemon determines whether it's fine to go to the next mds during an upgrade
 // mostly a placeholder function for the future but since we have standby mds this shouldn't be needed
 func okToContinueMDSDaemon(context *clusterd.Context, clusterInfo *ClusterInfo, deployment, daemonType, daemonName string) error {
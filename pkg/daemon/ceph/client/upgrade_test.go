@@ -111,6 +111,24 @@ func TestOkToStopDaemon(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestOkToStopDaemons(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		assert.Equal(t, []string{"osd", "ok-to-stop", "0", "1"}, args[:4])
+		return "", nil
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := OkToStopDaemons(context, AdminTestClusterInfo("mycluster"), "osd", []string{"0", "1"})
+	assert.NoError(t, err)
+
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		return "Error EBUSY: unsafe to stop osd(s) at this time (12 PGs are or would become offline)", errors.New("exit status 16")
+	}
+	err = OkToStopDaemons(context, AdminTestClusterInfo("mycluster"), "osd", []string{"0", "1"})
+	assert.ErrorContains(t, err, "unsafe to stop osd(s)")
+}
+
 func TestOkToContinue(t *testing.T) {
 	executor := &exectest.MockExecutor{}
 	context := &clusterd.Context{Executor: executor}
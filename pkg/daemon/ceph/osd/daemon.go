@@ -193,7 +193,7 @@ func Provision(context *clusterd.Context, agent *OsdAgent, crushLocation, topolo
 		// Ideally, we would use the "ceph-volume inventory" command instead
 		// However, it suffers from some limitation such as exposing available partitions and LVs
 		// See: https://tracker.ceph.com/issues/43579
-		rawDevices, err = clusterd.DiscoverDevicesWithFilter(context.Executor, deviceFilter, metaDevice)
+		rawDevices, err = clusterd.DiscoverDevicesWithFilter(context.Executor, deviceFilter, append([]string{metaDevice}, agent.storeConfig.MetadataDevices...)...)
 		if err != nil {
 			return errors.Wrap(err, "failed initial hardware discovery")
 		}
@@ -451,7 +451,7 @@ func getAvailableDevices(context *clusterd.Context, agent *OsdAgent) (*DeviceOsd
 		}
 
 		var deviceInfo *DeviceOsdIDEntry
-		if agent.metadataDevice != "" && agent.metadataDevice == device.Name {
+		if (agent.metadataDevice != "" && agent.metadataDevice == device.Name) || isMetadataDevice(device, agent.storeConfig.MetadataDevices) {
 			// current device is desired as the metadata device
 			deviceInfo = &DeviceOsdIDEntry{Data: unassignedOSDID, Metadata: []int{}, DeviceInfo: device}
 		} else if len(desiredDevices) == 1 && desiredDevices[0].Name == "all" {
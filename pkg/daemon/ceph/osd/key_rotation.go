@@ -17,10 +17,14 @@ limitations under the License.
 package osd
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
-	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
 	oposd "github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	"github.com/rook/rook/pkg/operator/ceph/config"
 )
 
 const (
@@ -28,7 +32,69 @@ const (
 	slotOne  string = "1"
 )
 
-func RotateKeyEncryptionKey(context *clusterd.Context, kms *kms.Config, secretName string, devicePaths []string) error {
+// KeyStore stores the encryption keys of the OSDs. The KMS stores the keys of the OSDs on PVCs, and
+// the mon config-key store the keys of the OSDs on host devices.
+type KeyStore interface {
+	GetSecret(secretName string) (string, error)
+	UpdateSecret(secretName, secretValue string) error
+}
+
+// monKeyStore stores the encryption keys in the mon config-key store, where ceph-volume keeps the
+// keys of the encrypted OSDs it creates on host devices
+type monKeyStore struct {
+	monStore *config.MonStore
+}
+
+// NewMonKeyStore returns a key store backed by the mon config-key store
+func NewMonKeyStore(context *clusterd.Context, clusterInfo *client.ClusterInfo) KeyStore {
+	return &monKeyStore{monStore: config.GetMonStore(context, clusterInfo)}
+}
+
+func (s *monKeyStore) GetSecret(secretName string) (string, error) {
+	secret, err := s.monStore.GetKeyValue(secretName)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(secret), nil
+}
+
+func (s *monKeyStore) UpdateSecret(secretName, secretValue string) error {
+	return s.monStore.SetSecretKeyValue(secretName, secretValue)
+}
+
+// HostEncryptedDevices returns the LUKS devices of a host-based OSD from its device paths, which
+// may be opened dm-crypt mappings. The encrypted logical volumes of the OSD reported by ceph-volume,
+// such as its DB and WAL volumes, are added since they are encrypted with the same key.
+func HostEncryptedDevices(context *clusterd.Context, osdID int, devicePaths []string) ([]string, error) {
+	devices := []string{}
+	for _, devicePath := range devicePaths {
+		if strings.HasPrefix(devicePath, "/dev/mapper/") {
+			backingDevice, err := GetBackingDeviceForEncryptedBlock(context, devicePath)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to find the device behind the encrypted block %q of osd.%d", devicePath, osdID)
+			}
+			devicePath = backingDevice
+		}
+		if !slices.Contains(devices, devicePath) {
+			devices = append(devices, devicePath)
+		}
+	}
+
+	entries, err := cephVolumeLVMList(context, osdID)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.Tags.Encrypted == "1" && entry.Path != "" && !slices.Contains(devices, entry.Path) {
+			devices = append(devices, entry.Path)
+		}
+	}
+	return devices, nil
+}
+
+// RotateKeyEncryptionKey replaces the key of the encrypted devices with a new key, and stores the
+// new key in the key store. The current key stays in a second key slot until the new key is stored.
+func RotateKeyEncryptionKey(context *clusterd.Context, kms KeyStore, secretName string, devicePaths []string) error {
 	logger.Info("fetching the current key")
 	// Fetch the currentKey.
 	currentKey, err := kms.GetSecret(secretName)
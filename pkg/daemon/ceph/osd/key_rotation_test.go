@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostEncryptedDevices(t *testing.T) {
+	// the block LV of the OSD is passed, and its DB LV is only known by ceph-volume
+	const lvmList = `{
+		"3": [
+			{"type": "block", "path": "/dev/ceph-bvg/osd-block-x", "tags": {"ceph.encrypted": "1", "ceph.osd_id": "3"}},
+			{"type": "db", "path": "/dev/ceph-dvg/osd-db-y", "tags": {"ceph.encrypted": "1", "ceph.osd_id": "3"}}
+		]
+	}`
+	lvmListOutput := lvmList
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch command {
+			case "cryptsetup":
+				if args[1] == "/dev/mapper/ceph-3-block-dmcrypt" {
+					return "  type:    LUKS2\n  device:  /dev/sdb\n", nil
+				}
+				return "", errors.New("not an active mapping")
+			case "stdbuf":
+				return lvmListOutput, nil
+			}
+			return "", errors.Errorf("unexpected command %q", command)
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	t.Run("lvm mode", func(t *testing.T) {
+		devices, err := HostEncryptedDevices(context, 3, []string{"/dev/ceph-bvg/osd-block-x"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/dev/ceph-bvg/osd-block-x", "/dev/ceph-dvg/osd-db-y"}, devices)
+	})
+
+	t.Run("raw mode", func(t *testing.T) {
+		lvmListOutput = `{}`
+		devices, err := HostEncryptedDevices(context, 3, []string{"/dev/mapper/ceph-3-block-dmcrypt"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/dev/sdb"}, devices)
+	})
+
+	t.Run("mapping not found", func(t *testing.T) {
+		_, err := HostEncryptedDevices(context, 3, []string{"/dev/mapper/unknown"})
+		assert.Error(t, err)
+	})
+}
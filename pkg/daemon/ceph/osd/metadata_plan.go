@@ -0,0 +1,172 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/util/sys"
+)
+
+// plannedDataDevice is a new data device waiting for a metadata device
+type plannedDataDevice struct {
+	name string
+	osds int
+}
+
+// planMetadataDevices picks a metadata device for each new data device of the node that does not
+// have its own metadata device. The OSDs already on the metadata devices are counted so that the
+// devices added later land on the least used metadata device. It returns the metadata device of
+// each data device, keyed by the data device name.
+func (a *OsdAgent) planMetadataDevices(context *clusterd.Context, devices *DeviceOsdMapping) (map[string]string, error) {
+	if len(a.storeConfig.MetadataDevices) == 0 {
+		return nil, nil
+	}
+	if a.metadataDevice != "" {
+		return nil, errors.Errorf("metadataDevice %q and metadataDevices %v cannot be set together", a.metadataDevice, a.storeConfig.MetadataDevices)
+	}
+
+	metadataDisks := make([]*sys.LocalDisk, len(a.storeConfig.MetadataDevices))
+	for i, md := range a.storeConfig.MetadataDevices {
+		disk := findLocalDevice(context.Devices, md)
+		if disk == nil {
+			return nil, errors.Errorf("metadata device %q is not found", md)
+		}
+		if disk.Type != sys.DiskType {
+			return nil, errors.Errorf("metadata device %q must be a whole disk, not a %q", md, disk.Type)
+		}
+		metadataDisks[i] = disk
+	}
+
+	existingOSDs, err := GetCephVolumeLVMOSDs(context, a.clusterInfo, a.clusterInfo.FSID, "", false, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the existing osds on the metadata devices")
+	}
+	load := make([]int, len(metadataDisks))
+	for _, osd := range existingOSDs {
+		for i, disk := range metadataDisks {
+			if osd.MetadataDevice != "" && isSameDevice(disk, osd.MetadataDevice) {
+				load[i]++
+				break
+			}
+		}
+	}
+
+	dataDevices := []plannedDataDevice{}
+	for name, device := range devices.Entries {
+		if device.Data != unassignedOSDID || device.Metadata != nil || device.Config.MetadataDevice != "" {
+			continue
+		}
+		osds := a.storeConfig.OSDsPerDevice
+		if device.Config.OSDsPerDevice > 1 {
+			osds = device.Config.OSDsPerDevice
+		}
+		dataDevices = append(dataDevices, plannedDataDevice{name: name, osds: max(osds, 1)})
+	}
+	sort.Slice(dataDevices, func(i, j int) bool { return dataDevices[i].name < dataDevices[j].name })
+
+	plan, err := assignMetadataDevices(dataDevices, a.storeConfig.MetadataDevices, load, a.storeConfig.MetadataDeviceRatio)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, md := range a.storeConfig.MetadataDevices {
+		assigned := []string{}
+		for _, device := range dataDevices {
+			if plan[device.name] == md {
+				assigned = append(assigned, device.name)
+			}
+		}
+		logger.Infof("metadata device %q: %d existing osds, new data devices %v", md, load[i], assigned)
+	}
+
+	return plan, nil
+}
+
+// assignMetadataDevices gives each data device to the metadata device with the fewest OSDs,
+// taking the first one of the list on a tie. A metadata device never gets more than ratio OSDs,
+// unless the ratio is 0. The load holds the number of OSDs already on each metadata device.
+func assignMetadataDevices(dataDevices []plannedDataDevice, metadataDevices []string, load []int, ratio int) (map[string]string, error) {
+	plan := map[string]string{}
+	for _, device := range dataDevices {
+		selected := -1
+		for i := range metadataDevices {
+			if ratio > 0 && load[i]+device.osds > ratio {
+				continue
+			}
+			if selected == -1 || load[i] < load[selected] {
+				selected = i
+			}
+		}
+		if selected == -1 {
+			return nil, errors.Errorf("no metadata device has room for the %d osds of device %q with a ratio of %d. current osds per metadata device: %s",
+				device.osds, device.name, ratio, formatLoad(metadataDevices, load))
+		}
+		load[selected] += device.osds
+		plan[device.name] = metadataDevices[selected]
+	}
+
+	return plan, nil
+}
+
+func formatLoad(metadataDevices []string, load []int) string {
+	items := make([]string, len(metadataDevices))
+	for i, md := range metadataDevices {
+		items[i] = md + "=" + strconv.Itoa(load[i])
+	}
+	return strings.Join(items, ", ")
+}
+
+// findLocalDevice returns the local device matching the given name, /dev path or device link
+func findLocalDevice(devices []*sys.LocalDisk, name string) *sys.LocalDisk {
+	for _, device := range devices {
+		if device.Name == name || filepath.Join("/dev", device.Name) == name {
+			return device
+		}
+		if strings.HasPrefix(name, "/dev/") && matchDevLinks(device.DevLinks, name) {
+			return device
+		}
+	}
+	return nil
+}
+
+func isSameDevice(device *sys.LocalDisk, devicePath string) bool {
+	if filepath.Join("/dev", device.Name) == devicePath || device.RealPath == devicePath {
+		return true
+	}
+	for link := range strings.SplitSeq(device.DevLinks, " ") {
+		if link == devicePath {
+			return true
+		}
+	}
+	return false
+}
+
+// isMetadataDevice returns whether the device is one of the metadata devices of the node
+func isMetadataDevice(device *sys.LocalDisk, metadataDevices []string) bool {
+	for _, md := range metadataDevices {
+		if device.Name == md || isSameDevice(device, md) {
+			return true
+		}
+	}
+	return false
+}
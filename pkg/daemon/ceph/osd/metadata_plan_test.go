@@ -0,0 +1,166 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/rook/rook/pkg/util/sys"
+	"github.com/stretchr/testify/assert"
+)
+
+// osd 0 has its block on sda and its db on nvme0n1
+var cephVolumeLVMWithDBTestResult = `{
+    "0": [
+        {
+            "devices": ["/dev/sda"],
+            "path": "/dev/ceph-block-0/osd-block-0",
+            "tags": {"ceph.cluster_fsid": "4bfe8b72-5e69-4330-b6c0-4d914db8ab89", "ceph.osd_fsid": "dbe407e0-c1cb-495e-b30a-02e01de6c8ae", "ceph.type": "block"},
+            "type": "block"
+        },
+        {
+            "devices": ["/dev/nvme0n1"],
+            "path": "/dev/ceph-db-0/osd-db-0",
+            "tags": {"ceph.cluster_fsid": "4bfe8b72-5e69-4330-b6c0-4d914db8ab89", "ceph.osd_fsid": "dbe407e0-c1cb-495e-b30a-02e01de6c8ae", "ceph.type": "db"},
+            "type": "db"
+        }
+    ]
+}`
+
+func TestAssignMetadataDevices(t *testing.T) {
+	hdds := func(count int) []plannedDataDevice {
+		devices := []plannedDataDevice{}
+		for i := range count {
+			devices = append(devices, plannedDataDevice{name: "sd" + string(rune('a'+i)), osds: 1})
+		}
+		return devices
+	}
+	nvmes := []string{"nvme0n1", "nvme1n1", "nvme2n1"}
+
+	t.Run("spread evenly", func(t *testing.T) {
+		plan, err := assignMetadataDevices(hdds(12), nvmes, []int{0, 0, 0}, 4)
+		assert.NoError(t, err)
+		assert.Len(t, plan, 12)
+		counts := map[string]int{}
+		for _, md := range plan {
+			counts[md]++
+		}
+		assert.Equal(t, map[string]int{"nvme0n1": 4, "nvme1n1": 4, "nvme2n1": 4}, counts)
+		assert.Equal(t, "nvme0n1", plan["sda"])
+		assert.Equal(t, "nvme1n1", plan["sdb"])
+		assert.Equal(t, "nvme2n1", plan["sdc"])
+	})
+
+	t.Run("least used first", func(t *testing.T) {
+		plan, err := assignMetadataDevices(hdds(2), nvmes, []int{4, 3, 4}, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, "nvme1n1", plan["sda"])
+		// all the metadata devices now have 4 osds, the first one wins
+		assert.Equal(t, "nvme0n1", plan["sdb"])
+	})
+
+	t.Run("ratio reached", func(t *testing.T) {
+		_, err := assignMetadataDevices(hdds(13), nvmes, []int{0, 0, 0}, 4)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "nvme0n1=4, nvme1n1=4, nvme2n1=4")
+	})
+
+	t.Run("no ratio", func(t *testing.T) {
+		plan, err := assignMetadataDevices(hdds(13), nvmes, []int{0, 0, 0}, 0)
+		assert.NoError(t, err)
+		assert.Len(t, plan, 13)
+	})
+
+	t.Run("multiple osds per device", func(t *testing.T) {
+		devices := []plannedDataDevice{{name: "sda", osds: 2}, {name: "sdb", osds: 2}, {name: "sdc", osds: 2}}
+		_, err := assignMetadataDevices(devices, nvmes[:1], []int{0}, 4)
+		assert.Error(t, err)
+	})
+}
+
+func TestPlanMetadataDevices(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("[MockExecuteCommandWithOutput] %s %v", command, args)
+		if slices.Contains(args, "lvm") && slices.Contains(args, "list") {
+			return cephVolumeLVMWithDBTestResult, nil
+		}
+		return "", nil
+	}
+	context := &clusterd.Context{
+		Executor: executor,
+		Devices: []*sys.LocalDisk{
+			{Name: "nvme0n1", Type: sys.DiskType},
+			{Name: "nvme1n1", Type: sys.DiskType, DevLinks: "/dev/disk/by-id/nvme-1"},
+			{Name: "sda", Type: sys.DiskType},
+			{Name: "sdb", Type: sys.DiskType},
+			{Name: "sdc", Type: sys.DiskType},
+			{Name: "sdc1", Type: sys.PartType},
+		},
+	}
+	agent := &OsdAgent{
+		clusterInfo: &cephclient.ClusterInfo{FSID: "4bfe8b72-5e69-4330-b6c0-4d914db8ab89"},
+		storeConfig: config.StoreConfig{MetadataDevices: []string{"nvme0n1", "/dev/disk/by-id/nvme-1"}, MetadataDeviceRatio: 2},
+	}
+	devices := &DeviceOsdMapping{Entries: map[string]*DeviceOsdIDEntry{
+		"sda":     {Data: 0},
+		"sdb":     {Data: unassignedOSDID},
+		"sdc":     {Data: unassignedOSDID},
+		"nvme0n1": {Data: unassignedOSDID, Metadata: []int{}},
+	}}
+
+	t.Run("existing osds are counted", func(t *testing.T) {
+		plan, err := agent.planMetadataDevices(context, devices)
+		assert.NoError(t, err)
+		// nvme0n1 already holds the db of osd 0
+		assert.Equal(t, map[string]string{"sdb": "/dev/disk/by-id/nvme-1", "sdc": "nvme0n1"}, plan)
+	})
+
+	t.Run("device config wins", func(t *testing.T) {
+		devices.Entries["sdc"].Config.MetadataDevice = "nvme0n1"
+		defer func() { devices.Entries["sdc"].Config.MetadataDevice = "" }()
+		plan, err := agent.planMetadataDevices(context, devices)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"sdb": "/dev/disk/by-id/nvme-1"}, plan)
+	})
+
+	t.Run("partition is not allowed", func(t *testing.T) {
+		agent.storeConfig.MetadataDevices = []string{"sdc1"}
+		_, err := agent.planMetadataDevices(context, devices)
+		assert.Error(t, err)
+	})
+
+	t.Run("single metadata device is exclusive", func(t *testing.T) {
+		agent.metadataDevice = "nvme0n1"
+		_, err := agent.planMetadataDevices(context, devices)
+		assert.Error(t, err)
+	})
+}
+
+func TestIsMetadataDevice(t *testing.T) {
+	device := &sys.LocalDisk{Name: "nvme1n1", DevLinks: "/dev/disk/by-id/nvme-1 /dev/disk/by-path/pci-1"}
+	assert.True(t, isMetadataDevice(device, []string{"nvme1n1"}))
+	assert.True(t, isMetadataDevice(device, []string{"/dev/nvme1n1"}))
+	assert.True(t, isMetadataDevice(device, []string{"nvme0n1", "/dev/disk/by-path/pci-1"}))
+	assert.False(t, isMetadataDevice(device, []string{"nvme0n1"}))
+	assert.False(t, isMetadataDevice(device, nil))
+}
@@ -70,16 +70,10 @@ func RemoveOSDs(context *clusterd.Context, clusterInfo *client.ClusterInfo, osds
 }
 
 func removeOSD(clusterdContext *clusterd.Context, clusterInfo *client.ClusterInfo, osdID int, preservePVC, forceOSDRemoval bool) {
-	// Get the host where the OSD is found
-	hostName, err := client.GetCrushHostName(clusterdContext, clusterInfo, osdID)
-	if err != nil {
-		logger.Errorf("failed to get the host where osd.%d is running. %v", osdID, err)
-	}
-
 	// Mark the OSD as out.
 	logger.Infof("marking osd.%d out", osdID)
 	args := []string{"osd", "out", fmt.Sprintf("osd.%d", osdID)}
-	_, err = client.NewCephCommand(clusterdContext, clusterInfo, args).Run()
+	_, err := client.NewCephCommand(clusterdContext, clusterInfo, args).Run()
 	if err != nil {
 		logger.Errorf("failed to exclude osd.%d out of the crush map. %v", osdID, err)
 	}
@@ -117,6 +111,24 @@ func removeOSD(clusterdContext *clusterd.Context, clusterInfo *client.ClusterInf
 		}
 	}
 
+	if err := PurgeOSD(clusterdContext, clusterInfo, osdID, preservePVC); err != nil {
+		logger.Errorf("failed to purge osd.%d. %v", osdID, err)
+		return
+	}
+
+	logger.Infof("completed removal of OSD %d", osdID)
+}
+
+// PurgeOSD deletes the deployment of an OSD that is out, along with its prepare job and PVCs if the
+// OSD runs on a PVC, purges the OSD from the cluster and removes its CRUSH host if no longer in use.
+// The caller is responsible for checking that the OSD is safe to destroy.
+func PurgeOSD(clusterdContext *clusterd.Context, clusterInfo *client.ClusterInfo, osdID int, preservePVC bool) error {
+	// Get the host where the OSD is found before the OSD is removed from the crush map
+	hostName, err := client.GetCrushHostName(clusterdContext, clusterInfo, osdID)
+	if err != nil {
+		logger.Errorf("failed to get the host where osd.%d is running. %v", osdID, err)
+	}
+
 	// Remove the OSD deployment
 	deploymentName := fmt.Sprintf("rook-ceph-osd-%d", osdID)
 	deployment, err := clusterdContext.Clientset.AppsV1().Deployments(clusterInfo.Namespace).Get(clusterInfo.Context, deploymentName, metav1.GetOptions{})
@@ -136,28 +148,35 @@ func removeOSD(clusterdContext *clusterd.Context, clusterInfo *client.ClusterInf
 		}
 	}
 
+	// The daemon was just stopped, so mark the osd down without waiting for the mons to notice
+	if err := client.OSDDown(clusterdContext, clusterInfo, osdID); err != nil {
+		logger.Errorf("failed to mark osd.%d down. %v", osdID, err)
+	}
+
 	// purge the osd
 	logger.Infof("purging osd.%d", osdID)
 	purgeOSDArgs := []string{"osd", "purge", fmt.Sprintf("osd.%d", osdID), "--force", "--yes-i-really-mean-it"}
 	_, err = client.NewCephCommand(clusterdContext, clusterInfo, purgeOSDArgs).Run()
 	if err != nil {
-		logger.Errorf("failed to purge osd.%d. %v", osdID, err)
+		return errors.Wrapf(err, "failed to purge osd.%d", osdID)
 	}
 
 	// Attempting to remove the parent host. Errors can be ignored if there are other OSDs on the same host
-	logger.Infof("attempting to remove host %q from crush map if not in use", hostName)
-	hostArgs := []string{"osd", "crush", "rm", hostName}
-	_, err = client.NewCephCommand(clusterdContext, clusterInfo, hostArgs).Run()
-	if err != nil {
-		logger.Infof("failed to remove CRUSH host %q. %v", hostName, err)
-	} else {
-		logger.Infof("removed CRUSH host %q", hostName)
+	if hostName != "" {
+		logger.Infof("attempting to remove host %q from crush map if not in use", hostName)
+		hostArgs := []string{"osd", "crush", "rm", hostName}
+		_, err = client.NewCephCommand(clusterdContext, clusterInfo, hostArgs).Run()
+		if err != nil {
+			logger.Infof("failed to remove CRUSH host %q. %v", hostName, err)
+		} else {
+			logger.Infof("removed CRUSH host %q", hostName)
+		}
 	}
 
 	// call archiveCrash to silence crash warning in ceph health if any
 	archiveCrash(clusterdContext, clusterInfo, osdID)
 
-	logger.Infof("completed removal of OSD %d", osdID)
+	return nil
 }
 
 func removeOSDPrepareJob(clusterdContext *clusterd.Context, clusterInfo *client.ClusterInfo, pvcName string) {
@@ -279,6 +298,16 @@ func DestroyOSD(context *clusterd.Context, clusterInfo *client.ClusterInfo, id i
 		osdInfo.BlockPath = diskInfo.RealPath
 	}
 
+	// an OSD created in lvm mode on a host device is prepared again on the device of its logical
+	// volume, in lvm or raw mode, so its id is kept if the device is known
+	var blockDevice string
+	if !isPVC && osdInfo.CVMode == "lvm" {
+		blockDevice, err = lvmBlockDevice(context, osdInfo.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the device of osd.%d", osdInfo.ID)
+		}
+	}
+
 	logger.Infof("zap OSD.%d path %q", osdInfo.ID, osdInfo.BlockPath)
 	output, err := context.Executor.ExecuteCommandWithCombinedOutput("stdbuf", "-oL", "ceph-volume", "lvm", "zap", osdInfo.BlockPath, "--destroy")
 	if err != nil {
@@ -288,5 +317,25 @@ func DestroyOSD(context *clusterd.Context, clusterInfo *client.ClusterInfo, id i
 	logger.Infof("%s\n", output)
 	logger.Infof("successfully zapped osd.%d path %q", osdInfo.ID, osdInfo.BlockPath)
 
+	if blockDevice != "" {
+		logger.Infof("osd.%d will be prepared again on device %q", osdInfo.ID, blockDevice)
+		osdInfo.BlockPath = blockDevice
+	}
+
 	return osdInfo, nil
 }
+
+// lvmBlockDevice returns the device holding the block logical volume of an OSD created in lvm mode,
+// or an empty string if the logical volume spans several devices
+func lvmBlockDevice(context *clusterd.Context, osdID int) (string, error) {
+	entries, err := cephVolumeLVMList(context, osdID)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.Type == "block" && len(entry.Devices) == 1 {
+			return entry.Devices[0], nil
+		}
+	}
+	return "", nil
+}
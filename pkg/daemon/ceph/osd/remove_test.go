@@ -247,3 +247,88 @@ func TestArchiveCrash(t *testing.T) {
 		assert.Empty(t, archived)
 	})
 }
+
+func TestPurgeOSD(t *testing.T) {
+	newExecutor := func(purgeErr error, commands *[]string) *exectest.MockExecutor {
+		executor := &exectest.MockExecutor{}
+		executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "osd" && args[1] == "find":
+				return `{"osd":2,"crush_location":{"host":"node-a","root":"default"}}`, nil
+			case args[0] == "osd" && args[1] == "purge":
+				*commands = append(*commands, fmt.Sprintf("%s %s %s", args[0], args[1], args[2]))
+				return "", purgeErr
+			case args[0] == "osd" && args[1] == "crush":
+				*commands = append(*commands, fmt.Sprintf("%s %s %s %s", args[0], args[1], args[2], args[3]))
+				return "", nil
+			case args[0] == "osd":
+				*commands = append(*commands, fmt.Sprintf("%s %s %s", args[0], args[1], args[2]))
+				return "", nil
+			case args[0] == "crash":
+				return "[]", nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		}
+		return executor
+	}
+
+	t.Run("purges the osd and its host", func(t *testing.T) {
+		var commands []string
+		context := &clusterd.Context{Clientset: testexec.New(t, 1), Executor: newExecutor(nil, &commands)}
+
+		err := PurgeOSD(context, client.AdminTestClusterInfo("mycluster"), 2, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"osd down 2", "osd purge osd.2", "osd crush rm node-a"}, commands)
+	})
+
+	t.Run("purge failure is returned", func(t *testing.T) {
+		var commands []string
+		context := &clusterd.Context{Clientset: testexec.New(t, 1), Executor: newExecutor(errors.New("osd.2 is not down"), &commands)}
+
+		err := PurgeOSD(context, client.AdminTestClusterInfo("mycluster"), 2, false)
+		assert.Error(t, err)
+		assert.Equal(t, []string{"osd down 2", "osd purge osd.2"}, commands)
+	})
+}
+
+func TestDestroyLVMOSD(t *testing.T) {
+	clusterInfo := client.AdminTestClusterInfo("rook-ceph")
+	clusterInfo.FSID = "4bfe8b72-5e69-4330-b6c0-4d914db8ab89"
+	lvmList := fmt.Sprintf(`{
+		"3": [
+			{"type": "block", "path": "/dev/ceph-bvg/osd-block-x", "devices": ["/dev/sdb"],
+				"tags": {"ceph.osd_fsid": "OSD-FSID", "ceph.cluster_fsid": %q, "ceph.crush_device_class": "hdd"}}
+		]
+	}`, clusterInfo.FSID)
+	var destroyed bool
+	var zapped string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case command == "stdbuf" && args[4] == "lvm" && args[5] == "list":
+				return lvmList, nil
+			case command == "stdbuf" && args[4] == "raw" && args[5] == "list":
+				return "{}", nil
+			case args[0] == "osd" && args[1] == "destroy":
+				destroyed = true
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %q %q", command, args)
+		},
+		MockExecuteCommandWithCombinedOutput: func(command string, args ...string) (string, error) {
+			if args[2] == "lvm" && args[3] == "zap" {
+				zapped = args[4]
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %q %q", command, args)
+		},
+	}
+
+	osdInfo, err := DestroyOSD(&clusterd.Context{Executor: executor}, clusterInfo, 3, false)
+	assert.NoError(t, err)
+	assert.True(t, destroyed)
+	// the logical volume is zapped, and the osd is prepared again on its device
+	assert.Equal(t, "/dev/ceph-bvg/osd-block-x", zapped)
+	assert.Equal(t, 3, osdInfo.ID)
+	assert.Equal(t, "/dev/sdb", osdInfo.BlockPath)
+}
@@ -40,6 +40,8 @@ type cvLVMListEntry struct {
 	LVUUID string  `json:"lv_uuid"` // LVM uuid of the LV; in lvm mode this is also the dm-crypt mapping name
 	Path   string  `json:"path"`    // /dev/<vg>/<lv>
 	Tags   osdTags `json:"tags"`    // ceph.* tags, including ceph.encrypted
+	// the physical devices of the LV, like /dev/sdb
+	Devices []string `json:"devices"`
 }
 
 // cephVolumeLVMList runs `ceph-volume lvm list <id>` and returns the entries reported for that osd id
@@ -75,9 +75,10 @@ type osdInfoBlock struct {
 }
 
 type osdInfo struct {
-	Name string  `json:"name"`
-	Path string  `json:"path"`
-	Tags osdTags `json:"tags"`
+	Name    string   `json:"name"`
+	Path    string   `json:"path"`
+	Tags    osdTags  `json:"tags"`
+	Devices []string `json:"devices"`
 	// "block" for bluestore
 	Type string `json:"type"`
 }
@@ -516,6 +517,10 @@ func (a *OsdAgent) allowRawMode(context *clusterd.Context) (bool, error) {
 		logger.Debugf("won't use raw mode since there is a metadata device %q", a.metadataDevice)
 		allowRawMode = false
 	}
+	if len(a.storeConfig.MetadataDevices) > 0 {
+		logger.Debugf("won't use raw mode since there are metadata devices %v", a.storeConfig.MetadataDevices)
+		allowRawMode = false
+	}
 
 	return allowRawMode, nil
 }
@@ -685,6 +690,11 @@ func (a *OsdAgent) initializeDevicesLVMMode(context *clusterd.Context, devices *
 	osdsPerDeviceCount := sanitizeOSDsPerDevice(a.storeConfig.OSDsPerDevice)
 	batchArgs := baseArgs
 
+	metadataPlan, err := a.planMetadataDevices(context, devices)
+	if err != nil {
+		return errors.Wrap(err, "failed to plan the metadata devices")
+	}
+
 	metadataDevices := make(map[string]map[string]string)
 	for name, device := range devices.Entries {
 		if device.Data == -1 {
@@ -718,26 +728,18 @@ func (a *OsdAgent) initializeDevicesLVMMode(context *clusterd.Context, devices *
 				deviceOSDCount = sanitizeOSDsPerDevice(device.Config.OSDsPerDevice)
 			}
 
-			if a.metadataDevice != "" || device.Config.MetadataDevice != "" {
+			// the metadata device of the device config wins over the planned one and the global one
+			md := device.Config.MetadataDevice
+			if md == "" {
+				md = metadataPlan[name]
+			}
+			if md == "" {
+				md = a.metadataDevice
+			}
+			if md != "" {
 				// When mixed hdd/ssd devices are given, ceph-volume configures db lv on the ssd.
 				// the device will be configured as a batch at the end of the method
-				md := a.metadataDevice
-				if device.Config.MetadataDevice != "" {
-					md = device.Config.MetadataDevice
-				}
-
-				var metadataDevice *sys.LocalDisk
-				for _, localDevice := range context.Devices {
-					if localDevice.Name == md || filepath.Join("/dev", localDevice.Name) == md {
-						logger.Infof("%s found in the desired metadata devices", md)
-						metadataDevice = localDevice
-						break
-					}
-					if strings.HasPrefix(md, "/dev/") && matchDevLinks(localDevice.DevLinks, md) {
-						metadataDevice = localDevice
-						break
-					}
-				}
+				metadataDevice := findLocalDevice(context.Devices, md)
 				if metadataDevice == nil {
 					return errors.Errorf("metadata device %s is not found", md)
 				}
@@ -1229,12 +1231,19 @@ func GetCephVolumeLVMOSDs(context *clusterd.Context, clusterInfo *client.Cluster
 			logger.Errorf("bad osd returned from ceph-volume %q", name)
 			continue
 		}
-		var osdFSID, osdDeviceClass string
+		var osdFSID, osdDeviceClass, metadataDevice string
 		for _, osd := range osdInfo {
 			if osd.Tags.ClusterFSID != cephfsid {
 				logger.Infof("skipping osd%d: %q running on a different ceph cluster %q", id, osd.Tags.OSDFSID, osd.Tags.ClusterFSID)
 				continue
 			}
+			if osd.Type == "db" {
+				// the db volume is on the metadata device of the osd, it is not the osd block
+				if len(osd.Devices) > 0 {
+					metadataDevice = osd.Devices[0]
+				}
+				continue
+			}
 			osdFSID = osd.Tags.OSDFSID
 			osdDeviceClass = osd.Tags.CrushDeviceClass
 
@@ -1263,15 +1272,16 @@ func GetCephVolumeLVMOSDs(context *clusterd.Context, clusterInfo *client.Cluster
 		}
 
 		osd := oposd.OSDInfo{
-			ID:            id,
-			Cluster:       "ceph",
-			UUID:          osdFSID,
-			BlockPath:     lvPath,
-			SkipLVRelease: skipLVRelease,
-			LVBackedPV:    lvBackedPV,
-			CVMode:        cvMode,
-			Store:         osdStore,
-			DeviceClass:   osdDeviceClass,
+			ID:             id,
+			Cluster:        "ceph",
+			UUID:           osdFSID,
+			BlockPath:      lvPath,
+			SkipLVRelease:  skipLVRelease,
+			LVBackedPV:     lvBackedPV,
+			CVMode:         cvMode,
+			Store:          osdStore,
+			DeviceClass:    osdDeviceClass,
+			MetadataDevice: metadataDevice,
 		}
 		osds = append(osds, osd)
 	}
@@ -215,6 +215,8 @@ func (c *cephStatusChecker) updateCephStatus(status *cephclient.CephStatus) {
 	} else {
 		// Update status with Ceph versions
 		cephCluster.Status.CephStatus.Versions = versions
+		// Update the upgrade in progress with the versions and the health checks
+		updateUpgradeHistory(cephCluster.Status.UpgradeHistory, versions, status, time.Now())
 	}
 
 	// Update condition
@@ -25,8 +25,10 @@ import (
 	"os/exec"
 	"path"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -52,6 +54,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -66,17 +69,21 @@ var errOsdUpgradeCephxRotationWorkaroundRestart = fmt.Errorf("cephx key rotation
 var telemetryMutex sync.Mutex
 
 type cluster struct {
-	ClusterInfo        *client.ClusterInfo
-	context            *clusterd.Context
-	Namespace          string
-	Spec               *cephv1.ClusterSpec
-	clusterMetadata    metav1.ObjectMeta
-	namespacedName     types.NamespacedName
-	mons               *mon.Cluster
-	ownerInfo          *k8sutil.OwnerInfo
-	isUpgrade          bool
-	monitoringRoutines sync.Map
-	observedGeneration int64
+	ClusterInfo     *client.ClusterInfo
+	context         *clusterd.Context
+	Namespace       string
+	Spec            *cephv1.ClusterSpec
+	clusterMetadata metav1.ObjectMeta
+	namespacedName  types.NamespacedName
+	mons            *mon.Cluster
+	ownerInfo       *k8sutil.OwnerInfo
+	isUpgrade       bool
+	// upgradePreflightOnly is true when the upgrade checks ran instead of the upgrade
+	upgradePreflightOnly bool
+	monitoringRoutines   sync.Map
+	observedGeneration   int64
+	// requeueAfter is the time after which the cluster must be reconciled again, if not zero
+	requeueAfter time.Duration
 }
 
 func newCluster(ctx context.Context, c *cephv1.CephCluster, context *clusterd.Context, ownerInfo *k8sutil.OwnerInfo, rookImage string) *cluster {
@@ -108,6 +115,15 @@ func (c *cluster) reconcileCephDaemons(rookImage string, cephVersion cephver.Cep
 	}
 	c.ClusterInfo.SetName(c.namespacedName.Name)
 
+	// The mon store must be restored from a backup or rebuilt from the OSDs before the mons are
+	// started since they cannot form quorum without it
+	if err := c.restoreMonBackupIfRequested(rookImage, cephVersion); err != nil {
+		return err
+	}
+	if err := c.recoverMonQuorumIfRequested(rookImage, cephVersion); err != nil {
+		return err
+	}
+
 	// Execute actions before the monitors are up and running, if needed during upgrades.
 	// These actions would be skipped in a new cluster.
 	log.NamespacedDebug(c.Namespace, logger, "monitors are about to reconcile, executing pre actions")
@@ -166,6 +182,7 @@ func (c *cluster) reconcileCephDaemons(rookImage string, cephVersion cephver.Cep
 	if err != nil {
 		return errors.Wrap(err, "failed to start ceph osds")
 	}
+	c.requeueAfter = osds.RequeueAfter()
 
 	// If a stretch cluster, enable the arbiter after the OSDs are created with the CRUSH map
 	if c.Spec.IsStretchCluster() {
@@ -187,6 +204,10 @@ func (c *cluster) reconcileCephDaemons(rookImage string, cephVersion cephver.Cep
 
 	log.NamespacedInfo(c.Namespace, logger, "done reconciling ceph cluster")
 
+	if err := c.recordRookUpgradeEnd(); err != nil {
+		log.NamespacedWarning(c.Namespace, logger, "failed to record the rook upgrade in the upgrade history. %v", err)
+	}
+
 	// We should be done updating by now
 	if c.isUpgrade {
 		c.printOverallCephVersion()
@@ -198,6 +219,83 @@ func (c *cluster) reconcileCephDaemons(rookImage string, cephVersion cephver.Cep
 	return nil
 }
 
+// restoreMonBackupIfRequested restores a mon backup if the user requested it with the mon backup
+// restore annotation on the CephCluster. The annotation is removed after the restore, also when it
+// failed, so that the restore is only repeated when the user sets the annotation again. The result
+// is reported in the MonBackupRestore condition.
+func (c *cluster) restoreMonBackupIfRequested(rookImage string, cephVersion cephver.CephVersion) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.ClusterInfo.Context, c.namespacedName, cephCluster); err != nil {
+		return errors.Wrapf(err, "failed to get cluster %v to check for a mon backup restore", c.namespacedName)
+	}
+	backupName, requested := cephCluster.Annotations[cephv1.RestoreMonBackupAnnotationKey]
+	if !requested {
+		return nil
+	}
+
+	restoreErr := c.mons.RestoreFromBackup(c.ClusterInfo, rookImage, cephVersion, *c.Spec, backupName)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := c.context.Client.Get(c.ClusterInfo.Context, c.namespacedName, cephCluster); err != nil {
+			return err
+		}
+		delete(cephCluster.Annotations, cephv1.RestoreMonBackupAnnotationKey)
+		return c.context.Client.Update(c.ClusterInfo.Context, cephCluster)
+	})
+	if err != nil {
+		log.NamespacedError(c.Namespace, logger, "failed to remove the %q annotation after the mon backup restore. remove it manually to avoid repeating the restore. %v", cephv1.RestoreMonBackupAnnotationKey, err)
+	}
+
+	if restoreErr != nil {
+		return errors.Wrapf(restoreErr, "failed to restore mon backup %q. set the %q annotation again to retry", backupName, cephv1.RestoreMonBackupAnnotationKey)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove the %q annotation after the mon backup restore", cephv1.RestoreMonBackupAnnotationKey)
+	}
+	return nil
+}
+
+// recoverMonQuorumIfRequested rebuilds the mon store from the OSDs if the user requested it with
+// the mon quorum recovery annotation on the CephCluster. The annotation is removed after the
+// recovery, also when it failed, so that the recovery is only repeated when the user sets the
+// annotation again. The result is reported in the MonQuorumRecovery condition.
+func (c *cluster) recoverMonQuorumIfRequested(rookImage string, cephVersion cephver.CephVersion) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.ClusterInfo.Context, c.namespacedName, cephCluster); err != nil {
+		return errors.Wrapf(err, "failed to get cluster %v to check for a mon quorum recovery", c.namespacedName)
+	}
+	value, requested := cephCluster.Annotations[cephv1.RecoverMonQuorumAnnotationKey]
+	if !requested {
+		return nil
+	}
+	if value != cephv1.RecoverMonQuorumAnnotationValue {
+		log.NamespacedWarning(c.Namespace, logger, "ignoring the %q annotation with value %q, the value must be %q to recover the mon quorum from the osds",
+			cephv1.RecoverMonQuorumAnnotationKey, value, cephv1.RecoverMonQuorumAnnotationValue)
+		return nil
+	}
+
+	recoveryErr := c.mons.RecoverQuorumFromOSDs(c.ClusterInfo, rookImage, cephVersion, *c.Spec)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := c.context.Client.Get(c.ClusterInfo.Context, c.namespacedName, cephCluster); err != nil {
+			return err
+		}
+		delete(cephCluster.Annotations, cephv1.RecoverMonQuorumAnnotationKey)
+		return c.context.Client.Update(c.ClusterInfo.Context, cephCluster)
+	})
+	if err != nil {
+		log.NamespacedError(c.Namespace, logger, "failed to remove the %q annotation after the mon quorum recovery. remove it manually to avoid repeating the recovery. %v", cephv1.RecoverMonQuorumAnnotationKey, err)
+	}
+
+	if recoveryErr != nil {
+		return errors.Wrapf(recoveryErr, "failed to recover the mon quorum from the osds. set the %q annotation again to retry", cephv1.RecoverMonQuorumAnnotationKey)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove the %q annotation after the mon quorum recovery", cephv1.RecoverMonQuorumAnnotationKey)
+	}
+	return nil
+}
+
 func (c *ClusterController) initializeCluster(cluster *cluster) error {
 	// Check if the dataDirHostPath is located in the disallowed paths list
 	cleanDataDirHostPath := path.Clean(cluster.Spec.DataDirHostPath)
@@ -601,7 +699,18 @@ func (c *cluster) postMgrStartupActions() error {
 		log.NamespacedInfo(c.Namespace, logger, "skipping cleanup of unused crush rules because %s is disabled", deleteUnusedCrushRulesSetting)
 		return nil
 	}
-	if err := client.CleanupUnusedCrushRules(c.context, c.ClusterInfo); err != nil {
+	// the rules of the CephCrushRules are kept even if no pool uses them yet
+	crushRules := &cephv1.CephCrushRuleList{}
+	err := c.context.Client.List(c.ClusterInfo.Context, crushRules, k8sClient.InNamespace(c.Namespace))
+	if err != nil {
+		log.NamespacedError(c.Namespace, logger, "failed to list crush rules, skipping cleanup of unused crush rules. %v", err)
+		return nil
+	}
+	keepRules := []string{}
+	for i := range crushRules.Items {
+		keepRules = append(keepRules, crushRules.Items[i].RuleName())
+	}
+	if err := client.CleanupUnusedCrushRules(c.context, c.ClusterInfo, keepRules); err != nil {
 		log.NamespacedError(c.Namespace, logger, "failed to clean up unused crush rules. %v", err)
 	}
 	return nil
@@ -683,15 +792,66 @@ func (c *cluster) updateConfigStoreFromCRD() error {
 	if err != nil {
 		return err
 	}
+	cephConfig := c.Spec.CephConfig
+
+	// the options that do not match the option schema of the cluster are not applied
+	fromSecretResult, err := monStore.ValidateOptions(cephConfigFromSecret)
+	var result *config.ValidationResult
+	if err == nil {
+		result, err = monStore.ValidateOptions(cephConfig)
+	}
+	if err != nil {
+		log.NamespacedWarning(c.Namespace, logger, "failed to validate the ceph config options, applying them without validation. %v", err)
+	} else {
+		cephConfigFromSecret, cephConfig = fromSecretResult.Valid, result.Valid
+		c.reportCephConfigValidation(fromSecretResult, result)
+	}
+
 	if err := monStore.SetAllMultiple(cephConfigFromSecret); err != nil {
 		return err
 	}
-	if err := monStore.SetAllMultiple(c.Spec.CephConfig); err != nil {
+	if err := monStore.SetAllMultiple(cephConfig); err != nil {
 		return err
 	}
 	return nil
 }
 
+// reportCephConfigValidation sets the CephConfigInvalid condition of the CephCluster with the
+// rejected options of the cephConfigFromSecret and the cephConfig settings
+func (c *cluster) reportCephConfigValidation(fromSecretResult, result *config.ValidationResult) {
+	rejected := []string{}
+	for _, option := range fromSecretResult.Rejected {
+		rejected = append(rejected, fmt.Sprintf("cephConfigFromSecret %s/%s: %s", option.Who, option.Option, option.Reason))
+	}
+	for _, option := range result.Rejected {
+		rejected = append(rejected, fmt.Sprintf("cephConfig %s/%s: %s", option.Who, option.Option, option.Reason))
+	}
+	restartRequired := []string{}
+	for _, option := range append(fromSecretResult.RestartRequired, result.RestartRequired...) {
+		restartRequired = append(restartRequired, option.Who+"/"+option.Option)
+	}
+
+	condition := cephv1.Condition{
+		Type:    cephv1.ConditionCephConfigInvalid,
+		Status:  v1.ConditionFalse,
+		Reason:  cephv1.CephConfigOptionsValidReason,
+		Message: "All the ceph config options are valid",
+	}
+	if len(rejected) > 0 {
+		log.NamespacedError(c.Namespace, logger, "rejected ceph config options: %s", strings.Join(rejected, "; "))
+		condition.Status = v1.ConditionTrue
+		condition.Reason = cephv1.CephConfigOptionsRejectedReason
+		condition.Message = fmt.Sprintf("Rejected ceph config options: %s", strings.Join(rejected, "; "))
+	}
+	if len(restartRequired) > 0 {
+		log.NamespacedInfo(c.Namespace, logger, "ceph config options %v take effect when the daemons restart", restartRequired)
+		condition.Message += fmt.Sprintf(". Options that take effect when the daemons restart: %s", strings.Join(restartRequired, ", "))
+	}
+	if err := controller.SetClusterCondition(c.ClusterInfo.Context, c.context, c.ClusterInfo.NamespacedName(), condition); err != nil {
+		log.NamespacedWarning(c.Namespace, logger, "failed to report the ceph config validation. %v", err)
+	}
+}
+
 func (c *cluster) reportTelemetry() {
 	// In the corner case that reconciles are started in quick succession and the telemetry
 	// hasn't had a chance to complete yet from a previous reconcile, simply allow
@@ -863,21 +1023,26 @@ func (c *cluster) configureMsgr2() error {
 }
 
 func (c *cluster) fetchCephConfigFromSecrets() (map[string]map[string]string, error) {
+	return fetchCephConfigFromSecrets(c.context, c.ClusterInfo, c.Spec.CephConfigFromSecret)
+}
+
+// fetchCephConfigFromSecrets reads the values of the cephConfigFromSecret settings from their secrets
+func fetchCephConfigFromSecrets(context *clusterd.Context, clusterInfo *client.ClusterInfo, cephConfigFromSecret map[string]map[string]v1.SecretKeySelector) (map[string]map[string]string, error) {
 	result := make(map[string]map[string]string)
 
-	for module, keys := range c.Spec.CephConfigFromSecret {
+	for module, keys := range cephConfigFromSecret {
 		result[module] = make(map[string]string)
 
 		for key, selector := range keys {
-			val, err := c.fetchSecretValue(selector)
+			val, err := fetchSecretValue(context, clusterInfo, selector)
 			if err != nil {
 				return nil, fmt.Errorf("failed to get value for key %q in module %q from secret %q: %w",
 					key, module, selector.LocalObjectReference.Name, err)
 			}
 
-			log.NamespacedDebug(c.Namespace, logger, "setting Ceph config key %q in module %q from secret %q",
+			log.NamespacedDebug(clusterInfo.Namespace, logger, "setting Ceph config key %q in module %q from secret %q",
 				key, module, selector.LocalObjectReference.Name)
-			log.NamespacedTrace(c.Namespace, logger, "setting Ceph config key %q in module %q to value %q from secret %q",
+			log.NamespacedTrace(clusterInfo.Namespace, logger, "setting Ceph config key %q in module %q to value %q from secret %q",
 				key, module, val, selector.LocalObjectReference.Name)
 			result[module][key] = val
 		}
@@ -886,9 +1051,9 @@ func (c *cluster) fetchCephConfigFromSecrets() (map[string]map[string]string, er
 	return result, nil
 }
 
-func (c *cluster) fetchSecretValue(selector v1.SecretKeySelector) (string, error) {
-	secret, err := c.context.Clientset.CoreV1().Secrets(c.ClusterInfo.Namespace).Get(
-		c.ClusterInfo.Context, selector.LocalObjectReference.Name, metav1.GetOptions{},
+func fetchSecretValue(context *clusterd.Context, clusterInfo *client.ClusterInfo, selector v1.SecretKeySelector) (string, error) {
+	secret, err := context.Clientset.CoreV1().Secrets(clusterInfo.Namespace).Get(
+		clusterInfo.Context, selector.LocalObjectReference.Name, metav1.GetOptions{},
 	)
 	if err != nil {
 		return "", fmt.Errorf("failed to get secret %q: %w", selector.LocalObjectReference.Name, err)
@@ -43,7 +43,9 @@ import (
 	"gopkg.in/ini.v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -244,7 +246,7 @@ func TestPostMgrStartupActionsCleansUnusedCrushRules(t *testing.T) {
 				return `{"pool":"mypool","pool_id":1,"crush_rule":"mypool_zone"}`, nil
 			}
 			if command == "ceph" && args[1] == "crush" && args[2] == "dump" {
-				return `{"rules":[{"rule_name":"mypool"},{"rule_name":"mypool_zone","steps":[{},{"type":"zone"}]}]}`, nil
+				return `{"rules":[{"rule_name":"mypool"},{"rule_name":"mypool_zone","steps":[{},{"type":"zone"}]},{"rule_name":"two-dc"}]}`, nil
 			}
 			if command == "ceph" && args[1] == "crush" && args[2] == "rule" && args[3] == "rm" {
 				removedRules = append(removedRules, args[4])
@@ -254,13 +256,20 @@ func TestPostMgrStartupActionsCleansUnusedCrushRules(t *testing.T) {
 		},
 	}
 
+	// the rule of a CephCrushRule is kept even if no pool uses it
+	crushRule := &cephv1.CephCrushRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "stretched", Namespace: "rook-ceph"},
+		Spec:       cephv1.CrushRuleSpec{Name: "two-dc"},
+	}
 	c := &cluster{
 		context: &clusterd.Context{
 			Clientset: testop.New(t, 1),
+			Client:    newTestCephClusterClient("rook-ceph", crushRule),
 			Executor:  executor,
 		},
-		ClusterInfo: cephclient.AdminTestClusterInfo("rook-ceph"),
+		ClusterInfo: newTestClusterInfo("rook-ceph"),
 		Spec:        &cephv1.ClusterSpec{},
+		Namespace:   "rook-ceph",
 	}
 
 	err := c.postMgrStartupActions()
@@ -281,9 +290,10 @@ func TestPostMgrStartupActionsSkipsUnusedCrushRuleCleanupWhenDisabled(t *testing
 	c := &cluster{
 		context: &clusterd.Context{
 			Clientset: testop.New(t, 1),
+			Client:    newTestCephClusterClient("rook-ceph"),
 			Executor:  executor,
 		},
-		ClusterInfo: cephclient.AdminTestClusterInfo("rook-ceph"),
+		ClusterInfo: newTestClusterInfo("rook-ceph"),
 		Spec:        &cephv1.ClusterSpec{},
 		Namespace:   "rook-ceph",
 	}
@@ -292,6 +302,91 @@ func TestPostMgrStartupActionsSkipsUnusedCrushRuleCleanupWhenDisabled(t *testing
 	assert.NoError(t, err)
 }
 
+// newTestCephClusterClient returns a fake client with the "my-cluster" CephCluster and the objects
+func newTestCephClusterClient(namespace string, objects ...runtime.Object) client.Client {
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: namespace}}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephClusterList{}, &cephv1.CephCrushRule{}, &cephv1.CephCrushRuleList{})
+	objects = append(objects, cephCluster)
+	return fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objects...).WithStatusSubresource(cephCluster).Build()
+}
+
+// newTestClusterInfo returns the cluster info of the "my-cluster" CephCluster
+func newTestClusterInfo(namespace string) *cephclient.ClusterInfo {
+	clusterInfo := cephclient.AdminTestClusterInfo(namespace)
+	clusterInfo.SetName("my-cluster")
+	return clusterInfo
+}
+
+func TestUpdateConfigStoreFromCRD(t *testing.T) {
+	schemas := map[string]string{
+		"osd_max_backfills":     `{"name":"osd_max_backfills","type":"uint","min":"","max":"","enum_values":[],"can_update_at_runtime":true}`,
+		"osd_op_queue":          `{"name":"osd_op_queue","type":"str","min":"","max":"","enum_values":["wpq","mclock_scheduler","debug_random"],"can_update_at_runtime":false}`,
+		"osd_pool_default_size": `{"name":"osd_pool_default_size","type":"uint","min":0,"max":10,"enum_values":[],"can_update_at_runtime":true}`,
+	}
+	applied := map[string]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "config" && args[1] == "ls":
+				return `["osd_max_backfills","osd_op_queue","osd_pool_default_size"]`, nil
+			case args[0] == "config" && args[1] == "help":
+				return schemas[args[2]], nil
+			case args[0] == "config" && args[1] == "assimilate-conf":
+				file, err := ini.Load(args[3])
+				require.NoError(t, err)
+				for _, section := range file.Sections() {
+					for _, key := range section.Keys() {
+						applied[section.Name()+"/"+key.Name()] = key.Value()
+					}
+				}
+			}
+			return "", nil
+		},
+	}
+	cl := newTestCephClusterClient("rook-ceph")
+	c := &cluster{
+		context: &clusterd.Context{
+			Clientset: testop.New(t, 1),
+			Client:    cl,
+			Executor:  executor,
+			ConfigDir: t.TempDir(),
+		},
+		ClusterInfo: newTestClusterInfo("rook-ceph"),
+		Namespace:   "rook-ceph",
+		Spec: &cephv1.ClusterSpec{
+			CephConfig: map[string]map[string]string{
+				"global": {"osd_pool_default_size": "11", "osd_max_backfils": "1"},
+				"osd":    {"osd max backfills": "2", "osd_op_queue": "wpq", "mgr/balancer/mode": "upmap"},
+			},
+		},
+	}
+	getCondition := func() *cephv1.Condition {
+		cephCluster := &cephv1.CephCluster{}
+		require.NoError(t, cl.Get(context.TODO(), c.ClusterInfo.NamespacedName(), cephCluster))
+		return cephv1.FindStatusCondition(cephCluster.Status.Conditions, cephv1.ConditionCephConfigInvalid)
+	}
+
+	assert.NoError(t, c.updateConfigStoreFromCRD())
+	// the rejected options are not applied
+	assert.Equal(t, map[string]string{"osd/osd max backfills": "2", "osd/osd_op_queue": "wpq", "osd/mgr/balancer/mode": "upmap"}, applied)
+	condition := getCondition()
+	require.NotNil(t, condition)
+	assert.Equal(t, v1.ConditionTrue, condition.Status)
+	assert.Equal(t, cephv1.CephConfigOptionsRejectedReason, condition.Reason)
+	assert.Equal(t, "Rejected ceph config options: cephConfig global/osd_max_backfils: unknown option; "+
+		"cephConfig global/osd_pool_default_size: value must be at most 10. "+
+		"Options that take effect when the daemons restart: osd/osd_op_queue", condition.Message)
+
+	// the options are fixed
+	c.Spec.CephConfig["global"] = map[string]string{"osd_pool_default_size": "3"}
+	assert.NoError(t, c.updateConfigStoreFromCRD())
+	assert.Equal(t, "3", applied["global/osd_pool_default_size"])
+	condition = getCondition()
+	assert.Equal(t, v1.ConditionFalse, condition.Status)
+	assert.Equal(t, cephv1.CephConfigOptionsValidReason, condition.Reason)
+}
+
 func TestTelemetry(t *testing.T) {
 	var expectedSettings map[string]string
 	clientset := testop.New(t, 3)
@@ -832,3 +927,62 @@ func Test_initClusterCephxStatus(t *testing.T) {
 		assert.Equal(t, cephv1.CephxStatus{}, cluster.Status.Cephx.Mgr) // no status means no update
 	})
 }
+
+func TestRecoverMonQuorumIfRequested(t *testing.T) {
+	ns := "rook-ceph"
+	newCluster := func(annotations map[string]string) *cluster {
+		cephCluster := &cephv1.CephCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: ns, Namespace: ns, Annotations: annotations},
+		}
+		s := scheme.Scheme
+		s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+		clusterdContext := &clusterd.Context{
+			Clientset: testop.New(t, 3),
+			Executor:  &exectest.MockExecutor{},
+			Client:    fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(cephCluster).WithStatusSubresource(cephCluster).Build(),
+			ConfigDir: t.TempDir(),
+		}
+		ownerInfo := cephclient.NewMinimumOwnerInfoWithOwnerRef()
+		clusterInfo := cephclient.AdminTestClusterInfo(ns)
+		clusterInfo.SetName(ns)
+		return &cluster{
+			ClusterInfo:    clusterInfo,
+			Namespace:      ns,
+			Spec:           &cephCluster.Spec,
+			context:        clusterdContext,
+			namespacedName: types.NamespacedName{Namespace: ns, Name: ns},
+			ownerInfo:      ownerInfo,
+			mons:           mon.New(context.TODO(), clusterdContext, ns, cephCluster.Spec, ownerInfo),
+		}
+	}
+	getCluster := func(c *cluster) *cephv1.CephCluster {
+		cephCluster := &cephv1.CephCluster{}
+		assert.NoError(t, c.context.Client.Get(context.TODO(), c.namespacedName, cephCluster))
+		return cephCluster
+	}
+
+	t.Run("no annotation", func(t *testing.T) {
+		c := newCluster(nil)
+		assert.NoError(t, c.recoverMonQuorumIfRequested("rook/ceph:myversion", cephver.Squid))
+	})
+
+	t.Run("annotation without the confirmation is ignored", func(t *testing.T) {
+		c := newCluster(map[string]string{cephv1.RecoverMonQuorumAnnotationKey: "yes"})
+		assert.NoError(t, c.recoverMonQuorumIfRequested("rook/ceph:myversion", cephver.Squid))
+		assert.Equal(t, "yes", getCluster(c).Annotations[cephv1.RecoverMonQuorumAnnotationKey])
+	})
+
+	t.Run("annotation is removed when the recovery fails", func(t *testing.T) {
+		c := newCluster(map[string]string{cephv1.RecoverMonQuorumAnnotationKey: cephv1.RecoverMonQuorumAnnotationValue})
+		// there are no mon deployments to recover
+		err := c.recoverMonQuorumIfRequested("rook/ceph:myversion", cephver.Squid)
+		assert.ErrorContains(t, err, "failed to recover the mon quorum")
+
+		cephCluster := getCluster(c)
+		_, ok := cephCluster.Annotations[cephv1.RecoverMonQuorumAnnotationKey]
+		assert.False(t, ok)
+		condition := cephCluster.Status.Conditions[0]
+		assert.Equal(t, cephv1.ConditionMonQuorumRecovery, condition.Type)
+		assert.Equal(t, cephv1.MonQuorumRecoveryFailedReason, condition.Reason)
+	})
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/util/log"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// defaultConfigDriftCheckInterval is the interval to check the drift of the central ceph config
+var defaultConfigDriftCheckInterval = 10 * time.Minute
+
+// maxDriftedOptionsInMessage is the number of drifted options listed in the condition message
+const maxDriftedOptionsInMessage = 5
+
+// configDriftChecker periodically compares the central ceph config with the ceph config of the
+// CephCluster and the rook defaults
+type configDriftChecker struct {
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+	interval    time.Duration
+}
+
+// newConfigDriftChecker creates a new configDriftChecker object
+func newConfigDriftChecker(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec) *configDriftChecker {
+	c := &configDriftChecker{
+		context:     context,
+		clusterInfo: clusterInfo,
+		interval:    defaultConfigDriftCheckInterval,
+	}
+	if clusterSpec.CephConfigDrift.Interval != nil {
+		c.interval = clusterSpec.CephConfigDrift.Interval.Duration
+	}
+	return c
+}
+
+// checkConfigDrift periodically checks the drift of the central ceph config
+func (c *configDriftChecker) checkConfigDrift(monitoringRoutines *sync.Map, daemon string) {
+	log.NamespacedInfo(c.clusterInfo.Namespace, logger, "ceph config drift check interval is %s", c.interval.String())
+	// the first check waits for the interval so that the cluster reconcile applies the ceph config first
+	for {
+		// We must perform this check otherwise the case will check an index that does not exist anymore and
+		// we will get an invalid pointer error and the go routine will panic
+		v, ok := monitoringRoutines.Load(daemon)
+		if !ok {
+			log.NamespacedInfo(c.clusterInfo.Namespace, logger, "ceph cluster %q has been deleted. stopping ceph config drift check", c.clusterInfo.Namespace)
+			return
+		}
+		health := v.(*opcontroller.ClusterHealth)
+		select {
+		case <-health.InternalCtx.Done():
+			log.NamespacedInfo(c.clusterInfo.Namespace, logger, "stopping ceph config drift check")
+			monitoringRoutines.Delete(daemon)
+			return
+
+		case <-time.After(c.interval):
+			if err := c.checkDrift(); err != nil {
+				if strings.Contains(err.Error(), opcontroller.UninitializedCephConfigError) {
+					log.NamespacedInfo(c.clusterInfo.Namespace, logger, "skipping ceph config drift check since operator is still initializing")
+					continue
+				}
+				log.NamespacedError(c.clusterInfo.Namespace, logger, "failed to check the drift of the ceph config. %v", err)
+			}
+		}
+	}
+}
+
+// checkDrift compares the central ceph config with the expected config, reverts the drifted
+// options if the drift policy is "Enforce", and reports the drift in the CephCluster status
+func (c *configDriftChecker) checkDrift() error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			log.NamespacedDebug(c.clusterInfo.Namespace, logger, "CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get cluster %v", c.clusterInfo.NamespacedName())
+	}
+	spec := cephCluster.Spec
+
+	cephConfigFromSecret, err := fetchCephConfigFromSecrets(c.context, c.clusterInfo, spec.CephConfigFromSecret)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the ceph config from secrets")
+	}
+	expected := config.MergeOptions(config.ClusterDefaultConfigs(c.clusterInfo.CephVersion, spec), cephConfigFromSecret, spec.CephConfig)
+
+	monStore := config.GetMonStore(c.context, c.clusterInfo)
+	// the options rejected by the validation are never applied, so they are not expected
+	if result, err := monStore.ValidateOptions(expected); err != nil {
+		log.NamespacedDebug(c.clusterInfo.Namespace, logger, "failed to validate the expected ceph config options. %v", err)
+	} else {
+		expected = result.Valid
+	}
+	// the recovery options are set by the recovery schedule while a recovery profile is applied
+	if cephCluster.Status.Recovery != nil {
+		expected = withoutRecoveryOptions(expected)
+	}
+	actual, err := monStore.GetAll()
+	if err != nil {
+		return err
+	}
+
+	drift := config.FindDrift(expected, actual, c.optionTypes(monStore))
+	reverted := false
+	if len(drift) > 0 {
+		log.NamespacedWarning(c.clusterInfo.Namespace, logger, "%d ceph config options differ from the CephCluster: %s", len(drift), driftedOptionNames(drift, len(drift)))
+		if spec.CephConfigDrift.DriftPolicy == cephv1.CephConfigDriftEnforce {
+			if err := revertDrift(monStore, drift); err != nil {
+				log.NamespacedError(c.clusterInfo.Namespace, logger, "failed to revert the drifted ceph config options. %v", err)
+			} else {
+				log.NamespacedInfo(c.clusterInfo.Namespace, logger, "reverted the drifted ceph config options")
+				reverted = true
+			}
+		}
+	}
+
+	status := &cephv1.CephConfigDriftStatus{LastChecked: &metav1.Time{Time: time.Now()}}
+	for _, option := range drift {
+		driftedOption := cephv1.CephConfigDriftedOption{
+			Who:      option.Who,
+			Option:   option.Option.Option,
+			Expected: option.Value,
+			Actual:   option.Actual,
+			Missing:  option.Missing,
+			Reverted: reverted,
+		}
+		// do not expose the values from secrets
+		if config.HasOption(cephConfigFromSecret, option.Who, option.Option.Option) && !config.HasOption(spec.CephConfig, option.Who, option.Option.Option) {
+			driftedOption.Expected = ""
+			driftedOption.Actual = ""
+		}
+		status.Options = append(status.Options, driftedOption)
+	}
+	return c.updateStatus(status, driftCondition(drift, reverted))
+}
+
+// optionTypes returns a function that returns the type of a config option from the option schema,
+// or an empty type if the schema is not known
+func (c *configDriftChecker) optionTypes(monStore *config.MonStore) func(option string) string {
+	types := map[string]string{}
+	return func(option string) string {
+		// the options of the mgr modules like "mgr/dashboard/ssl" have no schema in the mons
+		if strings.Contains(option, "/") {
+			return ""
+		}
+		if optionType, ok := types[option]; ok {
+			return optionType
+		}
+		schema, err := monStore.GetOptionSchema(option)
+		if err != nil {
+			log.NamespacedDebug(c.clusterInfo.Namespace, logger, "comparing the values of config option %q as strings. %v", option, err)
+			types[option] = ""
+			return ""
+		}
+		types[option] = schema.Type
+		return schema.Type
+	}
+}
+
+// revertDrift sets the drifted options back to their expected values
+func revertDrift(monStore *config.MonStore, drift []config.DriftedOption) error {
+	settings := config.CephConfigOptionsMap{}
+	for _, option := range drift {
+		if _, ok := settings[option.Who]; !ok {
+			settings[option.Who] = map[string]string{}
+		}
+		settings[option.Who][option.Option.Option] = option.Value
+	}
+	return monStore.SetAllMultiple(settings)
+}
+
+// driftCondition returns the CephConfigDrift condition for the result of a drift check
+func driftCondition(drift []config.DriftedOption, reverted bool) cephv1.Condition {
+	switch {
+	case len(drift) == 0:
+		return cephv1.Condition{
+			Type:    cephv1.ConditionCephConfigDrift,
+			Status:  v1.ConditionFalse,
+			Reason:  cephv1.CephConfigInSyncReason,
+			Message: "The central ceph config matches the CephCluster",
+		}
+	case reverted:
+		return cephv1.Condition{
+			Type:    cephv1.ConditionCephConfigDrift,
+			Status:  v1.ConditionFalse,
+			Reason:  cephv1.CephConfigDriftRevertedReason,
+			Message: fmt.Sprintf("Reverted %d drifted ceph config options: %s", len(drift), driftedOptionNames(drift, maxDriftedOptionsInMessage)),
+		}
+	default:
+		return cephv1.Condition{
+			Type:    cephv1.ConditionCephConfigDrift,
+			Status:  v1.ConditionTrue,
+			Reason:  cephv1.CephConfigDriftDetectedReason,
+			Message: fmt.Sprintf("%d ceph config options differ from the CephCluster: %s", len(drift), driftedOptionNames(drift, maxDriftedOptionsInMessage)),
+		}
+	}
+}
+
+// driftedOptionNames returns the first drifted options as "<who>/<option>"
+func driftedOptionNames(drift []config.DriftedOption, limit int) string {
+	names := []string{}
+	for i, option := range drift {
+		if i == limit {
+			names = append(names, "...")
+			break
+		}
+		names = append(names, option.Who+"/"+option.Option.Option)
+	}
+	return strings.Join(names, ", ")
+}
+
+// updateStatus sets the ceph config drift status and condition of the CephCluster
+func (c *configDriftChecker) updateStatus(status *cephv1.CephConfigDriftStatus, condition cephv1.Condition) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cephCluster := &cephv1.CephCluster{}
+		if err := c.context.Client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cephCluster); err != nil {
+			return errors.Wrapf(err, "failed to get cluster %v", c.clusterInfo.NamespacedName())
+		}
+		cephCluster.Status.CephConfigDrift = status
+		cephv1.SetStatusCondition(&cephCluster.Status.Conditions, condition)
+		return reporting.UpdateStatus(c.context.Client, cephCluster)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update the ceph config drift status")
+	}
+	return nil
+}
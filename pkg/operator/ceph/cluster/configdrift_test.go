@@ -0,0 +1,195 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckConfigDrift(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+
+	// the central config as a list of [section, name, value]
+	centralConfig := [][3]string{}
+	setOption := func(section, name, value string) {
+		for i, option := range centralConfig {
+			if option[0] == section && option[1] == name {
+				centralConfig[i][2] = value
+				return
+			}
+		}
+		centralConfig = append(centralConfig, [3]string{section, name, value})
+	}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "config" && args[1] == "dump":
+				dump := []map[string]string{}
+				for _, option := range centralConfig {
+					dump = append(dump, map[string]string{"section": option[0], "name": option[1], "value": option[2], "mask": ""})
+				}
+				out, err := json.Marshal(dump)
+				return string(out), err
+			case args[0] == "config" && args[1] == "help":
+				optionTypes := map[string]string{"log_to_file": "bool", "osd_max_backfills": "uint"}
+				out, err := json.Marshal(map[string]string{"name": args[2], "type": optionTypes[args[2]]})
+				return string(out), err
+			case args[0] == "config" && args[1] == "assimilate-conf":
+				input, err := os.ReadFile(args[3])
+				require.NoError(t, err)
+				file, err := ini.Load(input)
+				require.NoError(t, err)
+				for _, section := range file.Sections() {
+					for _, key := range section.Keys() {
+						setOption(section.Name(), key.Name(), key.Value())
+					}
+				}
+			}
+			return "", nil
+		},
+	}
+
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: namespace},
+		Spec: cephv1.ClusterSpec{
+			CephConfig: map[string]map[string]string{
+				"osd": {"osd_max_backfills": "1"},
+			},
+			CephConfigFromSecret: map[string]map[string]v1.SecretKeySelector{
+				"mgr": {"mgr/dashboard/GRAFANA_API_PASSWORD": {LocalObjectReference: v1.LocalObjectReference{Name: "grafana"}, Key: "password"}},
+			},
+		},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(cephCluster).WithStatusSubresource(cephCluster).Build()
+	clientset := testop.New(t, 1)
+	_, err := clientset.CoreV1().Secrets(namespace).Create(ctx, &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "grafana", Namespace: namespace},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	clusterInfo := cephclient.AdminTestClusterInfo(namespace)
+	clusterInfo.SetName("my-cluster")
+	checker := newConfigDriftChecker(&clusterd.Context{Client: cl, Clientset: clientset, Executor: executor, ConfigDir: t.TempDir()}, clusterInfo, &cephCluster.Spec)
+	assert.Equal(t, defaultConfigDriftCheckInterval, checker.interval)
+
+	getStatus := func() (*cephv1.CephConfigDriftStatus, *cephv1.Condition) {
+		cluster := &cephv1.CephCluster{}
+		require.NoError(t, cl.Get(ctx, clusterInfo.NamespacedName(), cluster))
+		return cluster.Status.CephConfigDrift, cephv1.FindStatusCondition(cluster.Status.Conditions, cephv1.ConditionCephConfigDrift)
+	}
+	updateSpec := func(update func(spec *cephv1.ClusterSpec)) {
+		cluster := &cephv1.CephCluster{}
+		require.NoError(t, cl.Get(ctx, clusterInfo.NamespacedName(), cluster))
+		update(&cluster.Spec)
+		require.NoError(t, cl.Update(ctx, cluster))
+	}
+
+	// the config applied by the operator
+	setOption("global", "mon_allow_pool_delete", "true")
+	setOption("global", "mon_cluster_log_file", "")
+	setOption("global", "mon_allow_pool_size_one", "true")
+	setOption("global", "log_to_file", "false")
+	setOption("osd", "osd_max_backfills", "1")
+	setOption("mgr", "mgr/dashboard/GRAFANA_API_PASSWORD", "secret")
+	// options set by ceph are ignored
+	setOption("mon", "auth_allow_insecure_global_id_reclaim", "false")
+
+	t.Run("in sync", func(t *testing.T) {
+		assert.NoError(t, checker.checkDrift())
+		status, condition := getStatus()
+		require.NotNil(t, status)
+		assert.NotNil(t, status.LastChecked)
+		assert.Empty(t, status.Options)
+		require.NotNil(t, condition)
+		assert.Equal(t, v1.ConditionFalse, condition.Status)
+		assert.Equal(t, cephv1.CephConfigInSyncReason, condition.Reason)
+	})
+
+	t.Run("values normalized by ceph", func(t *testing.T) {
+		setOption("global", "log_to_file", "0")
+		defer setOption("global", "log_to_file", "false")
+
+		assert.NoError(t, checker.checkDrift())
+		status, _ := getStatus()
+		assert.Empty(t, status.Options)
+	})
+
+	t.Run("detect the drift", func(t *testing.T) {
+		setOption("osd", "osd_max_backfills", "16")
+		setOption("mgr", "mgr/dashboard/GRAFANA_API_PASSWORD", "changed")
+		centralConfig = centralConfig[1:]
+
+		assert.NoError(t, checker.checkDrift())
+		status, condition := getStatus()
+		assert.Equal(t, []cephv1.CephConfigDriftedOption{
+			{Who: "global", Option: "mon allow pool delete", Expected: "true", Missing: true},
+			{Who: "mgr", Option: "mgr/dashboard/GRAFANA_API_PASSWORD"},
+			{Who: "osd", Option: "osd_max_backfills", Expected: "1", Actual: "16"},
+		}, status.Options)
+		assert.Equal(t, v1.ConditionTrue, condition.Status)
+		assert.Equal(t, cephv1.CephConfigDriftDetectedReason, condition.Reason)
+		assert.Equal(t, "3 ceph config options differ from the CephCluster: global/mon allow pool delete, mgr/mgr/dashboard/GRAFANA_API_PASSWORD, osd/osd_max_backfills", condition.Message)
+		// the central config is not changed
+		assert.Contains(t, centralConfig, [3]string{"osd", "osd_max_backfills", "16"})
+	})
+
+	t.Run("enforce the config", func(t *testing.T) {
+		updateSpec(func(spec *cephv1.ClusterSpec) { spec.CephConfigDrift.DriftPolicy = cephv1.CephConfigDriftEnforce })
+
+		assert.NoError(t, checker.checkDrift())
+		status, condition := getStatus()
+		require.Len(t, status.Options, 3)
+		assert.True(t, status.Options[0].Reverted)
+		assert.Equal(t, v1.ConditionFalse, condition.Status)
+		assert.Equal(t, cephv1.CephConfigDriftRevertedReason, condition.Reason)
+		assert.Contains(t, centralConfig, [3]string{"osd", "osd_max_backfills", "1"})
+		assert.Contains(t, centralConfig, [3]string{"global", "mon allow pool delete", "true"})
+		assert.Contains(t, centralConfig, [3]string{"mgr", "mgr/dashboard/GRAFANA_API_PASSWORD", "secret"})
+
+		assert.NoError(t, checker.checkDrift())
+		status, condition = getStatus()
+		assert.Empty(t, status.Options)
+		assert.Equal(t, cephv1.CephConfigInSyncReason, condition.Reason)
+	})
+}
+
+func TestIsConfigDriftCheckEnabled(t *testing.T) {
+	assert.True(t, isMonitoringEnabled("configdrift", &cephv1.ClusterSpec{}))
+	assert.False(t, isMonitoringEnabled("configdrift", &cephv1.ClusterSpec{CephConfigDrift: cephv1.CephConfigDriftSpec{Disabled: true}}))
+	assert.False(t, isMonitoringEnabled("configdrift", &cephv1.ClusterSpec{External: cephv1.ExternalSpec{Enable: true}}))
+}
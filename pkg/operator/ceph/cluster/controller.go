@@ -141,8 +141,12 @@ func watchOwnedCoreObject[T client.Object](c controller.Controller, mgr manager.
 	)
 }
 
-// isSecretRefFromCluster checks if the secret name is referenced in the CephCluster cephConfigFromSecret field.
+// isSecretRefFromCluster checks if the secret name is referenced in the CephCluster cephConfigFromSecret
+// or dashboard sslCertificateRef fields.
 func isSecretRefFromCluster(secretName string, clusterSpec cephv1.ClusterSpec) bool {
+	if clusterSpec.Dashboard.SSLCertificateRef != "" && secretName == clusterSpec.Dashboard.SSLCertificateRef {
+		return true
+	}
 	for _, secretKeyMap := range clusterSpec.CephConfigFromSecret {
 		for _, keySelector := range secretKeyMap {
 			if secretName == keySelector.Name {
@@ -224,7 +228,7 @@ func add(opManagerContext context.Context, mgr manager.Manager, r reconcile.Reco
 		return err
 	}
 
-	// Watch for changes to secrets referenced in ClusterSpec.CephConfigFromSecret
+	// Watch for changes to secrets referenced in ClusterSpec.CephConfigFromSecret and the dashboard cert
 	err = c.Watch(
 		source.Kind(
 			mgr.GetCache(),
@@ -239,7 +243,7 @@ func add(opManagerContext context.Context, mgr manager.Manager, r reconcile.Reco
 					requests := []reconcile.Request{}
 					for _, clusterResource := range clusterList.Items {
 						// No more than 1 cluster can exist in a namespace, and all secrets referenced by a
-						// CephConfigFromSecret must be in the same namespace as the cluster. We only need to
+						// CephConfigFromSecret or the dashboard must be in the same namespace as the cluster. We only need to
 						// trigger a reconcile once, so we only need to find one match per cluster.
 						if secret.GetNamespace() == clusterResource.GetNamespace() && isSecretRefFromCluster(secret.GetName(), clusterResource.Spec) {
 							requests = append(requests, reconcile.Request{
@@ -392,6 +396,14 @@ func (r *ReconcileCephCluster) reconcile(request reconcile.Request) (reconcile.R
 		return reconcile.Result{}, *cephCluster, errors.Wrapf(err, "failed to reconcile cluster %q", cephCluster.Name)
 	}
 
+	// Requeue if the daemons wait for the cluster, such as during the soak of a canary upgrade of the OSDs
+	if rawCluster, ok := r.clusterController.clusterMap.Load(cephCluster.Namespace); ok {
+		if requeueAfter := rawCluster.(*cluster).requeueAfter; requeueAfter > 0 {
+			log.NamespacedDebug(request.Namespace, logger, "requeueing the reconcile of CephCluster %q in %s", cephCluster.Name, requeueAfter.String())
+			return reconcile.Result{RequeueAfter: requeueAfter}, *cephCluster, nil
+		}
+	}
+
 	// Return and do not requeue
 	return reconcile.Result{}, *cephCluster, nil
 }
@@ -481,6 +493,7 @@ func (c *ClusterController) reconcileCephCluster(clusterObj *cephv1.CephCluster,
 	}
 	// updating observedGeneration in cluster if it's not the first reconcile
 	clustr.observedGeneration = clusterObj.ObjectMeta.Generation
+	clustr.requeueAfter = 0
 
 	// Pass down the client to interact with Kubernetes objects
 	// This will be used later down by spec code to create objects like deployment, services etc
@@ -266,3 +266,18 @@ func TestReconcileSkipsWhenSkipReconcileLabelSet(t *testing.T) {
 	assert.Contains(t, event, "ReconcileSkipped")
 	assert.Contains(t, event, cephv1.SkipReconcileLabelKey)
 }
+
+func TestIsSecretRefFromCluster(t *testing.T) {
+	spec := cephv1.ClusterSpec{
+		CephConfigFromSecret: map[string]map[string]corev1.SecretKeySelector{
+			"global": {"osd_max_backfills": {LocalObjectReference: corev1.LocalObjectReference{Name: "config-secret"}}},
+		},
+	}
+	assert.True(t, isSecretRefFromCluster("config-secret", spec))
+	assert.False(t, isSecretRefFromCluster("dashboard-cert", spec))
+	assert.False(t, isSecretRefFromCluster("", spec))
+
+	spec.Dashboard.SSLCertificateRef = "dashboard-cert"
+	assert.True(t, isSecretRefFromCluster("dashboard-cert", spec))
+	assert.False(t, isSecretRefFromCluster("other", spec))
+}
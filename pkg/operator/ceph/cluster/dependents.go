@@ -47,6 +47,10 @@ var cephClusterDependentListKinds []string = []string{
 	"CephBucketNotification",
 	"CephFilesystemSubVolumeGroup",
 	"CephBlockPoolRadosNamespace",
+	"CephMgrModuleList",
+	"CephDashboardUserList",
+	"CephErasureCodeProfileList",
+	"CephCrushRuleList",
 }
 
 // CephClusterDependents returns a DependentList of dependents of a CephCluster in the namespace.
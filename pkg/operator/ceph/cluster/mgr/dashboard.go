@@ -20,15 +20,22 @@ package mgr
 import (
 	"context"
 	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/util"
 	"github.com/rook/rook/pkg/util/exec"
@@ -36,6 +43,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
 )
 
 const (
@@ -48,6 +56,10 @@ const (
 	passwordLength        = 20
 	passwordKeyName       = "password"
 	invalidArgErrorCode   = int(syscall.EINVAL)
+	dashboardCertKey      = "mgr/dashboard/crt"
+	dashboardCertKeyKey   = "mgr/dashboard/key"
+	caCertKeyName         = "ca.crt"
+	selfSignedCertSource  = "self-signed"
 )
 
 var (
@@ -90,9 +102,13 @@ func (c *Cluster) configureDashboardModules() error {
 			return errors.Wrap(err, "failed to enable mgr dashboard module")
 		}
 	} else {
+		if err := c.removeDashboardAccessControl(); err != nil {
+			return errors.Wrap(err, "failed to remove the dashboard access control before disabling the dashboard")
+		}
 		if err := client.MgrDisableModule(c.context, c.clusterInfo, dashboardModuleName); err != nil {
 			log.NamespacedError(c.clusterInfo.Namespace, logger, "failed to disable mgr dashboard module. %v", err)
 		}
+		c.clearDashboardCert()
 		return nil
 	}
 
@@ -101,6 +117,10 @@ func (c *Cluster) configureDashboardModules() error {
 		return errors.Wrap(err, "failed to initialize dashboard")
 	}
 
+	if err := c.configureDashboardAccessControl(); err != nil {
+		return errors.Wrap(err, "failed to configure dashboard access control")
+	}
+
 	configChanged, err := c.configureDashboardModuleSettings()
 	if err != nil {
 		return err
@@ -215,13 +235,13 @@ func (c *Cluster) initializeSecureDashboard() (bool, error) {
 	}
 
 	if c.spec.Dashboard.SSL {
-		alreadyCreated, err := c.createSelfSignedCert()
+		certChanged, err := c.configureDashboardCert()
 		if err != nil {
-			return restartNeeded, errors.Wrap(err, "failed to create a self signed cert for the ceph dashboard")
-		}
-		if !alreadyCreated {
-			restartNeeded = true
+			return restartNeeded, err
 		}
+		restartNeeded = certChanged
+	} else {
+		c.clearDashboardCert()
 	}
 
 	if err := c.setLoginCredentials(password); err != nil {
@@ -231,9 +251,215 @@ func (c *Cluster) initializeSecureDashboard() (bool, error) {
 	return restartNeeded, nil
 }
 
+// configureDashboardCert applies the dashboard certificate from the secret of the sslCertificateRef,
+// or creates a self-signed certificate. Returns true if the certificate has changed.
+func (c *Cluster) configureDashboardCert() (bool, error) {
+	var certChanged bool
+	source := selfSignedCertSource
+	if c.spec.Dashboard.SSLCertificateRef != "" {
+		source = c.spec.Dashboard.SSLCertificateRef
+		changed, err := c.applyDashboardCertFromSecret(c.spec.Dashboard.SSLCertificateRef)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to apply the ceph dashboard cert from secret %q", c.spec.Dashboard.SSLCertificateRef)
+		}
+		certChanged = changed
+	} else {
+		// the cert from a secret must be removed first, or it would be kept as the self-signed cert
+		if err := c.removeDashboardCertFromSecret(); err != nil {
+			return false, err
+		}
+		alreadyCreated, err := c.createSelfSignedCert()
+		if err != nil {
+			return false, errors.Wrap(err, "failed to create a self signed cert for the ceph dashboard")
+		}
+		certChanged = !alreadyCreated
+	}
+
+	var expiry *metav1.Time
+	cert, err := client.NewCephCommand(c.context, c.clusterInfo, []string{"config-key", "get", dashboardCertKey}).RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		log.NamespacedWarning(c.clusterInfo.Namespace, logger, "failed to get the dashboard cert to report its expiry. %v", err)
+	} else if expiry, err = certificateExpiry(cert); err != nil {
+		log.NamespacedWarning(c.clusterInfo.Namespace, logger, "failed to read the dashboard cert expiry. %v", err)
+	}
+	c.updateDashboardStatus(func(status *cephv1.DashboardStatus) {
+		status.CertificateSource = source
+		status.CertificateExpiry = expiry
+	})
+
+	return certChanged, nil
+}
+
+// applyDashboardCertFromSecret sets the dashboard certificate and key from the secret if the
+// certificate in the secret is not already applied. Returns true if the certificate has changed.
+func (c *Cluster) applyDashboardCertFromSecret(secretName string) (bool, error) {
+	secret, err := c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Get(c.clusterInfo.Context, secretName, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get secret")
+	}
+	cert, ok := secret.Data[v1.TLSCertKey]
+	if !ok {
+		return false, errors.Errorf("%q not found in secret", v1.TLSCertKey)
+	}
+	key, ok := secret.Data[v1.TLSPrivateKeyKey]
+	if !ok {
+		return false, errors.Errorf("%q not found in secret", v1.TLSPrivateKeyKey)
+	}
+	if _, err := tls.X509KeyPair(cert, key); err != nil {
+		return false, errors.Wrap(err, "invalid certificate and key")
+	}
+
+	// the dashboard serves the CA certificate as part of the chain
+	chain := strings.TrimSpace(string(cert))
+	if ca := strings.TrimSpace(string(secret.Data[caCertKeyName])); ca != "" && !strings.Contains(chain, ca) {
+		chain = chain + "\n" + ca
+	}
+	chain += "\n"
+
+	currentCert, err := client.NewCephCommand(c.context, c.clusterInfo, []string{"config-key", "get", dashboardCertKey}).RunWithTimeout(exec.CephCommandsTimeout)
+	if err == nil && strings.TrimSpace(string(currentCert)) == strings.TrimSpace(chain) {
+		log.NamespacedDebug(c.clusterInfo.Namespace, logger, "dashboard cert from secret %q is already applied", secretName)
+		return false, nil
+	}
+
+	log.NamespacedInfo(c.clusterInfo.Namespace, logger, "applying the dashboard cert from secret %q", secretName)
+	// > ceph dashboard set-ssl-certificate -i <path-to-cert-file>
+	if err := c.setDashboardCertFile("set-ssl-certificate", chain); err != nil {
+		return false, err
+	}
+	// > ceph dashboard set-ssl-certificate-key -i <path-to-key-file>
+	if err := c.setDashboardCertFile("set-ssl-certificate-key", string(key)); err != nil {
+		return false, err
+	}
+	log.NamespacedInfo(c.clusterInfo.Namespace, logger, "dashboard cert from secret %q applied", secretName)
+	return true, nil
+}
+
+func (c *Cluster) setDashboardCertFile(command, content string) error {
+	file, err := util.CreateTempFile(content)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create a temporary file for %q", command)
+	}
+	defer func() {
+		if err := os.Remove(file.Name()); err != nil {
+			log.NamespacedError(c.clusterInfo.Namespace, logger, "failed to clean up dashboard cert file %q. %v", file.Name(), err)
+		}
+	}()
+
+	args := []string{"dashboard", command, "-i", file.Name()}
+	_, err = client.ExecuteCephCommandWithRetry(func() (string, []byte, error) {
+		output, err := client.NewCephCommand(c.context, c.clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout)
+		return command, output, err
+	}, 5, dashboardInitWaitTime)
+	if err != nil {
+		return errors.Wrapf(err, "failed to run %q", command)
+	}
+	return nil
+}
+
+// certificateExpiry returns the expiry of the first certificate of the PEM data
+func certificateExpiry(data []byte) (*metav1.Time, error) {
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse certificate")
+		}
+		return &metav1.Time{Time: cert.NotAfter}, nil
+	}
+	return nil, errors.New("no certificate found")
+}
+
+// getDashboardStatus returns the dashboard status of the cluster
+func (c *Cluster) getDashboardStatus() (*cephv1.DashboardStatus, error) {
+	cluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cluster); err != nil {
+		return nil, errors.Wrapf(err, "failed to get cluster %v", c.clusterInfo.NamespacedName())
+	}
+	if cluster.Status.Dashboard == nil {
+		return &cephv1.DashboardStatus{}, nil
+	}
+	return cluster.Status.Dashboard, nil
+}
+
+// removeDashboardCertFromSecret removes the dashboard certificate and key if they were applied from
+// a secret, as reported in the dashboard status
+func (c *Cluster) removeDashboardCertFromSecret() error {
+	status, err := c.getDashboardStatus()
+	if err != nil {
+		return err
+	}
+	if status.CertificateSource == "" || status.CertificateSource == selfSignedCertSource {
+		return nil
+	}
+
+	log.NamespacedInfo(c.clusterInfo.Namespace, logger, "removing the dashboard cert from secret %q", status.CertificateSource)
+	for _, key := range []string{dashboardCertKey, dashboardCertKeyKey} {
+		args := []string{"config-key", "rm", key}
+		if _, err := client.NewCephCommand(c.context, c.clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout); err != nil {
+			return errors.Wrapf(err, "failed to remove the dashboard cert from secret %q", status.CertificateSource)
+		}
+	}
+	return nil
+}
+
+// clearDashboardCert removes the dashboard certificate from a secret and the certificate from the
+// dashboard status when the dashboard no longer serves a certificate. The status is kept if the
+// certificate could not be removed so that the removal is retried.
+func (c *Cluster) clearDashboardCert() {
+	if err := c.removeDashboardCertFromSecret(); err != nil {
+		log.NamespacedWarning(c.clusterInfo.Namespace, logger, "%v", err)
+		return
+	}
+	c.updateDashboardStatus(func(status *cephv1.DashboardStatus) {
+		status.CertificateSource = ""
+		status.CertificateExpiry = nil
+	})
+}
+
+// updateDashboardStatus updates the dashboard status of the cluster. A nil update removes the
+// dashboard status.
+func (c *Cluster) updateDashboardStatus(update func(status *cephv1.DashboardStatus)) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster := &cephv1.CephCluster{}
+		if err := c.context.Client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cluster); err != nil {
+			return errors.Wrapf(err, "failed to get cluster %v", c.clusterInfo.NamespacedName())
+		}
+		var status *cephv1.DashboardStatus
+		if update != nil {
+			status = &cephv1.DashboardStatus{}
+			if cluster.Status.Dashboard != nil {
+				status = cluster.Status.Dashboard.DeepCopy()
+			}
+			update(status)
+			if dashboardStatusEqual(status, &cephv1.DashboardStatus{}) {
+				status = nil
+			}
+		}
+		if dashboardStatusEqual(cluster.Status.Dashboard, status) {
+			return nil
+		}
+		cluster.Status.Dashboard = status
+		return reporting.UpdateStatus(c.context.Client, cluster)
+	})
+	if err != nil {
+		log.NamespacedWarning(c.clusterInfo.Namespace, logger, "failed to update the dashboard status. %v", err)
+	}
+}
+
+func dashboardStatusEqual(a, b *cephv1.DashboardStatus) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.CertificateSource == b.CertificateSource && a.CertificateExpiry.Equal(b.CertificateExpiry) &&
+		a.SSO == b.SSO && slices.Equal(a.SSOUsers, b.SSOUsers) && slices.Equal(a.Roles, b.Roles)
+}
+
 func (c *Cluster) createSelfSignedCert() (bool, error) {
 	// Check if the cert already exists
-	args := []string{"config-key", "get", "mgr/dashboard/crt"}
+	args := []string{"config-key", "get", dashboardCertKey}
 	output, err := client.NewCephCommand(c.context, c.clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout)
 	if err == nil && len(output) > 0 {
 		log.NamespacedInfo(c.clusterInfo.Namespace, logger, "dashboard is already initialized with a cert")
@@ -0,0 +1,274 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/util/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	saml2Protocol                 = "saml2"
+	defaultSAML2UsernameAttribute = "uid"
+)
+
+// configureDashboardAccessControl reconciles the custom dashboard roles and the single sign-on of
+// the dashboard. The settings are stored by the mgr, so they survive mgr failovers, and are applied
+// again on each reconcile.
+func (c *Cluster) configureDashboardAccessControl() error {
+	return c.reconcileDashboardAccessControl(c.spec.Dashboard.Roles, c.spec.Dashboard.SSO)
+}
+
+// removeDashboardAccessControl deletes the roles and the single sign-on users created by the
+// operator, and disables the single sign-on. It must run before the dashboard module is disabled,
+// since the dashboard commands are not available anymore after.
+func (c *Cluster) removeDashboardAccessControl() error {
+	status, err := c.getDashboardStatus()
+	if err != nil {
+		return err
+	}
+	if len(status.Roles) == 0 && len(status.SSOUsers) == 0 && status.SSO == "" {
+		return nil
+	}
+	if err := c.reconcileDashboardAccessControl(nil, nil); err != nil {
+		return err
+	}
+	status, err = c.getDashboardStatus()
+	if err != nil {
+		return err
+	}
+	if len(status.Roles) > 0 {
+		// the roles still assigned to users not created by the operator are kept in the status, so
+		// they are deleted if the dashboard is enabled again
+		log.NamespacedWarning(c.clusterInfo.Namespace, logger, "dashboard roles %v still assigned to dashboard users are not deleted", status.Roles)
+	}
+	return nil
+}
+
+// reconcileDashboardAccessControl reconciles the desired dashboard roles and single sign-on, and
+// removes the roles and single sign-on users created by the operator that are not desired anymore
+func (c *Cluster) reconcileDashboardAccessControl(roles []cephv1.DashboardRoleSpec, sso *cephv1.DashboardSSOSpec) error {
+	status, err := c.getDashboardStatus()
+	if err != nil {
+		return err
+	}
+	managedRoles := slices.Clone(status.Roles)
+	managedUsers := slices.Clone(status.SSOUsers)
+	ssoProtocol := status.SSO
+	defer func() {
+		c.updateDashboardStatus(func(status *cephv1.DashboardStatus) {
+			status.Roles = managedRoles
+			status.SSOUsers = managedUsers
+			status.SSO = ssoProtocol
+		})
+	}()
+
+	// the roles are created before the users that have them, and deleted after
+	for _, role := range roles {
+		if err := c.reconcileDashboardRole(role); err != nil {
+			return err
+		}
+		if !slices.Contains(managedRoles, role.Name) {
+			managedRoles = append(managedRoles, role.Name)
+		}
+	}
+	sort.Strings(managedRoles)
+
+	if sso != nil {
+		if err := c.setupDashboardSAML2(sso.SAML2); err != nil {
+			return err
+		}
+		ssoProtocol = saml2Protocol
+	} else if ssoProtocol != "" {
+		if err := client.DisableDashboardSSO(c.context, c.clusterInfo); err != nil {
+			return err
+		}
+		log.NamespacedInfo(c.clusterInfo.Namespace, logger, "dashboard single sign-on disabled")
+		ssoProtocol = ""
+	}
+
+	var desiredUsers []cephv1.DashboardSSOUserSpec
+	if sso != nil {
+		desiredUsers = sso.Users
+	}
+	managedUsers, err = c.reconcileDashboardSSOUsers(desiredUsers, managedUsers)
+	if err != nil {
+		return err
+	}
+
+	managedRoles = slices.DeleteFunc(managedRoles, func(name string) bool {
+		if slices.ContainsFunc(roles, func(role cephv1.DashboardRoleSpec) bool { return role.Name == name }) {
+			return false
+		}
+		if err := client.DeleteDashboardRole(c.context, c.clusterInfo, name); err != nil {
+			log.NamespacedWarning(c.clusterInfo.Namespace, logger, "failed to delete dashboard role %q removed from the spec. %v", name, err)
+			return false
+		}
+		log.NamespacedInfo(c.clusterInfo.Namespace, logger, "deleted dashboard role %q", name)
+		return true
+	})
+
+	return nil
+}
+
+// reconcileDashboardRole creates the dashboard role if needed and sets its permissions
+func (c *Cluster) reconcileDashboardRole(spec cephv1.DashboardRoleSpec) error {
+	roles, err := client.ListDashboardRoles(c.context, c.clusterInfo)
+	if err != nil {
+		return err
+	}
+	current := &client.DashboardRole{Name: spec.Name}
+	if slices.Contains(roles, spec.Name) {
+		current, err = client.GetDashboardRole(c.context, c.clusterInfo, spec.Name)
+		if err != nil {
+			return err
+		}
+	} else {
+		if err := client.CreateDashboardRole(c.context, c.clusterInfo, spec.Name); err != nil {
+			return err
+		}
+		log.NamespacedInfo(c.clusterInfo.Namespace, logger, "created dashboard role %q", spec.Name)
+	}
+
+	for scope := range current.ScopesPermissions {
+		if _, ok := spec.ScopePermissions[scope]; ok {
+			continue
+		}
+		if err := client.DeleteDashboardRoleScopePermissions(c.context, c.clusterInfo, spec.Name, scope); err != nil {
+			return err
+		}
+	}
+	for scope, permissions := range spec.ScopePermissions {
+		desired := make([]string, 0, len(permissions))
+		for _, permission := range permissions {
+			desired = append(desired, string(permission))
+		}
+		if sameItems(desired, current.ScopesPermissions[scope]) {
+			continue
+		}
+		// the permissions replace the current permissions on the scope
+		if err := client.AddDashboardRoleScopePermissions(c.context, c.clusterInfo, spec.Name, scope, desired); err != nil {
+			return err
+		}
+		log.NamespacedInfo(c.clusterInfo.Namespace, logger, "set permissions %v on scope %q of dashboard role %q", desired, scope, spec.Name)
+	}
+	return nil
+}
+
+// setupDashboardSAML2 configures and enables the SAML2 single sign-on of the dashboard
+func (c *Cluster) setupDashboardSAML2(spec cephv1.DashboardSAML2Spec) error {
+	config := client.DashboardSAML2Config{
+		BaseURL:           spec.BaseURL,
+		IdPMetadata:       spec.IdPMetadataURL,
+		UsernameAttribute: spec.UsernameAttribute,
+		IdPEntityID:       spec.IdPEntityID,
+	}
+	if config.UsernameAttribute == "" {
+		config.UsernameAttribute = defaultSAML2UsernameAttribute
+	}
+	if spec.IdPMetadataSecret != nil {
+		secret, err := c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Get(c.clusterInfo.Context, spec.IdPMetadataSecret.Name, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the saml2 idp metadata secret %q", spec.IdPMetadataSecret.Name)
+		}
+		metadata, ok := secret.Data[spec.IdPMetadataSecret.Key]
+		if !ok || len(metadata) == 0 {
+			return errors.Errorf("key %q not found in the saml2 idp metadata secret %q", spec.IdPMetadataSecret.Key, spec.IdPMetadataSecret.Name)
+		}
+		config.IdPMetadata = string(metadata)
+	}
+
+	if err := client.SetupDashboardSAML2(c.context, c.clusterInfo, config); err != nil {
+		return err
+	}
+	if err := client.EnableDashboardSAML2(c.context, c.clusterInfo); err != nil {
+		return err
+	}
+	log.NamespacedInfo(c.clusterInfo.Namespace, logger, "dashboard saml2 single sign-on configured")
+	return nil
+}
+
+// reconcileDashboardSSOUsers creates the single sign-on users with their roles, and deletes the
+// users created by the operator that are not in the spec anymore. Returns the users created by the
+// operator.
+func (c *Cluster) reconcileDashboardSSOUsers(desiredUsers []cephv1.DashboardSSOUserSpec, managedUsers []string) ([]string, error) {
+	if len(desiredUsers) == 0 && len(managedUsers) == 0 {
+		return nil, nil
+	}
+
+	users, err := client.ListDashboardUsers(c.context, c.clusterInfo)
+	if err != nil {
+		return managedUsers, err
+	}
+	for _, desired := range desiredUsers {
+		if !slices.Contains(users, desired.Username) {
+			// the users log in with the identity provider, so the password is never used
+			password, err := GeneratePassword(passwordLength, DefaultKey)
+			if err != nil {
+				return managedUsers, errors.Wrap(err, "failed to generate password")
+			}
+			if err := client.CreateDashboardUser(c.context, c.clusterInfo, desired.Username, password, desired.Roles, nil); err != nil {
+				return managedUsers, err
+			}
+			log.NamespacedInfo(c.clusterInfo.Namespace, logger, "created dashboard sso user %q", desired.Username)
+		} else {
+			user, err := client.GetDashboardUser(c.context, c.clusterInfo, desired.Username)
+			if err != nil {
+				return managedUsers, err
+			}
+			if !sameItems(desired.Roles, user.Roles) {
+				if err := client.SetDashboardUserRoles(c.context, c.clusterInfo, desired.Username, desired.Roles); err != nil {
+					return managedUsers, err
+				}
+				log.NamespacedInfo(c.clusterInfo.Namespace, logger, "set roles %v of dashboard sso user %q", desired.Roles, desired.Username)
+			}
+		}
+		if !slices.Contains(managedUsers, desired.Username) {
+			managedUsers = append(managedUsers, desired.Username)
+		}
+	}
+
+	remaining := []string{}
+	for i, username := range managedUsers {
+		if slices.ContainsFunc(desiredUsers, func(user cephv1.DashboardSSOUserSpec) bool { return user.Username == username }) {
+			remaining = append(remaining, username)
+			continue
+		}
+		if slices.Contains(users, username) {
+			if err := client.DeleteDashboardUser(c.context, c.clusterInfo, username); err != nil {
+				return append(remaining, managedUsers[i:]...), err
+			}
+			log.NamespacedInfo(c.clusterInfo.Namespace, logger, "deleted dashboard sso user %q", username)
+		}
+	}
+	sort.Strings(remaining)
+	return remaining, nil
+}
+
+// sameItems returns true if both lists have the same items regardless of their order
+func sameItems(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
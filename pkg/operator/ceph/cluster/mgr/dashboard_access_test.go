@@ -0,0 +1,266 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeDashboard simulates the access control and the single sign-on of the dashboard module
+type fakeDashboard struct {
+	roles    map[string]map[string][]string
+	users    map[string][]string
+	sso      string
+	saml2    []string
+	commands []string
+}
+
+func (d *fakeDashboard) execute(args []string) (string, error) {
+	if args[0] != "dashboard" {
+		return "", nil
+	}
+	// ignore the ceph cli flags, and the password file in the recorded commands
+	if i := slices.Index(args, "--format"); i >= 0 {
+		args = args[:i]
+	}
+	args = slices.DeleteFunc(slices.Clone(args[1:]), func(arg string) bool { return strings.HasPrefix(arg, "--") })
+	if i := slices.Index(args, "-i"); i >= 0 {
+		args = slices.Delete(args, i, i+2)
+	}
+	switch args[0] {
+	case "ac-role-show":
+		if len(args) == 1 {
+			names := []string{"administrator", "read-only"}
+			for name := range d.roles {
+				names = append(names, name)
+			}
+			out, _ := json.Marshal(names)
+			return string(out), nil
+		}
+		out, _ := json.Marshal(cephclient.DashboardRole{Name: args[1], ScopesPermissions: d.roles[args[1]]})
+		return string(out), nil
+	case "ac-user-show":
+		if len(args) == 1 {
+			names := []string{"admin"}
+			for name := range d.users {
+				names = append(names, name)
+			}
+			out, _ := json.Marshal(names)
+			return string(out), nil
+		}
+		out, _ := json.Marshal(cephclient.DashboardUser{Username: args[1], Roles: d.users[args[1]]})
+		return string(out), nil
+	case "ac-user-create":
+		if args[1] == "admin" {
+			return "", nil
+		}
+		d.users[args[1]] = []string{}
+	case "ac-user-set-roles":
+		d.users[args[1]] = args[2:]
+	case "ac-user-delete":
+		delete(d.users, args[1])
+	case "ac-role-create":
+		d.roles[args[1]] = map[string][]string{}
+	case "ac-role-add-scope-perms":
+		d.roles[args[1]][args[2]] = args[3:]
+	case "ac-role-del-scope-perms":
+		delete(d.roles[args[1]], args[2])
+	case "ac-role-delete":
+		for _, roles := range d.users {
+			if slices.Contains(roles, args[1]) {
+				return "", errors.Errorf("role %q is associated with a user", args[1])
+			}
+		}
+		delete(d.roles, args[1])
+	case "sso":
+		switch args[1] {
+		case "setup":
+			d.saml2 = args[3:]
+		case "enable":
+			d.sso = args[2]
+		case "disable":
+			d.sso = ""
+		}
+	default:
+		return "", nil
+	}
+	d.commands = append(d.commands, strings.Join(args, " "))
+	return "", nil
+}
+
+func TestConfigureDashboardAccessControl(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "myns"
+	dashboard := &fakeDashboard{roles: map[string]map[string][]string{}, users: map[string][]string{}}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			return dashboard.execute(args)
+		},
+	}
+
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "mycluster", Namespace: namespace}}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(cephCluster).WithStatusSubresource(cephCluster).Build()
+	clientset := test.New(t, 1)
+	clusterInfo := &cephclient.ClusterInfo{Namespace: namespace, OwnerInfo: cephclient.NewMinimumOwnerInfoWithOwnerRef(), Context: ctx}
+	clusterInfo.SetName("mycluster")
+	c := &Cluster{
+		clusterInfo: clusterInfo,
+		context:     &clusterd.Context{Clientset: clientset, Client: cl, Executor: executor},
+	}
+	getStatus := func() *cephv1.DashboardStatus {
+		cluster := &cephv1.CephCluster{}
+		assert.NoError(t, cl.Get(ctx, clusterInfo.NamespacedName(), cluster))
+		return cluster.Status.Dashboard
+	}
+
+	_, err := clientset.CoreV1().Secrets(namespace).Create(ctx, &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "idp", Namespace: namespace},
+		Data:       map[string][]byte{"metadata.xml": []byte("<EntityDescriptor/>")},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	t.Run("nothing to configure", func(t *testing.T) {
+		assert.NoError(t, c.configureDashboardAccessControl())
+		assert.Empty(t, dashboard.commands)
+		assert.Nil(t, getStatus())
+	})
+
+	t.Run("roles and sso", func(t *testing.T) {
+		c.spec.Dashboard = cephv1.DashboardSpec{
+			Roles: []cephv1.DashboardRoleSpec{
+				{Name: "pool-viewer", ScopePermissions: map[string][]cephv1.DashboardPermission{"pool": {"read"}}},
+			},
+			SSO: &cephv1.DashboardSSOSpec{
+				SAML2: cephv1.DashboardSAML2Spec{
+					BaseURL:           "https://dashboard.example.com/",
+					IdPMetadataSecret: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "idp"}, Key: "metadata.xml"},
+				},
+				Users: []cephv1.DashboardSSOUserSpec{
+					{Username: "alice", Roles: []string{"pool-viewer"}},
+					{Username: "bob", Roles: []string{"read-only"}},
+				},
+			},
+		}
+		assert.NoError(t, c.configureDashboardAccessControl())
+		assert.Equal(t, map[string]map[string][]string{"pool-viewer": {"pool": {"read"}}}, dashboard.roles)
+		assert.Equal(t, map[string][]string{"alice": {"pool-viewer"}, "bob": {"read-only"}}, dashboard.users)
+		assert.Equal(t, "saml2", dashboard.sso)
+		assert.Equal(t, []string{"https://dashboard.example.com/", "<EntityDescriptor/>", "uid"}, dashboard.saml2)
+
+		status := getStatus()
+		require.NotNil(t, status)
+		assert.Equal(t, "saml2", status.SSO)
+		assert.Equal(t, []string{"alice", "bob"}, status.SSOUsers)
+		assert.Equal(t, []string{"pool-viewer"}, status.Roles)
+	})
+
+	t.Run("nothing changed", func(t *testing.T) {
+		dashboard.commands = nil
+		assert.NoError(t, c.configureDashboardAccessControl())
+		// the sso settings are applied again
+		assert.Equal(t, []string{
+			"sso setup saml2 https://dashboard.example.com/ <EntityDescriptor/> uid",
+			"sso enable saml2",
+		}, dashboard.commands)
+	})
+
+	t.Run("update the roles and users", func(t *testing.T) {
+		c.spec.Dashboard.Roles[0].ScopePermissions = map[string][]cephv1.DashboardPermission{"rbd-image": {"read", "create"}}
+		c.spec.Dashboard.SSO.Users = []cephv1.DashboardSSOUserSpec{{Username: "alice", Roles: []string{"pool-viewer", "read-only"}}}
+		assert.NoError(t, c.configureDashboardAccessControl())
+		assert.Equal(t, map[string]map[string][]string{"pool-viewer": {"rbd-image": {"read", "create"}}}, dashboard.roles)
+		assert.Equal(t, map[string][]string{"alice": {"pool-viewer", "read-only"}}, dashboard.users)
+		assert.Equal(t, []string{"alice"}, getStatus().SSOUsers)
+	})
+
+	t.Run("remove the sso and roles", func(t *testing.T) {
+		c.spec.Dashboard = cephv1.DashboardSpec{}
+		assert.NoError(t, c.configureDashboardAccessControl())
+		assert.Empty(t, dashboard.roles)
+		assert.Empty(t, dashboard.users)
+		assert.Empty(t, dashboard.sso)
+		assert.Nil(t, getStatus())
+	})
+
+	t.Run("a role in use is not deleted", func(t *testing.T) {
+		c.spec.Dashboard.Roles = []cephv1.DashboardRoleSpec{
+			{Name: "pool-viewer", ScopePermissions: map[string][]cephv1.DashboardPermission{"pool": {"read"}}},
+		}
+		assert.NoError(t, c.configureDashboardAccessControl())
+		// a user not managed by the operator has the role
+		dashboard.users["carol"] = []string{"pool-viewer"}
+
+		c.spec.Dashboard.Roles = nil
+		assert.NoError(t, c.configureDashboardAccessControl())
+		assert.Contains(t, dashboard.roles, "pool-viewer")
+		assert.Equal(t, []string{"pool-viewer"}, getStatus().Roles)
+
+		delete(dashboard.users, "carol")
+		assert.NoError(t, c.configureDashboardAccessControl())
+		assert.Empty(t, dashboard.roles)
+		assert.Nil(t, getStatus())
+	})
+
+	t.Run("disable the dashboard", func(t *testing.T) {
+		c.spec.Dashboard = cephv1.DashboardSpec{
+			Enabled: true,
+			Roles: []cephv1.DashboardRoleSpec{
+				{Name: "pool-viewer", ScopePermissions: map[string][]cephv1.DashboardPermission{"pool": {"read"}}},
+			},
+			SSO: &cephv1.DashboardSSOSpec{
+				SAML2: cephv1.DashboardSAML2Spec{BaseURL: "https://dashboard.example.com/", IdPMetadataURL: "https://idp.example.com/metadata"},
+				Users: []cephv1.DashboardSSOUserSpec{{Username: "alice", Roles: []string{"pool-viewer"}}},
+			},
+		}
+		assert.NoError(t, c.configureDashboardAccessControl())
+		assert.Equal(t, "saml2", dashboard.sso)
+		// a user not managed by the operator is kept
+		dashboard.users["carol"] = []string{"read-only"}
+
+		c.spec.Dashboard.Enabled = false
+		assert.NoError(t, c.configureDashboardModules())
+		assert.Empty(t, dashboard.roles)
+		assert.Equal(t, map[string][]string{"carol": {"read-only"}}, dashboard.users)
+		assert.Empty(t, dashboard.sso)
+		assert.Nil(t, getStatus())
+
+		// nothing to remove once the dashboard is disabled
+		dashboard.commands = nil
+		assert.NoError(t, c.configureDashboardModules())
+		assert.Empty(t, dashboard.commands)
+	})
+}
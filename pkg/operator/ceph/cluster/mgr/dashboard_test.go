@@ -17,11 +17,21 @@ package mgr
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	cryptorand "crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	cephver "github.com/rook/rook/pkg/operator/ceph/version"
@@ -32,6 +42,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestGeneratePassword(t *testing.T) {
@@ -119,8 +130,13 @@ func TestStartSecureDashboard(t *testing.T) {
 		OwnerInfo:   ownerInfo,
 		Context:     ctx,
 	}
+	clusterInfo.SetName("mycluster")
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "mycluster", Namespace: "myns"}}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(cephCluster).WithStatusSubresource(cephCluster).Build()
 	c := &Cluster{
-		clusterInfo: clusterInfo, context: &clusterd.Context{Clientset: clientset, Executor: executor},
+		clusterInfo: clusterInfo, context: &clusterd.Context{Clientset: clientset, Client: cl, Executor: executor},
 		spec: cephv1.ClusterSpec{
 			Dashboard:   cephv1.DashboardSpec{Port: 443, Enabled: true, SSL: true},
 			CephVersion: cephv1.CephVersionSpec{Image: "quay.io/ceph/ceph:v15"},
@@ -235,3 +251,183 @@ func TestCreateSelfSignedCertRetriesWrappedDeadlineExceeded(t *testing.T) {
 	assert.False(t, alreadyCreated)
 	assert.Equal(t, 3, attempts)
 }
+
+func newTestCert(t *testing.T, notAfter time.Time) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), cryptorand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "rook-ceph-mgr-dashboard"},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(cryptorand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func TestDashboardCertFromSecret(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "myns"
+	configKeys := map[string]string{}
+	setCommands := 0
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, arg ...string) (string, error) {
+			if arg[0] == "config-key" && arg[1] == "get" {
+				value, ok := configKeys[arg[2]]
+				if !ok {
+					return "", errors.New("not found")
+				}
+				return value, nil
+			}
+			if arg[0] == "config-key" && arg[1] == "rm" {
+				delete(configKeys, arg[2])
+				return "", nil
+			}
+			if arg[0] == "dashboard" && arg[1] == "create-self-signed-cert" {
+				selfSigned, _ := newTestCert(t, time.Now().Add(time.Hour))
+				configKeys[dashboardCertKey] = string(selfSigned)
+				return "", nil
+			}
+			if arg[0] == "dashboard" && strings.HasPrefix(arg[1], "set-ssl-certificate") {
+				setCommands++
+				content, err := os.ReadFile(arg[3])
+				assert.NoError(t, err)
+				switch arg[1] {
+				case "set-ssl-certificate":
+					configKeys[dashboardCertKey] = string(content)
+				case "set-ssl-certificate-key":
+					configKeys[dashboardCertKeyKey] = string(content)
+				}
+			}
+			return "", nil
+		},
+	}
+
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "mycluster", Namespace: namespace}}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(cephCluster).WithStatusSubresource(cephCluster).Build()
+	clientset := test.New(t, 1)
+	clusterInfo := &cephclient.ClusterInfo{Namespace: namespace, OwnerInfo: cephclient.NewMinimumOwnerInfoWithOwnerRef(), Context: ctx}
+	clusterInfo.SetName("mycluster")
+	c := &Cluster{
+		clusterInfo: clusterInfo,
+		context:     &clusterd.Context{Clientset: clientset, Client: cl, Executor: executor},
+		spec:        cephv1.ClusterSpec{Dashboard: cephv1.DashboardSpec{Enabled: true, SSL: true, SSLCertificateRef: "dashboard-tls"}},
+	}
+	dashboardInitWaitTime = 0
+
+	getStatus := func() *cephv1.DashboardStatus {
+		cluster := &cephv1.CephCluster{}
+		assert.NoError(t, cl.Get(ctx, clusterInfo.NamespacedName(), cluster))
+		return cluster.Status.Dashboard
+	}
+
+	t.Run("secret not found", func(t *testing.T) {
+		_, err := c.configureDashboardCert()
+		assert.ErrorContains(t, err, `failed to apply the ceph dashboard cert from secret "dashboard-tls"`)
+		assert.Equal(t, 0, setCommands)
+	})
+
+	expiry := time.Now().Add(90 * 24 * time.Hour).Truncate(time.Second)
+	cert, key := newTestCert(t, expiry)
+	ca, _ := newTestCert(t, expiry.Add(time.Hour))
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard-tls", Namespace: namespace},
+		Data:       map[string][]byte{v1.TLSCertKey: cert, v1.TLSPrivateKeyKey: key, caCertKeyName: ca},
+	}
+	_, err := clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	t.Run("apply the cert", func(t *testing.T) {
+		changed, err := c.configureDashboardCert()
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, 2, setCommands)
+		assert.Contains(t, configKeys[dashboardCertKey], string(cert))
+		assert.Contains(t, configKeys[dashboardCertKey], string(ca))
+		assert.Equal(t, string(key), configKeys[dashboardCertKeyKey])
+
+		status := getStatus()
+		require.NotNil(t, status)
+		assert.Equal(t, "dashboard-tls", status.CertificateSource)
+		assert.True(t, expiry.Equal(status.CertificateExpiry.Time))
+	})
+
+	t.Run("the cert is already applied", func(t *testing.T) {
+		changed, err := c.configureDashboardCert()
+		assert.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, 2, setCommands)
+	})
+
+	t.Run("the cert is renewed", func(t *testing.T) {
+		renewedExpiry := expiry.Add(30 * 24 * time.Hour)
+		cert, key = newTestCert(t, renewedExpiry)
+		secret.Data = map[string][]byte{v1.TLSCertKey: cert, v1.TLSPrivateKeyKey: key}
+		_, err := clientset.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{})
+		require.NoError(t, err)
+
+		changed, err := c.configureDashboardCert()
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, 4, setCommands)
+		assert.Equal(t, string(cert), configKeys[dashboardCertKey])
+		assert.True(t, renewedExpiry.Equal(getStatus().CertificateExpiry.Time))
+	})
+
+	t.Run("the key does not match the cert", func(t *testing.T) {
+		_, otherKey := newTestCert(t, expiry)
+		secret.Data[v1.TLSPrivateKeyKey] = otherKey
+		_, err := clientset.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{})
+		require.NoError(t, err)
+
+		_, err = c.configureDashboardCert()
+		assert.ErrorContains(t, err, "invalid certificate and key")
+		assert.Equal(t, 4, setCommands)
+	})
+
+	t.Run("switch to a self-signed cert", func(t *testing.T) {
+		c.spec.Dashboard.SSLCertificateRef = ""
+		changed, err := c.configureDashboardCert()
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.NotContains(t, configKeys[dashboardCertKey], string(cert))
+		assert.NotContains(t, configKeys, dashboardCertKeyKey)
+		assert.Equal(t, selfSignedCertSource, getStatus().CertificateSource)
+
+		// the self-signed cert is kept
+		changed, err = c.configureDashboardCert()
+		assert.NoError(t, err)
+		assert.False(t, changed)
+		assert.Contains(t, configKeys, dashboardCertKey)
+	})
+
+	t.Run("ssl disabled", func(t *testing.T) {
+		c.spec.Dashboard.SSL = false
+		_, err := c.initializeSecureDashboard()
+		assert.NoError(t, err)
+		assert.Nil(t, getStatus())
+	})
+
+	t.Run("ssl disabled after a cert from a secret", func(t *testing.T) {
+		c.spec.Dashboard.SSL = true
+		c.spec.Dashboard.SSLCertificateRef = "dashboard-tls"
+		secret.Data[v1.TLSPrivateKeyKey] = key
+		_, err := clientset.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		_, err = c.configureDashboardCert()
+		assert.NoError(t, err)
+		assert.Equal(t, "dashboard-tls", getStatus().CertificateSource)
+
+		c.spec.Dashboard.SSL = false
+		_, err = c.initializeSecureDashboard()
+		assert.NoError(t, err)
+		assert.Nil(t, getStatus())
+		assert.NotContains(t, configKeys, dashboardCertKey)
+		assert.NotContains(t, configKeys, dashboardCertKeyKey)
+	})
+}
@@ -0,0 +1,392 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	_ "embed"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/log"
+	apps "k8s.io/api/apps/v1"
+	batch "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+//go:embed mon-backup.sh
+var monBackupScript string
+
+//go:embed mon-backup-copy.sh
+var monBackupCopyScript string
+
+const (
+	monBackupAppName = "rook-ceph-mon-backup"
+	// the init container of the backup job that copies the store of the mon
+	monBackupCopyContainerName = "mon-store-copy"
+	// the directory where the backup PVC is mounted in the backup container
+	monBackupDir = "/var/lib/ceph/mon-backup"
+	// the directory where the copy of the mon store is kept until it is archived
+	monBackupStagingDir    = "/var/lib/ceph/mon-backup-staging"
+	monBackupStagingVolume = "mon-backup-staging"
+	// number of backups to keep if not specified in the cluster CR
+	defaultMonBackupRetention = 7
+	// a backup that could not stop the mon within this time gives up until the next schedule
+	monBackupDeadlineSeconds = int64(3600)
+)
+
+// reconcileMonBackup creates or updates the cron job that takes periodic backups of the store of a
+// mon, or deletes it if the backups are disabled in the cluster CR.
+func (c *Cluster) reconcileMonBackup() error {
+	if c.spec.Mon.Backup.Schedule == "" {
+		err := c.context.Clientset.BatchV1().CronJobs(c.Namespace).Delete(c.ClusterInfo.Context, monBackupAppName, metav1.DeleteOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				log.NamespacedDebug(c.Namespace, logger, "mon backup cronjob not found. Ignoring since object must be deleted.")
+				return nil
+			}
+			return errors.Wrap(err, "failed to delete mon backup cronjob")
+		}
+		log.NamespacedInfo(c.Namespace, logger, "mon backups are disabled, deleted the mon backup cronjob")
+		return nil
+	}
+
+	m, d, err := c.firstMonWithDeployment()
+	if err != nil {
+		return errors.Wrap(err, "failed to find the mon to back up")
+	}
+	cronJob, err := c.makeMonBackupCronJob(m, d)
+	if err != nil {
+		return errors.Wrapf(err, "failed to generate the mon backup cronjob for mon %q", m.DaemonName)
+	}
+	if err := c.ownerInfo.SetControllerReference(cronJob); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference on mon backup cronjob %q", cronJob.Name)
+	}
+	if _, err := k8sutil.CreateOrUpdateCronJob(c.ClusterInfo.Context, c.context.Clientset, cronJob); err != nil {
+		return errors.Wrap(err, "failed to create or update mon backup cronjob")
+	}
+	log.NamespacedDebug(c.Namespace, logger, "mon backup cronjob reconciled with schedule %q for mon %q", cronJob.Spec.Schedule, m.DaemonName)
+
+	return c.updateMonBackupStatus()
+}
+
+// firstMonWithDeployment returns the first mon in name order that has a deployment, along with the
+// deployment. Its deployment gives the volumes of its store to the jobs that back up or restore the
+// store. The floating mon is skipped.
+func (c *Cluster) firstMonWithDeployment() (*monConfig, *apps.Deployment, error) {
+	mons := c.clusterInfoToMonConfig()
+	sort.Slice(mons, func(i, j int) bool { return mons[i].DaemonName < mons[j].DaemonName })
+	for _, m := range mons {
+		if isFloatingMon(c, m.DaemonName) {
+			continue
+		}
+		d, err := c.context.Clientset.AppsV1().Deployments(c.Namespace).Get(c.ClusterInfo.Context, m.ResourceName, metav1.GetOptions{})
+		if err == nil {
+			return m, d, nil
+		}
+		if !kerrors.IsNotFound(err) {
+			return nil, nil, errors.Wrapf(err, "failed to get mon deployment %q", m.ResourceName)
+		}
+	}
+	return nil, nil, errors.New("no mon deployment found")
+}
+
+// makeMonBackupCronJob generates the cron job that backs up the store of the mon. The job is derived
+// from the mon deployment so that it mounts the store of the mon. Its init container waits for the
+// operator to stop the mon, since the store can only be copied consistently while the mon is
+// stopped, and the backup container archives the copy to the backup target.
+func (c *Cluster) makeMonBackupCronJob(m *monConfig, d *apps.Deployment) (*batch.CronJob, error) {
+	// the CRD validation ensures that exactly one of the PVC or S3 targets is set
+	backup := c.spec.Mon.Backup
+
+	podSpec := d.Spec.Template.Spec.DeepCopy()
+	monContainer, err := k8sutil.GetContainerByName(podSpec.Containers, monContainerName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the mon container of deployment %q", d.Name)
+	}
+
+	stagingMount := corev1.VolumeMount{Name: monBackupStagingVolume, MountPath: monBackupStagingDir}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name:         monBackupStagingVolume,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+	backupMounts := []corev1.VolumeMount{stagingMount}
+	if backup.PersistentVolumeClaim != "" {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: monBackupAppName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: backup.PersistentVolumeClaim},
+			},
+		})
+		backupMounts = append(backupMounts, corev1.VolumeMount{Name: monBackupAppName, MountPath: monBackupDir})
+	}
+
+	podSpec.InitContainers = []corev1.Container{
+		monStoreContainer(*monContainer, monBackupCopyContainerName, monBackupCopyScript, stagingMount,
+			corev1.EnvVar{Name: "ROOK_MON_ID", Value: m.DaemonName},
+			corev1.EnvVar{Name: "ROOK_MON_DATA_DIR", Value: m.DataPathMap.ContainerDataDir},
+			corev1.EnvVar{Name: "ROOK_MON_BACKUP_STAGING_DIR", Value: monBackupStagingDir},
+		),
+	}
+	podSpec.Containers = []corev1.Container{
+		{
+			Name:            "mon-backup",
+			Command:         []string{"/bin/bash", "-c", monBackupScript},
+			Image:           c.rookImage,
+			ImagePullPolicy: controller.GetContainerImagePullPolicy(c.spec.CephVersion.ImagePullPolicy),
+			Env: append(monBackupTargetEnvVars(backup),
+				corev1.EnvVar{Name: "ROOK_MON_ID", Value: m.DaemonName},
+				corev1.EnvVar{Name: "ROOK_MON_BACKUP_STAGING_DIR", Value: monBackupStagingDir},
+				corev1.EnvVar{Name: "ROOK_MON_BACKUP_RETENTION", Value: strconv.Itoa(monBackupRetention(backup))},
+			),
+			VolumeMounts:    backupMounts,
+			Resources:       cephv1.GetMonResources(c.spec.Resources),
+			SecurityContext: controller.DefaultContainerSecurityContext(),
+		},
+	}
+	podSpec.RestartPolicy = corev1.RestartPolicyNever
+	if monOnPVC(&d.Spec.Template.Spec) {
+		// a mon on a PVC has no node selector, so the job runs next to the mon to mount the PVC
+		addMonPodAffinity(podSpec, m.DaemonName)
+	}
+
+	labels := controller.AppLabels(monBackupAppName, c.Namespace)
+	labels[controller.DaemonIDLabel] = m.DaemonName
+
+	// After 100 failures, the cron job will no longer run.
+	// To avoid this, the cronjob is configured to only count the failures
+	// that occurred in the last hour.
+	deadline := int64(60)
+	activeDeadline := monBackupDeadlineSeconds
+	cronJob := &batch.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      monBackupAppName,
+			Namespace: c.Namespace,
+			Labels:    labels,
+		},
+		Spec: batch.CronJobSpec{
+			Schedule:                backup.Schedule,
+			ConcurrencyPolicy:       batch.ForbidConcurrent,
+			StartingDeadlineSeconds: &deadline,
+			JobTemplate: batch.JobTemplateSpec{
+				Spec: batch.JobSpec{
+					ActiveDeadlineSeconds: &activeDeadline,
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: labels},
+						Spec:       *podSpec,
+					},
+				},
+			},
+		},
+	}
+
+	return cronJob, nil
+}
+
+// monStoreContainer turns the mon container into a container that runs the given script on the
+// store of the mon with the same image, volumes, and privileges as the mon
+func monStoreContainer(mon corev1.Container, name, script string, mount corev1.VolumeMount, env ...corev1.EnvVar) corev1.Container {
+	container := mon
+	container.Name = name
+	container.Command = []string{"/bin/bash", "-c", script}
+	container.Args = nil
+	container.Ports = nil
+	container.WorkingDir = ""
+	container.Lifecycle = nil
+	container.StartupProbe = nil
+	container.LivenessProbe = nil
+	container.ReadinessProbe = nil
+	container.Env = append(slices.Clone(mon.Env), env...)
+	container.VolumeMounts = append(slices.Clone(mon.VolumeMounts), mount)
+	return container
+}
+
+func monOnPVC(podSpec *corev1.PodSpec) bool {
+	for _, volume := range podSpec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func addMonPodAffinity(podSpec *corev1.PodSpec, monName string) {
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	if podSpec.Affinity.PodAffinity == nil {
+		podSpec.Affinity.PodAffinity = &corev1.PodAffinity{}
+	}
+	podSpec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(podSpec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+		corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{k8sutil.AppAttr: AppName, controller.DaemonIDLabel: monName},
+			},
+			TopologyKey: corev1.LabelHostname,
+		})
+}
+
+func monBackupRetention(backup cephv1.MonBackupSpec) int {
+	if backup.Retention <= 0 {
+		return defaultMonBackupRetention
+	}
+	return backup.Retention
+}
+
+// monBackupTargetEnvVars returns the env vars of the containers that write or read the backups
+func monBackupTargetEnvVars(backup cephv1.MonBackupSpec) []corev1.EnvVar {
+	if backup.S3 == nil {
+		return []corev1.EnvVar{{Name: "ROOK_MON_BACKUP_DIR", Value: monBackupDir}}
+	}
+
+	// the prefix is used as a directory, so make sure it ends with a slash
+	prefix := backup.S3.Prefix
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	secretKeyRef := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: backup.S3.CredentialsSecretName},
+				Key:                  key,
+			},
+		}
+	}
+	envVars := []corev1.EnvVar{
+		{Name: "ROOK_MON_BACKUP_S3_ENDPOINT", Value: backup.S3.Endpoint},
+		{Name: "ROOK_MON_BACKUP_S3_BUCKET", Value: backup.S3.Bucket},
+		{Name: "ROOK_MON_BACKUP_S3_PREFIX", Value: prefix},
+		{Name: "AWS_ACCESS_KEY_ID", ValueFrom: secretKeyRef("AWS_ACCESS_KEY_ID")},
+		{Name: "AWS_SECRET_ACCESS_KEY", ValueFrom: secretKeyRef("AWS_SECRET_ACCESS_KEY")},
+	}
+	if backup.S3.Region != "" {
+		envVars = append(envVars, corev1.EnvVar{Name: "AWS_REGION", Value: backup.S3.Region})
+	}
+	return envVars
+}
+
+// monWaitingForBackup returns the name of the mon whose store a backup job is waiting to copy, or
+// an empty name if no backup is waiting
+func (c *Cluster) monWaitingForBackup() (string, error) {
+	pods, err := c.context.Clientset.CoreV1().Pods(c.Namespace).List(c.ClusterInfo.Context, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, monBackupAppName)})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list mon backup pods")
+	}
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.InitContainerStatuses {
+			if status.Name == monBackupCopyContainerName && status.State.Running != nil {
+				return pod.Labels[controller.DaemonIDLabel], nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// stopMonForBackup stops the mon whose store a backup job is waiting to copy. The mon is only
+// stopped if the quorum does not depend on it.
+func (c *Cluster) stopMonForBackup() {
+	if c.monStoppedForBackup != "" {
+		return
+	}
+	name, err := c.monWaitingForBackup()
+	if err != nil {
+		log.NamespacedWarning(c.Namespace, logger, "failed to check for a pending mon backup. %v", err)
+		return
+	}
+	if name == "" {
+		return
+	}
+	if _, err := cephclient.NewCephCommand(c.context, c.ClusterInfo, []string{"mon", "ok-to-stop", name}).Run(); err != nil {
+		log.NamespacedWarning(c.Namespace, logger, "mon %q is not ok to stop, waiting to back up its store. %v", name, err)
+		return
+	}
+	log.NamespacedInfo(c.Namespace, logger, "stopping mon %q to back up its store", name)
+	if err := c.updateMonDeploymentReplica(name, false); err != nil {
+		log.NamespacedWarning(c.Namespace, logger, "failed to stop mon %q for the mon backup. %v", name, err)
+		return
+	}
+	c.monStoppedForBackup = name
+}
+
+// startMonAfterBackup starts the mon stopped for a backup again once its store was copied
+func (c *Cluster) startMonAfterBackup() {
+	if c.monStoppedForBackup == "" {
+		return
+	}
+	name, err := c.monWaitingForBackup()
+	if err != nil {
+		log.NamespacedWarning(c.Namespace, logger, "failed to check for a pending mon backup. %v", err)
+		return
+	}
+	if name == c.monStoppedForBackup {
+		log.NamespacedDebug(c.Namespace, logger, "mon %q is stopped until its store is copied by the mon backup", name)
+		return
+	}
+	log.NamespacedInfo(c.Namespace, logger, "starting mon %q after the backup of its store", c.monStoppedForBackup)
+	if err := c.updateMonDeploymentReplica(c.monStoppedForBackup, true); err != nil {
+		log.NamespacedWarning(c.Namespace, logger, "failed to start mon %q after the mon backup. %v", c.monStoppedForBackup, err)
+		return
+	}
+	c.monStoppedForBackup = ""
+}
+
+// updateMonBackupStatus records the last scheduled and last successful backup times of the mon
+// backup cronjob in the CephCluster status.
+func (c *Cluster) updateMonBackupStatus() error {
+	cronJob, err := c.context.Clientset.BatchV1().CronJobs(c.Namespace).Get(c.ClusterInfo.Context, monBackupAppName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get mon backup cronjob")
+	}
+	backupStatus := &cephv1.MonBackupStatus{
+		LastScheduleTime:   cronJob.Status.LastScheduleTime,
+		LastSuccessfulTime: cronJob.Status.LastSuccessfulTime,
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster := &cephv1.CephCluster{}
+		if err := c.context.Client.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cluster); err != nil {
+			return errors.Wrapf(err, "failed to get cluster %v to update the mon backup status", c.ClusterInfo.NamespacedName())
+		}
+		if monBackupStatusEqual(cluster.Status.MonBackup, backupStatus) {
+			return nil
+		}
+		cluster.Status.MonBackup = backupStatus
+		return reporting.UpdateStatus(c.context.Client, cluster)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update the mon backup status")
+	}
+	return nil
+}
+
+func monBackupStatusEqual(a, b *cephv1.MonBackupStatus) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.LastScheduleTime.Equal(b.LastScheduleTime) && a.LastSuccessfulTime.Equal(b.LastSuccessfulTime)
+}
@@ -0,0 +1,276 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestMonDeployment(namespace, name string) *apps.Deployment {
+	replicas := int32(1)
+	return &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: resourceName(name), Namespace: namespace, Labels: map[string]string{k8sutil.AppAttr: AppName}},
+		Spec: apps.DeploymentSpec{
+			Replicas: &replicas,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					NodeSelector:   map[string]string{v1.LabelHostname: "node1"},
+					InitContainers: []v1.Container{{Name: "init-mon-fs"}},
+					Containers: []v1.Container{{
+						Name:          monContainerName,
+						Image:         "quay.io/ceph/ceph:v20",
+						Args:          []string{"--foreground"},
+						VolumeMounts:  []v1.VolumeMount{{Name: "ceph-daemon-data", MountPath: "/var/lib/ceph/mon/ceph-" + name}},
+						LivenessProbe: &v1.Probe{},
+					}},
+					RestartPolicy: v1.RestartPolicyAlways,
+					Volumes:       []v1.Volume{{Name: "ceph-daemon-data"}},
+				},
+			},
+		},
+	}
+}
+
+func TestReconcileMonBackup(t *testing.T) {
+	namespace := "default"
+	c := newTestClusterWithCR(t, namespace, 1)
+	ctx := c.ClusterInfo.Context
+
+	t.Run("disabled backups create no cronjob", func(t *testing.T) {
+		assert.NoError(t, c.reconcileMonBackup())
+		_, err := c.context.Clientset.BatchV1().CronJobs(namespace).Get(ctx, monBackupAppName, metav1.GetOptions{})
+		assert.True(t, kerrors.IsNotFound(err))
+	})
+
+	t.Run("backups require a mon deployment", func(t *testing.T) {
+		c.spec.Mon.Backup = cephv1.MonBackupSpec{Schedule: "@daily", PersistentVolumeClaim: "backups"}
+		assert.Error(t, c.reconcileMonBackup())
+	})
+
+	_, err := c.context.Clientset.AppsV1().Deployments(namespace).Create(ctx, newTestMonDeployment(namespace, "a"), metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	t.Run("backups to a pvc", func(t *testing.T) {
+		c.spec.Mon.Backup = cephv1.MonBackupSpec{Schedule: "@daily", PersistentVolumeClaim: "backups"}
+		assert.NoError(t, c.reconcileMonBackup())
+		cronJob, err := c.context.Clientset.BatchV1().CronJobs(namespace).Get(ctx, monBackupAppName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "@daily", cronJob.Spec.Schedule)
+		assert.Equal(t, monBackupDeadlineSeconds, *cronJob.Spec.JobTemplate.Spec.ActiveDeadlineSeconds)
+		assert.Equal(t, "a", cronJob.Spec.JobTemplate.Spec.Template.Labels[opcontroller.DaemonIDLabel])
+
+		// the job runs where the mon store is
+		podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+		assert.Equal(t, "node1", podSpec.NodeSelector[v1.LabelHostname])
+		assert.Equal(t, v1.RestartPolicyNever, podSpec.RestartPolicy)
+		assert.Nil(t, podSpec.Affinity)
+
+		// the store is copied with the mon image and volumes
+		assert.Equal(t, 1, len(podSpec.InitContainers))
+		storeCopy := podSpec.InitContainers[0]
+		assert.Equal(t, monBackupCopyContainerName, storeCopy.Name)
+		assert.Equal(t, "quay.io/ceph/ceph:v20", storeCopy.Image)
+		assert.Equal(t, []string{"/bin/bash", "-c", monBackupCopyScript}, storeCopy.Command)
+		assert.Nil(t, storeCopy.Args)
+		assert.Nil(t, storeCopy.LivenessProbe)
+		assert.Contains(t, storeCopy.Env, v1.EnvVar{Name: "ROOK_MON_DATA_DIR", Value: "/var/lib/ceph/mon/ceph-a"})
+		assert.Contains(t, storeCopy.VolumeMounts, v1.VolumeMount{Name: "ceph-daemon-data", MountPath: "/var/lib/ceph/mon/ceph-a"})
+		assert.Contains(t, storeCopy.VolumeMounts, v1.VolumeMount{Name: monBackupStagingVolume, MountPath: monBackupStagingDir})
+
+		// the copy is archived with the rook image
+		assert.Equal(t, 1, len(podSpec.Containers))
+		container := podSpec.Containers[0]
+		assert.Equal(t, "myversion", container.Image)
+		assert.Contains(t, container.Env, v1.EnvVar{Name: "ROOK_MON_BACKUP_RETENTION", Value: "7"})
+		assert.Contains(t, container.Env, v1.EnvVar{Name: "ROOK_MON_BACKUP_DIR", Value: monBackupDir})
+		assert.Equal(t, []v1.VolumeMount{
+			{Name: monBackupStagingVolume, MountPath: monBackupStagingDir},
+			{Name: monBackupAppName, MountPath: monBackupDir},
+		}, container.VolumeMounts)
+		found := false
+		for _, vol := range podSpec.Volumes {
+			if vol.PersistentVolumeClaim != nil {
+				assert.Equal(t, "backups", vol.PersistentVolumeClaim.ClaimName)
+				found = true
+			}
+		}
+		assert.True(t, found)
+	})
+
+	t.Run("backups to s3", func(t *testing.T) {
+		c.spec.Mon.Backup = cephv1.MonBackupSpec{
+			Schedule:  "0 2 * * *",
+			Retention: 3,
+			S3:        &cephv1.MonBackupS3Spec{Endpoint: "http://s3", Bucket: "b", Prefix: "mons", CredentialsSecretName: "creds"},
+		}
+		assert.NoError(t, c.reconcileMonBackup())
+		cronJob, err := c.context.Clientset.BatchV1().CronJobs(namespace).Get(ctx, monBackupAppName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "0 2 * * *", cronJob.Spec.Schedule)
+		container := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+		assert.Contains(t, container.Env, v1.EnvVar{Name: "ROOK_MON_BACKUP_RETENTION", Value: "3"})
+		assert.Contains(t, container.Env, v1.EnvVar{Name: "ROOK_MON_BACKUP_S3_BUCKET", Value: "b"})
+		assert.Contains(t, container.Env, v1.EnvVar{Name: "ROOK_MON_BACKUP_S3_PREFIX", Value: "mons/"})
+		for _, vol := range cronJob.Spec.JobTemplate.Spec.Template.Spec.Volumes {
+			assert.Nil(t, vol.PersistentVolumeClaim)
+		}
+	})
+
+	t.Run("mons on pvcs are backed up next to the mon", func(t *testing.T) {
+		d := newTestMonDeployment(namespace, "a")
+		d.Spec.Template.Spec.NodeSelector = nil
+		d.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim = &v1.PersistentVolumeClaimVolumeSource{ClaimName: "rook-ceph-mon-a"}
+		_, err := c.context.Clientset.AppsV1().Deployments(namespace).Update(ctx, d, metav1.UpdateOptions{})
+		assert.NoError(t, err)
+
+		assert.NoError(t, c.reconcileMonBackup())
+		cronJob, err := c.context.Clientset.BatchV1().CronJobs(namespace).Get(ctx, monBackupAppName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		affinity := cronJob.Spec.JobTemplate.Spec.Template.Spec.Affinity
+		assert.NotNil(t, affinity)
+		terms := affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+		assert.Equal(t, 1, len(terms))
+		assert.Equal(t, map[string]string{k8sutil.AppAttr: AppName, opcontroller.DaemonIDLabel: "a"}, terms[0].LabelSelector.MatchLabels)
+	})
+
+	t.Run("disabling backups deletes the cronjob", func(t *testing.T) {
+		c.spec.Mon.Backup = cephv1.MonBackupSpec{}
+		assert.NoError(t, c.reconcileMonBackup())
+		_, err := c.context.Clientset.BatchV1().CronJobs(namespace).Get(ctx, monBackupAppName, metav1.GetOptions{})
+		assert.True(t, kerrors.IsNotFound(err))
+	})
+}
+
+func TestStopMonForBackup(t *testing.T) {
+	namespace := "default"
+	c := newTestClusterWithCR(t, namespace, 1)
+	ctx := c.ClusterInfo.Context
+
+	okToStop := true
+	var okToStopMons []string
+	c.context.Executor = &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "mon" && args[1] == "ok-to-stop" {
+				okToStopMons = append(okToStopMons, args[2])
+				if !okToStop {
+					return "", errors.New("quorum would be lost")
+				}
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %q %v", command, args)
+		},
+	}
+	_, err := c.context.Clientset.AppsV1().Deployments(namespace).Create(ctx, newTestMonDeployment(namespace, "a"), metav1.CreateOptions{})
+	assert.NoError(t, err)
+	monReplicas := func() int32 {
+		d, err := c.context.Clientset.AppsV1().Deployments(namespace).Get(ctx, resourceName("a"), metav1.GetOptions{})
+		assert.NoError(t, err)
+		return *d.Spec.Replicas
+	}
+
+	// no backup is waiting
+	c.stopMonForBackup()
+	assert.Equal(t, "", c.monStoppedForBackup)
+	assert.Empty(t, okToStopMons)
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rook-ceph-mon-backup-1234",
+			Namespace: namespace,
+			Labels:    map[string]string{k8sutil.AppAttr: monBackupAppName, opcontroller.DaemonIDLabel: "a"},
+		},
+		Status: v1.PodStatus{
+			InitContainerStatuses: []v1.ContainerStatus{
+				{Name: monBackupCopyContainerName, State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+			},
+		},
+	}
+	_, err = c.context.Clientset.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	t.Run("mon not ok to stop", func(t *testing.T) {
+		okToStop = false
+		c.stopMonForBackup()
+		assert.Equal(t, "", c.monStoppedForBackup)
+		assert.Equal(t, []string{"a"}, okToStopMons)
+		assert.Equal(t, int32(1), monReplicas())
+	})
+
+	t.Run("mon stopped for the backup", func(t *testing.T) {
+		okToStop = true
+		c.stopMonForBackup()
+		assert.Equal(t, "a", c.monStoppedForBackup)
+		assert.Equal(t, int32(0), monReplicas())
+
+		// the mon stays stopped until its store is copied
+		c.startMonAfterBackup()
+		assert.Equal(t, "a", c.monStoppedForBackup)
+		assert.Equal(t, int32(0), monReplicas())
+	})
+
+	t.Run("mon started after the store copy", func(t *testing.T) {
+		pod.Status.InitContainerStatuses[0].State = v1.ContainerState{Terminated: &v1.ContainerStateTerminated{}}
+		_, err = c.context.Clientset.CoreV1().Pods(namespace).UpdateStatus(ctx, pod, metav1.UpdateOptions{})
+		assert.NoError(t, err)
+
+		c.startMonAfterBackup()
+		assert.Equal(t, "", c.monStoppedForBackup)
+		assert.Equal(t, int32(1), monReplicas())
+	})
+}
+
+func TestUpdateMonBackupStatus(t *testing.T) {
+	namespace := "default"
+	c := newTestClusterWithCR(t, namespace, 1)
+	ctx := c.ClusterInfo.Context
+
+	_, err := c.context.Clientset.AppsV1().Deployments(namespace).Create(ctx, newTestMonDeployment(namespace, "a"), metav1.CreateOptions{})
+	assert.NoError(t, err)
+	c.spec.Mon.Backup = cephv1.MonBackupSpec{Schedule: "@daily", PersistentVolumeClaim: "backups"}
+	assert.NoError(t, c.reconcileMonBackup())
+
+	cluster := &cephv1.CephCluster{}
+	assert.NoError(t, c.context.Client.Get(ctx, c.ClusterInfo.NamespacedName(), cluster))
+	assert.NotNil(t, cluster.Status.MonBackup)
+	assert.Nil(t, cluster.Status.MonBackup.LastSuccessfulTime)
+
+	// a successful backup is reported in the cluster status
+	cronJob, err := c.context.Clientset.BatchV1().CronJobs(namespace).Get(ctx, monBackupAppName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	lastSuccess := metav1.NewTime(time.Now().Truncate(time.Second))
+	cronJob.Status.LastScheduleTime = &lastSuccess
+	cronJob.Status.LastSuccessfulTime = &lastSuccess
+	_, err = c.context.Clientset.BatchV1().CronJobs(namespace).UpdateStatus(ctx, cronJob, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, c.updateMonBackupStatus())
+	assert.NoError(t, c.context.Client.Get(ctx, c.ClusterInfo.NamespacedName(), cluster))
+	assert.True(t, lastSuccess.Equal(cluster.Status.MonBackup.LastSuccessfulTime))
+	assert.True(t, lastSuccess.Equal(cluster.Status.MonBackup.LastScheduleTime))
+}
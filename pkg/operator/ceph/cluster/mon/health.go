@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"regexp"
 	"slices"
 	"strings"
 	"sync"
@@ -30,11 +31,13 @@ import (
 	cephutil "github.com/rook/rook/pkg/daemon/ceph/util"
 	"github.com/rook/rook/pkg/operator/ceph/config"
 	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/util/log"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
 )
 
 var (
@@ -198,6 +201,15 @@ func (c *Cluster) checkHealth(ctx context.Context) error {
 		return nil
 	}
 
+	// Keep the last backup times in the cluster status up to date between reconciles
+	if c.spec.Mon.Backup.Schedule != "" {
+		if err := c.updateMonBackupStatus(); err != nil {
+			log.NamespacedWarning(c.Namespace, logger, "failed to update mon backup status. %v", err)
+		}
+	}
+	// Start the mon stopped for a backup as soon as its store was copied
+	c.startMonAfterBackup()
+
 	// Update the mon PDBs in case any mons have joined or left the quorum.
 	// If any mons are out of quorum, this prevents more mons from being drained
 	// until we know the mon quorum is stable with full quorum.
@@ -229,6 +241,14 @@ func (c *Cluster) checkHealth(ctx context.Context) error {
 	desiredMonCount := c.spec.Mon.Count
 	log.NamespacedDebug(c.Namespace, logger, "targeting the mon count %d", desiredMonCount)
 
+	// Score the health of each mon from the quorum status and the ceph health checks
+	degradedMon := ""
+	if c.spec.Mon.HealthScoring.Enabled {
+		degradedMon = c.scoreMonHealth(quorumStatus, desiredMonCount)
+	} else if c.monHealth != nil {
+		c.clearMonHealth()
+	}
+
 	// Source of truth of which mons should exist is our *clusterInfo*
 	monsNotFound := map[string]interface{}{}
 	for _, mon := range c.ClusterInfo.InternalMonitors {
@@ -282,6 +302,12 @@ func (c *Cluster) checkHealth(ctx context.Context) error {
 			return errors.Wrapf(err, "failed to track out of quorum mon %q", mon.Name)
 		}
 
+		// a mon stopped for a backup is started again once its store was copied
+		if mon.Name == c.monStoppedForBackup {
+			log.NamespacedDebug(c.Namespace, logger, "mon %q is stopped for a mon backup, not failing it over", mon.Name)
+			continue
+		}
+
 		// if the time out is set to 0 this indicate that we don't want to trigger mon failover
 		if MonOutTimeout == timeZero {
 			log.NamespacedWarning(c.Namespace, logger, "mon %q NOT found in quorum and health timeout is 0, mon will never fail over", mon.Name)
@@ -369,6 +395,10 @@ func (c *Cluster) checkHealth(ctx context.Context) error {
 		log.NamespacedDebug(c.Namespace, logger, "mon cluster is healthy, removing any existing canary deployment")
 		c.removeCanaryDeployments(monCanaryLabelSelector)
 
+		// stop the mon whose store a backup is waiting to copy, only while all the mons are in
+		// quorum so the backup never risks the quorum
+		c.stopMonForBackup()
+
 		// Check whether two healthy mons are on the same node when they should not be.
 		// This should be a rare event to find them on the same node, so we just need to check
 		// once per operator restart.
@@ -393,6 +423,36 @@ func (c *Cluster) checkHealth(ctx context.Context) error {
 		}
 	}
 
+	// relocate a mon to the node requested in the cluster CR, only while all the mons are in quorum
+	// so the new mon can join the quorum before the relocated mon is removed
+	if allMonsInQuorum && len(quorumStatus.MonMap.Mons) == desiredMonCount {
+		for _, relocation := range c.spec.Mon.Relocations {
+			target, err := c.monRelocationTarget(ctx, relocation)
+			if err != nil {
+				log.NamespacedWarning(c.Namespace, logger, "skipping relocation of mon %q to node %q. %v", relocation.Mon, relocation.Node, err)
+				continue
+			}
+			if target == nil {
+				continue
+			}
+			log.NamespacedInfo(c.Namespace, logger, "relocating mon %q to node %q", relocation.Mon, relocation.Node)
+			if err := c.replaceMon(relocation.Mon, target); err != nil {
+				log.NamespacedError(c.Namespace, logger, "failed to relocate mon %q to node %q. %v", relocation.Mon, relocation.Node, err)
+			}
+			// only relocate one mon per health check
+			return nil
+		}
+	}
+
+	// failover a mon in quorum whose health score stayed below the failover threshold, only while
+	// all the mons are in quorum so the failover never risks the quorum
+	if degradedMon != "" && allMonsInQuorum && len(quorumStatus.MonMap.Mons) == desiredMonCount {
+		log.NamespacedWarning(c.Namespace, logger, "mon %q health score stayed below the failover threshold, mon will be failed over", degradedMon)
+		c.failMon(len(quorumStatus.MonMap.Mons), desiredMonCount, degradedMon)
+		delete(c.monHealth, degradedMon)
+		return nil
+	}
+
 	// failover any mons present in the mon fail over list
 	for _, mon := range c.ClusterInfo.InternalMonitors {
 		if _, ok := c.monsToFailover[mon.Name]; ok {
@@ -741,13 +801,25 @@ func (c *Cluster) updateMonDeploymentReplica(name string, enabled bool) error {
 
 func (c *Cluster) failoverMon(name string) error {
 	log.NamespacedInfo(c.Namespace, logger, "Failing over monitor %q", name)
+	return c.replaceMon(name, nil)
+}
 
-	// remove the failed mon from a local list of the existing mons for finding a stretch zone
-	existingMons := c.clusterInfoToMonConfigWithExclude(name)
+// replaceMon starts a new mon and removes the given mon once the new mon is in quorum. If a target
+// is set, the new mon is placed on the target node and the given mon keeps running until it is
+// removed. Otherwise, the new mon is scheduled like any other new mon.
+func (c *Cluster) replaceMon(name string, target *controller.MonScheduleInfo) error {
+	zone := ""
+	if target != nil {
+		zone = target.Zone
+	} else {
+		// remove the failed mon from a local list of the existing mons for finding a stretch zone
+		existingMons := c.clusterInfoToMonConfigWithExclude(name)
 
-	zone, err := c.findAvailableZone(existingMons)
-	if err != nil {
-		return errors.Wrap(err, "failed to find available stretch zone")
+		var err error
+		zone, err = c.findAvailableZone(existingMons)
+		if err != nil {
+			return errors.Wrap(err, "failed to find available stretch zone")
+		}
 	}
 
 	// Start a new monitor
@@ -755,7 +827,7 @@ func (c *Cluster) failoverMon(name string) error {
 	log.NamespacedInfo(c.Namespace, logger, "starting new mon: %+v", m)
 
 	// Scale down the failed mon to allow a new one to start
-	if c.stopMonDuringFailover(name) {
+	if target == nil && c.stopMonDuringFailover(name) {
 		if err := c.updateMonDeploymentReplica(name, false); err != nil {
 			// attempt to continue with the failover even if the bad mon could not be stopped
 			log.NamespacedWarning(c.Namespace, logger, "failed to stop mon %q for failover. %v", name, err)
@@ -788,7 +860,11 @@ func (c *Cluster) failoverMon(name string) error {
 		}
 	}()
 
-	// Assign the pod to a node
+	// Assign the pod to a node, the target node is assigned before since scheduling is skipped
+	// for the mons already in the node mapping
+	if target != nil {
+		c.mapping.Schedule[m.DaemonName] = target
+	}
 	mConf := []*monConfig{m}
 	if err := c.assignMons(mConf, sets.New[string]()); err != nil {
 		return errors.Wrap(err, "failed to place new mon on a node")
@@ -1095,3 +1171,284 @@ func (c *Cluster) evictMonIfMultipleOnSameNode() error {
 
 	return nil
 }
+
+const (
+	defaultMonHealthFailoverThreshold = 50
+	defaultMonHealthFailoverDelay     = 10 * time.Minute
+	// quorum changes of a mon within this window count toward its election churn
+	monElectionChurnWindow = 30 * time.Minute
+	// score lost for each quorum change of a mon within the churn window
+	monElectionChurnPenalty    = 10
+	maxMonElectionChurnPenalty = 30
+)
+
+var (
+	// score lost by a mon for each signal, except the election churn that depends on the number of quorum changes
+	monHealthSignalPenalties = map[cephv1.MonHealthSignal]int{
+		cephv1.MonHealthSignalOutOfQuorum: 60,
+		cephv1.MonHealthSignalClockSkew:   25,
+		cephv1.MonHealthSignalStoreSize:   20,
+		cephv1.MonHealthSignalSlowOps:     20,
+	}
+	// ceph health checks that name the mons they affect
+	monHealthCheckSignals = map[string]cephv1.MonHealthSignal{
+		"MON_CLOCK_SKEW": cephv1.MonHealthSignalClockSkew,
+		"MON_DISK_BIG":   cephv1.MonHealthSignalStoreSize,
+		"MON_DISK_LOW":   cephv1.MonHealthSignalStoreSize,
+		"MON_DISK_CRIT":  cephv1.MonHealthSignalStoreSize,
+		"SLOW_OPS":       cephv1.MonHealthSignalSlowOps,
+	}
+	// matches the mon daemons of messages such as "clock skew detected on mon.b, mon.c"
+	monDaemonNameRegex = regexp.MustCompile(`\bmon\.([a-z0-9-]+)`)
+	// matches the mon list of messages such as "mons a,b are low on available space"
+	monListRegex = regexp.MustCompile(`^mons? ([a-z0-9,-]+) (?:is|are) `)
+)
+
+// monHealthState is the health scoring state of a mon that is kept between health checks
+type monHealthState struct {
+	inQuorum bool
+	// the times the mon joined or left the quorum within the election churn window
+	quorumChanges []time.Time
+	// the time since the score of the mon is below the failover threshold
+	degradedSince *metav1.Time
+}
+
+// scoreMonHealth scores the health of each mon in the mon map and reports the scores in the
+// cluster status. It returns the name of the mon to fail over with the proactive failover policy,
+// or an empty string if no mon needs to be failed over.
+func (c *Cluster) scoreMonHealth(quorumStatus cephclient.MonStatusResponse, desiredMonCount int) string {
+	status, err := cephclient.Status(c.context, c.ClusterInfo)
+	if err != nil {
+		log.NamespacedWarning(c.Namespace, logger, "failed to get ceph status to score the mon health. %v", err)
+		return ""
+	}
+
+	now := time.Now()
+	scores := c.computeMonHealth(quorumStatus, status, now)
+	if err := c.updateMonHealthStatus(scores); err != nil {
+		log.NamespacedWarning(c.Namespace, logger, "failed to update mon health status. %v", err)
+	}
+	return c.monToFailoverForHealth(scores, quorumStatus, desiredMonCount, now)
+}
+
+// computeMonHealth scores each mon in the mon map, starting from 100 and subtracting the penalty of
+// each signal found for the mon
+func (c *Cluster) computeMonHealth(quorumStatus cephclient.MonStatusResponse, status cephclient.CephStatus, now time.Time) []cephv1.MonHealthStatus {
+	if c.monHealth == nil {
+		c.monHealth = map[string]*monHealthState{}
+	}
+
+	monSignals := map[string][]cephv1.MonHealthSignal{}
+	for checkName, check := range status.Health.Checks {
+		signal, ok := monHealthCheckSignals[checkName]
+		if !ok {
+			continue
+		}
+		for _, monName := range monsInHealthCheck(check) {
+			if !slices.Contains(monSignals[monName], signal) {
+				monSignals[monName] = append(monSignals[monName], signal)
+			}
+		}
+	}
+
+	threshold := c.spec.Mon.HealthScoring.FailoverThreshold
+	if threshold == 0 {
+		threshold = defaultMonHealthFailoverThreshold
+	}
+
+	scores := []cephv1.MonHealthStatus{}
+	inMonMap := map[string]bool{}
+	for _, mon := range quorumStatus.MonMap.Mons {
+		inMonMap[mon.Name] = true
+		inQuorum := monInQuorum(mon, quorumStatus.Quorum)
+
+		state, ok := c.monHealth[mon.Name]
+		if !ok {
+			state = &monHealthState{inQuorum: inQuorum}
+			c.monHealth[mon.Name] = state
+		}
+		if state.inQuorum != inQuorum {
+			state.quorumChanges = append(state.quorumChanges, now)
+			state.inQuorum = inQuorum
+		}
+		state.quorumChanges = slices.DeleteFunc(state.quorumChanges, func(t time.Time) bool {
+			return now.Sub(t) > monElectionChurnWindow
+		})
+
+		score := 100
+		signals := []cephv1.MonHealthSignal{}
+		if !inQuorum {
+			signals = append(signals, cephv1.MonHealthSignalOutOfQuorum)
+		}
+		for _, signal := range []cephv1.MonHealthSignal{cephv1.MonHealthSignalClockSkew, cephv1.MonHealthSignalStoreSize} {
+			if slices.Contains(monSignals[mon.Name], signal) {
+				signals = append(signals, signal)
+			}
+		}
+		for _, signal := range signals {
+			score -= monHealthSignalPenalties[signal]
+		}
+		if len(state.quorumChanges) > 0 {
+			signals = append(signals, cephv1.MonHealthSignalElectionChurn)
+			score -= min(len(state.quorumChanges)*monElectionChurnPenalty, maxMonElectionChurnPenalty)
+		}
+		if slices.Contains(monSignals[mon.Name], cephv1.MonHealthSignalSlowOps) {
+			signals = append(signals, cephv1.MonHealthSignalSlowOps)
+			score -= monHealthSignalPenalties[cephv1.MonHealthSignalSlowOps]
+		}
+		score = max(score, 0)
+
+		if score < threshold {
+			if state.degradedSince == nil {
+				state.degradedSince = &metav1.Time{Time: now.Truncate(time.Second)}
+				log.NamespacedWarning(c.Namespace, logger, "mon %q health score %d is below the failover threshold %d. signals: %v", mon.Name, score, threshold, signals)
+			}
+		} else if state.degradedSince != nil {
+			log.NamespacedInfo(c.Namespace, logger, "mon %q health score %d is back above the failover threshold %d", mon.Name, score, threshold)
+			state.degradedSince = nil
+		}
+
+		monScore := cephv1.MonHealthStatus{Name: mon.Name, Score: score, DegradedSince: state.degradedSince}
+		if len(signals) > 0 {
+			monScore.Signals = signals
+		}
+		scores = append(scores, monScore)
+	}
+
+	// forget the state of the mons that were removed from the mon map
+	for monName := range c.monHealth {
+		if !inMonMap[monName] {
+			delete(c.monHealth, monName)
+		}
+	}
+
+	slices.SortFunc(scores, func(a, b cephv1.MonHealthStatus) int { return strings.Compare(a.Name, b.Name) })
+	return scores
+}
+
+// monsInHealthCheck returns the names of the mons named in the summary of a ceph health check
+func monsInHealthCheck(check cephclient.CheckMessage) []string {
+	monNames := []string{}
+	for _, match := range monDaemonNameRegex.FindAllStringSubmatch(check.Summary.Message, -1) {
+		monNames = append(monNames, match[1])
+	}
+	if match := monListRegex.FindStringSubmatch(check.Summary.Message); match != nil {
+		monNames = append(monNames, strings.Split(match[1], ",")...)
+	}
+	return monNames
+}
+
+// monToFailoverForHealth returns the mon in quorum with the lowest score that stayed below the
+// failover threshold for longer than the failover delay, if the proactive failover policy is set
+func (c *Cluster) monToFailoverForHealth(scores []cephv1.MonHealthStatus, quorumStatus cephclient.MonStatusResponse, desiredMonCount int, now time.Time) string {
+	if c.spec.Mon.HealthScoring.FailoverPolicy != cephv1.MonHealthFailoverPolicyProactive {
+		return ""
+	}
+	// Failing over a mon that is still in quorum is only safe with enough mons to keep the quorum
+	// while the new mon is added and the degraded mon is removed
+	if desiredMonCount < 3 {
+		log.NamespacedDebug(c.Namespace, logger, "skipping proactive mon failover with fewer than 3 mons")
+		return ""
+	}
+
+	delay := defaultMonHealthFailoverDelay
+	if c.spec.Mon.HealthScoring.FailoverDelay != nil {
+		delay = c.spec.Mon.HealthScoring.FailoverDelay.Duration
+	}
+
+	monToFailover := ""
+	lowestScore := 0
+	for _, score := range scores {
+		if score.DegradedSince == nil || now.Sub(score.DegradedSince.Time) < delay {
+			continue
+		}
+		// mons out of quorum are failed over by the mon out timeout instead
+		if slices.Contains(score.Signals, cephv1.MonHealthSignalOutOfQuorum) {
+			continue
+		}
+		if _, ok := c.ClusterInfo.InternalMonitors[score.Name]; !ok {
+			continue
+		}
+		if monToFailover == "" || score.Score < lowestScore {
+			monToFailover = score.Name
+			lowestScore = score.Score
+		}
+	}
+	return monToFailover
+}
+
+// updateMonHealthStatus sets the mon health scores in the cluster status if they changed
+func (c *Cluster) updateMonHealthStatus(scores []cephv1.MonHealthStatus) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster := &cephv1.CephCluster{}
+		if err := c.context.Client.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cluster); err != nil {
+			return errors.Wrapf(err, "failed to get cluster %v to update the mon health status", c.ClusterInfo.NamespacedName())
+		}
+		if slices.EqualFunc(cluster.Status.MonHealth, scores, monHealthStatusEqual) {
+			return nil
+		}
+		cluster.Status.MonHealth = scores
+		return reporting.UpdateStatus(c.context.Client, cluster)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update the mon health status")
+	}
+	return nil
+}
+
+// clearMonHealth forgets the mon health scoring state and removes the scores from the cluster
+// status after the health scoring is disabled
+func (c *Cluster) clearMonHealth() {
+	if err := c.updateMonHealthStatus(nil); err != nil {
+		log.NamespacedWarning(c.Namespace, logger, "failed to clear mon health status. %v", err)
+		return
+	}
+	c.monHealth = nil
+}
+
+func monHealthStatusEqual(a, b cephv1.MonHealthStatus) bool {
+	return a.Name == b.Name && a.Score == b.Score && slices.Equal(a.Signals, b.Signals) && a.DegradedSince.Equal(b.DegradedSince)
+}
+
+// monRelocationTarget returns the node where the mon of the relocation is placed, or nil if the mon
+// does not need to be relocated
+func (c *Cluster) monRelocationTarget(ctx context.Context, relocation cephv1.MonRelocation) (*controller.MonScheduleInfo, error) {
+	if _, ok := c.ClusterInfo.InternalMonitors[relocation.Mon]; !ok {
+		log.NamespacedDebug(c.Namespace, logger, "mon %q not found, already relocated to node %q or does not exist", relocation.Mon, relocation.Node)
+		return nil, nil
+	}
+	if isFloatingMon(c, relocation.Mon) {
+		return nil, errors.New("floating mons cannot be relocated")
+	}
+	schedule, ok := c.mapping.Schedule[relocation.Mon]
+	if !ok || schedule == nil {
+		return nil, errors.New("mons not assigned to a node, such as mons on PVCs, cannot be relocated")
+	}
+	if schedule.Name == relocation.Node {
+		return nil, nil
+	}
+
+	if !c.spec.Mon.AllowMultiplePerNode {
+		for monName, monSchedule := range c.mapping.Schedule {
+			if monSchedule != nil && monSchedule.Name == relocation.Node {
+				return nil, errors.Errorf("mon %q is already on the node", monName)
+			}
+		}
+	}
+
+	node, err := c.context.Clientset.CoreV1().Nodes().Get(ctx, relocation.Node, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get node")
+	}
+	if err := k8sutil.ValidNode(*node, c.getMonPlacement(schedule.Zone), false); err != nil {
+		return nil, errors.Wrap(err, "node is not valid for a mon")
+	}
+
+	target, err := getNodeInfoFromNode(*node)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get node info")
+	}
+	// a relocated mon stays in its stretch zone
+	target.Zone = schedule.Zone
+	return target, nil
+}
@@ -21,10 +21,12 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"slices"
 	"testing"
 	"time"
 
 	csiopv1 "github.com/ceph/ceph-csi-operator/api/v1"
+	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/rook/rook/pkg/clusterd"
@@ -39,6 +41,7 @@ import (
 	"github.com/stretchr/testify/require"
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
@@ -1332,3 +1335,276 @@ func getCephCluster(name, namespace string) *cephv1.CephCluster {
 		},
 	}
 }
+
+func monHealthTestQuorumStatus(quorum ...int) cephclient.MonStatusResponse {
+	quorumStatus := cephclient.MonStatusResponse{Quorum: quorum}
+	for rank, name := range []string{"a", "b", "c"} {
+		quorumStatus.MonMap.Mons = append(quorumStatus.MonMap.Mons, cephclient.MonMapEntry{Name: name, Rank: rank})
+	}
+	return quorumStatus
+}
+
+func TestMonsInHealthCheck(t *testing.T) {
+	tests := []struct {
+		message string
+		mons    []string
+	}{
+		{"clock skew detected on mon.b, mon.c", []string{"b", "c"}},
+		{"mon a is low on available space", []string{"a"}},
+		{"mons a,c are using a lot of disk space", []string{"a", "c"}},
+		{"3 slow ops, oldest one blocked for 35 sec, daemons [mon.a,osd.1] have slow ops.", []string{"a"}},
+		{"1 osds down", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			mons := monsInHealthCheck(cephclient.CheckMessage{Summary: cephclient.Summary{Message: tt.message}})
+			assert.Equal(t, tt.mons, mons)
+		})
+	}
+}
+
+func TestComputeMonHealth(t *testing.T) {
+	c := newCluster(&clusterd.Context{}, "ns", true, v1.ResourceRequirements{})
+	c.ClusterInfo = clienttest.CreateTestClusterInfo(3)
+	now := time.Now()
+
+	t.Run("healthy mons", func(t *testing.T) {
+		scores := c.computeMonHealth(monHealthTestQuorumStatus(0, 1, 2), cephclient.CephStatus{}, now)
+		assert.Len(t, scores, 3)
+		for _, score := range scores {
+			assert.Equal(t, 100, score.Score)
+			assert.Empty(t, score.Signals)
+			assert.Nil(t, score.DegradedSince)
+		}
+	})
+
+	t.Run("signals lower the score", func(t *testing.T) {
+		status := cephclient.CephStatus{}
+		status.Health.Checks = map[string]cephclient.CheckMessage{
+			"MON_CLOCK_SKEW": {Summary: cephclient.Summary{Message: "clock skew detected on mon.b"}},
+			"MON_DISK_LOW":   {Summary: cephclient.Summary{Message: "mons b,c are low on available space"}},
+			"SLOW_OPS":       {Summary: cephclient.Summary{Message: "daemons [mon.b] have slow ops."}},
+		}
+		scores := c.computeMonHealth(monHealthTestQuorumStatus(0, 1, 2), status, now)
+		assert.Equal(t, "a", scores[0].Name)
+		assert.Equal(t, 100, scores[0].Score)
+		assert.Equal(t, "b", scores[1].Name)
+		assert.Equal(t, 35, scores[1].Score)
+		assert.Equal(t, []cephv1.MonHealthSignal{cephv1.MonHealthSignalClockSkew, cephv1.MonHealthSignalStoreSize, cephv1.MonHealthSignalSlowOps}, scores[1].Signals)
+		assert.NotNil(t, scores[1].DegradedSince)
+		assert.Equal(t, 80, scores[2].Score)
+		assert.Equal(t, []cephv1.MonHealthSignal{cephv1.MonHealthSignalStoreSize}, scores[2].Signals)
+		assert.Nil(t, scores[2].DegradedSince)
+	})
+
+	t.Run("the degraded time is reset once the score recovers", func(t *testing.T) {
+		status := cephclient.CephStatus{}
+		status.Health.Checks = map[string]cephclient.CheckMessage{
+			"MON_CLOCK_SKEW": {Summary: cephclient.Summary{Message: "clock skew detected on mon.b"}},
+			"MON_DISK_CRIT":  {Summary: cephclient.Summary{Message: "mon b is very low on available space"}},
+		}
+		scores := c.computeMonHealth(monHealthTestQuorumStatus(0, 1, 2), status, now.Add(time.Minute))
+		assert.Equal(t, 55, scores[1].Score)
+		assert.Nil(t, scores[1].DegradedSince)
+
+		c.spec.Mon.HealthScoring.FailoverThreshold = 60
+		scores = c.computeMonHealth(monHealthTestQuorumStatus(0, 1, 2), status, now.Add(2*time.Minute))
+		assert.True(t, scores[1].DegradedSince.Equal(&metav1.Time{Time: now.Add(2 * time.Minute).Truncate(time.Second)}))
+		c.spec.Mon.HealthScoring.FailoverThreshold = 0
+	})
+
+	t.Run("election churn", func(t *testing.T) {
+		// mon c leaves and joins the quorum twice
+		scores := c.computeMonHealth(monHealthTestQuorumStatus(0, 1), cephclient.CephStatus{}, now)
+		assert.Equal(t, 30, scores[2].Score)
+		assert.Equal(t, []cephv1.MonHealthSignal{cephv1.MonHealthSignalOutOfQuorum, cephv1.MonHealthSignalElectionChurn}, scores[2].Signals)
+		scores = c.computeMonHealth(monHealthTestQuorumStatus(0, 1, 2), cephclient.CephStatus{}, now)
+		assert.Equal(t, 80, scores[2].Score)
+		c.computeMonHealth(monHealthTestQuorumStatus(0, 1), cephclient.CephStatus{}, now)
+		scores = c.computeMonHealth(monHealthTestQuorumStatus(0, 1, 2), cephclient.CephStatus{}, now)
+		assert.Equal(t, 70, scores[2].Score)
+
+		// the churn penalty is capped
+		c.computeMonHealth(monHealthTestQuorumStatus(0, 1), cephclient.CephStatus{}, now)
+		scores = c.computeMonHealth(monHealthTestQuorumStatus(0, 1, 2), cephclient.CephStatus{}, now)
+		assert.Equal(t, 70, scores[2].Score)
+
+		// the quorum changes expire after the churn window
+		scores = c.computeMonHealth(monHealthTestQuorumStatus(0, 1, 2), cephclient.CephStatus{}, now.Add(monElectionChurnWindow+time.Minute))
+		assert.Equal(t, 100, scores[2].Score)
+		assert.Empty(t, scores[2].Signals)
+	})
+
+	t.Run("removed mons are forgotten", func(t *testing.T) {
+		quorumStatus := monHealthTestQuorumStatus(0, 1)
+		quorumStatus.MonMap.Mons = quorumStatus.MonMap.Mons[:2]
+		scores := c.computeMonHealth(quorumStatus, cephclient.CephStatus{}, now)
+		assert.Len(t, scores, 2)
+		assert.NotContains(t, c.monHealth, "c")
+	})
+}
+
+func TestMonToFailoverForHealth(t *testing.T) {
+	c := newCluster(&clusterd.Context{}, "ns", true, v1.ResourceRequirements{})
+	c.ClusterInfo = clienttest.CreateTestClusterInfo(3)
+	now := time.Now()
+	degradedSince := metav1.NewTime(now.Add(-15 * time.Minute))
+	recentlyDegraded := metav1.NewTime(now.Add(-time.Minute))
+	scores := []cephv1.MonHealthStatus{
+		{Name: "a", Score: 100},
+		{Name: "b", Score: 35, DegradedSince: &degradedSince, Signals: []cephv1.MonHealthSignal{cephv1.MonHealthSignalClockSkew}},
+		{Name: "c", Score: 20, DegradedSince: &recentlyDegraded, Signals: []cephv1.MonHealthSignal{cephv1.MonHealthSignalSlowOps}},
+	}
+	quorumStatus := monHealthTestQuorumStatus(0, 1, 2)
+
+	// the score is only reported by default
+	assert.Equal(t, "", c.monToFailoverForHealth(scores, quorumStatus, 3, now))
+
+	c.spec.Mon.HealthScoring.FailoverPolicy = cephv1.MonHealthFailoverPolicyProactive
+	assert.Equal(t, "b", c.monToFailoverForHealth(scores, quorumStatus, 3, now))
+
+	// not enough mons to keep the quorum during the failover
+	assert.Equal(t, "", c.monToFailoverForHealth(scores, quorumStatus, 1, now))
+
+	// the mon with the lowest score is failed over first once the delay passed
+	c.spec.Mon.HealthScoring.FailoverDelay = &metav1.Duration{Duration: 30 * time.Second}
+	assert.Equal(t, "c", c.monToFailoverForHealth(scores, quorumStatus, 3, now))
+
+	// mons out of quorum are left to the mon out timeout
+	scores[2].Signals = []cephv1.MonHealthSignal{cephv1.MonHealthSignalOutOfQuorum}
+	assert.Equal(t, "b", c.monToFailoverForHealth(scores, quorumStatus, 3, now))
+}
+
+func TestUpdateMonHealthStatus(t *testing.T) {
+	c := newTestClusterWithCR(t, "default", 3)
+	ctx := c.ClusterInfo.Context
+	degradedSince := metav1.NewTime(time.Now().Truncate(time.Second))
+	scores := []cephv1.MonHealthStatus{
+		{Name: "a", Score: 100},
+		{Name: "b", Score: 35, DegradedSince: &degradedSince, Signals: []cephv1.MonHealthSignal{cephv1.MonHealthSignalClockSkew}},
+	}
+
+	assert.NoError(t, c.updateMonHealthStatus(scores))
+	cluster := &cephv1.CephCluster{}
+	assert.NoError(t, c.context.Client.Get(ctx, c.ClusterInfo.NamespacedName(), cluster))
+	assert.Len(t, cluster.Status.MonHealth, 2)
+	assert.True(t, slices.EqualFunc(scores, cluster.Status.MonHealth, monHealthStatusEqual))
+
+	// the scores are cleared when the health scoring is disabled
+	c.monHealth = map[string]*monHealthState{"a": {inQuorum: true}}
+	c.clearMonHealth()
+	assert.Nil(t, c.monHealth)
+	assert.NoError(t, c.context.Client.Get(ctx, c.ClusterInfo.NamespacedName(), cluster))
+	assert.Empty(t, cluster.Status.MonHealth)
+}
+
+func TestMonRelocationTarget(t *testing.T) {
+	ctx := context.TODO()
+	clientset := test.New(t, 3)
+	c := newCluster(&clusterd.Context{Clientset: clientset}, "ns", false, v1.ResourceRequirements{})
+	c.ClusterInfo = clienttest.CreateTestClusterInfo(3)
+	c.mapping.Schedule["a"] = &opcontroller.MonScheduleInfo{Name: "node0"}
+	c.mapping.Schedule["b"] = &opcontroller.MonScheduleInfo{Name: "node1", Zone: "zone1"}
+
+	t.Run("relocated mon", func(t *testing.T) {
+		target, err := c.monRelocationTarget(ctx, cephv1.MonRelocation{Mon: "z", Node: "node2"})
+		assert.NoError(t, err)
+		assert.Nil(t, target)
+	})
+
+	t.Run("mon already on the node", func(t *testing.T) {
+		target, err := c.monRelocationTarget(ctx, cephv1.MonRelocation{Mon: "a", Node: "node0"})
+		assert.NoError(t, err)
+		assert.Nil(t, target)
+	})
+
+	t.Run("mon not assigned to a node", func(t *testing.T) {
+		_, err := c.monRelocationTarget(ctx, cephv1.MonRelocation{Mon: "c", Node: "node2"})
+		assert.ErrorContains(t, err, "cannot be relocated")
+	})
+
+	t.Run("node with another mon", func(t *testing.T) {
+		_, err := c.monRelocationTarget(ctx, cephv1.MonRelocation{Mon: "a", Node: "node1"})
+		assert.ErrorContains(t, err, `mon "b" is already on the node`)
+	})
+
+	t.Run("node not found", func(t *testing.T) {
+		_, err := c.monRelocationTarget(ctx, cephv1.MonRelocation{Mon: "a", Node: "node9"})
+		assert.ErrorContains(t, err, "failed to get node")
+	})
+
+	t.Run("cordoned node", func(t *testing.T) {
+		node, err := clientset.CoreV1().Nodes().Get(ctx, "node2", metav1.GetOptions{})
+		assert.NoError(t, err)
+		node.Spec.Unschedulable = true
+		_, err = clientset.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+		assert.NoError(t, err)
+		_, err = c.monRelocationTarget(ctx, cephv1.MonRelocation{Mon: "b", Node: "node2"})
+		assert.ErrorContains(t, err, "unschedulable")
+
+		node.Spec.Unschedulable = false
+		_, err = clientset.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("valid target keeps the zone", func(t *testing.T) {
+		target, err := c.monRelocationTarget(ctx, cephv1.MonRelocation{Mon: "b", Node: "node2"})
+		assert.NoError(t, err)
+		assert.Equal(t, "node2", target.Name)
+		assert.Equal(t, "zone1", target.Zone)
+		assert.NotEmpty(t, target.Address)
+	})
+}
+
+func TestRelocateMon(t *testing.T) {
+	ctx := context.TODO()
+	var deploymentsUpdated *[]*apps.Deployment
+	updateDeploymentAndWait, deploymentsUpdated = testopk8s.UpdateDeploymentAndWaitStub()
+
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "auth" && args[1] == "get-or-create-key" {
+				return "{\"key\":\"mysecurekey\"}", nil
+			}
+			return clienttest.MonInQuorumResponse(), nil
+		},
+	}
+	clientset := test.New(t, 2)
+	context := &clusterd.Context{
+		Clientset: clientset,
+		ConfigDir: t.TempDir(),
+		Executor:  executor,
+		Client:    getClient(t, getCephCluster("default", "default")),
+	}
+	c := New(ctx, context, "ns", cephv1.ClusterSpec{}, cephclient.NewMinimumOwnerInfoWithOwnerRef())
+	setCommonMonProperties(c, 1, cephv1.MonSpec{Count: 1}, "myversion")
+	c.maxMonID = 0
+	c.waitForStart = false
+	c.ClusterInfo.Context = ctx
+	c.mapping.Schedule["a"] = &opcontroller.MonScheduleInfo{Name: "node0"}
+	assert.NoError(t, c.saveMonConfig())
+	assert.NoError(t, c.startMon(c.clusterInfoToMonConfig()[0], c.mapping.Schedule["a"]))
+	testopk8s.ClearDeploymentsUpdated(deploymentsUpdated)
+
+	// the scheduler must not be used for a relocated mon
+	defer func(f func(*Cluster, *apps.Deployment) (SchedulingResult, error)) { waitForMonitorScheduling = f }(waitForMonitorScheduling)
+	waitForMonitorScheduling = func(c *Cluster, d *apps.Deployment) (SchedulingResult, error) {
+		return SchedulingResult{}, errors.New("unexpected scheduling")
+	}
+
+	target, err := c.monRelocationTarget(ctx, cephv1.MonRelocation{Mon: "a", Node: "node1"})
+	assert.NoError(t, err)
+	assert.NoError(t, c.replaceMon("a", target))
+
+	// the relocated mon was never stopped before the new mon started
+	assert.Empty(t, testopk8s.DeploymentNamesUpdated(deploymentsUpdated))
+	assert.NotContains(t, c.ClusterInfo.InternalMonitors, "a")
+	assert.Contains(t, c.ClusterInfo.InternalMonitors, "b")
+	assert.Equal(t, "node1", c.mapping.Schedule["b"].Name)
+
+	deployment, err := clientset.AppsV1().Deployments(c.Namespace).Get(ctx, "rook-ceph-mon-b", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "node1", deployment.Spec.Template.Spec.NodeSelector[v1.LabelHostname])
+	_, err = clientset.AppsV1().Deployments(c.Namespace).Get(ctx, "rook-ceph-mon-a", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+}
@@ -0,0 +1,16 @@
+#!/usr/bin/env bash
+set -o errexit
+set -o nounset
+set -o pipefail
+
+# The store of a running mon changes while it is read, so it is only copied once the operator
+# stopped the mon. The mon holds the lock of the store while it runs, so the copy fails until the
+# mon is stopped.
+COPY_DIR="${ROOK_MON_BACKUP_STAGING_DIR}/store"
+
+echo "waiting for mon.${ROOK_MON_ID} to be stopped by the operator to copy its store"
+until ceph-monstore-tool "${ROOK_MON_DATA_DIR}" store-copy "${COPY_DIR}" >/dev/null 2>&1; do
+  rm -rf "${COPY_DIR}"
+  sleep 5
+done
+echo "copied the store of mon.${ROOK_MON_ID}"
@@ -0,0 +1,22 @@
+#!/usr/bin/env bash
+set -o errexit
+set -o nounset
+set -o pipefail
+
+# Fetch the backup from the backup target and extract the mon store it contains.
+ARCHIVE="${ROOK_MON_BACKUP_RESTORE_DIR}/${ROOK_MON_BACKUP_NAME}.tar.gz"
+
+echo "fetching backup ${ROOK_MON_BACKUP_NAME}"
+if [[ -n "${ROOK_MON_BACKUP_S3_BUCKET:-}" ]]; then
+  s5cmd --endpoint-url "${ROOK_MON_BACKUP_S3_ENDPOINT}" cp \
+    "s3://${ROOK_MON_BACKUP_S3_BUCKET}/${ROOK_MON_BACKUP_S3_PREFIX}${ROOK_MON_BACKUP_NAME}.tar.gz" "${ARCHIVE}"
+else
+  cp "${ROOK_MON_BACKUP_DIR}/${ROOK_MON_BACKUP_NAME}.tar.gz" "${ARCHIVE}"
+fi
+
+tar -xzf "${ARCHIVE}" -C "${ROOK_MON_BACKUP_RESTORE_DIR}"
+rm -f "${ARCHIVE}"
+if [ ! -d "${ROOK_MON_BACKUP_RESTORE_DIR}/store/store.db" ]; then
+  echo "backup ${ROOK_MON_BACKUP_NAME} does not contain a mon store"
+  exit 1
+fi
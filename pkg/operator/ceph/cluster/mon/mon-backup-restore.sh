@@ -0,0 +1,30 @@
+#!/usr/bin/env bash
+set -o errexit
+set -o nounset
+set -o pipefail
+
+# The store of the backup replaces the store of the mon. The backup may be from another mon, and the
+# other mons are removed after the restore, so the monmap of the store is reduced to this mon at its
+# current address.
+STORE_DIR="${ROOK_MON_BACKUP_RESTORE_DIR}/store"
+MONMAP="${ROOK_MON_BACKUP_RESTORE_DIR}/monmap"
+
+ceph-monstore-tool "${STORE_DIR}" get monmap -- --out "${MONMAP}"
+if ! monmaptool --print "${MONMAP}" | grep -q "^fsid ${ROOK_FSID}$"; then
+  echo "the backup is not from the cluster with fsid ${ROOK_FSID}"
+  exit 1
+fi
+for mon in $(monmaptool --print "${MONMAP}" | sed -n 's/^[0-9]*: .* mon\.\(.*\)$/\1/p'); do
+  monmaptool --rm "${mon}" "${MONMAP}"
+done
+monmaptool --addv "${ROOK_MON_ID}" "${ROOK_MON_ADDRS}" "${MONMAP}"
+
+# keep the lost store in case it is needed for a later investigation
+if [ -d "${ROOK_MON_DATA_DIR}/store.db" ]; then
+  mv "${ROOK_MON_DATA_DIR}/store.db" "${ROOK_MON_DATA_DIR}/store.db.lost-$(date -u +%Y%m%d-%H%M%S)"
+fi
+mv "${STORE_DIR}/store.db" "${ROOK_MON_DATA_DIR}/store.db"
+
+ceph-mon --id "${ROOK_MON_ID}" --mon-data "${ROOK_MON_DATA_DIR}" --inject-monmap "${MONMAP}"
+chown -R ceph:ceph "${ROOK_MON_DATA_DIR}"
+echo "restored the store of mon.${ROOK_MON_ID}"
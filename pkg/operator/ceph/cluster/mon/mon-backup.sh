@@ -0,0 +1,37 @@
+#!/usr/bin/env bash
+set -o errexit
+set -o nounset
+set -o pipefail
+
+# The init container copied the store of the mon while the mon was stopped, so the copy is a
+# consistent snapshot of the mon store.
+BACKUP_NAME="mon-backup-$(date -u +%Y%m%d-%H%M%S)"
+ARCHIVE="$(mktemp -d)/${BACKUP_NAME}.tar.gz"
+
+echo "archiving the store of mon.${ROOK_MON_ID} to backup ${BACKUP_NAME}"
+tar -C "${ROOK_MON_BACKUP_STAGING_DIR}" -czf "${ARCHIVE}" store
+
+if [[ -n "${ROOK_MON_BACKUP_S3_BUCKET:-}" ]]; then
+  S5CMD=(s5cmd --endpoint-url "${ROOK_MON_BACKUP_S3_ENDPOINT}")
+  TARGET="s3://${ROOK_MON_BACKUP_S3_BUCKET}/${ROOK_MON_BACKUP_S3_PREFIX}"
+  "${S5CMD[@]}" cp "${ARCHIVE}" "${TARGET}${BACKUP_NAME}.tar.gz"
+
+  # the backup names sort by creation time, so the oldest backups are listed first
+  mapfile -t BACKUPS < <("${S5CMD[@]}" ls "${TARGET}mon-backup-*.tar.gz" | awk '{print $NF}' | sort)
+  for ((i = 0; i < ${#BACKUPS[@]} - ROOK_MON_BACKUP_RETENTION; i++)); do
+    echo "removing old backup ${BACKUPS[$i]}"
+    "${S5CMD[@]}" rm "${TARGET}${BACKUPS[$i]}"
+  done
+else
+  cp "${ARCHIVE}" "${ROOK_MON_BACKUP_DIR}/"
+  sync
+
+  mapfile -t BACKUPS < <(find "${ROOK_MON_BACKUP_DIR}" -maxdepth 1 -name 'mon-backup-*.tar.gz' -printf '%f\n' | sort)
+  for ((i = 0; i < ${#BACKUPS[@]} - ROOK_MON_BACKUP_RETENTION; i++)); do
+    echo "removing old backup ${BACKUPS[$i]}"
+    rm -f "${ROOK_MON_BACKUP_DIR}/${BACKUPS[$i]}"
+  done
+fi
+
+rm -f "${ARCHIVE}"
+echo "mon store backup ${BACKUP_NAME} completed"
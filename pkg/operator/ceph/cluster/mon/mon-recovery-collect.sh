@@ -0,0 +1,33 @@
+#!/usr/bin/env bash
+set -o errexit
+set -o nounset
+set -o pipefail
+
+# The mon store is passed from OSD to OSD by the operator so that it accumulates the maps of all
+# the OSDs. The operator uploads the store collected so far before this OSD is added to it, and
+# downloads it again once the store is marked as collected.
+OSD_DATA_DIR=/var/lib/ceph/osd/ceph-"${ROOK_OSD_ID}"
+STORE_DIR="${ROOK_MON_RECOVERY_DIR}/store"
+KEYRING="${ROOK_MON_RECOVERY_DIR}/keyring"
+
+until [ -f "${ROOK_MON_RECOVERY_DIR}/.uploaded" ]; do
+  sleep 1
+done
+
+echo "collecting the cluster maps from osd.${ROOK_OSD_ID}"
+mkdir -p "${STORE_DIR}"
+ceph-objectstore-tool --data-path "${OSD_DATA_DIR}" --no-mon-config --op update-mon-db --mon-store-path "${STORE_DIR}"
+
+# the OSD keys are not part of the cluster maps, so they are collected separately to be imported
+# into the rebuilt store
+if [ ! -f "${KEYRING}" ]; then
+  ceph-authtool --create-keyring "${KEYRING}"
+fi
+ceph-authtool "${KEYRING}" --import-keyring "${OSD_DATA_DIR}/keyring"
+ceph-authtool "${KEYRING}" -n "osd.${ROOK_OSD_ID}" --cap mon 'allow profile osd' --cap mgr 'allow profile osd' --cap osd 'allow *'
+
+touch "${ROOK_MON_RECOVERY_DIR}/.done"
+echo "collected the cluster maps from osd.${ROOK_OSD_ID}"
+
+# keep running until the operator downloaded the store and deleted the job
+sleep infinity
@@ -0,0 +1,37 @@
+#!/usr/bin/env bash
+set -o errexit
+set -o nounset
+set -o pipefail
+
+# The operator uploads the mon store collected from all the OSDs, which is rebuilt here and
+# replaces the store of the mon.
+STORE_DIR="${ROOK_MON_RECOVERY_DIR}/store"
+KEYRING="${ROOK_MON_RECOVERY_DIR}/keyring"
+
+until [ -f "${ROOK_MON_RECOVERY_DIR}/.uploaded" ]; do
+  sleep 1
+done
+
+# the mon and admin keys are shared by all the mons, the keys of the other daemons are created
+# again by the operator when they are reconciled
+ceph-authtool "${KEYRING}" --import-keyring "${ROOK_MON_KEYRING}"
+
+echo "rebuilding the store of mon.${ROOK_MON_ID}"
+ceph-monstore-tool "${STORE_DIR}" rebuild -- --keyring "${KEYRING}" --mon-ids "${ROOK_MON_ID}"
+
+# keep the lost store in case it is needed for a later investigation
+if [ -d "${ROOK_MON_DATA_DIR}/store.db" ]; then
+  mv "${ROOK_MON_DATA_DIR}/store.db" "${ROOK_MON_DATA_DIR}/store.db.lost-$(date -u +%Y%m%d-%H%M%S)"
+fi
+mv "${STORE_DIR}/store.db" "${ROOK_MON_DATA_DIR}/store.db"
+
+# the rebuilt store does not know the address of the mon, so a monmap with only this mon is injected
+monmaptool --create --clobber --fsid "${ROOK_FSID}" --addv "${ROOK_MON_ID}" "${ROOK_MON_ADDRS}" --enable-all-features /tmp/monmap
+ceph-mon --id "${ROOK_MON_ID}" --mon-data "${ROOK_MON_DATA_DIR}" --inject-monmap /tmp/monmap
+chown -R ceph:ceph "${ROOK_MON_DATA_DIR}"
+
+touch "${ROOK_MON_RECOVERY_DIR}/.done"
+echo "restored the store of mon.${ROOK_MON_ID}"
+
+# keep running until the operator deleted the job
+sleep infinity
@@ -140,8 +140,12 @@ type Cluster struct {
 	arbiterMon         string
 	// list of mons to be failed over
 	monsToFailover map[string]*monConfig
+	// health scoring state of each mon, kept between health checks
+	monHealth map[string]*monHealthState
 	// reference to the secret that stores mon key
 	monKeySecretResourceVersion string
+	// the mon stopped until a backup job copied its store
+	monStoppedForBackup string
 }
 
 // monConfig for a single monitor
@@ -329,6 +333,11 @@ func (c *Cluster) startMons(targetCount int) error {
 		return errors.Wrap(err, "failed to reconcile mon PDB")
 	}
 
+	// reconcile the periodic mon backups. A failure must not block the rest of the mon reconcile.
+	if err := c.reconcileMonBackup(); err != nil {
+		log.NamespacedWarning(c.Namespace, logger, "failed to reconcile mon backup. %v", err)
+	}
+
 	// Check if there are orphaned mon resources that should be cleaned up at the end of a reconcile.
 	// There may be orphaned resources if a mon failover was aborted.
 	c.removeOrphanMonResources()
@@ -139,6 +139,26 @@ func newCluster(context *clusterd.Context, namespace string, allowMultiplePerNod
 	}
 }
 
+// newTestClusterWithCR returns a mon cluster with the given number of mons in the cluster info and
+// a CephCluster in the controller-runtime client, for tests that update the cluster status
+func newTestClusterWithCR(t *testing.T, namespace string, monCount int) *Cluster {
+	context, err := newTestStartCluster(t, namespace)
+	assert.NoError(t, err)
+	c := newCluster(context, namespace, true, v1.ResourceRequirements{})
+	c.ClusterInfo = clienttest.CreateTestClusterInfo(monCount)
+
+	cluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      namespace,
+			Namespace: namespace,
+		},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{})
+	c.context.Client = fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(cluster).WithStatusSubresource(cluster).Build()
+	return c
+}
+
 // setCommonMonProperties is a convenience helper for setting common test properties
 func setCommonMonProperties(c *Cluster, currentMons int, mon cephv1.MonSpec, rookImage string) {
 	c.ClusterInfo = clienttest.CreateTestClusterInfo(currentMons)
@@ -0,0 +1,510 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config/keyring"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/exec"
+	"github.com/rook/rook/pkg/util/log"
+	apps "k8s.io/api/apps/v1"
+	batch "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+//go:embed mon-recovery-collect.sh
+var monRecoveryCollectScript string
+
+//go:embed mon-recovery-restore.sh
+var monRecoveryRestoreScript string
+
+const (
+	monRecoveryAppName       = "rook-ceph-mon-recovery"
+	monRecoveryContainerName = "mon-recovery"
+	// the directory of the recovery jobs where the operator uploads and downloads the mon store
+	monRecoveryDir = "/var/lib/ceph/mon-recovery"
+	// can't use the osd package constants due to a circular dependency
+	osdAppName            = "rook-ceph-osd"
+	osdIDLabelKey         = "ceph-osd-id"
+	osdOverPVCLabelKey    = "ceph.rook.io/pvc"
+	osdCVModeEnvVarName   = "ROOK_CV_MODE"
+	osdContainerName      = "osd"
+	osdKeyUpdateContainer = "cephx-keyring-update"
+)
+
+var (
+	// hooks for tests to override
+	uploadMonRecoveryStore   = execUploadMonRecoveryStore
+	downloadMonRecoveryStore = execDownloadMonRecoveryStore
+
+	monRecoveryPollInterval = 5 * time.Second
+	// collecting the maps from an OSD with a long map history can take a while
+	monRecoveryJobTimeout = 30 * time.Minute
+)
+
+// RecoverQuorumFromOSDs rebuilds the mon store from the cluster maps kept by the OSDs and restarts
+// a single mon with the rebuilt store. This is only meant for a disaster where all the mons are
+// lost, and is requested with the RecoverMonQuorumAnnotationKey annotation on the CephCluster.
+// The other mons are removed and are replaced by new mons in the next mon reconcile.
+func (c *Cluster) RecoverQuorumFromOSDs(clusterInfo *cephclient.ClusterInfo, rookImage string, cephVersion cephver.CephVersion, spec cephv1.ClusterSpec) error {
+	c.acquireOrchestrationLock()
+	defer c.releaseOrchestrationLock()
+
+	clusterInfo.OwnerInfo = c.ownerInfo
+	c.ClusterInfo = clusterInfo
+	c.rookImage = rookImage
+	c.spec = spec
+
+	var monName string
+	err := c.initClusterInfo(cephVersion, c.ClusterInfo.NamespacedName().Name)
+	if err != nil {
+		// the cluster info is not set if it failed to load
+		c.ClusterInfo = clusterInfo
+		err = errors.Wrap(err, "failed to initialize ceph cluster info")
+	} else {
+		monName, err = c.recoverQuorumFromOSDs()
+	}
+	if err != nil {
+		c.updateMonQuorumRecoveryCondition(corev1.ConditionFalse, cephv1.MonQuorumRecoveryFailedReason, err.Error())
+		return err
+	}
+	c.updateMonQuorumRecoveryCondition(corev1.ConditionFalse, cephv1.MonQuorumRecoveryCompletedReason,
+		fmt.Sprintf("mon %q was restarted with the mon store rebuilt from the OSDs", monName))
+	return nil
+}
+
+func (c *Cluster) recoverQuorumFromOSDs() (monName string, err error) {
+	m, monDeployment, err := c.firstMonWithDeployment()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find the mon to restore the mon store to")
+	}
+	log.NamespacedInfo(c.Namespace, logger, "recovering mon quorum from the osds with mon %q", m.DaemonName)
+
+	// all the recovery jobs are generated before any daemon is stopped so that OSDs that cannot be
+	// read by the recovery fail it while the cluster is still untouched
+	osdDeployments, err := c.context.Clientset.AppsV1().Deployments(c.Namespace).List(c.ClusterInfo.Context, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, osdAppName)})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list osd deployments")
+	}
+	if len(osdDeployments.Items) == 0 {
+		return "", errors.New("no osd deployments found to rebuild the mon store from")
+	}
+	sortDeploymentsByOSDID(osdDeployments.Items)
+	collectJobs := make([]*batch.Job, 0, len(osdDeployments.Items))
+	for i := range osdDeployments.Items {
+		job, err := c.makeOSDCollectJob(&osdDeployments.Items[i])
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to generate the mon recovery job for osd deployment %q", osdDeployments.Items[i].Name)
+		}
+		collectJobs = append(collectJobs, job)
+	}
+	restoreJob, err := c.makeMonRestoreJob(m, monDeployment)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to generate the mon recovery job for mon %q", m.DaemonName)
+	}
+
+	// the mon store is kept in a file between the jobs rather than in memory since it grows with
+	// the osd map history
+	storeFile, err := os.CreateTemp(c.context.ConfigDir, "mon-recovery-store-")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create the mon store file")
+	}
+	storePath := storeFile.Name()
+	defer os.Remove(storePath)
+	if err := storeFile.Close(); err != nil {
+		return "", errors.Wrap(err, "failed to close the mon store file")
+	}
+
+	c.updateMonQuorumRecoveryProgress("scaling down the mons and OSDs")
+	scaled, err := c.scaleDownForMonRecovery()
+	defer func() {
+		// the daemons are scaled back up after a failure so the cluster is left as it was found
+		if err != nil {
+			if scaleErr := c.scaleUpAfterMonRecovery(scaled); scaleErr != nil {
+				log.NamespacedWarning(c.Namespace, logger, "failed to scale up the daemons after the failed mon quorum recovery. %v", scaleErr)
+			}
+		}
+	}()
+	if err != nil {
+		return "", err
+	}
+
+	// the mon store is passed from OSD to OSD so it accumulates the maps of all the OSDs
+	for i, job := range collectJobs {
+		d := &osdDeployments.Items[i]
+		c.updateMonQuorumRecoveryProgress(fmt.Sprintf("collecting the cluster maps from osd %s (%d/%d)", d.Labels[osdIDLabelKey], i+1, len(collectJobs)))
+		if err = c.runMonRecoveryJob(job, storePath, true); err != nil {
+			return "", errors.Wrapf(err, "failed to collect the cluster maps from osd deployment %q", d.Name)
+		}
+	}
+
+	c.updateMonQuorumRecoveryProgress(fmt.Sprintf("rebuilding the store of mon %q", m.DaemonName))
+	if err = c.runMonRecoveryJob(restoreJob, storePath, false); err != nil {
+		return "", errors.Wrapf(err, "failed to restore the store of mon %q", m.DaemonName)
+	}
+
+	c.updateMonQuorumRecoveryProgress(fmt.Sprintf("restarting mon %q with the rebuilt store", m.DaemonName))
+	if err = c.restartMonWithRestoredStore(m); err != nil {
+		return "", err
+	}
+
+	c.updateMonQuorumRecoveryProgress("scaling up the OSDs")
+	if err = c.scaleUpAfterMonRecovery(scaled); err != nil {
+		return "", err
+	}
+
+	log.NamespacedInfo(c.Namespace, logger, "mon quorum recovered from the osds with mon %q", m.DaemonName)
+	return m.DaemonName, nil
+}
+
+// scaleDownForMonRecovery stops all the mons and OSDs since the OSD stores can only be read when
+// the OSDs are not running. The names of the deployments that were scaled down are returned, also
+// on failure, so they can be scaled back up.
+func (c *Cluster) scaleDownForMonRecovery() ([]string, error) {
+	var scaled []string
+	for _, appName := range []string{AppName, FloatingMonAppName, osdAppName} {
+		deployments, err := c.context.Clientset.AppsV1().Deployments(c.Namespace).List(c.ClusterInfo.Context, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, appName)})
+		if err != nil {
+			return scaled, errors.Wrapf(err, "failed to list %q deployments", appName)
+		}
+		for _, d := range deployments.Items {
+			if d.Spec.Replicas != nil && *d.Spec.Replicas == 0 {
+				continue
+			}
+			if err := c.scaleDeployment(d.Name, 0); err != nil {
+				return scaled, err
+			}
+			scaled = append(scaled, d.Name)
+		}
+	}
+
+	err := wait.PollUntilContextTimeout(c.ClusterInfo.Context, monRecoveryPollInterval, monRecoveryJobTimeout, true, func(ctx context.Context) (bool, error) {
+		for _, appName := range []string{AppName, FloatingMonAppName, osdAppName} {
+			pods, err := c.context.Clientset.CoreV1().Pods(c.Namespace).List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, appName)})
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to list %q pods", appName)
+			}
+			if len(pods.Items) > 0 {
+				log.NamespacedInfo(c.Namespace, logger, "waiting for %d %q pod(s) to stop", len(pods.Items), appName)
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return scaled, errors.Wrap(err, "failed to wait for the mons and osds to stop")
+	}
+	return scaled, nil
+}
+
+// scaleUpAfterMonRecovery scales the deployments stopped for the recovery back up. The mons that
+// were removed by the recovery no longer have a deployment and are skipped.
+func (c *Cluster) scaleUpAfterMonRecovery(names []string) error {
+	var lastErr error
+	for _, name := range names {
+		err := c.scaleDeployment(name, 1)
+		if err == nil || kerrors.IsNotFound(errors.Cause(err)) {
+			continue
+		}
+		log.NamespacedWarning(c.Namespace, logger, "%v", err)
+		lastErr = err
+	}
+	if lastErr != nil {
+		return errors.Wrap(lastErr, "failed to scale up the daemons after the mon quorum recovery")
+	}
+	return nil
+}
+
+func sortDeploymentsByOSDID(deployments []apps.Deployment) {
+	slices.SortFunc(deployments, func(a, b apps.Deployment) int {
+		idA, _ := strconv.Atoi(a.Labels[osdIDLabelKey])
+		idB, _ := strconv.Atoi(b.Labels[osdIDLabelKey])
+		return idA - idB
+	})
+}
+
+// makeOSDCollectJob generates the job that adds the maps of an OSD to the mon store. The job is
+// derived from the OSD deployment so that the OSD is activated the same way as the OSD daemon.
+func (c *Cluster) makeOSDCollectJob(d *apps.Deployment) (*batch.Job, error) {
+	osdID, ok := d.Labels[osdIDLabelKey]
+	if !ok {
+		return nil, errors.Errorf("osd deployment %q has no %q label", d.Name, osdIDLabelKey)
+	}
+	podSpec := d.Spec.Template.Spec.DeepCopy()
+	osdContainer, err := k8sutil.GetContainerByName(podSpec.Containers, osdContainerName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the osd container of deployment %q", d.Name)
+	}
+
+	// an lvm osd on a pvc is only activated by the "rook ceph osd start" command of the osd
+	// container, so its data dir is never populated for the recovery container. An empty mode means
+	// the osd was created before the mode was recorded and is in lvm mode.
+	if pvcName, onPVC := d.Labels[osdOverPVCLabelKey]; onPVC {
+		if cvMode := envVarValue(osdContainer.Env, osdCVModeEnvVarName); cvMode == "" || cvMode == "lvm" {
+			return nil, errors.Errorf("osd %s on pvc %q was created in lvm mode, which is not supported by the mon quorum recovery", osdID, pvcName)
+		}
+	}
+
+	// the cephx key update requires the mons, so the OSD starts with its on-disk key like it does
+	// when the key cannot be fetched
+	var initContainers []corev1.Container
+	for _, initContainer := range podSpec.InitContainers {
+		if initContainer.Name != osdKeyUpdateContainer {
+			initContainers = append(initContainers, initContainer)
+		}
+	}
+	podSpec.InitContainers = initContainers
+	podSpec.Containers = []corev1.Container{
+		monRecoveryContainer(*osdContainer, monRecoveryCollectScript, corev1.EnvVar{Name: "ROOK_OSD_ID", Value: osdID}),
+	}
+
+	return c.makeMonRecoveryJob(fmt.Sprintf("%s-osd-%s", monRecoveryAppName, osdID), podSpec)
+}
+
+// makeMonRestoreJob generates the job that rebuilds the mon store and restores it to the mon. The
+// job is derived from the mon deployment so that it mounts the store of the mon.
+func (c *Cluster) makeMonRestoreJob(m *monConfig, d *apps.Deployment) (*batch.Job, error) {
+	podSpec := d.Spec.Template.Spec.DeepCopy()
+	monContainer, err := k8sutil.GetContainerByName(podSpec.Containers, monContainerName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the mon container of deployment %q", d.Name)
+	}
+
+	podSpec.Containers = []corev1.Container{
+		monRecoveryContainer(*monContainer, monRecoveryRestoreScript,
+			corev1.EnvVar{Name: "ROOK_MON_ID", Value: m.DaemonName},
+			corev1.EnvVar{Name: "ROOK_MON_DATA_DIR", Value: m.DataPathMap.ContainerDataDir},
+			corev1.EnvVar{Name: "ROOK_MON_KEYRING", Value: keyring.VolumeMount().KeyringFilePath()},
+			corev1.EnvVar{Name: "ROOK_MON_ADDRS", Value: monAddrVec(m)},
+			corev1.EnvVar{Name: "ROOK_FSID", Value: c.ClusterInfo.FSID},
+		),
+	}
+
+	return c.makeMonRecoveryJob(fmt.Sprintf("%s-mon-%s", monRecoveryAppName, m.DaemonName), podSpec)
+}
+
+func envVarValue(env []corev1.EnvVar, name string) string {
+	for _, e := range env {
+		if e.Name == name {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+// monRecoveryContainer turns a daemon container into a container that runs the given recovery
+// script with the same image, volumes, and privileges as the daemon.
+func monRecoveryContainer(daemon corev1.Container, script string, env ...corev1.EnvVar) corev1.Container {
+	container := daemon
+	container.Name = monRecoveryContainerName
+	container.Command = []string{"/bin/bash", "-c", script}
+	container.Args = nil
+	container.Ports = nil
+	container.WorkingDir = ""
+	container.Lifecycle = nil
+	container.StartupProbe = nil
+	container.LivenessProbe = nil
+	container.ReadinessProbe = &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{Command: []string{"test", "-f", monRecoveryDir + "/.done"}},
+		},
+		PeriodSeconds: int32(monRecoveryPollInterval.Seconds()),
+	}
+	container.Env = append(slices.Clone(daemon.Env), env...)
+	container.Env = append(container.Env, corev1.EnvVar{Name: "ROOK_MON_RECOVERY_DIR", Value: monRecoveryDir})
+	container.VolumeMounts = append(slices.Clone(daemon.VolumeMounts), corev1.VolumeMount{Name: monRecoveryAppName, MountPath: monRecoveryDir})
+	return container
+}
+
+func (c *Cluster) makeMonRecoveryJob(name string, podSpec *corev1.PodSpec) (*batch.Job, error) {
+	podSpec.RestartPolicy = corev1.RestartPolicyNever
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name:         monRecoveryAppName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+
+	labels := controller.AppLabels(monRecoveryAppName, c.Namespace)
+	backoffLimit := int32(0)
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.Namespace,
+			Labels:    labels,
+		},
+		Spec: batch.JobSpec{
+			// the store is uploaded by the operator, so a failed pod cannot simply be retried
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       *podSpec,
+			},
+		},
+	}
+	k8sutil.AddRookVersionLabelToJob(job)
+	if err := c.ownerInfo.SetControllerReference(job); err != nil {
+		return nil, errors.Wrapf(err, "failed to set owner reference on job %q", job.Name)
+	}
+	return job, nil
+}
+
+// runMonRecoveryJob runs a recovery job, uploads the mon store file to it, and waits for the job to
+// complete its work on the store. The store file is replaced with the updated store if download is
+// true.
+func (c *Cluster) runMonRecoveryJob(job *batch.Job, storePath string, download bool) error {
+	if err := k8sutil.RunReplaceableJob(c.ClusterInfo.Context, c.context.Clientset, job, true); err != nil {
+		return errors.Wrapf(err, "failed to run job %q", job.Name)
+	}
+	defer func() {
+		if err := k8sutil.DeleteBatchJob(c.ClusterInfo.Context, c.context.Clientset, c.Namespace, job.Name, false); err != nil {
+			log.NamespacedWarning(c.Namespace, logger, "failed to delete mon recovery job %q. %v", job.Name, err)
+		}
+	}()
+
+	pod, err := c.waitForMonRecoveryPod(job.Name, func(pod *corev1.Pod) bool { return pod.Status.Phase == corev1.PodRunning })
+	if err != nil {
+		return err
+	}
+	if err := uploadMonRecoveryStore(c.ClusterInfo.Context, c.context, pod, storePath); err != nil {
+		return errors.Wrapf(err, "failed to upload the mon store to pod %q", pod.Name)
+	}
+
+	pod, err = c.waitForMonRecoveryPod(job.Name, isPodReady)
+	if err != nil {
+		return err
+	}
+	if !download {
+		return nil
+	}
+	if err := downloadMonRecoveryStore(c.ClusterInfo.Context, c.context, pod, storePath); err != nil {
+		return errors.Wrapf(err, "failed to download the mon store from pod %q", pod.Name)
+	}
+	return nil
+}
+
+func (c *Cluster) waitForMonRecoveryPod(jobName string, condition func(pod *corev1.Pod) bool) (*corev1.Pod, error) {
+	var pod *corev1.Pod
+	err := wait.PollUntilContextTimeout(c.ClusterInfo.Context, monRecoveryPollInterval, monRecoveryJobTimeout, true, func(ctx context.Context) (bool, error) {
+		pods, err := c.context.Clientset.CoreV1().Pods(c.Namespace).List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", batch.JobNameLabel, jobName)})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to list the pods of job %q", jobName)
+		}
+		for i := range pods.Items {
+			if pods.Items[i].Status.Phase == corev1.PodFailed {
+				return false, errors.Errorf("pod %q of job %q failed", pods.Items[i].Name, jobName)
+			}
+			if condition(&pods.Items[i]) {
+				pod = &pods.Items[i]
+				return true, nil
+			}
+		}
+		log.NamespacedDebug(c.Namespace, logger, "waiting for the pod of mon recovery job %q", jobName)
+		return false, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to wait for the pod of job %q", jobName)
+	}
+	return pod, nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// execUploadMonRecoveryStore streams the mon store file into the pod. An empty file means no OSD
+// has been read yet, and the pod starts with a new store.
+func execUploadMonRecoveryStore(ctx context.Context, clusterdContext *clusterd.Context, pod *corev1.Pod, storePath string) error {
+	store, err := os.Open(storePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to open the mon store file")
+	}
+	defer store.Close()
+	info, err := store.Stat()
+	if err != nil {
+		return errors.Wrap(err, "failed to stat the mon store file")
+	}
+
+	options := exec.ExecOptions{
+		Command:       []string{"/bin/bash", "-c", fmt.Sprintf("touch %s/.uploaded", monRecoveryDir)},
+		Namespace:     pod.Namespace,
+		PodName:       pod.Name,
+		ContainerName: monRecoveryContainerName,
+		CaptureStdout: true,
+		CaptureStderr: true,
+	}
+	if info.Size() > 0 {
+		options.Command = []string{"/bin/bash", "-c", fmt.Sprintf("tar -xzf - -C %[1]s && touch %[1]s/.uploaded", monRecoveryDir)}
+		options.Stdin = store
+	}
+	_, stderr, err := clusterdContext.RemoteExecutor.ExecWithOptions(ctx, options)
+	if err != nil {
+		return errors.Wrapf(err, "failed to extract the mon store. %s", stderr)
+	}
+	return nil
+}
+
+// execDownloadMonRecoveryStore streams the mon store out of the pod into the mon store file
+func execDownloadMonRecoveryStore(ctx context.Context, clusterdContext *clusterd.Context, pod *corev1.Pod, storePath string) error {
+	store, err := os.Create(storePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the mon store file")
+	}
+	defer store.Close()
+
+	_, stderr, err := clusterdContext.RemoteExecutor.ExecWithOptions(ctx, exec.ExecOptions{
+		Command:       []string{"tar", "-czf", "-", "-C", monRecoveryDir, "store", "keyring"},
+		Namespace:     pod.Namespace,
+		PodName:       pod.Name,
+		ContainerName: monRecoveryContainerName,
+		Stdout:        store,
+		CaptureStderr: true,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to archive the mon store. %s", stderr)
+	}
+	return store.Sync()
+}
+
+func (c *Cluster) updateMonQuorumRecoveryProgress(message string) {
+	log.NamespacedInfo(c.Namespace, logger, "mon quorum recovery: %s", message)
+	c.updateMonQuorumRecoveryCondition(corev1.ConditionTrue, cephv1.MonQuorumRecoveryInProgressReason, message)
+}
+
+func (c *Cluster) updateMonQuorumRecoveryCondition(status corev1.ConditionStatus, reason cephv1.ConditionReason, message string) {
+	controller.UpdateCondition(c.ClusterInfo.Context, c.context, c.ClusterInfo.NamespacedName(), k8sutil.ObservedGenerationNotAvailable, cephv1.ConditionMonQuorumRecovery, status, reason, message)
+}
@@ -0,0 +1,449 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rook/rook/pkg/clusterd"
+	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	apps "k8s.io/api/apps/v1"
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newDaemonDeployment(namespace, name string, labels map[string]string, initContainers []string, containers ...string) *apps.Deployment {
+	replicas := int32(1)
+	d := &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Spec: apps.DeploymentSpec{
+			Replicas: &replicas,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					NodeSelector:  map[string]string{v1.LabelHostname: "node1"},
+					RestartPolicy: v1.RestartPolicyAlways,
+					Volumes:       []v1.Volume{{Name: "ceph-daemon-data"}},
+				},
+			},
+		},
+	}
+	for _, name := range initContainers {
+		d.Spec.Template.Spec.InitContainers = append(d.Spec.Template.Spec.InitContainers, v1.Container{Name: name})
+	}
+	for _, name := range containers {
+		d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, v1.Container{
+			Name:          name,
+			Image:         "quay.io/ceph/ceph:v20",
+			Args:          []string{"--foreground"},
+			Env:           []v1.EnvVar{{Name: "ROOK_CEPH_MON_HOST"}},
+			VolumeMounts:  []v1.VolumeMount{{Name: "ceph-daemon-data", MountPath: "/var/lib/ceph/data"}},
+			LivenessProbe: &v1.Probe{},
+		})
+	}
+	return d
+}
+
+func TestMakeOSDCollectJob(t *testing.T) {
+	namespace := "default"
+	c := newTestClusterWithCR(t, namespace, 3)
+
+	d := newDaemonDeployment(namespace, "rook-ceph-osd-3", map[string]string{osdIDLabelKey: "3"},
+		[]string{"activate", "expand-bluefs", osdKeyUpdateContainer}, osdContainerName, "log-collector")
+	job, err := c.makeOSDCollectJob(d)
+	assert.NoError(t, err)
+	assert.Equal(t, "rook-ceph-mon-recovery-osd-3", job.Name)
+	assert.Equal(t, monRecoveryAppName, job.Labels["app"])
+	assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+
+	podSpec := job.Spec.Template.Spec
+	assert.Equal(t, v1.RestartPolicyNever, podSpec.RestartPolicy)
+	assert.Equal(t, "node1", podSpec.NodeSelector[v1.LabelHostname])
+	// the key update needs the mons
+	assert.Equal(t, 2, len(podSpec.InitContainers))
+	assert.Equal(t, "activate", podSpec.InitContainers[0].Name)
+	assert.Equal(t, "expand-bluefs", podSpec.InitContainers[1].Name)
+
+	assert.Equal(t, 1, len(podSpec.Containers))
+	container := podSpec.Containers[0]
+	assert.Equal(t, monRecoveryContainerName, container.Name)
+	assert.Equal(t, "quay.io/ceph/ceph:v20", container.Image)
+	assert.Equal(t, []string{"/bin/bash", "-c", monRecoveryCollectScript}, container.Command)
+	assert.Nil(t, container.Args)
+	assert.Nil(t, container.LivenessProbe)
+	assert.NotNil(t, container.ReadinessProbe)
+	assert.Contains(t, container.Env, v1.EnvVar{Name: "ROOK_OSD_ID", Value: "3"})
+	assert.Contains(t, container.Env, v1.EnvVar{Name: "ROOK_MON_RECOVERY_DIR", Value: monRecoveryDir})
+	assert.Contains(t, container.VolumeMounts, v1.VolumeMount{Name: "ceph-daemon-data", MountPath: "/var/lib/ceph/data"})
+	assert.Contains(t, container.VolumeMounts, v1.VolumeMount{Name: monRecoveryAppName, MountPath: monRecoveryDir})
+	assert.Equal(t, monRecoveryAppName, podSpec.Volumes[len(podSpec.Volumes)-1].Name)
+
+	// the deployment is not modified
+	assert.Equal(t, 3, len(d.Spec.Template.Spec.InitContainers))
+	assert.Equal(t, 1, len(d.Spec.Template.Spec.Containers[0].VolumeMounts))
+
+	t.Run("osd id label is required", func(t *testing.T) {
+		d := newDaemonDeployment(namespace, "rook-ceph-osd-4", nil, nil, osdContainerName)
+		_, err := c.makeOSDCollectJob(d)
+		assert.Error(t, err)
+	})
+
+	t.Run("lvm osds on pvcs are not supported", func(t *testing.T) {
+		d := newDaemonDeployment(namespace, "rook-ceph-osd-5", map[string]string{osdIDLabelKey: "5", osdOverPVCLabelKey: "set1-data-0"}, nil, osdContainerName)
+		_, err := c.makeOSDCollectJob(d)
+		assert.ErrorContains(t, err, "lvm mode")
+
+		d.Spec.Template.Spec.Containers[0].Env = append(d.Spec.Template.Spec.Containers[0].Env, v1.EnvVar{Name: osdCVModeEnvVarName, Value: "lvm"})
+		_, err = c.makeOSDCollectJob(d)
+		assert.ErrorContains(t, err, "lvm mode")
+
+		d.Spec.Template.Spec.Containers[0].Env[1].Value = "raw"
+		_, err = c.makeOSDCollectJob(d)
+		assert.NoError(t, err)
+	})
+
+	t.Run("lvm osds on devices are supported", func(t *testing.T) {
+		d := newDaemonDeployment(namespace, "rook-ceph-osd-6", map[string]string{osdIDLabelKey: "6"}, nil, osdContainerName)
+		d.Spec.Template.Spec.Containers[0].Env = append(d.Spec.Template.Spec.Containers[0].Env, v1.EnvVar{Name: osdCVModeEnvVarName, Value: "lvm"})
+		_, err := c.makeOSDCollectJob(d)
+		assert.NoError(t, err)
+	})
+}
+
+func TestMakeMonRestoreJob(t *testing.T) {
+	namespace := "default"
+	c := newTestClusterWithCR(t, namespace, 3)
+
+	m := &monConfig{
+		ResourceName: "rook-ceph-mon-a",
+		DaemonName:   "a",
+		PublicIP:     "1.2.3.1",
+		Port:         DefaultMsgr2Port,
+		DataPathMap:  config.NewStatefulDaemonDataPathMap("/var/lib/rook", dataDirRelativeHostPath("a"), config.MonType, "a", namespace),
+	}
+	d := newDaemonDeployment(namespace, m.ResourceName, nil, []string{"chown-container-data-dir", "init-mon-fs"}, monContainerName)
+	job, err := c.makeMonRestoreJob(m, d)
+	assert.NoError(t, err)
+	assert.Equal(t, "rook-ceph-mon-recovery-mon-a", job.Name)
+
+	podSpec := job.Spec.Template.Spec
+	assert.Equal(t, 2, len(podSpec.InitContainers))
+	container := podSpec.Containers[0]
+	assert.Equal(t, []string{"/bin/bash", "-c", monRecoveryRestoreScript}, container.Command)
+	assert.Contains(t, container.Env, v1.EnvVar{Name: "ROOK_MON_ID", Value: "a"})
+	assert.Contains(t, container.Env, v1.EnvVar{Name: "ROOK_MON_DATA_DIR", Value: "/var/lib/ceph/mon/ceph-a"})
+	assert.Contains(t, container.Env, v1.EnvVar{Name: "ROOK_MON_ADDRS", Value: "[v2:1.2.3.1:3300]"})
+	assert.Contains(t, container.Env, v1.EnvVar{Name: "ROOK_FSID", Value: "12345"})
+}
+
+func TestFirstMonWithDeployment(t *testing.T) {
+	namespace := "default"
+	c := newTestClusterWithCR(t, namespace, 3)
+	ctx := c.ClusterInfo.Context
+
+	_, _, err := c.firstMonWithDeployment()
+	assert.Error(t, err)
+
+	// the first mon with a deployment is restored
+	for _, name := range []string{"rook-ceph-mon-c", "rook-ceph-mon-b"} {
+		_, err := c.context.Clientset.AppsV1().Deployments(namespace).Create(ctx, newDaemonDeployment(namespace, name, nil, nil, monContainerName), metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	m, d, err := c.firstMonWithDeployment()
+	assert.NoError(t, err)
+	assert.Equal(t, "b", m.DaemonName)
+	assert.Equal(t, "rook-ceph-mon-b", d.Name)
+	assert.Equal(t, "1.2.3.2", m.PublicIP)
+}
+
+// setMonRecoveryTestHooks makes the recovery poll quickly and replaces the store transfer with
+// one that appends the name of each job pod to the store file
+func setMonRecoveryTestHooks(t *testing.T) *[]string {
+	var uploads []string
+	monRecoveryPollInterval = time.Millisecond
+	monRecoveryJobTimeout = time.Second
+	uploadMonRecoveryStore = func(_ context.Context, _ *clusterd.Context, _ *v1.Pod, storePath string) error {
+		store, err := os.ReadFile(storePath)
+		uploads = append(uploads, string(store))
+		return err
+	}
+	downloadMonRecoveryStore = func(_ context.Context, _ *clusterd.Context, pod *v1.Pod, storePath string) error {
+		store, err := os.ReadFile(storePath)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(storePath, append(store, []byte(pod.Labels[batch.JobNameLabel]+";")...), 0600)
+	}
+	t.Cleanup(func() {
+		monRecoveryPollInterval = 5 * time.Second
+		monRecoveryJobTimeout = 30 * time.Minute
+		uploadMonRecoveryStore = execUploadMonRecoveryStore
+		downloadMonRecoveryStore = execDownloadMonRecoveryStore
+	})
+	return &uploads
+}
+
+// runJobPods creates a pod for every job created with the fake clientset. The pods of the given
+// failed jobs fail, and the other pods are ready.
+func runJobPods(t *testing.T, c *Cluster, failedJobs ...string) {
+	clientset := c.context.Clientset.(*fake.Clientset)
+	clientset.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		job := action.(k8stesting.CreateAction).GetObject().(*batch.Job)
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: job.Name + "-xyz", Namespace: job.Namespace, Labels: map[string]string{batch.JobNameLabel: job.Name}},
+			Status: v1.PodStatus{
+				Phase:      v1.PodRunning,
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+			},
+		}
+		if slices.Contains(failedJobs, job.Name) {
+			pod.Status = v1.PodStatus{Phase: v1.PodFailed}
+		}
+		// the pod of a previous run of the job is replaced
+		_ = clientset.Tracker().Delete(v1.SchemeGroupVersion.WithResource("pods"), pod.Namespace, pod.Name)
+		assert.NoError(t, clientset.Tracker().Add(pod))
+		return false, nil, nil
+	})
+}
+
+func TestRunMonRecoveryJob(t *testing.T) {
+	namespace := "default"
+	c := newTestClusterWithCR(t, namespace, 3)
+	ctx := c.ClusterInfo.Context
+	uploads := setMonRecoveryTestHooks(t)
+
+	storePath := path.Join(t.TempDir(), "store")
+	assert.NoError(t, os.WriteFile(storePath, []byte("store;"), 0600))
+
+	d := newDaemonDeployment(namespace, "rook-ceph-osd-0", map[string]string{osdIDLabelKey: "0"}, nil, osdContainerName)
+	job, err := c.makeOSDCollectJob(d)
+	assert.NoError(t, err)
+
+	t.Run("store is passed through the job", func(t *testing.T) {
+		runJobPods(t, c)
+		assert.NoError(t, c.runMonRecoveryJob(job, storePath, true))
+		assert.Equal(t, []string{"store;"}, *uploads)
+		store, err := os.ReadFile(storePath)
+		assert.NoError(t, err)
+		assert.Equal(t, "store;rook-ceph-mon-recovery-osd-0;", string(store))
+
+		// the job is cleaned up
+		_, err = c.context.Clientset.BatchV1().Jobs(namespace).Get(ctx, job.Name, metav1.GetOptions{})
+		assert.True(t, kerrors.IsNotFound(err))
+	})
+
+	t.Run("failed pod fails the job", func(t *testing.T) {
+		c := newTestClusterWithCR(t, namespace, 3)
+		runJobPods(t, c, job.Name)
+		assert.Error(t, c.runMonRecoveryJob(job, storePath, true))
+	})
+}
+
+func createDeployments(t *testing.T, c *Cluster, deployments ...*apps.Deployment) {
+	for _, d := range deployments {
+		_, err := c.context.Clientset.AppsV1().Deployments(c.Namespace).Create(c.ClusterInfo.Context, d, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+}
+
+func deploymentReplicas(t *testing.T, c *Cluster, name string) int32 {
+	d, err := c.context.Clientset.AppsV1().Deployments(c.Namespace).Get(c.ClusterInfo.Context, name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	return *d.Spec.Replicas
+}
+
+func TestScaleDownForMonRecovery(t *testing.T) {
+	namespace := "default"
+	c := newTestClusterWithCR(t, namespace, 3)
+	setMonRecoveryTestHooks(t)
+
+	monLabels := func(id string) map[string]string { return map[string]string{k8sutil.AppAttr: AppName, "mon": id} }
+	osdLabels := func(id string) map[string]string {
+		return map[string]string{k8sutil.AppAttr: osdAppName, osdIDLabelKey: id}
+	}
+	stoppedOSD := newDaemonDeployment(namespace, "rook-ceph-osd-2", osdLabels("2"), nil, osdContainerName)
+	stoppedOSD.Spec.Replicas = new(int32(0))
+	createDeployments(t, c,
+		newDaemonDeployment(namespace, "rook-ceph-mon-a", monLabels("a"), nil, monContainerName),
+		newDaemonDeployment(namespace, "rook-ceph-mon-b", monLabels("b"), nil, monContainerName),
+		newDaemonDeployment(namespace, "rook-ceph-osd-0", osdLabels("0"), nil, osdContainerName),
+		newDaemonDeployment(namespace, "rook-ceph-osd-1", osdLabels("1"), nil, osdContainerName),
+		stoppedOSD,
+	)
+
+	scaled, err := c.scaleDownForMonRecovery()
+	assert.NoError(t, err)
+	// the osd that was already stopped is not scaled back up after the recovery
+	assert.ElementsMatch(t, []string{"rook-ceph-mon-a", "rook-ceph-mon-b", "rook-ceph-osd-0", "rook-ceph-osd-1"}, scaled)
+	for _, name := range append(scaled, stoppedOSD.Name) {
+		assert.Equal(t, int32(0), deploymentReplicas(t, c, name))
+	}
+
+	t.Run("waits for the pods to stop", func(t *testing.T) {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-0-xyz", Namespace: namespace, Labels: osdLabels("0")}}
+		_, err := c.context.Clientset.CoreV1().Pods(namespace).Create(c.ClusterInfo.Context, pod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+		defer func() {
+			assert.NoError(t, c.context.Clientset.CoreV1().Pods(namespace).Delete(c.ClusterInfo.Context, pod.Name, metav1.DeleteOptions{}))
+		}()
+		_, err = c.scaleDownForMonRecovery()
+		assert.Error(t, err)
+	})
+
+	t.Run("scale up skips the removed mons", func(t *testing.T) {
+		assert.NoError(t, c.context.Clientset.AppsV1().Deployments(namespace).Delete(c.ClusterInfo.Context, "rook-ceph-mon-b", metav1.DeleteOptions{}))
+		assert.NoError(t, c.scaleUpAfterMonRecovery(scaled))
+		for _, name := range []string{"rook-ceph-mon-a", "rook-ceph-osd-0", "rook-ceph-osd-1"} {
+			assert.Equal(t, int32(1), deploymentReplicas(t, c, name))
+		}
+		assert.Equal(t, int32(0), deploymentReplicas(t, c, stoppedOSD.Name))
+	})
+}
+
+// newMonRecoveryTestCluster returns a cluster with mons a, b, and c, each with a deployment, and
+// with osds 0 and 1
+func newMonRecoveryTestCluster(t *testing.T, namespace string) *Cluster {
+	c := newTestClusterWithCR(t, namespace, 3)
+	c.context.Executor = &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			return clienttest.MonInQuorumResponse(), nil
+		},
+	}
+	for _, m := range c.clusterInfoToMonConfig() {
+		c.mapping.Schedule[m.DaemonName] = &opcontroller.MonScheduleInfo{Hostname: "node-" + m.DaemonName}
+		createDeployments(t, c, newDaemonDeployment(namespace, m.ResourceName, map[string]string{k8sutil.AppAttr: AppName, "mon": m.DaemonName}, nil, monContainerName))
+	}
+	for _, id := range []string{"1", "0"} {
+		createDeployments(t, c, newDaemonDeployment(namespace, "rook-ceph-osd-"+id, map[string]string{k8sutil.AppAttr: osdAppName, osdIDLabelKey: id}, nil, osdContainerName))
+	}
+
+	// the mon pod is running as soon as its deployment is created again after the recovery
+	clientset := c.context.Clientset.(*fake.Clientset)
+	clientset.PrependReactor("create", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		d := action.(k8stesting.CreateAction).GetObject().(*apps.Deployment)
+		if d.Labels[k8sutil.AppAttr] == AppName && d.Labels[opcontroller.DaemonIDLabel] != "" {
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: d.Name + "-xyz", Namespace: d.Namespace, Labels: d.Spec.Template.Labels},
+				Status:     v1.PodStatus{Phase: v1.PodRunning},
+			}
+			assert.NoError(t, clientset.Tracker().Add(pod))
+		}
+		return false, nil, nil
+	})
+	return c
+}
+
+func TestRestartMonWithRestoredStore(t *testing.T) {
+	namespace := "default"
+	c := newMonRecoveryTestCluster(t, namespace)
+	ctx := c.ClusterInfo.Context
+
+	m, _, err := c.firstMonWithDeployment()
+	assert.NoError(t, err)
+	assert.Equal(t, "a", m.DaemonName)
+	assert.NoError(t, c.restartMonWithRestoredStore(m))
+
+	// only the recovered mon remains
+	assert.Equal(t, []string{"a"}, slices.Collect(maps.Keys(c.ClusterInfo.InternalMonitors)))
+	assert.Equal(t, []string{"a"}, slices.Collect(maps.Keys(c.mapping.Schedule)))
+	for _, name := range []string{"rook-ceph-mon-b", "rook-ceph-mon-c"} {
+		_, err := c.context.Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		assert.True(t, kerrors.IsNotFound(err))
+	}
+	cm, err := c.context.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, EndpointConfigMapName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "a=1.2.3.1:3300", cm.Data[EndpointDataKey])
+
+	// the mon deployment was generated again by the operator
+	d, err := c.context.Clientset.AppsV1().Deployments(namespace).Get(ctx, m.ResourceName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "node-a", d.Spec.Template.Spec.NodeSelector[v1.LabelHostname])
+}
+
+func TestRecoverQuorumFromOSDs(t *testing.T) {
+	namespace := "default"
+
+	t.Run("store is collected from all the osds and restored to the first mon", func(t *testing.T) {
+		c := newMonRecoveryTestCluster(t, namespace)
+		uploads := setMonRecoveryTestHooks(t)
+		runJobPods(t, c)
+
+		monName, err := c.recoverQuorumFromOSDs()
+		assert.NoError(t, err)
+		assert.Equal(t, "a", monName)
+		// the store passes through the osds in order and ends at the mon
+		assert.Equal(t, []string{
+			"",
+			"rook-ceph-mon-recovery-osd-0;",
+			"rook-ceph-mon-recovery-osd-0;rook-ceph-mon-recovery-osd-1;",
+		}, *uploads)
+		for _, name := range []string{"rook-ceph-mon-a", "rook-ceph-osd-0", "rook-ceph-osd-1"} {
+			assert.Equal(t, int32(1), deploymentReplicas(t, c, name))
+		}
+		assert.Equal(t, 1, len(c.ClusterInfo.InternalMonitors))
+
+		// the store file is cleaned up
+		files, err := filepath.Glob(path.Join(c.context.ConfigDir, "mon-recovery-store-*"))
+		assert.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("daemons are scaled back up when the recovery fails", func(t *testing.T) {
+		c := newMonRecoveryTestCluster(t, namespace)
+		setMonRecoveryTestHooks(t)
+		runJobPods(t, c, "rook-ceph-mon-recovery-osd-1")
+
+		_, err := c.recoverQuorumFromOSDs()
+		assert.ErrorContains(t, err, "rook-ceph-osd-1")
+		for _, name := range []string{"rook-ceph-mon-a", "rook-ceph-mon-b", "rook-ceph-mon-c", "rook-ceph-osd-0", "rook-ceph-osd-1"} {
+			assert.Equal(t, int32(1), deploymentReplicas(t, c, name))
+		}
+		// the mons are not removed
+		assert.Equal(t, 3, len(c.ClusterInfo.InternalMonitors))
+	})
+
+	t.Run("unsupported osds fail the recovery before anything is stopped", func(t *testing.T) {
+		c := newMonRecoveryTestCluster(t, namespace)
+		setMonRecoveryTestHooks(t)
+		createDeployments(t, c, newDaemonDeployment(namespace, "rook-ceph-osd-2", map[string]string{k8sutil.AppAttr: osdAppName, osdIDLabelKey: "2", osdOverPVCLabelKey: "set1-data-0"}, nil, osdContainerName))
+
+		_, err := c.recoverQuorumFromOSDs()
+		assert.ErrorContains(t, err, "lvm mode")
+		for _, name := range []string{"rook-ceph-mon-a", "rook-ceph-osd-0", "rook-ceph-osd-2"} {
+			assert.Equal(t, int32(1), deploymentReplicas(t, c, name))
+		}
+		jobs, err := c.context.Clientset.BatchV1().Jobs(namespace).List(c.ClusterInfo.Context, metav1.ListOptions{})
+		assert.NoError(t, err)
+		assert.Empty(t, jobs.Items)
+	})
+}
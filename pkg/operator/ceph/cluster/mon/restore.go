@@ -0,0 +1,350 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/log"
+	apps "k8s.io/api/apps/v1"
+	batch "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+//go:embed mon-backup-fetch.sh
+var monBackupFetchScript string
+
+//go:embed mon-backup-restore.sh
+var monBackupRestoreScript string
+
+const (
+	monBackupRestoreAppName = "rook-ceph-mon-backup-restore"
+	// the directory of the restore job where the backup is fetched and extracted
+	monBackupRestoreDir    = "/var/lib/ceph/mon-backup-restore"
+	monBackupRestoreVolume = "mon-backup-restore"
+)
+
+var (
+	// the names of the backups written by the backup job
+	monBackupNameRegex = regexp.MustCompile(`^mon-backup-[0-9]{8}-[0-9]{6}$`)
+
+	// hooks for tests to override
+	monBackupRestorePollInterval = 5 * time.Second
+	monBackupRestoreTimeout      = 30 * time.Minute
+)
+
+// RestoreFromBackup restores the store of a mon backup to a single mon and restarts it. This is
+// only meant for a disaster where all the mons are lost, and is requested with the
+// RestoreMonBackupAnnotationKey annotation on the CephCluster. The other mons are removed and are
+// replaced by new mons in the next mon reconcile.
+func (c *Cluster) RestoreFromBackup(clusterInfo *cephclient.ClusterInfo, rookImage string, cephVersion cephver.CephVersion, spec cephv1.ClusterSpec, backupName string) error {
+	c.acquireOrchestrationLock()
+	defer c.releaseOrchestrationLock()
+
+	clusterInfo.OwnerInfo = c.ownerInfo
+	c.ClusterInfo = clusterInfo
+	c.rookImage = rookImage
+	c.spec = spec
+
+	var monName string
+	err := c.initClusterInfo(cephVersion, c.ClusterInfo.NamespacedName().Name)
+	if err != nil {
+		// the cluster info is not set if it failed to load
+		c.ClusterInfo = clusterInfo
+		err = errors.Wrap(err, "failed to initialize ceph cluster info")
+	} else {
+		monName, err = c.restoreFromBackup(backupName)
+	}
+	if err != nil {
+		c.updateMonBackupRestoreCondition(corev1.ConditionFalse, cephv1.MonBackupRestoreFailedReason, err.Error())
+		return err
+	}
+	c.updateMonBackupRestoreCondition(corev1.ConditionFalse, cephv1.MonBackupRestoreCompletedReason,
+		fmt.Sprintf("mon %q was restarted with the store of backup %q", monName, backupName))
+	return nil
+}
+
+func (c *Cluster) restoreFromBackup(backupName string) (monName string, err error) {
+	if !monBackupNameRegex.MatchString(backupName) {
+		return "", errors.Errorf("invalid mon backup name %q, expected a name like %q", backupName, "mon-backup-20260101-020000")
+	}
+	backup := c.spec.Mon.Backup
+	if backup.PersistentVolumeClaim == "" && backup.S3 == nil {
+		return "", errors.New("no mon backup target is set in the cluster CR to restore the backup from")
+	}
+
+	m, d, err := c.firstMonWithDeployment()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find the mon to restore the backup to")
+	}
+	job, err := c.makeMonBackupRestoreJob(m, d, backupName)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to generate the mon backup restore job for mon %q", m.DaemonName)
+	}
+	log.NamespacedInfo(c.Namespace, logger, "restoring mon backup %q to mon %q", backupName, m.DaemonName)
+
+	c.updateMonBackupRestoreProgress("scaling down the mons")
+	scaled, err := c.scaleDownMons()
+	defer func() {
+		// the mons are scaled back up after a failure so the cluster is left as it was found
+		if err != nil {
+			for _, name := range scaled {
+				if scaleErr := c.scaleDeployment(name, 1); scaleErr != nil && !kerrors.IsNotFound(errors.Cause(scaleErr)) {
+					log.NamespacedWarning(c.Namespace, logger, "failed to scale up the mons after the failed mon backup restore. %v", scaleErr)
+				}
+			}
+		}
+	}()
+	if err != nil {
+		return "", err
+	}
+
+	c.updateMonBackupRestoreProgress(fmt.Sprintf("restoring backup %q to the store of mon %q", backupName, m.DaemonName))
+	if err = c.runMonBackupRestoreJob(job); err != nil {
+		return "", errors.Wrapf(err, "failed to restore backup %q to the store of mon %q", backupName, m.DaemonName)
+	}
+
+	c.updateMonBackupRestoreProgress(fmt.Sprintf("restarting mon %q with the restored store", m.DaemonName))
+	if err = c.restartMonWithRestoredStore(m); err != nil {
+		return "", err
+	}
+
+	log.NamespacedInfo(c.Namespace, logger, "mon backup %q restored to mon %q", backupName, m.DaemonName)
+	return m.DaemonName, nil
+}
+
+// scaleDownMons stops all the mons so that no mon runs with the lost store while the backup is
+// restored. The names of the deployments that were scaled down are returned, also on failure, so
+// they can be scaled back up.
+func (c *Cluster) scaleDownMons() ([]string, error) {
+	var scaled []string
+	for _, appName := range []string{AppName, FloatingMonAppName} {
+		deployments, err := c.context.Clientset.AppsV1().Deployments(c.Namespace).List(c.ClusterInfo.Context, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, appName)})
+		if err != nil {
+			return scaled, errors.Wrapf(err, "failed to list %q deployments", appName)
+		}
+		for _, d := range deployments.Items {
+			if d.Spec.Replicas != nil && *d.Spec.Replicas == 0 {
+				continue
+			}
+			if err := c.scaleDeployment(d.Name, 0); err != nil {
+				return scaled, err
+			}
+			scaled = append(scaled, d.Name)
+		}
+	}
+
+	err := wait.PollUntilContextTimeout(c.ClusterInfo.Context, monBackupRestorePollInterval, monBackupRestoreTimeout, true, func(ctx context.Context) (bool, error) {
+		for _, appName := range []string{AppName, FloatingMonAppName} {
+			pods, err := c.context.Clientset.CoreV1().Pods(c.Namespace).List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, appName)})
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to list %q pods", appName)
+			}
+			if len(pods.Items) > 0 {
+				log.NamespacedInfo(c.Namespace, logger, "waiting for %d %q pod(s) to stop", len(pods.Items), appName)
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return scaled, errors.Wrap(err, "failed to wait for the mons to stop")
+	}
+	return scaled, nil
+}
+
+func (c *Cluster) scaleDeployment(name string, replicas int32) error {
+	d, err := c.context.Clientset.AppsV1().Deployments(c.Namespace).Get(c.ClusterInfo.Context, name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get deployment %q", name)
+	}
+	log.NamespacedInfo(c.Namespace, logger, "scaling deployment %q to replicas=%d", name, replicas)
+	d.Spec.Replicas = &replicas
+	if _, err := c.context.Clientset.AppsV1().Deployments(c.Namespace).Update(c.ClusterInfo.Context, d, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to scale deployment %q to %d replicas", name, replicas)
+	}
+	return nil
+}
+
+// makeMonBackupRestoreJob generates the job that restores a backup to the store of the mon. The job
+// is derived from the mon deployment so that it mounts the store of the mon. Its init container
+// fetches the backup from the backup target, and the restore container replaces the store of the
+// mon with the store of the backup.
+func (c *Cluster) makeMonBackupRestoreJob(m *monConfig, d *apps.Deployment, backupName string) (*batch.Job, error) {
+	backup := c.spec.Mon.Backup
+
+	podSpec := d.Spec.Template.Spec.DeepCopy()
+	monContainer, err := k8sutil.GetContainerByName(podSpec.Containers, monContainerName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the mon container of deployment %q", d.Name)
+	}
+
+	restoreMount := corev1.VolumeMount{Name: monBackupRestoreVolume, MountPath: monBackupRestoreDir}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name:         monBackupRestoreVolume,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+	fetchMounts := []corev1.VolumeMount{restoreMount}
+	if backup.S3 == nil {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: monBackupAppName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: backup.PersistentVolumeClaim, ReadOnly: true},
+			},
+		})
+		fetchMounts = append(fetchMounts, corev1.VolumeMount{Name: monBackupAppName, MountPath: monBackupDir, ReadOnly: true})
+	}
+
+	podSpec.InitContainers = []corev1.Container{
+		{
+			Name:            "mon-backup-fetch",
+			Command:         []string{"/bin/bash", "-c", monBackupFetchScript},
+			Image:           c.rookImage,
+			ImagePullPolicy: controller.GetContainerImagePullPolicy(c.spec.CephVersion.ImagePullPolicy),
+			Env: append(monBackupTargetEnvVars(backup),
+				corev1.EnvVar{Name: "ROOK_MON_BACKUP_NAME", Value: backupName},
+				corev1.EnvVar{Name: "ROOK_MON_BACKUP_RESTORE_DIR", Value: monBackupRestoreDir},
+			),
+			VolumeMounts:    fetchMounts,
+			SecurityContext: controller.DefaultContainerSecurityContext(),
+		},
+	}
+	podSpec.Containers = []corev1.Container{
+		monStoreContainer(*monContainer, "mon-backup-restore", monBackupRestoreScript, restoreMount,
+			corev1.EnvVar{Name: "ROOK_MON_ID", Value: m.DaemonName},
+			corev1.EnvVar{Name: "ROOK_MON_DATA_DIR", Value: m.DataPathMap.ContainerDataDir},
+			corev1.EnvVar{Name: "ROOK_MON_ADDRS", Value: monAddrVec(m)},
+			corev1.EnvVar{Name: "ROOK_FSID", Value: c.ClusterInfo.FSID},
+			corev1.EnvVar{Name: "ROOK_MON_BACKUP_RESTORE_DIR", Value: monBackupRestoreDir},
+		),
+	}
+	podSpec.RestartPolicy = corev1.RestartPolicyNever
+
+	labels := controller.AppLabels(monBackupRestoreAppName, c.Namespace)
+	backoffLimit := int32(0)
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      monBackupRestoreAppName,
+			Namespace: c.Namespace,
+			Labels:    labels,
+		},
+		Spec: batch.JobSpec{
+			// a failed restore is only retried when the user requests it again
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       *podSpec,
+			},
+		},
+	}
+	k8sutil.AddRookVersionLabelToJob(job)
+	if err := c.ownerInfo.SetControllerReference(job); err != nil {
+		return nil, errors.Wrapf(err, "failed to set owner reference on job %q", job.Name)
+	}
+	return job, nil
+}
+
+// runMonBackupRestoreJob runs the restore job and waits for it to complete
+func (c *Cluster) runMonBackupRestoreJob(job *batch.Job) error {
+	if err := k8sutil.RunReplaceableJob(c.ClusterInfo.Context, c.context.Clientset, job, true); err != nil {
+		return errors.Wrapf(err, "failed to run job %q", job.Name)
+	}
+
+	err := wait.PollUntilContextTimeout(c.ClusterInfo.Context, monBackupRestorePollInterval, monBackupRestoreTimeout, true, func(ctx context.Context) (bool, error) {
+		j, err := c.context.Clientset.BatchV1().Jobs(c.Namespace).Get(ctx, job.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get job %q", job.Name)
+		}
+		if j.Status.Failed > 0 {
+			return false, errors.Errorf("job %q failed, see the logs of its pod for details", job.Name)
+		}
+		log.NamespacedDebug(c.Namespace, logger, "waiting for mon backup restore job %q to complete", job.Name)
+		return j.Status.Succeeded > 0, nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to wait for job %q", job.Name)
+	}
+
+	// the job is kept after a failure so that the logs of its pod can be read
+	if err := k8sutil.DeleteBatchJob(c.ClusterInfo.Context, c.context.Clientset, c.Namespace, job.Name, false); err != nil {
+		log.NamespacedWarning(c.Namespace, logger, "failed to delete mon backup restore job %q. %v", job.Name, err)
+	}
+	return nil
+}
+
+// restartMonWithRestoredStore removes all the other mons, which cannot join the restored store, and
+// starts the mon with the restored store through the regular mon deployment.
+func (c *Cluster) restartMonWithRestoredStore(m *monConfig) error {
+	for name := range c.ClusterInfo.InternalMonitors {
+		if name == m.DaemonName {
+			continue
+		}
+		log.NamespacedInfo(c.Namespace, logger, "removing mon %q that is replaced after the restore of the mon store", name)
+		delete(c.ClusterInfo.InternalMonitors, name)
+		delete(c.mapping.Schedule, name)
+		c.removeMonResources(name)
+	}
+	if err := c.saveMonConfig(); err != nil {
+		return errors.Wrap(err, "failed to save the mon config after the restore of the mon store")
+	}
+
+	// the deployment is created again so the mon does not wait for the ok-to-stop checks of an
+	// update, which cannot succeed without a quorum
+	if err := k8sutil.DeleteDeployment(c.ClusterInfo.Context, c.context.Clientset, c.Namespace, m.ResourceName); err != nil {
+		return errors.Wrapf(err, "failed to delete mon deployment %q", m.ResourceName)
+	}
+	if err := c.startMon(m, c.mapping.Schedule[m.DaemonName]); err != nil {
+		return errors.Wrapf(err, "failed to start mon %q", m.DaemonName)
+	}
+	if err := c.waitForQuorumWithMons(c.context, c.ClusterInfo, []string{m.DaemonName}, 10, true); err != nil {
+		return errors.Wrapf(err, "failed to wait for mon %q to form quorum", m.DaemonName)
+	}
+	return nil
+}
+
+// monAddrVec returns the address vector of the mon in the format of the monmap
+func monAddrVec(m *monConfig) string {
+	v2Addr := "v2:" + net.JoinHostPort(m.PublicIP, strconv.Itoa(int(DefaultMsgr2Port)))
+	if m.Port == DefaultMsgr2Port {
+		return fmt.Sprintf("[%s]", v2Addr)
+	}
+	return fmt.Sprintf("[%s,v1:%s]", v2Addr, net.JoinHostPort(m.PublicIP, strconv.Itoa(int(m.Port))))
+}
+
+func (c *Cluster) updateMonBackupRestoreProgress(message string) {
+	log.NamespacedInfo(c.Namespace, logger, "mon backup restore: %s", message)
+	c.updateMonBackupRestoreCondition(corev1.ConditionTrue, cephv1.MonBackupRestoreInProgressReason, message)
+}
+
+func (c *Cluster) updateMonBackupRestoreCondition(status corev1.ConditionStatus, reason cephv1.ConditionReason, message string) {
+	controller.UpdateCondition(c.ClusterInfo.Context, c.context, c.ClusterInfo.NamespacedName(), k8sutil.ObservedGenerationNotAvailable, cephv1.ConditionMonBackupRestore, status, reason, message)
+}
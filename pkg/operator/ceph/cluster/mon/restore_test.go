@@ -0,0 +1,148 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/stretchr/testify/assert"
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestMakeMonBackupRestoreJob(t *testing.T) {
+	namespace := "default"
+	c := newTestClusterWithCR(t, namespace, 1)
+	c.ClusterInfo.FSID = "12345"
+
+	m := &monConfig{
+		ResourceName: "rook-ceph-mon-a",
+		DaemonName:   "a",
+		PublicIP:     "1.2.3.1",
+		Port:         DefaultMsgr2Port,
+		DataPathMap:  config.NewStatefulDaemonDataPathMap("/var/lib/rook", dataDirRelativeHostPath("a"), config.MonType, "a", namespace),
+	}
+	d := newTestMonDeployment(namespace, "a")
+
+	t.Run("restore from a pvc", func(t *testing.T) {
+		c.spec.Mon.Backup = cephv1.MonBackupSpec{PersistentVolumeClaim: "backups"}
+		job, err := c.makeMonBackupRestoreJob(m, d, "mon-backup-20260101-020000")
+		assert.NoError(t, err)
+		assert.Equal(t, monBackupRestoreAppName, job.Name)
+		assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+
+		podSpec := job.Spec.Template.Spec
+		assert.Equal(t, v1.RestartPolicyNever, podSpec.RestartPolicy)
+		assert.Equal(t, "node1", podSpec.NodeSelector[v1.LabelHostname])
+
+		assert.Equal(t, 1, len(podSpec.InitContainers))
+		fetch := podSpec.InitContainers[0]
+		assert.Equal(t, "myversion", fetch.Image)
+		assert.Contains(t, fetch.Env, v1.EnvVar{Name: "ROOK_MON_BACKUP_NAME", Value: "mon-backup-20260101-020000"})
+		assert.Contains(t, fetch.Env, v1.EnvVar{Name: "ROOK_MON_BACKUP_DIR", Value: monBackupDir})
+		assert.Contains(t, fetch.VolumeMounts, v1.VolumeMount{Name: monBackupAppName, MountPath: monBackupDir, ReadOnly: true})
+
+		assert.Equal(t, 1, len(podSpec.Containers))
+		restore := podSpec.Containers[0]
+		assert.Equal(t, "quay.io/ceph/ceph:v20", restore.Image)
+		assert.Equal(t, []string{"/bin/bash", "-c", monBackupRestoreScript}, restore.Command)
+		assert.Nil(t, restore.LivenessProbe)
+		assert.Contains(t, restore.Env, v1.EnvVar{Name: "ROOK_MON_ID", Value: "a"})
+		assert.Contains(t, restore.Env, v1.EnvVar{Name: "ROOK_MON_DATA_DIR", Value: "/var/lib/ceph/mon/ceph-a"})
+		assert.Contains(t, restore.Env, v1.EnvVar{Name: "ROOK_MON_ADDRS", Value: "[v2:1.2.3.1:3300]"})
+		assert.Contains(t, restore.Env, v1.EnvVar{Name: "ROOK_FSID", Value: "12345"})
+		assert.Contains(t, restore.VolumeMounts, v1.VolumeMount{Name: "ceph-daemon-data", MountPath: "/var/lib/ceph/mon/ceph-a"})
+		assert.Contains(t, restore.VolumeMounts, v1.VolumeMount{Name: monBackupRestoreVolume, MountPath: monBackupRestoreDir})
+
+		// the deployment is not modified
+		assert.Equal(t, "init-mon-fs", d.Spec.Template.Spec.InitContainers[0].Name)
+		assert.Equal(t, 1, len(d.Spec.Template.Spec.Volumes))
+	})
+
+	t.Run("restore from s3", func(t *testing.T) {
+		c.spec.Mon.Backup = cephv1.MonBackupSpec{
+			S3: &cephv1.MonBackupS3Spec{Endpoint: "http://s3", Bucket: "b", CredentialsSecretName: "creds"},
+		}
+		job, err := c.makeMonBackupRestoreJob(m, d, "mon-backup-20260101-020000")
+		assert.NoError(t, err)
+		fetch := job.Spec.Template.Spec.InitContainers[0]
+		assert.Contains(t, fetch.Env, v1.EnvVar{Name: "ROOK_MON_BACKUP_S3_BUCKET", Value: "b"})
+		for _, vol := range job.Spec.Template.Spec.Volumes {
+			assert.Nil(t, vol.PersistentVolumeClaim)
+		}
+	})
+}
+
+func TestMonAddrVec(t *testing.T) {
+	assert.Equal(t, "[v2:1.2.3.4:3300]", monAddrVec(&monConfig{PublicIP: "1.2.3.4", Port: DefaultMsgr2Port}))
+	assert.Equal(t, "[v2:1.2.3.4:3300,v1:1.2.3.4:6789]", monAddrVec(&monConfig{PublicIP: "1.2.3.4", Port: DefaultMsgr1Port}))
+	assert.Equal(t, "[v2:[fd00::1]:3300,v1:[fd00::1]:6789]", monAddrVec(&monConfig{PublicIP: "fd00::1", Port: DefaultMsgr1Port}))
+}
+
+func TestRestoreFromBackup(t *testing.T) {
+	monBackupRestorePollInterval = time.Millisecond
+	monBackupRestoreTimeout = time.Second
+	defer func() {
+		monBackupRestorePollInterval = 5 * time.Second
+		monBackupRestoreTimeout = 30 * time.Minute
+	}()
+
+	namespace := "default"
+	c := newTestClusterWithCR(t, namespace, 1)
+	ctx := c.ClusterInfo.Context
+
+	t.Run("invalid backup name", func(t *testing.T) {
+		c.spec.Mon.Backup = cephv1.MonBackupSpec{PersistentVolumeClaim: "backups"}
+		_, err := c.restoreFromBackup("../mon-backup-20260101-020000")
+		assert.ErrorContains(t, err, "invalid mon backup name")
+	})
+
+	t.Run("no backup target", func(t *testing.T) {
+		c.spec.Mon.Backup = cephv1.MonBackupSpec{}
+		_, err := c.restoreFromBackup("mon-backup-20260101-020000")
+		assert.ErrorContains(t, err, "no mon backup target")
+	})
+
+	t.Run("failed restore scales the mons back up", func(t *testing.T) {
+		c.spec.Mon.Backup = cephv1.MonBackupSpec{PersistentVolumeClaim: "backups"}
+		_, err := c.context.Clientset.AppsV1().Deployments(namespace).Create(ctx, newTestMonDeployment(namespace, "a"), metav1.CreateOptions{})
+		assert.NoError(t, err)
+		c.context.Clientset.(*fake.Clientset).PrependReactor("get", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			job := &batch.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: monBackupRestoreAppName, Namespace: namespace},
+				Status:     batch.JobStatus{Failed: 1},
+			}
+			return true, job, nil
+		})
+
+		_, err = c.restoreFromBackup("mon-backup-20260101-020000")
+		assert.ErrorContains(t, err, "failed to restore backup")
+		d, err := c.context.Clientset.AppsV1().Deployments(namespace).Get(ctx, "rook-ceph-mon-a", metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), *d.Spec.Replicas)
+		// the failed job is kept for its logs
+		_, err = c.context.Clientset.BatchV1().Jobs(namespace).Get(ctx, monBackupRestoreAppName, metav1.GetOptions{})
+		assert.NoError(t, err)
+	})
+}
@@ -28,7 +28,7 @@ import (
 	"github.com/rook/rook/pkg/util/log"
 )
 
-var monitorDaemonList = []string{"mon", "osd", "status"}
+var monitorDaemonList = []string{"mon", "osd", "status", "configdrift", "recovery"}
 
 func (c *ClusterController) configureCephMonitoring(cluster *cluster, clusterInfo *cephclient.ClusterInfo) {
 	var isEnabled bool
@@ -77,6 +77,14 @@ func isMonitoringEnabled(daemon string, clusterSpec *cephv1.ClusterSpec) bool {
 
 	case "status":
 		return !clusterSpec.HealthCheck.DaemonHealth.Status.Disabled
+
+	case "configdrift":
+		// the ceph config of external clusters is not managed by rook
+		return !clusterSpec.CephConfigDrift.Disabled && !clusterSpec.External.Enable
+
+	case "recovery":
+		// the routine also applies the fast recovery forced with an annotation, without a schedule
+		return !clusterSpec.External.Enable
 	}
 
 	return false
@@ -92,6 +100,7 @@ func (c *ClusterController) startMonitoringCheck(cluster *cluster, clusterInfo *
 	case "osd":
 		if !cluster.Spec.External.Enable {
 			osdChecker := osd.NewOSDHealthMonitor(c.context, clusterInfo, cluster.Spec.RemoveOSDsIfOutAndSafeToRemove, cluster.Spec.HealthCheck, *cluster.Spec, c.rookImage)
+			osdChecker.SetEventRecorder(c.recorder)
 			log.NamespacedInfo(cluster.Namespace, logger, "enabling ceph %s monitoring goroutine", daemon)
 			go osdChecker.Start(&cluster.monitoringRoutines, daemon)
 		}
@@ -100,5 +109,15 @@ func (c *ClusterController) startMonitoringCheck(cluster *cluster, clusterInfo *
 		cephChecker := newCephStatusChecker(c.context, clusterInfo, cluster.Spec)
 		log.NamespacedInfo(cluster.Namespace, logger, "enabling ceph %s monitoring goroutine", daemon)
 		go cephChecker.checkCephStatus(&cluster.monitoringRoutines, daemon)
+
+	case "configdrift":
+		driftChecker := newConfigDriftChecker(c.context, clusterInfo, cluster.Spec)
+		log.NamespacedInfo(cluster.Namespace, logger, "enabling ceph %s monitoring goroutine", daemon)
+		go driftChecker.checkConfigDrift(&cluster.monitoringRoutines, daemon)
+
+	case "recovery":
+		recoveryScheduler := newRecoveryScheduler(c.context, clusterInfo)
+		log.NamespacedInfo(cluster.Namespace, logger, "enabling ceph %s schedule goroutine", daemon)
+		go recoveryScheduler.checkRecoverySchedule(&cluster.monitoringRoutines, daemon)
 	}
 }
@@ -0,0 +1,378 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/util/log"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	defaultCanaryFailureDomain = "host"
+	defaultCanarySoakDuration  = 10 * time.Minute
+)
+
+var (
+	// allow unit tests to override these values
+	// canaryHealthCheckInterval is the interval between the reconciles that check the cluster health
+	// during the soak
+	canaryHealthCheckInterval = 30 * time.Second
+	canaryNow                 = time.Now
+)
+
+// canaryUpgrade holds back the OSDs that are updated to a new Ceph image with the Canary upgrade
+// strategy. The OSDs of one failure domain are released to the update queue at a time. After each
+// failure domain, the cluster health is checked for the soak duration, and after the canary failure
+// domain the upgrade waits for the approval of the user. The position of the upgrade is kept in the
+// CephCluster status so that the upgrade resumes in the next reconcile. The soak does not hold the
+// reconcile: the cluster health is checked once per reconcile, and the reconcile is requeued until
+// the soak ends.
+type canaryUpgrade struct {
+	cluster      *Cluster
+	image        string
+	soakDuration time.Duration
+	approved     bool
+	domains      map[string][]int // the outdated OSDs held back by failure domain
+	releasedOSDs []int            // the OSDs released to the update queue since they were taken
+	status       *cephv1.OSDUpgradeStatus
+}
+
+// startCanaryUpgrade returns the canary upgrade of the OSDs, or nil if the OSDs are not updated
+// with the Canary upgrade strategy
+func (c *Cluster) startCanaryUpgrade(queue *updateQueue) (*canaryUpgrade, error) {
+	if c.spec.Storage.UpgradeStrategy.Type != cephv1.OSDUpgradeStrategyCanary {
+		return nil, nil
+	}
+	deployments, err := c.getOSDDeployments()
+	if err != nil {
+		return nil, err
+	}
+	return c.newCanaryUpgrade(queue, deployments)
+}
+
+// newCanaryUpgrade returns the canary upgrade of the OSDs, or nil if no upgrade is in progress. The
+// outdated OSDs of the failure domains that are not released yet are removed from the update queue.
+func (c *Cluster) newCanaryUpgrade(queue *updateQueue, deployments *appsv1.DeploymentList) (*canaryUpgrade, error) {
+	strategy := c.spec.Storage.UpgradeStrategy
+	image := c.spec.CephVersion.Image
+
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get cluster %v", c.clusterInfo.NamespacedName())
+	}
+	var previous *cephv1.OSDUpgradeStatus
+	if cephCluster.Status.CephStorage != nil && cephCluster.Status.CephStorage.OSD.Upgrade != nil && cephCluster.Status.CephStorage.OSD.Upgrade.Image == image {
+		previous = cephCluster.Status.CephStorage.OSD.Upgrade.DeepCopy()
+	}
+
+	u := &canaryUpgrade{
+		cluster:      c,
+		image:        image,
+		soakDuration: defaultCanarySoakDuration,
+		approved:     cephCluster.Annotations[cephv1.ApproveOSDUpgradeAnnotationKey] == image,
+		domains:      map[string][]int{},
+	}
+	if strategy.SoakDuration != nil {
+		u.soakDuration = strategy.SoakDuration.Duration
+	}
+	failureDomainType := strategy.FailureDomain
+	if failureDomainType == "" {
+		failureDomainType = defaultCanaryFailureDomain
+	}
+
+	outdated := outdatedOSDs(queue, deployments, image)
+	if len(outdated) > 0 {
+		tree, err := cephclient.HostTree(c.context, c.clusterInfo)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the failure domains of the osds")
+		}
+		for failureDomain, osdIDs := range osdsByFailureDomain(tree, failureDomainType, outdated) {
+			u.domains[failureDomain] = osdIDs
+			queue.Remove(osdIDs)
+		}
+	}
+
+	if previous == nil {
+		if len(u.domains) == 0 {
+			// no upgrade with the canary strategy is in progress
+			return nil, nil
+		}
+		pending := u.pendingDomains()
+		previous = &cephv1.OSDUpgradeStatus{
+			Image:               image,
+			FailureDomainType:   failureDomainType,
+			CanaryFailureDomain: pending[0],
+		}
+		log.NamespacedInfo(c.clusterInfo.Namespace, logger, "updating the osds to image %q with the canary strategy. the canary %s is %q", image, failureDomainType, pending[0])
+	}
+	u.status = previous
+	u.status.PendingFailureDomains = u.pendingDomains()
+
+	switch u.status.Phase {
+	case cephv1.OSDUpgradeCompleted:
+		if len(u.domains) == 0 {
+			return nil, nil
+		}
+		// OSDs were added with the previous image after the upgrade completed
+		u.releaseNextDomain()
+	case cephv1.OSDUpgradeAwaitingApproval:
+		if u.approved {
+			log.NamespacedInfo(c.clusterInfo.Namespace, logger, "the osd upgrade to image %q is approved", image)
+			u.releaseNextDomain()
+		}
+	case cephv1.OSDUpgradeSoaking, cephv1.OSDUpgradeHalted:
+		if _, ok := u.domains[u.status.CurrentFailureDomain]; ok {
+			// the current failure domain was not fully updated
+			u.release(u.status.CurrentFailureDomain)
+		} else if u.status.Phase == cephv1.OSDUpgradeHalted {
+			log.NamespacedInfo(c.clusterInfo.Namespace, logger, "restarting the soak checks of %s %q for the halted osd upgrade", u.status.FailureDomainType, u.status.CurrentFailureDomain)
+			u.startSoak()
+		} else {
+			u.soak()
+		}
+	case cephv1.OSDUpgradeUpdating:
+		if _, ok := u.domains[u.status.CurrentFailureDomain]; ok {
+			u.release(u.status.CurrentFailureDomain)
+		} else if u.status.CurrentFailureDomain != "" {
+			u.startSoak()
+		} else {
+			u.releaseNextDomain()
+		}
+	default:
+		u.status.CurrentFailureDomain = u.status.CanaryFailureDomain
+		u.release(u.status.CanaryFailureDomain)
+	}
+
+	for _, osdID := range u.takeReleased() {
+		queue.Push(osdID)
+	}
+	if err := u.updateStatus(); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// outdatedOSDs returns the OSDs in the update queue whose deployments do not run the image
+func outdatedOSDs(queue *updateQueue, deployments *appsv1.DeploymentList, image string) []int {
+	outdated := []int{}
+	for i := range deployments.Items {
+		osdID, err := GetOSDID(&deployments.Items[i])
+		if err != nil || !queue.Exists(osdID) {
+			continue
+		}
+		container, err := findOSDContainer(deployments.Items[i].Spec.Template.Spec.Containers)
+		if err != nil {
+			continue
+		}
+		if container.Image != image {
+			outdated = append(outdated, osdID)
+		}
+	}
+	return outdated
+}
+
+// osdsByFailureDomain groups the OSDs by their ancestor CRUSH bucket of the failure domain type.
+// The OSDs that are not under a bucket of the type are not grouped, so they are not held back.
+func osdsByFailureDomain(tree cephclient.OsdTree, failureDomainType string, osdIDs []int) map[string][]int {
+	parents := map[int]int{}
+	buckets := map[int]int{}
+	for i, node := range tree.Nodes {
+		buckets[node.ID] = i
+		for _, child := range node.Children {
+			parents[child] = node.ID
+		}
+	}
+
+	domains := map[string][]int{}
+	for _, osdID := range osdIDs {
+		id := osdID
+		for {
+			parent, ok := parents[id]
+			if !ok {
+				break
+			}
+			node := tree.Nodes[buckets[parent]]
+			if node.Type == failureDomainType {
+				domains[node.Name] = append(domains[node.Name], osdID)
+				break
+			}
+			id = parent
+		}
+	}
+	for _, ids := range domains {
+		sort.Ints(ids)
+	}
+	return domains
+}
+
+// pendingDomains returns the sorted failure domains whose OSDs are held back
+func (u *canaryUpgrade) pendingDomains() []string {
+	pending := []string{}
+	for failureDomain := range u.domains {
+		pending = append(pending, failureDomain)
+	}
+	sort.Strings(pending)
+	return pending
+}
+
+// release pushes the OSDs of the failure domain to the update queue
+func (u *canaryUpgrade) release(failureDomain string) {
+	u.status.Phase = cephv1.OSDUpgradeUpdating
+	u.status.CurrentFailureDomain = failureDomain
+	u.status.SoakStartTime = nil
+	u.status.Message = fmt.Sprintf("Updating the osds of %s %q", u.status.FailureDomainType, failureDomain)
+	osdIDs := u.domains[failureDomain]
+	u.releasedOSDs = append(u.releasedOSDs, osdIDs...)
+	delete(u.domains, failureDomain)
+	u.status.PendingFailureDomains = u.pendingDomains()
+	log.NamespacedInfo(u.cluster.clusterInfo.Namespace, logger, "updating the osds %v of %s %q to image %q", osdIDs, u.status.FailureDomainType, failureDomain, u.image)
+}
+
+// releaseNextDomain releases the next pending failure domain, or completes the upgrade if no
+// failure domain is pending
+func (u *canaryUpgrade) releaseNextDomain() {
+	pending := u.pendingDomains()
+	if len(pending) == 0 {
+		u.status.Phase = cephv1.OSDUpgradeCompleted
+		u.status.CurrentFailureDomain = ""
+		u.status.SoakStartTime = nil
+		u.status.Message = "All the osds are updated"
+		log.NamespacedInfo(u.cluster.clusterInfo.Namespace, logger, "the osd upgrade to image %q is completed", u.image)
+		return
+	}
+	u.release(pending[0])
+}
+
+// startSoak starts the soak checks of the current failure domain
+func (u *canaryUpgrade) startSoak() {
+	if !slices.Contains(u.status.UpdatedFailureDomains, u.status.CurrentFailureDomain) {
+		u.status.UpdatedFailureDomains = append(u.status.UpdatedFailureDomains, u.status.CurrentFailureDomain)
+	}
+	u.status.Phase = cephv1.OSDUpgradeSoaking
+	u.status.SoakStartTime = &metav1.Time{Time: canaryNow()}
+	u.status.Message = fmt.Sprintf("Checking the cluster health for %s after updating %s %q", u.soakDuration.String(), u.status.FailureDomainType, u.status.CurrentFailureDomain)
+	log.NamespacedInfo(u.cluster.clusterInfo.Namespace, logger, "checking the cluster health for %s after updating the osds of %s %q", u.soakDuration.String(), u.status.FailureDomainType, u.status.CurrentFailureDomain)
+}
+
+// takeReleased returns the OSDs released since the last call
+func (u *canaryUpgrade) takeReleased() []int {
+	released := u.releasedOSDs
+	u.releasedOSDs = nil
+	return released
+}
+
+// blocked returns true if the upgrade cannot continue in this reconcile
+func (u *canaryUpgrade) blocked() bool {
+	return u.status.Phase != cephv1.OSDUpgradeUpdating
+}
+
+// requeueAfter returns the time after which the OSDs must be reconciled again to continue the
+// upgrade, or zero if the upgrade waits for the approval or is completed
+func (u *canaryUpgrade) requeueAfter() time.Duration {
+	switch u.status.Phase {
+	case cephv1.OSDUpgradeSoaking:
+		return canaryHealthCheckInterval
+	case cephv1.OSDUpgradeHalted:
+		// the soak checks restart in the next reconcile
+		return u.soakDuration
+	}
+	return 0
+}
+
+// advance starts the soak checks after the released OSDs are updated
+func (u *canaryUpgrade) advance() {
+	if u.status.Phase != cephv1.OSDUpgradeUpdating {
+		return
+	}
+	u.startSoak()
+	if err := u.updateStatus(); err != nil {
+		log.NamespacedError(u.cluster.clusterInfo.Namespace, logger, "failed to update the osd upgrade status. %v", err)
+	}
+}
+
+// soak checks the cluster health after the current failure domain was updated. The upgrade halts if
+// the health is in error, and continues when the PGs are clean at the end of the soak duration.
+func (u *canaryUpgrade) soak() {
+	namespace := u.cluster.clusterInfo.Namespace
+	status, err := cephclient.Status(u.cluster.context, u.cluster.clusterInfo)
+	if err != nil {
+		log.NamespacedWarning(namespace, logger, "failed to check the cluster health of the osd upgrade. will check again later. %v", err)
+		return
+	}
+	if status.Health.Status == "HEALTH_ERR" {
+		u.status.Phase = cephv1.OSDUpgradeHalted
+		u.status.Message = fmt.Sprintf("The cluster health is HEALTH_ERR after updating %s %q. The soak checks restart in %s", u.status.FailureDomainType, u.status.CurrentFailureDomain, u.soakDuration.String())
+		log.NamespacedError(namespace, logger, "halting the osd upgrade to image %q. the cluster health is HEALTH_ERR after updating the osds of %s %q", u.image, u.status.FailureDomainType, u.status.CurrentFailureDomain)
+		return
+	}
+	if u.status.SoakStartTime == nil || canaryNow().Sub(u.status.SoakStartTime.Time) < u.soakDuration {
+		return
+	}
+	pgHealthMsg, pgClean, err := cephclient.IsClusterClean(u.cluster.context, u.cluster.clusterInfo, u.cluster.spec.DisruptionManagement.PGHealthyRegex)
+	if err != nil {
+		log.NamespacedWarning(namespace, logger, "failed to check the PGs status of the osd upgrade. will check again later. %v", err)
+		return
+	}
+	if !pgClean {
+		log.NamespacedInfo(namespace, logger, "waiting for the PGs to be clean after updating the osds of %s %q. %s", u.status.FailureDomainType, u.status.CurrentFailureDomain, pgHealthMsg)
+		return
+	}
+
+	if u.status.CurrentFailureDomain == u.status.CanaryFailureDomain && len(u.domains) > 0 && !u.approved {
+		u.status.Phase = cephv1.OSDUpgradeAwaitingApproval
+		u.status.SoakStartTime = nil
+		u.status.Message = fmt.Sprintf("The canary %s %q is updated. Set the annotation %q to %q on the CephCluster to update the other osds", u.status.FailureDomainType, u.status.CanaryFailureDomain, cephv1.ApproveOSDUpgradeAnnotationKey, u.image)
+		log.NamespacedInfo(namespace, logger, "the canary %s %q is updated to image %q. waiting for the approval of the upgrade with the annotation %q", u.status.FailureDomainType, u.status.CanaryFailureDomain, u.image, cephv1.ApproveOSDUpgradeAnnotationKey)
+		return
+	}
+	u.releaseNextDomain()
+}
+
+// updateStatus sets the upgrade status of the OSDs in the CephCluster status
+func (u *canaryUpgrade) updateStatus() error {
+	c := u.cluster
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cephCluster := &cephv1.CephCluster{}
+		if err := c.context.Client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cephCluster); err != nil {
+			return errors.Wrapf(err, "failed to get cluster %v", c.clusterInfo.NamespacedName())
+		}
+		if cephCluster.Status.CephStorage == nil {
+			cephCluster.Status.CephStorage = &cephv1.CephStorage{}
+		}
+		cephCluster.Status.CephStorage.OSD.Upgrade = u.status.DeepCopy()
+		return reporting.UpdateStatus(c.context.Client, cephCluster)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update the osd upgrade status")
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephclientfake "github.com/rook/rook/pkg/daemon/ceph/client/fake"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestOSDsByFailureDomain(t *testing.T) {
+	var tree cephclient.OsdTree
+	require.NoError(t, json.Unmarshal([]byte(cephclientfake.OsdTreeOutput(3, 2)), &tree))
+
+	assert.Equal(t, map[string][]int{"node0": {0, 3}, "node1": {1, 4}, "node2": {5}}, osdsByFailureDomain(tree, "host", []int{0, 1, 3, 4, 5}))
+	assert.Equal(t, map[string][]int{"default": {0, 2, 4}}, osdsByFailureDomain(tree, "root", []int{4, 0, 2}))
+	// the OSDs that are not under a bucket of the type or not in the tree are not grouped
+	assert.Equal(t, map[string][]int{}, osdsByFailureDomain(tree, "rack", []int{0, 1}))
+	assert.Equal(t, map[string][]int{}, osdsByFailureDomain(tree, "host", []int{9}))
+}
+
+func TestCanaryUpgrade(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	oldImage := "quay.io/ceph/ceph:v20.2.0"
+	newImage := "quay.io/ceph/ceph:v20.2.1"
+
+	oldNow := canaryNow
+	defer func() { canaryNow = oldNow }()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	canaryNow = func() time.Time { return now }
+
+	health := "HEALTH_OK"
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "osd" && args[1] == "tree":
+				return cephclientfake.OsdTreeOutput(3, 2), nil
+			case args[0] == "status":
+				return `{"health":{"status":"` + health + `"},"pgmap":{"num_pgs":10,"pgs_by_state":[{"state_name":"active+clean","count":10}]}}`, nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+	}
+
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: namespace}}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+	cl := clientfake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(cephCluster).WithStatusSubresource(cephCluster).Build()
+
+	clusterInfo := cephclient.AdminTestClusterInfo(namespace)
+	clusterInfo.SetName("my-cluster")
+	spec := cephv1.ClusterSpec{
+		CephVersion: cephv1.CephVersionSpec{Image: newImage},
+		Storage: cephv1.StorageScopeSpec{
+			UpgradeStrategy: cephv1.OSDUpgradeStrategy{Type: cephv1.OSDUpgradeStrategyCanary},
+		},
+	}
+	c := New(&clusterd.Context{Client: cl, Executor: executor}, clusterInfo, spec, "rook/rook:master")
+
+	// the OSD deployments with the image of each OSD
+	images := map[int]string{}
+	for osdID := 0; osdID < 6; osdID++ {
+		images[osdID] = oldImage
+	}
+	deployments := func() *appsv1.DeploymentList {
+		list := &appsv1.DeploymentList{}
+		for osdID := 0; osdID < 6; osdID++ {
+			list.Items = append(list.Items, appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: deploymentName(osdID), Labels: map[string]string{OsdIdLabelKey: strconv.Itoa(osdID)}},
+				Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "osd", Image: images[osdID]}},
+				}}},
+			})
+		}
+		return list
+	}
+	// updates the released OSDs, as updateExistingOSDs would do
+	update := func(queue *updateQueue) {
+		for {
+			osdID, ok := queue.Pop()
+			if !ok {
+				return
+			}
+			images[osdID] = newImage
+		}
+	}
+	reconcile := func() (*canaryUpgrade, *updateQueue) {
+		queue := newUpdateQueueWithIDs(0, 1, 2, 3, 4, 5)
+		u, err := c.newCanaryUpgrade(queue, deployments())
+		require.NoError(t, err)
+		return u, queue
+	}
+	getStatus := func() *cephv1.OSDUpgradeStatus {
+		cluster := &cephv1.CephCluster{}
+		require.NoError(t, cl.Get(ctx, clusterInfo.NamespacedName(), cluster))
+		require.NotNil(t, cluster.Status.CephStorage)
+		return cluster.Status.CephStorage.OSD.Upgrade
+	}
+
+	t.Run("update the canary failure domain", func(t *testing.T) {
+		u, queue := reconcile()
+		require.NotNil(t, u)
+		assert.Equal(t, []int{0, 3}, queue.q)
+		status := getStatus()
+		assert.Equal(t, newImage, status.Image)
+		assert.Equal(t, cephv1.OSDUpgradeUpdating, status.Phase)
+		assert.Equal(t, "host", status.FailureDomainType)
+		assert.Equal(t, "node0", status.CanaryFailureDomain)
+		assert.Equal(t, "node0", status.CurrentFailureDomain)
+		assert.Equal(t, []string{"node1", "node2"}, status.PendingFailureDomains)
+
+		updateConfig := c.newUpdateConfig(c.newProvisionConfig(), queue, newExistenceListWithIDs(0, 1, 2, 3, 4, 5), sets.New[string]())
+		updateConfig.canary = u
+		assert.False(t, updateConfig.doneUpdating())
+		update(queue)
+
+		// the soak checks start after the canary failure domain is updated, and the reconcile is
+		// requeued instead of waiting for the soak
+		updateConfig.updateExistingOSDs(newProvisionErrors())
+		assert.True(t, updateConfig.doneUpdating())
+		assert.Equal(t, canaryHealthCheckInterval, u.requeueAfter())
+		status = getStatus()
+		assert.Equal(t, cephv1.OSDUpgradeSoaking, status.Phase)
+		assert.Equal(t, []string{"node0"}, status.UpdatedFailureDomains)
+		assert.Equal(t, now, status.SoakStartTime.Time.UTC())
+
+		// the soak continues in the next reconciles until the soak duration elapsed
+		now = now.Add(5 * time.Minute)
+		u, queue = reconcile()
+		assert.Equal(t, cephv1.OSDUpgradeSoaking, getStatus().Phase)
+		assert.Equal(t, canaryHealthCheckInterval, u.requeueAfter())
+		assert.Equal(t, []int{0, 3}, queue.q)
+
+		// the upgrade halts when the cluster health is in error
+		health = "HEALTH_ERR"
+		u, _ = reconcile()
+		assert.True(t, u.blocked())
+		assert.Equal(t, cephv1.OSDUpgradeHalted, getStatus().Phase)
+		// the halted upgrade is reconciled again after the soak duration
+		assert.Equal(t, defaultCanarySoakDuration, u.requeueAfter())
+	})
+
+	t.Run("wait for the approval", func(t *testing.T) {
+		health = "HEALTH_OK"
+		u, queue := reconcile()
+		require.NotNil(t, u)
+		// the soak checks restart for the halted upgrade and the other failure domains are held back.
+		// the OSDs that run the image are updated as usual.
+		assert.Equal(t, []int{0, 3}, queue.q)
+		assert.Equal(t, cephv1.OSDUpgradeSoaking, getStatus().Phase)
+
+		now = now.Add(10 * time.Minute)
+		u, _ = reconcile()
+		assert.True(t, u.blocked())
+		assert.Equal(t, time.Duration(0), u.requeueAfter())
+		status := getStatus()
+		assert.Equal(t, cephv1.OSDUpgradeAwaitingApproval, status.Phase)
+		assert.Nil(t, status.SoakStartTime)
+		assert.Contains(t, status.Message, cephv1.ApproveOSDUpgradeAnnotationKey)
+
+		// the upgrade waits until it is approved for the image
+		u, queue = reconcile()
+		assert.True(t, u.blocked())
+		assert.Equal(t, []int{0, 3}, queue.q)
+	})
+
+	t.Run("update the other failure domains after the approval", func(t *testing.T) {
+		cluster := &cephv1.CephCluster{}
+		require.NoError(t, cl.Get(ctx, clusterInfo.NamespacedName(), cluster))
+		cluster.Annotations = map[string]string{cephv1.ApproveOSDUpgradeAnnotationKey: newImage}
+		require.NoError(t, cl.Update(ctx, cluster))
+
+		u, queue := reconcile()
+		assert.Equal(t, []int{0, 3, 1, 4}, queue.q)
+		assert.Equal(t, "node1", getStatus().CurrentFailureDomain)
+		update(queue)
+
+		// each failure domain is followed by the soak checks
+		u.advance()
+		assert.Equal(t, cephv1.OSDUpgradeSoaking, getStatus().Phase)
+		now = now.Add(10 * time.Minute)
+		u, queue = reconcile()
+		assert.False(t, u.blocked())
+		assert.Equal(t, []int{0, 1, 3, 4, 2, 5}, queue.q)
+		status := getStatus()
+		assert.Equal(t, cephv1.OSDUpgradeUpdating, status.Phase)
+		assert.Equal(t, "node2", status.CurrentFailureDomain)
+		assert.Empty(t, status.PendingFailureDomains)
+		images[2], images[5] = newImage, newImage
+
+		u.advance()
+		now = now.Add(10 * time.Minute)
+		u, _ = reconcile()
+		assert.True(t, u.blocked())
+		status = getStatus()
+		assert.Equal(t, cephv1.OSDUpgradeCompleted, status.Phase)
+		assert.Equal(t, []string{"node0", "node1", "node2"}, status.UpdatedFailureDomains)
+
+		// no upgrade is in progress when all the OSDs run the image
+		u, queue = reconcile()
+		assert.Nil(t, u)
+		assert.Equal(t, 6, queue.Len())
+	})
+
+	t.Run("all strategy", func(t *testing.T) {
+		c.spec.Storage.UpgradeStrategy.Type = cephv1.OSDUpgradeStrategyAll
+		images[0] = oldImage
+		u, err := c.startCanaryUpgrade(newUpdateQueueWithIDs(0))
+		assert.NoError(t, err)
+		assert.Nil(t, u)
+	})
+}
@@ -66,3 +66,9 @@ func GenerateDmCryptKey() (string, error) {
 
 	return base64.StdEncoding.EncodeToString(key), nil
 }
+
+// DmCryptConfigKey returns the key of the mon config-key store where ceph-volume keeps the
+// encryption key of an OSD on host devices
+func DmCryptConfigKey(osdUUID string) string {
+	return fmt.Sprintf("dm-crypt/osd/%s/luks", osdUUID)
+}
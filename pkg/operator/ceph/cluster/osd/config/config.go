@@ -20,32 +20,37 @@ package config
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 )
 
 const (
-	WalSizeMBKey       = "walSizeMB"
-	DatabaseSizeMBKey  = "databaseSizeMB"
-	OSDsPerDeviceKey   = "osdsPerDevice"
-	EncryptedDeviceKey = "encryptedDevice"
-	MetadataDeviceKey  = "metadataDevice"
-	DeviceClassKey     = "deviceClass"
-	InitialWeightKey   = "initialWeight"
-	PrimaryAffinityKey = "primaryAffinity"
+	WalSizeMBKey           = "walSizeMB"
+	DatabaseSizeMBKey      = "databaseSizeMB"
+	OSDsPerDeviceKey       = "osdsPerDevice"
+	EncryptedDeviceKey     = "encryptedDevice"
+	MetadataDeviceKey      = "metadataDevice"
+	MetadataDevicesKey     = "metadataDevices"
+	MetadataDeviceRatioKey = "metadataDeviceRatio"
+	DeviceClassKey         = "deviceClass"
+	InitialWeightKey       = "initialWeight"
+	PrimaryAffinityKey     = "primaryAffinity"
 )
 
 // StoreConfig represents the configuration of an OSD on a device.
 type StoreConfig struct {
-	WalSizeMB       int    `json:"walSizeMB,omitempty"`
-	DatabaseSizeMB  int    `json:"databaseSizeMB,omitempty"`
-	OSDsPerDevice   int    `json:"osdsPerDevice,omitempty"`
-	EncryptedDevice bool   `json:"encryptedDevice,omitempty"`
-	MetadataDevice  string `json:"metadataDevice,omitempty"`
-	DeviceClass     string `json:"deviceClass,omitempty"`
-	InitialWeight   string `json:"initialWeight,omitempty"`
-	PrimaryAffinity string `json:"primaryAffinity,omitempty"`
-	StoreType       string `json:"storeType,omitempty"`
+	WalSizeMB           int      `json:"walSizeMB,omitempty"`
+	DatabaseSizeMB      int      `json:"databaseSizeMB,omitempty"`
+	OSDsPerDevice       int      `json:"osdsPerDevice,omitempty"`
+	EncryptedDevice     bool     `json:"encryptedDevice,omitempty"`
+	MetadataDevice      string   `json:"metadataDevice,omitempty"`
+	MetadataDevices     []string `json:"metadataDevices,omitempty"`
+	MetadataDeviceRatio int      `json:"metadataDeviceRatio,omitempty"`
+	DeviceClass         string   `json:"deviceClass,omitempty"`
+	InitialWeight       string   `json:"initialWeight,omitempty"`
+	PrimaryAffinity     string   `json:"primaryAffinity,omitempty"`
+	StoreType           string   `json:"storeType,omitempty"`
 }
 
 func (s StoreConfig) IsValidStoreType() bool {
@@ -85,6 +90,10 @@ func ToStoreConfig(config map[string]string) StoreConfig {
 			storeConfig.EncryptedDevice = (v == "true")
 		case MetadataDeviceKey:
 			storeConfig.MetadataDevice = v
+		case MetadataDevicesKey:
+			storeConfig.MetadataDevices = splitList(v)
+		case MetadataDeviceRatioKey:
+			storeConfig.MetadataDeviceRatio = convertToIntIgnoreErr(v)
 		case DeviceClassKey:
 			storeConfig.DeviceClass = v
 		case InitialWeightKey:
@@ -108,6 +117,17 @@ func MetadataDevice(config map[string]string) string {
 	return ""
 }
 
+// splitList returns the non-empty items of a comma-separated list
+func splitList(raw string) []string {
+	items := []string{}
+	for item := range strings.SplitSeq(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func convertToIntIgnoreErr(raw string) int {
 	val, err := strconv.Atoi(raw)
 	if err != nil {
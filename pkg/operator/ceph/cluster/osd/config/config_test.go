@@ -63,6 +63,15 @@ func TestToStoreConfig(t *testing.T) {
 		assert.Equal(t, "0.5", cfg.PrimaryAffinity)
 	})
 
+	t.Run("metadata devices", func(t *testing.T) {
+		cfg := ToStoreConfig(map[string]string{
+			MetadataDevicesKey:     "nvme0n1, nvme1n1,,/dev/disk/by-id/nvme-2",
+			MetadataDeviceRatioKey: "4",
+		})
+		assert.Equal(t, []string{"nvme0n1", "nvme1n1", "/dev/disk/by-id/nvme-2"}, cfg.MetadataDevices)
+		assert.Equal(t, 4, cfg.MetadataDeviceRatio)
+	})
+
 	t.Run("OSDsPerDevice invalid input becomes 1", func(t *testing.T) {
 		cfg := ToStoreConfig(map[string]string{
 			OSDsPerDeviceKey: "0",
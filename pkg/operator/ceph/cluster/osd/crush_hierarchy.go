@@ -0,0 +1,204 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd/topology"
+	"github.com/rook/rook/pkg/util/log"
+)
+
+const crushHostType = "host"
+
+// crushTree is the name, type and parent of the buckets of the CRUSH map, without the shadow
+// buckets of the device classes
+type crushTree struct {
+	types   map[string]string
+	parents map[string]string
+}
+
+func newCrushTree(crushMap cephclient.CrushMap) *crushTree {
+	tree := &crushTree{types: map[string]string{}, parents: map[string]string{}}
+	names := map[int]string{}
+	for _, bucket := range crushMap.Buckets {
+		if strings.Contains(bucket.Name, "~") {
+			continue
+		}
+		names[bucket.ID] = bucket.Name
+		tree.types[bucket.Name] = bucket.TypeName
+	}
+	for _, bucket := range crushMap.Buckets {
+		if _, ok := names[bucket.ID]; !ok {
+			continue
+		}
+		for _, item := range bucket.Items {
+			if child, ok := names[item.ID]; ok {
+				tree.parents[child] = bucket.Name
+			}
+		}
+	}
+	return tree
+}
+
+// crushLevel returns the position of the bucket type in the CRUSH hierarchy from the hosts up, or -1
+// if the type is not a level of the hierarchy
+func crushLevel(bucketType string) int {
+	return slices.Index(topology.CRUSHMapLevelsOrdered, bucketType)
+}
+
+// validateCrushHierarchy checks that the buckets and hosts are declared once and that each bucket
+// is in a bucket of a higher type, which also rules out cycles
+func validateCrushHierarchy(spec cephv1.CrushHierarchySpec) error {
+	buckets := map[string]cephv1.CrushBucketSpec{}
+	for _, bucket := range spec.Buckets {
+		if bucket.Name == "" {
+			return errors.New("crush hierarchy bucket name must be specified")
+		}
+		if _, ok := buckets[bucket.Name]; ok {
+			return errors.Errorf("crush hierarchy bucket %q is declared more than once", bucket.Name)
+		}
+		if crushLevel(bucket.Type) <= 0 {
+			return errors.Errorf("crush hierarchy bucket %q has unsupported type %q, expected one of %v", bucket.Name, bucket.Type, topology.CRUSHMapLevelsOrdered[1:])
+		}
+		buckets[bucket.Name] = bucket
+	}
+
+	hosts := map[string]string{}
+	for _, bucket := range spec.Buckets {
+		if parent, ok := buckets[bucket.Parent]; ok && crushLevel(parent.Type) <= crushLevel(bucket.Type) {
+			return errors.Errorf("crush hierarchy bucket %q of type %q cannot be in bucket %q of type %q", bucket.Name, bucket.Type, parent.Name, parent.Type)
+		}
+		for _, host := range bucket.Hosts {
+			hostName := cephclient.NormalizeCrushName(host)
+			if other, ok := hosts[hostName]; ok {
+				return errors.Errorf("crush hierarchy host %q is in both buckets %q and %q", host, other, bucket.Name)
+			}
+			if _, ok := buckets[hostName]; ok {
+				return errors.Errorf("crush hierarchy host %q has the name of a bucket", host)
+			}
+			hosts[hostName] = bucket.Name
+		}
+	}
+	return nil
+}
+
+// reconcileCrushHierarchy adds the declared buckets to the CRUSH map and moves the buckets and the
+// hosts to their declared parents. It returns the status of the hierarchy, or nil if no hierarchy is
+// declared.
+func (c *Cluster) reconcileCrushHierarchy() (*cephv1.CrushHierarchyStatus, error) {
+	buckets := slices.Clone(c.spec.Storage.CrushHierarchy.Buckets)
+	if len(buckets) == 0 {
+		return nil, nil
+	}
+
+	crushMap, err := cephclient.GetCrushMap(c.context, c.clusterInfo)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get crush map")
+	}
+	tree := newCrushTree(crushMap)
+	defaultParent := cephclient.GetCrushRootFromSpec(&c.spec)
+
+	// the parents are reconciled before the buckets in them
+	sort.SliceStable(buckets, func(i, j int) bool {
+		return crushLevel(buckets[i].Type) > crushLevel(buckets[j].Type)
+	})
+
+	differences := []string{}
+	skipped := map[string]bool{}
+	for _, bucket := range buckets {
+		parent := bucket.Parent
+		if parent == "" {
+			parent = defaultParent
+		}
+
+		if skipped[parent] {
+			differences = append(differences, fmt.Sprintf("bucket %q is not moved since its parent %q is not reconciled", bucket.Name, parent))
+			skipped[bucket.Name] = true
+			continue
+		}
+		currentType, exists := tree.types[bucket.Name]
+		if exists && currentType != bucket.Type {
+			differences = append(differences, fmt.Sprintf("bucket %q has type %q instead of %q", bucket.Name, currentType, bucket.Type))
+			skipped[bucket.Name] = true
+			continue
+		}
+		parentType, ok := tree.types[parent]
+		if !ok {
+			differences = append(differences, fmt.Sprintf("parent %q of bucket %q is not in the crush map", parent, bucket.Name))
+			skipped[bucket.Name] = true
+			continue
+		}
+
+		if !exists {
+			log.NamespacedInfo(c.clusterInfo.Namespace, logger, "adding %s %q to the crush map", bucket.Type, bucket.Name)
+			if err := cephclient.AddCrushBucket(c.context, c.clusterInfo, bucket.Name, bucket.Type); err != nil {
+				return nil, err
+			}
+			tree.types[bucket.Name] = bucket.Type
+		}
+		if err := c.moveCrushBucket(tree, bucket.Name, parentType, parent); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, bucket := range c.spec.Storage.CrushHierarchy.Buckets {
+		for _, host := range bucket.Hosts {
+			hostName := cephclient.NormalizeCrushName(host)
+			if skipped[bucket.Name] {
+				differences = append(differences, fmt.Sprintf("host %q is not moved since its bucket %q is not reconciled", hostName, bucket.Name))
+				continue
+			}
+			hostType, ok := tree.types[hostName]
+			if !ok {
+				differences = append(differences, fmt.Sprintf("host %q is not in the crush map", hostName))
+				continue
+			}
+			if hostType != crushHostType {
+				differences = append(differences, fmt.Sprintf("host %q has type %q instead of %q", hostName, hostType, crushHostType))
+				continue
+			}
+			if err := c.moveCrushBucket(tree, hostName, bucket.Type, bucket.Name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, difference := range differences {
+		log.NamespacedWarning(c.clusterInfo.Namespace, logger, "crush hierarchy differs from the crush map: %s", difference)
+	}
+	return &cephv1.CrushHierarchyStatus{InSync: len(differences) == 0, Differences: differences}, nil
+}
+
+// moveCrushBucket moves the bucket into the parent unless it is already there
+func (c *Cluster) moveCrushBucket(tree *crushTree, name, parentType, parent string) error {
+	if tree.parents[name] == parent {
+		return nil
+	}
+	log.NamespacedInfo(c.clusterInfo.Namespace, logger, "moving crush bucket %q from %q to %s %q", name, tree.parents[name], parentType, parent)
+	if err := cephclient.MoveCrushBucket(c.context, c.clusterInfo, name, parentType, parent); err != nil {
+		return err
+	}
+	tree.parents[name] = parent
+	return nil
+}
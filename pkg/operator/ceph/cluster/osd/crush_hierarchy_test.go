@@ -0,0 +1,173 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the default root has the hosts node-a and node-b and the empty rack rack1
+const crushHierarchyDump = `{
+  "buckets": [
+    {"id": -1, "name": "default", "type_name": "root", "items": [{"id": -2}, {"id": -3}, {"id": -4}]},
+    {"id": -2, "name": "node-a", "type_name": "host", "items": [{"id": 0}]},
+    {"id": -3, "name": "node-b", "type_name": "host", "items": [{"id": 1}]},
+    {"id": -4, "name": "rack1", "type_name": "rack", "items": []},
+    {"id": -5, "name": "default~hdd", "type_name": "root", "items": [{"id": -6}]},
+    {"id": -6, "name": "node-a~hdd", "type_name": "host", "items": [{"id": 0}]}
+  ]
+}`
+
+func TestReconcileCrushHierarchy(t *testing.T) {
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] != "osd" || args[1] != "crush" {
+				return "", errors.Errorf("unexpected command %v", args)
+			}
+			switch args[2] {
+			case "dump":
+				return crushHierarchyDump, nil
+			case "add-bucket", "move":
+				commands = append(commands, strings.Join(args[2:5], " "))
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %v", args)
+		},
+	}
+	clusterInfo := cephclient.AdminTestClusterInfo("ns")
+	clusterInfo.Context = context.TODO()
+	c := New(&clusterd.Context{Executor: executor}, clusterInfo, cephv1.ClusterSpec{}, "version")
+
+	t.Run("no hierarchy", func(t *testing.T) {
+		status, err := c.reconcileCrushHierarchy()
+		assert.NoError(t, err)
+		assert.Nil(t, status)
+		assert.Empty(t, commands)
+	})
+
+	t.Run("buckets added and moved", func(t *testing.T) {
+		commands = nil
+		c.spec.Storage.CrushHierarchy.Buckets = []cephv1.CrushBucketSpec{
+			{Name: "rack1", Type: "rack", Parent: "dc1", Hosts: []string{"node-a"}},
+			{Name: "rack2", Type: "rack", Parent: "dc1", Hosts: []string{"node-b", "node-c"}},
+			{Name: "dc1", Type: "datacenter"},
+		}
+		status, err := c.reconcileCrushHierarchy()
+		assert.NoError(t, err)
+		require.NotNil(t, status)
+		assert.False(t, status.InSync)
+		assert.Equal(t, []string{`host "node-c" is not in the crush map`}, status.Differences)
+		assert.Equal(t, []string{
+			"add-bucket dc1 datacenter",
+			"move dc1 root=default",
+			"move rack1 datacenter=dc1",
+			"add-bucket rack2 rack",
+			"move rack2 datacenter=dc1",
+			"move node-a rack=rack1",
+			"move node-b rack=rack2",
+		}, commands)
+	})
+
+	t.Run("in sync", func(t *testing.T) {
+		commands = nil
+		c.spec.Storage.CrushHierarchy.Buckets = []cephv1.CrushBucketSpec{
+			{Name: "rack1", Type: "rack", Hosts: []string{"node-b"}},
+		}
+		status, err := c.reconcileCrushHierarchy()
+		assert.NoError(t, err)
+		require.NotNil(t, status)
+		assert.True(t, status.InSync)
+		assert.Empty(t, status.Differences)
+		assert.Equal(t, []string{"move node-b rack=rack1"}, commands)
+	})
+
+	t.Run("differences", func(t *testing.T) {
+		commands = nil
+		c.spec.Storage.CrushHierarchy.Buckets = []cephv1.CrushBucketSpec{
+			{Name: "rack1", Type: "row", Hosts: []string{"node-a"}},
+			{Name: "rack3", Type: "rack", Parent: "rack1"},
+			{Name: "rack4", Type: "rack", Parent: "dc2"},
+			{Name: "rack5", Type: "rack", Hosts: []string{"rack1"}},
+		}
+		status, err := c.reconcileCrushHierarchy()
+		assert.NoError(t, err)
+		require.NotNil(t, status)
+		assert.False(t, status.InSync)
+		assert.Equal(t, []string{
+			`bucket "rack1" has type "rack" instead of "row"`,
+			`bucket "rack3" is not moved since its parent "rack1" is not reconciled`,
+			`parent "dc2" of bucket "rack4" is not in the crush map`,
+			`host "node-a" is not moved since its bucket "rack1" is not reconciled`,
+			`host "rack1" has type "rack" instead of "host"`,
+		}, status.Differences)
+		assert.Equal(t, []string{"add-bucket rack5 rack", "move rack5 root=default"}, commands)
+	})
+}
+
+func TestValidateCrushHierarchy(t *testing.T) {
+	tests := []struct {
+		name    string
+		buckets []cephv1.CrushBucketSpec
+		err     string
+	}{
+		{"empty", nil, ""},
+		{"valid", []cephv1.CrushBucketSpec{
+			{Name: "dc1", Type: "datacenter"},
+			{Name: "rack1", Type: "rack", Parent: "dc1", Hosts: []string{"node-a"}},
+			{Name: "rack2", Type: "rack", Parent: "dc1", Hosts: []string{"node-b"}},
+		}, ""},
+		{"no name", []cephv1.CrushBucketSpec{{Type: "rack"}}, "bucket name must be specified"},
+		{"duplicate bucket", []cephv1.CrushBucketSpec{
+			{Name: "rack1", Type: "rack"},
+			{Name: "rack1", Type: "rack"},
+		}, `bucket "rack1" is declared more than once`},
+		{"host type", []cephv1.CrushBucketSpec{{Name: "node-a", Type: "host"}}, `unsupported type "host"`},
+		{"lower parent", []cephv1.CrushBucketSpec{
+			{Name: "rack1", Type: "rack"},
+			{Name: "dc1", Type: "datacenter", Parent: "rack1"},
+		}, `bucket "dc1" of type "datacenter" cannot be in bucket "rack1" of type "rack"`},
+		{"own parent", []cephv1.CrushBucketSpec{{Name: "rack1", Type: "rack", Parent: "rack1"}}, "cannot be in bucket"},
+		{"duplicate host", []cephv1.CrushBucketSpec{
+			{Name: "rack1", Type: "rack", Hosts: []string{"node-a"}},
+			{Name: "rack2", Type: "rack", Hosts: []string{"node-a"}},
+		}, `host "node-a" is in both buckets "rack1" and "rack2"`},
+		{"host named as a bucket", []cephv1.CrushBucketSpec{
+			{Name: "rack1", Type: "rack", Hosts: []string{"rack1"}},
+		}, `host "rack1" has the name of a bucket`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCrushHierarchy(cephv1.CrushHierarchySpec{Buckets: tt.buckets})
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
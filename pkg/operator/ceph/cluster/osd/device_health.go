@@ -0,0 +1,296 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/util/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	defaultDeviceHealthCheckInterval = time.Hour
+	// a device predicted to fail within 4 weeks is failing, and within 12 weeks is a warning, which
+	// are the mark out and warn thresholds of the devicehealth mgr module
+	deviceFailingLifeExpectancy = 4 * 7 * 24 * time.Hour
+	deviceWarningLifeExpectancy = 12 * 7 * 24 * time.Hour
+	// nvmeWearWarningPercentage is the percentage of the rated NVMe endurance used for a warning
+	nvmeWearWarningPercentage = 90
+	// minOSDInRatio is the ratio of OSDs that must stay in when the OSDs of failing devices are
+	// marked out, which is the default of mon_osd_min_in_ratio
+	minOSDInRatio = 0.75
+
+	deviceFailingEventReason = "DeviceFailing"
+)
+
+// ataSmartErrorAttributes are the ATA SMART attributes counting sectors that went bad
+var ataSmartErrorAttributes = map[int]string{
+	5:   "reallocated sectors",
+	197: "pending sectors",
+	198: "uncorrectable sectors",
+}
+
+// checkDeviceHealth reports the health of the devices used by the OSDs in the CephCluster status
+// when the device health checks are enabled. An event is raised when a device starts failing, and
+// the OSDs of the failing devices are marked out with the MarkOut failure policy.
+func (m *OSDHealthMonitor) checkDeviceHealth(now time.Time) error {
+	// The settings are read from the CephCluster so that changing them takes effect on the next check
+	cephCluster := &cephv1.CephCluster{}
+	if err := m.context.Client.Get(m.clusterInfo.Context, m.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		return errors.Wrapf(err, "failed to get cluster %v", m.clusterInfo.NamespacedName())
+	}
+	spec := cephCluster.Spec.Storage.DeviceHealth
+	if !spec.Enabled {
+		m.lastDeviceHealthCheck = time.Time{}
+		if cephCluster.Status.CephStorage != nil && cephCluster.Status.CephStorage.DeviceHealth != nil {
+			return m.updateDeviceHealthStatus(nil)
+		}
+		return nil
+	}
+	interval := defaultDeviceHealthCheckInterval
+	if spec.Interval != nil && spec.Interval.Duration > 0 {
+		interval = spec.Interval.Duration
+	}
+	if !m.lastDeviceHealthCheck.IsZero() && now.Sub(m.lastDeviceHealthCheck) < interval {
+		return nil
+	}
+
+	var previous []cephv1.DeviceHealth
+	if cephCluster.Status.CephStorage != nil && cephCluster.Status.CephStorage.DeviceHealth != nil {
+		previous = cephCluster.Status.CephStorage.DeviceHealth.Devices
+	}
+
+	devices, err := cephclient.ListDevices(m.context, m.clusterInfo)
+	if err != nil {
+		return err
+	}
+	var health []cephv1.DeviceHealth
+	for _, device := range devices {
+		osds := deviceOSDs(device)
+		if len(osds) == 0 {
+			continue
+		}
+		var metrics *cephclient.DeviceHealthMetrics
+		metrics, err = cephclient.GetLatestDeviceHealthMetrics(m.context, m.clusterInfo, device.DevID)
+		if err != nil {
+			log.NamespacedWarning(m.clusterInfo.Namespace, logger, "failed to check the health of device %q. %v", device.DevID, err)
+		}
+		state, message := evaluateDeviceHealth(device, metrics, now)
+		status := cephv1.DeviceHealth{
+			DeviceID:       device.DevID,
+			OSDs:           osds,
+			Health:         state,
+			LifeExpectancy: device.LifeExpectancyMin,
+			Message:        message,
+		}
+		if len(device.Location) > 0 {
+			status.Host = device.Location[0].Host
+		}
+		prev := findDeviceHealth(previous, device.DevID)
+		if prev != nil {
+			status.MarkedOut = prev.MarkedOut && state == cephv1.DeviceHealthFailing
+		}
+		if state == cephv1.DeviceHealthFailing && (prev == nil || prev.Health != cephv1.DeviceHealthFailing) {
+			log.NamespacedWarning(m.clusterInfo.Namespace, logger, "device %q of osd(s) %v on host %q is failing. %s", device.DevID, osds, status.Host, message)
+			if m.recorder != nil {
+				m.recorder.Eventf(cephCluster, nil, corev1.EventTypeWarning, deviceFailingEventReason, "CheckDeviceHealth",
+					"device %q of osd(s) %v on host %q is failing. %s", device.DevID, osds, status.Host, message)
+			}
+		}
+		health = append(health, status)
+	}
+	sort.Slice(health, func(i, j int) bool { return health[i].DeviceID < health[j].DeviceID })
+
+	if spec.FailurePolicy == cephv1.DeviceFailurePolicyMarkOut {
+		if err := m.markOutFailingDevices(health); err != nil {
+			log.NamespacedWarning(m.clusterInfo.Namespace, logger, "failed to mark out the osds of the failing devices. %v", err)
+		}
+	}
+
+	m.lastDeviceHealthCheck = now
+	return m.updateDeviceHealthStatus(&cephv1.DeviceHealthStatus{LastChecked: &metav1.Time{Time: now}, Devices: health})
+}
+
+// evaluateDeviceHealth returns the health of a device from its predicted life expectancy and its
+// latest SMART data, with a message giving the reasons when the device is not healthy
+func evaluateDeviceHealth(device cephclient.Device, metrics *cephclient.DeviceHealthMetrics, now time.Time) (cephv1.DeviceHealthState, string) {
+	var failing, warnings []string
+
+	lifeExpectancy, err := cephclient.ParseLifeExpectancy(device.LifeExpectancyMin)
+	if err != nil {
+		logger.Debugf("ignoring the life expectancy of device %q. %v", device.DevID, err)
+	}
+	if lifeExpectancy != nil {
+		remaining := lifeExpectancy.Sub(now)
+		switch {
+		case remaining < deviceFailingLifeExpectancy:
+			failing = append(failing, fmt.Sprintf("failure predicted by %s", device.LifeExpectancyMin))
+		case remaining < deviceWarningLifeExpectancy:
+			warnings = append(warnings, fmt.Sprintf("failure predicted by %s", device.LifeExpectancyMin))
+		}
+	}
+
+	if metrics == nil {
+		if len(failing) > 0 {
+			return cephv1.DeviceHealthFailing, strings.Join(failing, "; ")
+		}
+		return cephv1.DeviceHealthUnknown, "no SMART data"
+	}
+
+	if metrics.SmartStatus != nil && !metrics.SmartStatus.Passed {
+		failing = append(failing, "SMART overall health check failed")
+	}
+	for _, attr := range metrics.ATASmartAttributes.Table {
+		if name, ok := ataSmartErrorAttributes[attr.ID]; ok && attr.Raw.Value > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d %s", attr.Raw.Value, name))
+		}
+	}
+	if nvme := metrics.NVMeSmartHealthInformationLog; nvme != nil {
+		if nvme.CriticalWarning != 0 {
+			failing = append(failing, fmt.Sprintf("NVMe critical warning 0x%x", nvme.CriticalWarning))
+		}
+		if nvme.MediaErrors > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d media errors", nvme.MediaErrors))
+		}
+		if nvme.PercentageUsed >= nvmeWearWarningPercentage {
+			warnings = append(warnings, fmt.Sprintf("%d%% of the endurance used", nvme.PercentageUsed))
+		}
+	}
+
+	switch {
+	case len(failing) > 0:
+		return cephv1.DeviceHealthFailing, strings.Join(append(failing, warnings...), "; ")
+	case len(warnings) > 0:
+		return cephv1.DeviceHealthWarning, strings.Join(warnings, "; ")
+	}
+	return cephv1.DeviceHealthGood, ""
+}
+
+// markOutFailingDevices marks out the OSDs of the failing devices that are still in, as long as
+// enough OSDs stay in. A device is flagged as marked out once all its OSDs are out.
+func (m *OSDHealthMonitor) markOutFailingDevices(devices []cephv1.DeviceHealth) error {
+	var osdDump *cephclient.OSDDump
+	var total, in int
+	for i := range devices {
+		device := &devices[i]
+		if device.Health != cephv1.DeviceHealthFailing || device.MarkedOut {
+			continue
+		}
+		if osdDump == nil {
+			var err error
+			osdDump, err = cephclient.GetOSDDump(m.context, m.clusterInfo)
+			if err != nil {
+				return errors.Wrap(err, "failed to get osd dump")
+			}
+			total, in = countOSDsIn(osdDump)
+		}
+
+		allOut := true
+		for _, osdID := range device.OSDs {
+			_, osdIn, err := osdDump.StatusByID(int64(osdID))
+			if err != nil || osdIn != inStatus {
+				continue
+			}
+			if float64(in-1) < minOSDInRatio*float64(total) {
+				log.NamespacedWarning(m.clusterInfo.Namespace, logger,
+					"not marking out osd.%d of failing device %q since fewer than %.0f%% of the osds would be in", osdID, device.DeviceID, minOSDInRatio*100)
+				allOut = false
+				continue
+			}
+			log.NamespacedInfo(m.clusterInfo.Namespace, logger, "marking out osd.%d of failing device %q", osdID, device.DeviceID)
+			if err := cephclient.OSDOut(m.context, m.clusterInfo, osdID); err != nil {
+				log.NamespacedWarning(m.clusterInfo.Namespace, logger, "failed to mark out osd.%d. %v", osdID, err)
+				allOut = false
+				continue
+			}
+			in--
+		}
+		device.MarkedOut = allOut
+	}
+	return nil
+}
+
+// updateDeviceHealthStatus records the device health in the CephCluster status, or removes it if nil
+func (m *OSDHealthMonitor) updateDeviceHealthStatus(status *cephv1.DeviceHealthStatus) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cephCluster := &cephv1.CephCluster{}
+		if err := m.context.Client.Get(m.clusterInfo.Context, m.clusterInfo.NamespacedName(), cephCluster); err != nil {
+			return errors.Wrapf(err, "failed to get cluster %v", m.clusterInfo.NamespacedName())
+		}
+		if cephCluster.Status.CephStorage == nil {
+			if status == nil {
+				return nil
+			}
+			cephCluster.Status.CephStorage = &cephv1.CephStorage{}
+		}
+		cephCluster.Status.CephStorage.DeviceHealth = status
+		return reporting.UpdateStatus(m.context.Client, cephCluster)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update the device health status")
+	}
+	return nil
+}
+
+// deviceOSDs returns the IDs of the OSDs using the device
+func deviceOSDs(device cephclient.Device) []int {
+	var osds []int
+	for _, daemon := range device.Daemons {
+		id, ok := strings.CutPrefix(daemon, "osd.")
+		if !ok {
+			continue
+		}
+		osdID, err := strconv.Atoi(id)
+		if err != nil {
+			continue
+		}
+		osds = append(osds, osdID)
+	}
+	sort.Ints(osds)
+	return osds
+}
+
+func findDeviceHealth(devices []cephv1.DeviceHealth, devID string) *cephv1.DeviceHealth {
+	for i := range devices {
+		if devices[i].DeviceID == devID {
+			return &devices[i]
+		}
+	}
+	return nil
+}
+
+// countOSDsIn returns the number of OSDs and the number of OSDs that are in
+func countOSDsIn(osdDump *cephclient.OSDDump) (int, int) {
+	var in int
+	for _, osd := range osdDump.OSDs {
+		if value, err := osd.In.Int64(); err == nil && value == inStatus {
+			in++
+		}
+	}
+	return len(osdDump.OSDs), in
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEvaluateDeviceHealth(t *testing.T) {
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	passed := func(passed bool) *cephclient.DeviceHealthMetrics {
+		metrics := &cephclient.DeviceHealthMetrics{}
+		metrics.SmartStatus = &struct {
+			Passed bool `json:"passed"`
+		}{Passed: passed}
+		return metrics
+	}
+	withAttribute := func(id int, value int64) *cephclient.DeviceHealthMetrics {
+		metrics := passed(true)
+		attr := cephclient.ATASmartAttribute{ID: id}
+		attr.Raw.Value = value
+		metrics.ATASmartAttributes.Table = []cephclient.ATASmartAttribute{{ID: 9}, attr}
+		return metrics
+	}
+	nvme := func(log cephclient.NVMeSmartHealthInformationLog) *cephclient.DeviceHealthMetrics {
+		metrics := passed(true)
+		metrics.NVMeSmartHealthInformationLog = &log
+		return metrics
+	}
+
+	tests := []struct {
+		name           string
+		lifeExpectancy string
+		metrics        *cephclient.DeviceHealthMetrics
+		want           cephv1.DeviceHealthState
+		message        string
+	}{
+		{"good", "", passed(true), cephv1.DeviceHealthGood, ""},
+		{"no smart data", "", nil, cephv1.DeviceHealthUnknown, "no SMART data"},
+		{"smart failed", "", passed(false), cephv1.DeviceHealthFailing, "SMART overall health check failed"},
+		{"failure predicted soon", "2026-11-01 00:00:00.000000", nil, cephv1.DeviceHealthFailing, "failure predicted by 2026-11-01 00:00:00.000000"},
+		{"failure predicted later", "2026-12-15 00:00:00.000000", passed(true), cephv1.DeviceHealthWarning, "failure predicted by 2026-12-15 00:00:00.000000"},
+		{"failure predicted far away", "2027-06-01 00:00:00.000000", passed(true), cephv1.DeviceHealthGood, ""},
+		{"reallocated sectors", "", withAttribute(5, 8), cephv1.DeviceHealthWarning, "8 reallocated sectors"},
+		{"other attribute", "", withAttribute(194, 40), cephv1.DeviceHealthGood, ""},
+		{"nvme critical warning", "", nvme(cephclient.NVMeSmartHealthInformationLog{CriticalWarning: 4, MediaErrors: 2}), cephv1.DeviceHealthFailing, "NVMe critical warning 0x4; 2 media errors"},
+		{"nvme worn out", "", nvme(cephclient.NVMeSmartHealthInformationLog{PercentageUsed: 95}), cephv1.DeviceHealthWarning, "95% of the endurance used"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, message := evaluateDeviceHealth(cephclient.Device{DevID: "dev", LifeExpectancyMin: tt.lifeExpectancy}, tt.metrics, now)
+			assert.Equal(t, tt.want, state)
+			assert.Equal(t, tt.message, message)
+		})
+	}
+}
+
+func TestCheckDeviceHealth(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "ns"
+
+	// osd.0 is on a failing device, osd.1 and osd.2 share a healthy device and osd.3 has no SMART data
+	deviceLs := `[
+		{"devid": "HDD_FAILING", "location": [{"host": "node-1", "dev": "sdb"}], "daemons": ["osd.0"]},
+		{"devid": "NVME_SHARED", "location": [{"host": "node-1", "dev": "nvme0n1"}], "daemons": ["osd.2", "osd.1"]},
+		{"devid": "HDD_UNKNOWN", "location": [{"host": "node-2", "dev": "sdb"}], "daemons": ["osd.3"]},
+		{"devid": "MON_DISK", "location": [{"host": "node-2", "dev": "sda"}], "daemons": ["mon.a"]}
+	]`
+	smartPassed := `{"20261017-000000": {"smart_status": {"passed": %t}}}`
+	osdIn := map[int]int{0: 1, 1: 1, 2: 1, 3: 1}
+	var markedOut []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			switch {
+			case args[0] == "device" && args[1] == "ls":
+				return deviceLs, nil
+			case args[0] == "device" && args[1] == "get-health-metrics":
+				switch {
+				case strings.HasPrefix(args[2], "HDD_FAILING"):
+					return fmt.Sprintf(smartPassed, false), nil
+				case args[2] == "NVME_SHARED":
+					return fmt.Sprintf(smartPassed, true), nil
+				case args[2] == "HDD_UNKNOWN":
+					return "{}", nil
+				}
+			case args[0] == "osd" && args[1] == "dump":
+				osds := []string{}
+				for id := 0; id < 4; id++ {
+					osds = append(osds, fmt.Sprintf(`{"osd": %d, "up": 1, "in": %d}`, id, osdIn[id]))
+				}
+				return fmt.Sprintf(`{"osds": [%s]}`, strings.Join(osds, ",")), nil
+			case args[0] == "osd" && args[1] == "out":
+				markedOut = append(markedOut, args[2])
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+	}
+
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: namespace},
+		Spec: cephv1.ClusterSpec{Storage: cephv1.StorageScopeSpec{DeviceHealth: cephv1.DeviceHealthSpec{
+			Enabled: true,
+		}}},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+	cl := clientfake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(cephCluster).WithStatusSubresource(cephCluster).Build()
+
+	clusterInfo := cephclient.AdminTestClusterInfo(namespace)
+	clusterInfo.SetName("my-cluster")
+	clusterInfo.Context = ctx
+	m := NewOSDHealthMonitor(&clusterd.Context{Executor: executor, Client: cl}, clusterInfo, false, cephv1.CephClusterHealthCheckSpec{}, cephv1.ClusterSpec{}, "rook/ceph:test")
+	recorder := events.NewFakeRecorder(10)
+	m.SetEventRecorder(recorder)
+
+	getStatus := func() *cephv1.DeviceHealthStatus {
+		cluster := &cephv1.CephCluster{}
+		require.NoError(t, cl.Get(ctx, clusterInfo.NamespacedName(), cluster))
+		if cluster.Status.CephStorage == nil {
+			return nil
+		}
+		return cluster.Status.CephStorage.DeviceHealth
+	}
+	updateSpec := func(spec cephv1.DeviceHealthSpec) {
+		cluster := &cephv1.CephCluster{}
+		require.NoError(t, cl.Get(ctx, clusterInfo.NamespacedName(), cluster))
+		cluster.Spec.Storage.DeviceHealth = spec
+		require.NoError(t, cl.Update(ctx, cluster))
+	}
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+
+	t.Run("report", func(t *testing.T) {
+		require.NoError(t, m.checkDeviceHealth(now))
+		status := getStatus()
+		require.NotNil(t, status)
+		assert.Equal(t, now, status.LastChecked.UTC())
+		assert.Equal(t, []cephv1.DeviceHealth{
+			{DeviceID: "HDD_FAILING", Host: "node-1", OSDs: []int{0}, Health: cephv1.DeviceHealthFailing, Message: "SMART overall health check failed"},
+			{DeviceID: "HDD_UNKNOWN", Host: "node-2", OSDs: []int{3}, Health: cephv1.DeviceHealthUnknown, Message: "no SMART data"},
+			{DeviceID: "NVME_SHARED", Host: "node-1", OSDs: []int{1, 2}, Health: cephv1.DeviceHealthGood},
+		}, status.Devices)
+		assert.Empty(t, markedOut)
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, `Warning DeviceFailing device "HDD_FAILING" of osd(s) [0] on host "node-1" is failing`)
+	})
+
+	t.Run("interval", func(t *testing.T) {
+		deviceLs = "[]"
+		require.NoError(t, m.checkDeviceHealth(now.Add(30*time.Minute)))
+		assert.Len(t, getStatus().Devices, 3)
+
+		deviceLs = `[{"devid": "HDD_FAILING", "location": [{"host": "node-1", "dev": "sdb"}], "daemons": ["osd.0"]}]`
+		require.NoError(t, m.checkDeviceHealth(now.Add(time.Hour)))
+		assert.Len(t, getStatus().Devices, 1)
+		// the event is only raised when the device starts failing
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("mark out", func(t *testing.T) {
+		updateSpec(cephv1.DeviceHealthSpec{Enabled: true, FailurePolicy: cephv1.DeviceFailurePolicyMarkOut})
+		require.NoError(t, m.checkDeviceHealth(now.Add(2*time.Hour)))
+		assert.Equal(t, []string{"0"}, markedOut)
+		assert.True(t, getStatus().Devices[0].MarkedOut)
+
+		// the osds are not marked out again
+		require.NoError(t, m.checkDeviceHealth(now.Add(3*time.Hour)))
+		assert.Equal(t, []string{"0"}, markedOut)
+		assert.True(t, getStatus().Devices[0].MarkedOut)
+	})
+
+	t.Run("too few osds in", func(t *testing.T) {
+		markedOut = nil
+		osdIn = map[int]int{0: 1, 1: 1, 2: 0, 3: 1}
+		deviceLs = `[{"devid": "HDD_FAILING_2", "location": [{"host": "node-2", "dev": "sdc"}], "daemons": ["osd.3"]}]`
+		require.NoError(t, m.checkDeviceHealth(now.Add(4*time.Hour)))
+		// 2 of the 4 osds would be left in
+		assert.Empty(t, markedOut)
+		assert.False(t, getStatus().Devices[0].MarkedOut)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		updateSpec(cephv1.DeviceHealthSpec{})
+		require.NoError(t, m.checkDeviceHealth(now.Add(5*time.Hour)))
+		assert.Nil(t, getStatus())
+	})
+}
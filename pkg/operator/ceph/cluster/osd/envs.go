@@ -18,6 +18,7 @@ package osd
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	opmon "github.com/rook/rook/pkg/operator/ceph/cluster/mon"
@@ -39,6 +40,8 @@ const (
 	// Hardcoded in ceph-volume do NOT touch
 	CephVolumeEncryptedKeyEnvVarName = "CEPH_VOLUME_DMCRYPT_SECRET"
 	osdMetadataDeviceEnvVarName      = "ROOK_METADATA_DEVICE"
+	osdMetadataDevicesEnvVarName     = "ROOK_METADATA_DEVICES"
+	osdMetadataDeviceRatioEnvVarName = "ROOK_METADATA_DEVICE_RATIO"
 	osdWalDeviceEnvVarName           = "ROOK_WAL_DEVICE"
 	// PVCBackedOSDVarName indicates whether the OSD is on PVC ("true") or not ("false")
 	PVCBackedOSDVarName                 = "ROOK_PVC_BACKED_OSD"
@@ -138,6 +141,13 @@ func metadataDeviceEnvVar(metadataDevice string) v1.EnvVar {
 	return v1.EnvVar{Name: osdMetadataDeviceEnvVarName, Value: metadataDevice}
 }
 
+func metadataDevicesEnvVars(metadataDevices []string, ratio int) []v1.EnvVar {
+	return []v1.EnvVar{
+		{Name: osdMetadataDevicesEnvVarName, Value: strings.Join(metadataDevices, ",")},
+		{Name: osdMetadataDeviceRatioEnvVarName, Value: strconv.Itoa(ratio)},
+	}
+}
+
 func walDeviceEnvVar(walDevice string) v1.EnvVar {
 	return v1.EnvVar{Name: osdWalDeviceEnvVarName, Value: walDevice}
 }
@@ -66,6 +66,15 @@ func TestOsdActivateEnvVar(t *testing.T) {
 	assert.Equal(t, "-m $(ROOK_CEPH_MON_HOST)", osdActivateEnv[4].Value)
 }
 
+func TestMetadataDevicesEnvVars(t *testing.T) {
+	envVars := metadataDevicesEnvVars([]string{"nvme0n1", "/dev/disk/by-id/nvme-1"}, 4)
+	assert.Equal(t, 2, len(envVars))
+	assert.Equal(t, "ROOK_METADATA_DEVICES", envVars[0].Name)
+	assert.Equal(t, "nvme0n1,/dev/disk/by-id/nvme-1", envVars[0].Value)
+	assert.Equal(t, "ROOK_METADATA_DEVICE_RATIO", envVars[1].Name)
+	assert.Equal(t, "4", envVars[1].Value)
+}
+
 func TestGetTcmallocMaxTotalThreadCacheBytes(t *testing.T) {
 	// No file, nothing
 	v := getTcmallocMaxTotalThreadCacheBytes("")
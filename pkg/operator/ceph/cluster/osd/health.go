@@ -30,6 +30,7 @@ import (
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/util/log"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/events"
 )
 
 const (
@@ -52,6 +53,9 @@ type OSDHealthMonitor struct {
 	// reads spec.Placement, spec.PriorityClassNames, spec.Storage.OnlyApplyOSDPlacement and the rook
 	// image, so it holds the ClusterSpec captured when the monitor was created.
 	cluster *Cluster
+	// recorder raises the events about failing devices, if set
+	recorder              events.EventRecorder
+	lastDeviceHealthCheck time.Time
 }
 
 // NewOSDHealthMonitor instantiates OSD monitoring
@@ -74,6 +78,11 @@ func NewOSDHealthMonitor(context *clusterd.Context, clusterInfo *client.ClusterI
 	return h
 }
 
+// SetEventRecorder sets the recorder of the events raised on the CephCluster
+func (m *OSDHealthMonitor) SetEventRecorder(recorder events.EventRecorder) {
+	m.recorder = recorder
+}
+
 // Start runs monitoring logic for osds status at set intervals
 func (m *OSDHealthMonitor) Start(monitoringRoutines *sync.Map, daemon string) {
 	for {
@@ -110,11 +119,22 @@ func (m *OSDHealthMonitor) checkOSDHealth() {
 	if err != nil {
 		log.NamespacedWarning(m.clusterInfo.Namespace, logger, "failed to process OSD replacements. %v", err)
 	}
+	// Request the replacement of OSDs with a failed disk if enabled and record the replacements in the status
+	if err := m.processOSDReplacementPolicy(osdsUnderReplacement); err != nil {
+		log.NamespacedWarning(m.clusterInfo.Namespace, logger, "failed to process the OSD replacement policy. %v", err)
+	}
 
 	if err := m.checkOSDDump(osdsUnderReplacement); err != nil {
 		log.NamespacedDebug(m.clusterInfo.Namespace, logger, "failed to check OSD Dump. %v", err)
 	}
 	m.checkRequireOSDRelease()
+
+	if err := m.checkDeviceHealth(time.Now()); err != nil {
+		log.NamespacedWarning(m.clusterInfo.Namespace, logger, "failed to check the device health. %v", err)
+	}
+	if err := m.updateKeyRotationStatus(); err != nil {
+		log.NamespacedWarning(m.clusterInfo.Namespace, logger, "failed to update the key rotation status. %v", err)
+	}
 }
 
 // checkRequireOSDRelease checks if all OSDs report the same version and, if so,
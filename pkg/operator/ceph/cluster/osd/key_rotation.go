@@ -19,16 +19,25 @@ package osd
 import (
 	"fmt"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	kms "github.com/rook/rook/pkg/daemon/ceph/osd/kms"
+	opmon "github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	"github.com/rook/rook/pkg/operator/ceph/config/keyring"
 	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/util/log"
 	batch "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrl "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -36,8 +45,21 @@ import (
 const (
 	keyRotationCronJobAppName    = "rook-ceph-osd-key-rotation"
 	keyRotationCronJobAppNameFmt = "rook-ceph-osd-key-rotation-%d"
+
+	// keyRotationCephUser is the cephx user of the key rotation jobs of the host-based OSDs
+	keyRotationCephUser = "client.osd-key-rotation"
+	//#nosec G101 -- This is only a secret name
+	keyRotationSecretName = "rook-ceph-osd-key-rotation-cephx"
+	//#nosec G101 -- This is only a volume name
+	keyRotationSecretVolumeName = "osd-key-rotation-secret"
 )
 
+// keyRotationCephCaps only allow the key rotation user to read and write the encryption keys that
+// ceph-volume keeps in the mon config-key store
+var keyRotationCephCaps = []string{
+	"mon", `allow command "config-key get" with key prefix dm-crypt/osd/, allow command "config-key set" with key prefix dm-crypt/osd/`,
+}
+
 // keyRotationCronJobName returns the name of the key rotation cron job for the given OSD ID.
 func keyRotationCronJobName(osdID int) string {
 	return fmt.Sprintf(keyRotationCronJobAppNameFmt, osdID)
@@ -65,7 +87,7 @@ func applyKeyRotationPlacement(spec *v1.PodSpec, labels map[string]string) {
 }
 
 // getKeyRotationContainer returns the container spec for the key rotation job.
-func (c *Cluster) getKeyRotationContainer(osdProps osdProperties, volumeMounts []v1.VolumeMount, devices []string) (v1.Container, error) {
+func (c *Cluster) getKeyRotationContainer(osdProps osdProperties, volumeMounts []v1.VolumeMount, rotateKeyArgs []string) (v1.Container, error) {
 	envVars := c.getConfigEnvVars(osdProps, k8sutil.DataDir, true)
 
 	// enable debug logging
@@ -79,8 +101,7 @@ func (c *Cluster) getKeyRotationContainer(osdProps osdProperties, volumeMounts [
 	runAsNonRoot := false
 	readOnlyRootFilesystem := false
 
-	args := []string{"key-management", "rotate-key", osdProps.pvc.ClaimName}
-	args = append(args, devices...)
+	args := append([]string{"key-management", "rotate-key"}, rotateKeyArgs...)
 
 	osdProvisionContainer := v1.Container{
 		Args:            args,
@@ -108,11 +129,8 @@ func (c *Cluster) getKeyRotationContainer(osdProps osdProperties, volumeMounts [
 	return osdProvisionContainer, nil
 }
 
-// getKeyRotationPodTemplateSpec returns the pod template spec for the key rotation job.
+// getKeyRotationPodTemplateSpec returns the pod template spec for the key rotation job of an OSD on a PVC.
 func (c *Cluster) getKeyRotationPodTemplateSpec(osdProps osdProperties, osd OSDInfo, restart v1.RestartPolicy) (*v1.PodTemplateSpec, error) {
-	// create a volume on /dev so the pod can access devices on the host
-	devVolume := v1.Volume{Name: "devices", VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/dev"}}}
-	udevVolume := v1.Volume{Name: "udev", VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/run/udev"}}}
 	hostPathType := v1.HostPathDirectory
 	hostPath := filepath.Join(c.spec.DataDirHostPath, c.clusterInfo.Namespace, osdProps.pvc.ClaimName, fmt.Sprintf("ceph-%d", osd.ID))
 	hostPathVolume := v1.Volume{
@@ -125,32 +143,149 @@ func (c *Cluster) getKeyRotationPodTemplateSpec(osdProps osdProperties, osd OSDI
 		},
 	}
 	devicesBasePath := "/var/lib/ceph/osd/"
-	volumes := []v1.Volume{
-		udevVolume,
-		devVolume,
-		hostPathVolume,
-	}
-	volumeMounts := []v1.VolumeMount{
-		{Name: "devices", MountPath: "/dev"},
-		{Name: "udev", MountPath: "/run/udev"},
-		{Name: "bridge", MountPath: devicesBasePath},
-	}
 
-	devices := []string{encryptionBlockDestinationCopy(devicesBasePath, bluestoreBlockName)}
+	args := []string{osdProps.pvc.ClaimName, encryptionBlockDestinationCopy(devicesBasePath, bluestoreBlockName)}
 	if osdProps.metadataPVC.ClaimName != "" {
-		devices = append(devices, encryptionBlockDestinationCopy(devicesBasePath, bluestoreMetadataName))
+		args = append(args, encryptionBlockDestinationCopy(devicesBasePath, bluestoreMetadataName))
 	}
 	if osdProps.walPVC.ClaimName != "" {
-		devices = append(devices, encryptionBlockDestinationCopy(devicesBasePath, bluestoreWalName))
+		args = append(args, encryptionBlockDestinationCopy(devicesBasePath, bluestoreWalName))
 	}
 
+	podTemplateSpec, err := c.keyRotationPodTemplateSpec(osdProps, restart,
+		[]v1.Volume{hostPathVolume}, []v1.VolumeMount{{Name: "bridge", MountPath: devicesBasePath}}, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// apply storageClassDeviceSets.Placement
+	osdProps.placement.ApplyToPodSpec(&podTemplateSpec.Spec)
+	applyKeyRotationPlacement(&podTemplateSpec.Spec, c.getOSDLabels(osd, osdProps.crushHostname, osdProps.portable))
+
+	k8sutil.RemoveDuplicateEnvVars(&podTemplateSpec.Spec)
+	return podTemplateSpec, nil
+}
+
+// getHostKeyRotationPodTemplateSpec returns the pod template spec for the key rotation job of a
+// host-based OSD. The key of these OSDs is kept by ceph-volume in the mon config-key store, so the
+// job connects to the mons as the key rotation user. The job runs on the node of the OSD.
+func (c *Cluster) getHostKeyRotationPodTemplateSpec(osdProps osdProperties, osd OSDInfo, restart v1.RestartPolicy) (*v1.PodTemplateSpec, error) {
+	args := []string{"--osd-id", strconv.Itoa(osd.ID), DmCryptConfigKey(osd.UUID), osd.BlockPath}
+	if osd.MetadataPath != "" {
+		args = append(args, osd.MetadataPath)
+	}
+	if osd.WalPath != "" {
+		args = append(args, osd.WalPath)
+	}
+
+	secretVolume, secretVolumeMount := keyRotationSecretVolume()
+	podTemplateSpec, err := c.keyRotationPodTemplateSpec(osdProps, restart,
+		[]v1.Volume{secretVolume}, []v1.VolumeMount{secretVolumeMount}, args)
+	if err != nil {
+		return nil, err
+	}
+	for i := range podTemplateSpec.Spec.Containers[0].Env {
+		env := &podTemplateSpec.Spec.Containers[0].Env[i]
+		if env.Name == opmon.CephUsernameEnvVar().Name {
+			env.ValueFrom = &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: keyRotationSecretName},
+				Key:                  controller.CephUsernameKey,
+			}}
+		}
+	}
+
+	c.spec.Placement[cephv1.KeyOSD].ApplyToPodSpec(&podTemplateSpec.Spec)
+	podTemplateSpec.Spec.NodeSelector = map[string]string{k8sutil.LabelHostname(): osd.NodeName}
+
+	k8sutil.RemoveDuplicateEnvVars(&podTemplateSpec.Spec)
+	return podTemplateSpec, nil
+}
+
+// keyRotationSecretVolume returns the volume and mount of the key of the key rotation user, mounted
+// where the rotate-key command reads the ceph secret
+func keyRotationSecretVolume() (v1.Volume, v1.VolumeMount) {
+	volume := v1.Volume{
+		Name: keyRotationSecretVolumeName,
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{
+				SecretName: keyRotationSecretName,
+				Items:      []v1.KeyToPath{{Key: controller.CephUserSecretKey, Path: opmon.CephSecretFilename}},
+			},
+		},
+	}
+	volumeMount := v1.VolumeMount{Name: keyRotationSecretVolumeName, MountPath: opmon.CephSecretMountPath, ReadOnly: true}
+	return volume, volumeMount
+}
+
+// reconcileKeyRotationSecret creates the cephx user of the key rotation jobs of the host-based OSDs
+// and stores its key in the secret mounted by the jobs
+func (c *Cluster) reconcileKeyRotationSecret() error {
+	s := keyring.GetSecretStore(c.context, c.clusterInfo, c.clusterInfo.OwnerInfo)
+	key, err := s.GenerateKey(keyRotationCephUser, cephv1.CephxKeyTypeUndefined, keyRotationCephCaps)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the cephx user %q", keyRotationCephUser)
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      keyRotationSecretName,
+			Namespace: c.clusterInfo.Namespace,
+		},
+		StringData: map[string]string{
+			controller.CephUsernameKey:   keyRotationCephUser,
+			controller.CephUserSecretKey: key,
+		},
+		Type: k8sutil.RookType,
+	}
+	if err := c.clusterInfo.OwnerInfo.SetControllerReference(secret); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to secret %q", secret.Name)
+	}
+	if _, err := s.CreateSecret(secret); err != nil {
+		return errors.Wrapf(err, "failed to store the key of the cephx user %q", keyRotationCephUser)
+	}
+	return nil
+}
+
+// deleteKeyRotationSecret deletes the cephx user of the key rotation jobs and its secret
+func (c *Cluster) deleteKeyRotationSecret() error {
+	_, err := c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Get(c.clusterInfo.Context, keyRotationSecretName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get secret %q", keyRotationSecretName)
+	}
+	if err := cephclient.AuthDelete(c.context, c.clusterInfo, keyRotationCephUser); err != nil {
+		return err
+	}
+	err = c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Delete(c.clusterInfo.Context, keyRotationSecretName, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete secret %q", keyRotationSecretName)
+	}
+	return nil
+}
+
+// keyRotationPodTemplateSpec returns the pod template spec running the rotate-key command with the
+// given arguments, with access to the devices of the host in addition to the given volumes.
+func (c *Cluster) keyRotationPodTemplateSpec(osdProps osdProperties, restart v1.RestartPolicy, extraVolumes []v1.Volume, extraVolumeMounts []v1.VolumeMount, rotateKeyArgs []string) (*v1.PodTemplateSpec, error) {
+	// create a volume on /dev so the pod can access devices on the host
+	devVolume := v1.Volume{Name: "devices", VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/dev"}}}
+	udevVolume := v1.Volume{Name: "udev", VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/run/udev"}}}
+	volumes := append([]v1.Volume{
+		udevVolume,
+		devVolume,
+	}, extraVolumes...)
+	volumeMounts := append([]v1.VolumeMount{
+		{Name: "devices", MountPath: "/dev"},
+		{Name: "udev", MountPath: "/run/udev"},
+	}, extraVolumeMounts...)
+
 	if c.spec.Security.KeyManagementService.IsVaultKMS() {
 		volumeTLS, volumeMountTLS := kms.VaultVolumeAndMount(c.spec.Security.KeyManagementService.ConnectionDetails, "")
 		volumes = append(volumes, volumeTLS)
 		volumeMounts = append(volumeMounts, volumeMountTLS)
 	}
 
-	keyRotationContainer, err := c.getKeyRotationContainer(osdProps, volumeMounts, devices)
+	keyRotationContainer, err := c.getKeyRotationContainer(osdProps, volumeMounts, rotateKeyArgs)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to generate key rotation container")
 	}
@@ -189,23 +324,35 @@ func (c *Cluster) getKeyRotationPodTemplateSpec(osdProps osdProperties, osd OSDI
 	cephv1.GetKeyRotationLabels(c.spec.Labels).ApplyToObjectMeta(&podTemplateSpec.ObjectMeta)
 
 	c.applyAllPlacementIfNeeded(&podTemplateSpec.Spec)
-	// apply storageClassDeviceSets.Placement
-	osdProps.placement.ApplyToPodSpec(&podTemplateSpec.Spec)
-	applyKeyRotationPlacement(&podTemplateSpec.Spec, c.getOSDLabels(osd, osdProps.crushHostname, osdProps.portable))
 
 	// cryptsetup synchronizes with udev on host through semaphore
 	podTemplateSpec.Spec.HostIPC = true
 
-	k8sutil.RemoveDuplicateEnvVars(&podTemplateSpec.Spec)
 	return &podTemplateSpec, nil
 }
 
-// makeKeyRotationCronJob creates a key rotation cron job for the given OSD.
+// makeKeyRotationCronJob creates a key rotation cron job for the given OSD, on a PVC if pvcName is
+// set or on host devices otherwise.
 func (c *Cluster) makeKeyRotationCronJob(pvcName string, osd OSDInfo, osdProps osdProperties) (*batch.CronJob, error) {
-	podSpec, err := c.getKeyRotationPodTemplateSpec(osdProps, osd, v1.RestartPolicyOnFailure)
+	var podSpec *v1.PodTemplateSpec
+	var err error
+	if pvcName != "" {
+		podSpec, err = c.getKeyRotationPodTemplateSpec(osdProps, osd, v1.RestartPolicyOnFailure)
+	} else {
+		podSpec, err = c.getHostKeyRotationPodTemplateSpec(osdProps, osd, v1.RestartPolicyOnFailure)
+	}
 	if err != nil {
 		return nil, err
 	}
+	labels := map[string]string{OsdIdLabelKey: strconv.Itoa(osd.ID)}
+	if pvcName != "" {
+		labels[OSDOverPVCLabelKey] = pvcName
+	} else {
+		labels[k8sutil.LabelHostname()] = osd.NodeName
+	}
+	for key, value := range podSpec.Labels {
+		labels[key] = value
+	}
 	c.applyResourcesToAllContainers(&podSpec.Spec, cephv1.GetOSDResources(c.spec.Resources, osd.DeviceClass))
 	schedule := c.spec.Security.KeyRotation.Schedule
 	if schedule == "" {
@@ -216,7 +363,7 @@ func (c *Cluster) makeKeyRotationCronJob(pvcName string, osd OSDInfo, osdProps o
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        keyRotationCronJobName(osd.ID),
 			Namespace:   c.clusterInfo.Namespace,
-			Labels:      podSpec.Labels,
+			Labels:      labels,
 			Annotations: podSpec.Annotations,
 		},
 		Spec: batch.CronJobSpec{
@@ -236,6 +383,9 @@ func (c *Cluster) makeKeyRotationCronJob(pvcName string, osd OSDInfo, osdProps o
 // reconcileKeyRotationCronJob reconciles the key rotation cron jobs for the OSDs.
 func (c *Cluster) reconcileKeyRotationCronJob() error {
 	if !c.spec.Security.KeyRotation.Enabled {
+		if err := c.deleteKeyRotationSecret(); err != nil {
+			log.NamespacedWarning(c.clusterInfo.Namespace, logger, "failed to delete the cephx user of the key rotation jobs. %v", err)
+		}
 		listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, keyRotationCronJobAppName)}
 		err := c.context.Clientset.BatchV1().
 			CronJobs(c.clusterInfo.Namespace).
@@ -253,31 +403,50 @@ func (c *Cluster) reconcileKeyRotationCronJob() error {
 		return nil
 	}
 
-	// Get the list of OSDs backed by pvc.
-	osdListOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s,%s", k8sutil.AppAttr, AppName, OSDOverPVCLabelKey)}
-	deployments, err := c.context.Clientset.AppsV1().Deployments(c.clusterInfo.Namespace).List(c.clusterInfo.Context, osdListOpts)
+	deployments, err := c.getOSDDeployments()
 	if err != nil {
 		return errors.Wrap(err, "failed to query existing OSD deployments")
 	}
 
 	log.NamespacedDebug(c.clusterInfo.Namespace, logger, "found %d osd deployments", len(deployments.Items))
+	secretReconciled := false
 	for i := range deployments.Items {
 		osdDep := deployments.Items[i]
 		osd, err := c.getOSDInfo(&osdDep)
 		if err != nil {
 			return errors.Wrapf(err, "failed to get osd info for osd %q", osdDep.Name)
 		}
-		pvcName := osdDep.Labels[OSDOverPVCLabelKey]
-		if pvcName == "" {
-			return errors.Errorf("pvc name label %q for osd %q is empty",
-				OSDOverPVCLabelKey, osdDep.Name)
-		}
-		osdProps, err := c.getOSDPropsForPVC(pvcName)
-		if err != nil {
-			return errors.Wrapf(err, "failed to generate config for osd %q", osdDep.Name)
-		}
-		if !osdProps.encrypted {
-			continue
+		var osdProps osdProperties
+		pvcName, isPVC := osdDep.Labels[OSDOverPVCLabelKey]
+		if isPVC {
+			if pvcName == "" {
+				return errors.Errorf("pvc name label %q for osd %q is empty",
+					OSDOverPVCLabelKey, osdDep.Name)
+			}
+			osdProps, err = c.getOSDPropsForPVC(pvcName)
+			if err != nil {
+				return errors.Wrapf(err, "failed to generate config for osd %q", osdDep.Name)
+			}
+			if !osdProps.encrypted {
+				continue
+			}
+		} else {
+			// the key of the host-based OSDs is in the mon config-key store, not in the KMS
+			if !osd.Encrypted || osd.NodeName == "" {
+				continue
+			}
+			osdProps, err = c.getOSDPropsForNode(osd.NodeName, osd.DeviceClass)
+			if err != nil {
+				// the node may have been removed from the storage spec while its OSDs still run
+				log.NamespacedWarning(c.clusterInfo.Namespace, logger, "not rotating the key of osd %d. %v", osd.ID, err)
+				continue
+			}
+			if !secretReconciled {
+				if err := c.reconcileKeyRotationSecret(); err != nil {
+					return err
+				}
+				secretReconciled = true
+			}
 		}
 
 		log.NamespacedInfo(c.clusterInfo.Namespace, logger, "starting OSD key rotation cron job for osd %d", osd.ID)
@@ -301,3 +470,74 @@ func (c *Cluster) reconcileKeyRotationCronJob() error {
 
 	return nil
 }
+
+// keyRotationResult returns the result of the most recent run of a key rotation cron job
+func keyRotationResult(cj *batch.CronJob) cephv1.OSDKeyRotationResult {
+	switch {
+	case len(cj.Status.Active) > 0:
+		return cephv1.OSDKeyRotationRunning
+	case cj.Status.LastScheduleTime == nil:
+		return cephv1.OSDKeyRotationPending
+	case cj.Status.LastSuccessfulTime != nil && !cj.Status.LastSuccessfulTime.Before(cj.Status.LastScheduleTime):
+		return cephv1.OSDKeyRotationSucceeded
+	default:
+		return cephv1.OSDKeyRotationFailed
+	}
+}
+
+// updateKeyRotationStatus records the result of the key rotation cron job of each OSD in the
+// CephCluster status
+func (m *OSDHealthMonitor) updateKeyRotationStatus() error {
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, keyRotationCronJobAppName)}
+	cronJobs, err := m.context.Clientset.BatchV1().CronJobs(m.clusterInfo.Namespace).List(m.clusterInfo.Context, listOpts)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the key rotation cron jobs")
+	}
+
+	rotations := []cephv1.OSDKeyRotationStatus{}
+	for i := range cronJobs.Items {
+		cj := &cronJobs.Items[i]
+		osdID, err := strconv.Atoi(cj.Labels[OsdIdLabelKey])
+		if err != nil {
+			log.NamespacedDebug(m.clusterInfo.Namespace, logger, "skipping key rotation cron job %q without an osd id label", cj.Name)
+			continue
+		}
+		host := cj.Labels[k8sutil.LabelHostname()]
+		if pvcName, ok := cj.Labels[OSDOverPVCLabelKey]; ok {
+			host = pvcName
+		}
+		rotations = append(rotations, cephv1.OSDKeyRotationStatus{
+			ID:                 osdID,
+			Host:               host,
+			LastScheduleTime:   cj.Status.LastScheduleTime,
+			LastSuccessfulTime: cj.Status.LastSuccessfulTime,
+			Result:             keyRotationResult(cj),
+		})
+	}
+	sort.Slice(rotations, func(i, j int) bool { return rotations[i].ID < rotations[j].ID })
+	if len(rotations) == 0 {
+		rotations = nil
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cephCluster := &cephv1.CephCluster{}
+		if err := m.context.Client.Get(m.clusterInfo.Context, m.clusterInfo.NamespacedName(), cephCluster); err != nil {
+			return errors.Wrapf(err, "failed to get cluster %v", m.clusterInfo.NamespacedName())
+		}
+		if cephCluster.Status.CephStorage == nil {
+			if rotations == nil {
+				return nil
+			}
+			cephCluster.Status.CephStorage = &cephv1.CephStorage{}
+		}
+		if reflect.DeepEqual(cephCluster.Status.CephStorage.OSD.KeyRotations, rotations) {
+			return nil
+		}
+		cephCluster.Status.CephStorage.OSD.KeyRotations = rotations
+		return reporting.UpdateStatus(m.context.Client, cephCluster)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update the key rotation status")
+	}
+	return nil
+}
@@ -17,12 +17,25 @@ limitations under the License.
 package osd
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batch "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func Test_keyRotationCronJobName(t *testing.T) {
@@ -114,3 +127,148 @@ func Test_applyKeyRotationPlacement(t *testing.T) {
 		})
 	}
 }
+
+func TestMakeHostKeyRotationCronJob(t *testing.T) {
+	c := newTestReplaceClusterWithSpec(fake.NewClientset(), cephv1.ClusterSpec{
+		Security: cephv1.ClusterSecuritySpec{KeyRotation: cephv1.KeyRotationSpec{Enabled: true, Schedule: "@daily"}},
+	})
+	osd := OSDInfo{
+		ID:           3,
+		UUID:         "0f8bb9b6-0d5d-4a3e-9d4b-cd6b2cf0b3f1",
+		BlockPath:    "/dev/ceph-block/osd-block",
+		MetadataPath: "/dev/ceph-db/osd-db",
+		NodeName:     "node-a",
+		Encrypted:    true,
+	}
+	osdProps := osdProperties{crushHostname: "node-a"}
+
+	cj, err := c.makeKeyRotationCronJob("", osd, osdProps)
+	require.NoError(t, err)
+	assert.Equal(t, "rook-ceph-osd-key-rotation-3", cj.Name)
+	assert.Equal(t, "@daily", cj.Spec.Schedule)
+	assert.Equal(t, "3", cj.Labels[OsdIdLabelKey])
+	assert.Equal(t, "node-a", cj.Labels[k8sutil.LabelHostname()])
+	assert.NotContains(t, cj.Labels, OSDOverPVCLabelKey)
+
+	spec := cj.Spec.JobTemplate.Spec.Template.Spec
+	// pinned to the node of the OSD
+	assert.Equal(t, map[string]string{k8sutil.LabelHostname(): "node-a"}, spec.NodeSelector)
+	assert.True(t, spec.HostIPC)
+	require.Len(t, spec.Containers, 1)
+	assert.Equal(t, []string{
+		"key-management", "rotate-key", "--osd-id", "3",
+		"dm-crypt/osd/0f8bb9b6-0d5d-4a3e-9d4b-cd6b2cf0b3f1/luks",
+		"/dev/ceph-block/osd-block", "/dev/ceph-db/osd-db",
+	}, spec.Containers[0].Args)
+
+	// the job connects to the mons as the key rotation user, not with the admin secret
+	volumes := []string{}
+	for _, volume := range spec.Volumes {
+		volumes = append(volumes, volume.Name)
+		if volume.Secret != nil {
+			assert.Equal(t, "rook-ceph-osd-key-rotation-cephx", volume.Secret.SecretName)
+		}
+	}
+	assert.ElementsMatch(t, []string{"udev", "devices", "osd-key-rotation-secret"}, volumes)
+	envVars := map[string]v1.EnvVar{}
+	for _, env := range spec.Containers[0].Env {
+		envVars[env.Name] = env
+	}
+	for _, name := range []string{"ROOK_MON_ENDPOINTS", "ROOK_CEPH_USERNAME", "ROOK_FSID"} {
+		assert.Contains(t, envVars, name)
+	}
+	assert.Equal(t, "rook-ceph-osd-key-rotation-cephx", envVars["ROOK_CEPH_USERNAME"].ValueFrom.SecretKeyRef.Name)
+}
+
+func TestReconcileKeyRotationSecret(t *testing.T) {
+	ctx := context.TODO()
+	clientset := fake.NewClientset()
+	c := newTestReplaceClusterWithSpec(clientset, cephv1.ClusterSpec{})
+	var authArgs [][]string
+	c.context.Executor = &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "auth" {
+				authArgs = append(authArgs, args)
+				if args[1] == "get-or-create-key" {
+					return `{"key":"AQBkeyrotation=="}`, nil
+				}
+			}
+			return "", nil
+		},
+	}
+
+	require.NoError(t, c.reconcileKeyRotationSecret())
+	require.Len(t, authArgs, 1)
+	assert.Equal(t, []string{"auth", "get-or-create-key", "client.osd-key-rotation", "mon",
+		`allow command "config-key get" with key prefix dm-crypt/osd/, allow command "config-key set" with key prefix dm-crypt/osd/`}, authArgs[0][:5])
+	secret, err := clientset.CoreV1().Secrets("rook-ceph").Get(ctx, "rook-ceph-osd-key-rotation-cephx", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "client.osd-key-rotation", secret.StringData["ceph-username"])
+	assert.Equal(t, "AQBkeyrotation==", secret.StringData["ceph-secret"])
+
+	// the user and its secret are deleted when the key rotation is disabled
+	require.NoError(t, c.reconcileKeyRotationCronJob())
+	assert.Equal(t, []string{"auth", "del", "client.osd-key-rotation"}, authArgs[1][:3])
+	_, err = clientset.CoreV1().Secrets("rook-ceph").Get(ctx, "rook-ceph-osd-key-rotation-cephx", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+}
+
+func TestUpdateKeyRotationStatus(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	scheduled := metav1.NewTime(time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC))
+	succeeded := metav1.NewTime(scheduled.Add(time.Minute))
+	earlier := metav1.NewTime(scheduled.Add(-24 * time.Hour))
+	cronJob := func(osdID, host, pvc string, status batch.CronJobStatus) *batch.CronJob {
+		labels := map[string]string{k8sutil.AppAttr: keyRotationCronJobAppName, OsdIdLabelKey: osdID}
+		if host != "" {
+			labels[k8sutil.LabelHostname()] = host
+		}
+		if pvc != "" {
+			labels[OSDOverPVCLabelKey] = pvc
+		}
+		return &batch.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-key-rotation-" + osdID, Namespace: namespace, Labels: labels},
+			Status:     status,
+		}
+	}
+	clientset := fake.NewClientset(
+		cronJob("2", "node-b", "", batch.CronJobStatus{LastScheduleTime: &scheduled, LastSuccessfulTime: &earlier}),
+		cronJob("0", "node-a", "", batch.CronJobStatus{LastScheduleTime: &scheduled, LastSuccessfulTime: &succeeded}),
+		cronJob("1", "", "set1-data-0", batch.CronJobStatus{}),
+		cronJob("3", "node-b", "", batch.CronJobStatus{Active: []v1.ObjectReference{{Name: "job"}}, LastScheduleTime: &scheduled}),
+	)
+
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: namespace}}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+	cl := clientfake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(cephCluster).WithStatusSubresource(cephCluster).Build()
+
+	clusterInfo := cephclient.AdminTestClusterInfo(namespace)
+	clusterInfo.SetName("test")
+	clusterInfo.Context = ctx
+	m := NewOSDHealthMonitor(&clusterd.Context{Clientset: clientset, Client: cl}, clusterInfo, false, cephv1.CephClusterHealthCheckSpec{}, cephv1.ClusterSpec{}, "rook/ceph:test")
+
+	require.NoError(t, m.updateKeyRotationStatus())
+	cluster := &cephv1.CephCluster{}
+	require.NoError(t, cl.Get(ctx, clusterInfo.NamespacedName(), cluster))
+	require.NotNil(t, cluster.Status.CephStorage)
+	rotations := cluster.Status.CephStorage.OSD.KeyRotations
+	require.Len(t, rotations, 4)
+	assert.Equal(t, 0, rotations[0].ID)
+	assert.Equal(t, "node-a", rotations[0].Host)
+	assert.True(t, scheduled.Equal(rotations[0].LastScheduleTime))
+	assert.True(t, succeeded.Equal(rotations[0].LastSuccessfulTime))
+	assert.Equal(t, cephv1.OSDKeyRotationSucceeded, rotations[0].Result)
+	assert.Equal(t, cephv1.OSDKeyRotationStatus{ID: 1, Host: "set1-data-0", Result: cephv1.OSDKeyRotationPending}, rotations[1])
+	assert.Equal(t, cephv1.OSDKeyRotationFailed, rotations[2].Result)
+	assert.Equal(t, cephv1.OSDKeyRotationRunning, rotations[3].Result)
+
+	// the status is cleared when the cron jobs are deleted
+	for _, osdID := range []int{0, 1, 2, 3} {
+		require.NoError(t, clientset.BatchV1().CronJobs(namespace).Delete(ctx, keyRotationCronJobName(osdID), metav1.DeleteOptions{}))
+	}
+	require.NoError(t, m.updateKeyRotationStatus())
+	require.NoError(t, cl.Get(ctx, clusterInfo.NamespacedName(), cluster))
+	assert.Empty(t, cluster.Status.CephStorage.OSD.KeyRotations)
+}
@@ -20,11 +20,13 @@ package osd
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	osdconfig "github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/util/log"
 	appsv1 "k8s.io/api/apps/v1"
@@ -42,17 +44,25 @@ const (
 	OSDMigrationConfigName = "osd-migration-config"
 	// OSDIdKey is the key used to store the OSD ID inside the `osd-migration-config` configMap
 	OSDIdKey = "osdID"
+
+	// the reasons to migrate an OSD, reported in the migration status
+	migrationReasonEncryption = "encryption"
+	migrationReasonStoreType  = "storeType"
+	migrationReasonLVMToRaw   = "lvmToRaw"
 )
 
 // migrationConfig represents the OSDs that need migration
 type migrationConfig struct {
 	// osds that require migration (map key is the OSD id)
 	osds map[int]*OSDInfo
+	// reasons why the osds require migration (map key is the OSD id)
+	reasons map[int]string
 }
 
 func (c *Cluster) newMigrationConfig() (*migrationConfig, error) {
 	mc := migrationConfig{
-		osds: map[int]*OSDInfo{},
+		osds:    map[int]*OSDInfo{},
+		reasons: map[int]string{},
 	}
 
 	osdDeployments, err := c.getOSDDeployments()
@@ -72,9 +82,38 @@ func (c *Cluster) newMigrationConfig() (*migrationConfig, error) {
 		return nil, errors.Wrapf(err, "failed to get OSDs that require migration due to change in OSD Store type setting")
 	}
 
+	// get OSDs that require migration from lvm mode to raw mode
+	err = mc.migrateForLVMToRaw(c, osdDeployments)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get OSDs that require migration from lvm mode to raw mode")
+	}
+
 	return &mc, nil
 }
 
+// addOSD adds an OSD that requires migration, unless it already requires migration for another reason
+func (m *migrationConfig) addOSD(osdInfo *OSDInfo, reason string) {
+	if _, exists := m.osds[osdInfo.ID]; !exists {
+		if m.reasons == nil {
+			m.reasons = map[int]string{}
+		}
+		m.osds[osdInfo.ID] = osdInfo
+		m.reasons[osdInfo.ID] = reason
+	}
+}
+
+// pendingByReason returns the number of OSDs that require migration for each reason
+func (m *migrationConfig) pendingByReason() map[string]int {
+	if len(m.osds) == 0 {
+		return nil
+	}
+	pending := map[string]int{}
+	for osdID := range m.osds {
+		pending[m.reasons[osdID]]++
+	}
+	return pending
+}
+
 // migrateForEncryption gets all the OSDs that require migration due to change in the cephCluster encryption setting
 func (m *migrationConfig) migrateForEncryption(c *Cluster, osdDeployments *appsv1.DeploymentList) error {
 	deviceSetMap := map[string]cephv1.StorageClassDeviceSet{}
@@ -96,9 +135,7 @@ func (m *migrationConfig) migrateForEncryption(c *Cluster, osdDeployments *appsv
 				return errors.Wrapf(err, "failed to details about the OSD %q", osdDeployments.Items[i].Name)
 			}
 			log.NamespacedInfo(c.clusterInfo.Namespace, logger, "migration is required for OSD.%d due to change in encryption settings from %t to %t in storageClassDeviceSet %q", osdInfo.ID, actualEncryptedSetting, requestedEncryptionSetting, osdDeviceSetName)
-			if _, exists := m.osds[osdInfo.ID]; !exists {
-				m.osds[osdInfo.ID] = &osdInfo
-			}
+			m.addOSD(&osdInfo, migrationReasonEncryption)
 		}
 	}
 	return nil
@@ -115,15 +152,101 @@ func (m *migrationConfig) migrateForOSDStore(c *Cluster, osdDeployments *appsv1.
 					return errors.Wrapf(err, "failed to details about the OSD %q", osdDeployments.Items[i].Name)
 				}
 				log.NamespacedInfo(c.clusterInfo.Namespace, logger, "migration is required for OSD.%d to update storeType from %q to %q", osdInfo.ID, osdStore, desiredOSDStore)
-				if _, exists := m.osds[osdInfo.ID]; !exists {
-					m.osds[osdInfo.ID] = &osdInfo
-				}
+				m.addOSD(&osdInfo, migrationReasonStoreType)
 			}
 		}
 	}
 	return nil
 }
 
+// migrateForLVMToRaw gets all the OSDs created in lvm mode on host devices that can be migrated to
+// raw mode, if requested in the cephCluster migration settings
+func (m *migrationConfig) migrateForLVMToRaw(c *Cluster, osdDeployments *appsv1.DeploymentList) error {
+	if !c.spec.Storage.Migration.LVMToRaw {
+		return nil
+	}
+
+	lvmOSDs := []OSDInfo{}
+	// number of OSDs in each volume group of each node
+	vgOSDs := map[string]int{}
+	for i := range osdDeployments.Items {
+		if _, ok := osdDeployments.Items[i].Labels[OSDOverPVCLabelKey]; ok {
+			continue
+		}
+		osdInfo, err := c.getOSDInfo(&osdDeployments.Items[i])
+		if err != nil {
+			return errors.Wrapf(err, "failed to details about the OSD %q", osdDeployments.Items[i].Name)
+		}
+		if osdInfo.CVMode != "lvm" {
+			continue
+		}
+		lvmOSDs = append(lvmOSDs, osdInfo)
+		if vgName := cephVolumeVGName(osdInfo.BlockPath); vgName != "" {
+			vgOSDs[osdInfo.NodeName+"/"+vgName]++
+		}
+	}
+
+	for i := range lvmOSDs {
+		osdInfo := lvmOSDs[i]
+		if reason := c.lvmToRawUnsupportedReason(osdInfo, vgOSDs); reason != "" {
+			log.NamespacedDebug(c.clusterInfo.Namespace, logger, "not migrating OSD.%d from lvm mode to raw mode since %s", osdInfo.ID, reason)
+			continue
+		}
+		log.NamespacedInfo(c.clusterInfo.Namespace, logger, "migration is required for OSD.%d from lvm mode to raw mode", osdInfo.ID)
+		m.addOSD(&osdInfo, migrationReasonLVMToRaw)
+	}
+	return nil
+}
+
+// lvmToRawUnsupportedReason returns why the raw mode does not support an OSD created in lvm mode,
+// or an empty string if the OSD can be migrated to raw mode. The OSD would be prepared again in lvm
+// mode, and migrated on every reconcile, if the storage config of its node requires the lvm mode.
+func (c *Cluster) lvmToRawUnsupportedReason(osdInfo OSDInfo, vgOSDs map[string]int) string {
+	vgName := cephVolumeVGName(osdInfo.BlockPath)
+	if vgName == "" {
+		return fmt.Sprintf("its logical volume %q was not created by ceph-volume", osdInfo.BlockPath)
+	}
+	if vgOSDs[osdInfo.NodeName+"/"+vgName] > 1 {
+		return "its device holds several OSDs"
+	}
+	if osdInfo.Encrypted {
+		return "it is encrypted"
+	}
+	if osdInfo.MetadataPath != "" {
+		return "it has a metadata device"
+	}
+
+	osdProps, err := c.getOSDPropsForNode(osdInfo.NodeName, osdInfo.DeviceClass)
+	if err != nil {
+		return fmt.Sprintf("its node is not in the storage spec. %v", err)
+	}
+	configs := []osdconfig.StoreConfig{osdProps.storeConfig}
+	for _, device := range osdProps.devices {
+		configs = append(configs, osdconfig.ToStoreConfig(device.Config))
+	}
+	for _, config := range configs {
+		switch {
+		case config.EncryptedDevice:
+			return fmt.Sprintf("the devices of node %q are encrypted", osdInfo.NodeName)
+		case config.OSDsPerDevice > 1:
+			return fmt.Sprintf("the devices of node %q hold several OSDs", osdInfo.NodeName)
+		case config.MetadataDevice != "" || len(config.MetadataDevices) > 0:
+			return fmt.Sprintf("the devices of node %q have a metadata device", osdInfo.NodeName)
+		}
+	}
+	return ""
+}
+
+// cephVolumeVGName returns the volume group of a logical volume created by ceph-volume in lvm mode,
+// like "/dev/ceph-<uuid>/osd-block-<uuid>", or an empty string for another logical volume
+func cephVolumeVGName(lvPath string) string {
+	vgName, lvName, ok := strings.Cut(strings.TrimPrefix(lvPath, "/dev/"), "/")
+	if !ok || !strings.HasPrefix(vgName, "ceph-") || !strings.HasPrefix(lvName, "osd-block-") {
+		return ""
+	}
+	return vgName
+}
+
 // getOSDToMigrate returns the next OSD to migrate from the list of OSDs that are pending migration.
 func (m *migrationConfig) getOSDToMigrate() *OSDInfo {
 	osdInfo := &OSDInfo{}
@@ -177,6 +177,76 @@ func TestMigrationForOSDStore(t *testing.T) {
 	})
 }
 
+func TestMigrationForLVMToRaw(t *testing.T) {
+	clientset := fake.NewClientset()
+	ctx := &clusterd.Context{
+		Clientset: clientset,
+	}
+	clusterInfo := &cephclient.ClusterInfo{
+		Namespace: "rook-ceph",
+		Context:   context.TODO(),
+	}
+	clusterInfo.SetName("mycluster")
+	clusterInfo.OwnerInfo = cephclient.NewMinimumOwnerInfo(t)
+
+	c := New(ctx, clusterInfo, cephv1.ClusterSpec{}, "rook/rook:master")
+
+	createOSD := func(nodeName string, osdID int, cvMode, blockPath string) {
+		d := getDummyDeploymentOnNode(clientset, c, nodeName, osdID)
+		osd := &OSDInfo{ID: osdID, UUID: "some-uuid", BlockPath: blockPath, CVMode: cvMode, Store: "bluestore"}
+		d, err := deploymentOnNode(c, osd, nodeName, c.newProvisionConfig())
+		assert.NoError(t, err)
+		createDeploymentOrPanic(clientset, d)
+	}
+	// osd.0 can be migrated
+	createOSD("node1", 0, "lvm", "/dev/ceph-vg0/osd-block-0")
+	// osd.1 is already in raw mode
+	createOSD("node1", 1, "raw", "/dev/sdc")
+	// osd.2 is on a logical volume created by the user
+	createOSD("node1", 2, "lvm", "/dev/myvg/mylv")
+	// osd.3 and osd.4 share their device
+	createOSD("node1", 3, "lvm", "/dev/ceph-vg3/osd-block-3")
+	createOSD("node1", 4, "lvm", "/dev/ceph-vg3/osd-block-4")
+	// osd.5 is on a node with a metadata device
+	createOSD("node2", 5, "lvm", "/dev/ceph-vg5/osd-block-5")
+	for i := range c.ValidStorage.Nodes {
+		if c.ValidStorage.Nodes[i].Name == "node2" {
+			c.ValidStorage.Nodes[i].Config = map[string]string{"metadataDevice": "nvme0n1"}
+		}
+	}
+
+	deployments, err := c.getOSDDeployments()
+	assert.NoError(t, err)
+
+	t.Run("not requested", func(t *testing.T) {
+		mc := migrationConfig{osds: map[int]*OSDInfo{}}
+		assert.NoError(t, mc.migrateForLVMToRaw(c, deployments))
+		assert.Empty(t, mc.osds)
+	})
+
+	t.Run("osd.0 needs migration", func(t *testing.T) {
+		c.spec.Storage.Migration.LVMToRaw = true
+		mc := migrationConfig{osds: map[int]*OSDInfo{}}
+		assert.NoError(t, mc.migrateForLVMToRaw(c, deployments))
+		assert.Equal(t, 1, len(mc.osds))
+		assert.Equal(t, 0, mc.osds[0].ID)
+		assert.Equal(t, map[string]int{migrationReasonLVMToRaw: 1}, mc.pendingByReason())
+	})
+
+	t.Run("another reason is reported first", func(t *testing.T) {
+		mc := migrationConfig{osds: map[int]*OSDInfo{}}
+		mc.addOSD(&OSDInfo{ID: 0}, migrationReasonStoreType)
+		assert.NoError(t, mc.migrateForLVMToRaw(c, deployments))
+		assert.Equal(t, map[string]int{migrationReasonStoreType: 1}, mc.pendingByReason())
+	})
+}
+
+func TestCephVolumeVGName(t *testing.T) {
+	assert.Equal(t, "ceph-0a1b", cephVolumeVGName("/dev/ceph-0a1b/osd-block-2c3d"))
+	assert.Equal(t, "", cephVolumeVGName("/dev/myvg/mylv"))
+	assert.Equal(t, "", cephVolumeVGName("/dev/sdb"))
+}
+
 func TestStartOSDMigrationSkipsFencedOSDs(t *testing.T) {
 	namespace := "rook-ceph"
 	// PGs report as clean so migration is not blocked on PG health.
@@ -101,6 +101,8 @@ type Cluster struct {
 	migrateOSD     *OSDInfo
 	deprecatedOSDs map[string][]int
 	nodeConfigmaps map[string]struct{}
+	requeueAfter   time.Duration
+	crushHierarchy *cephv1.CrushHierarchyStatus
 }
 
 // New creates an instance of the OSD manager
@@ -121,6 +123,8 @@ type OSDInfo struct {
 	UUID           string `json:"uuid"`
 	DevicePartUUID string `json:"device-part-uuid"`
 	DeviceClass    string `json:"device-class"`
+	// MetadataDevice is the device holding the DB of an OSD created by ceph-volume in lvm mode
+	MetadataDevice string `json:"metadata-device,omitempty"`
 	// BlockPath is the logical Volume path for an OSD created by Ceph-volume with format '/dev/<Volume Group>/<Logical Volume>' or simply /dev/vdb if block mode is used
 	BlockPath     string `json:"lv-path"`
 	MetadataPath  string `json:"metadata-path"`
@@ -208,6 +212,9 @@ func (c *Cluster) validateOSDSettings() error {
 		}
 		deviceSetNames[deviceSet.Name] = true
 	}
+	if err := validateCrushHierarchy(c.spec.Storage.CrushHierarchy); err != nil {
+		return errors.Wrap(err, "invalid crush hierarchy")
+	}
 	return nil
 }
 
@@ -315,8 +322,14 @@ func (c *Cluster) Start() error {
 		}
 	}
 
+	canary, err := c.startCanaryUpgrade(updateQueue)
+	if err != nil {
+		return errors.Wrapf(err, "failed to start the canary upgrade of the OSDs")
+	}
+
 	log.NamespacedDebug(c.clusterInfo.Namespace, logger, "%d of %d OSD Deployments need update", updateQueue.Len(), deployments.Len())
 	updateConfig := c.newUpdateConfig(config, updateQueue, deployments, osdsToSkipReconcile)
+	updateConfig.canary = canary
 
 	// prepare for creating new OSDs
 	statusConfigMaps := sets.New[string]()
@@ -366,6 +379,11 @@ func (c *Cluster) Start() error {
 		return errors.Wrap(err, "failed post reconcile of osd properties")
 	}
 
+	c.crushHierarchy, err = c.reconcileCrushHierarchy()
+	if err != nil {
+		return errors.Wrap(err, "failed to reconcile crush hierarchy")
+	}
+
 	err = c.updateCephOsdStorageStatus()
 	if err != nil {
 		return errors.Wrapf(err, "failed to update ceph storage status")
@@ -374,10 +392,20 @@ func (c *Cluster) Start() error {
 	// remove the osdBootstrapKey as the osds are running
 	c.deleteOsdBootstrapKeyring()
 
+	if canary != nil {
+		c.requeueAfter = canary.requeueAfter()
+	}
+
 	log.NamespacedInfo(c.clusterInfo.Namespace, logger, "finished running OSDs in namespace %q", namespace)
 	return nil
 }
 
+// RequeueAfter returns the time after which the OSDs must be reconciled again, such as during the
+// soak of a canary upgrade, or zero if no reconcile is needed
+func (c *Cluster) RequeueAfter() time.Duration {
+	return c.requeueAfter
+}
+
 func (c *Cluster) deleteOsdBootstrapKeyring() {
 	err := cephclient.AuthDelete(c.context, c.clusterInfo, "client.bootstrap-osd")
 	if err != nil {
@@ -1165,6 +1193,8 @@ func (c *Cluster) updateCephOsdStorageStatus() error {
 	// Add the status about deprecated OSDs
 	cephClusterStorage.DeprecatedOSDs = c.deprecatedOSDs
 
+	cephClusterStorage.CrushHierarchy = c.crushHierarchy
+
 	// Update pending migration status
 	if c.isMigrationRequested() {
 		migrationConfig, err := c.newMigrationConfig()
@@ -1172,6 +1202,7 @@ func (c *Cluster) updateCephOsdStorageStatus() error {
 			return errors.Wrapf(err, "failed to get osd migration config to update cluster status")
 		}
 		cephClusterStorage.OSD.MigrationStatus.Pending = len(migrationConfig.osds)
+		cephClusterStorage.OSD.MigrationStatus.PendingByReason = migrationConfig.pendingByReason()
 	}
 
 	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
@@ -1185,6 +1216,18 @@ func (c *Cluster) updateCephOsdStorageStatus() error {
 			return errors.Wrapf(err, "failed to retrieve ceph cluster %q to update ceph Storage", c.clusterInfo.NamespacedName().Name)
 		}
 
+		if c.spec.Storage.UpgradeStrategy.Type == cephv1.OSDUpgradeStrategyCanary && cephCluster.Status.CephStorage != nil {
+			// the upgrade status is updated by the canary upgrade
+			cephClusterStorage.OSD.Upgrade = cephCluster.Status.CephStorage.OSD.Upgrade
+		}
+		if cephCluster.Status.CephStorage != nil {
+			// the replacements are updated by the OSD health monitor
+			cephClusterStorage.OSD.Replacements = cephCluster.Status.CephStorage.OSD.Replacements
+			// the key rotation results are updated by the OSD health monitor
+			cephClusterStorage.OSD.KeyRotations = cephCluster.Status.CephStorage.OSD.KeyRotations
+			// the device health is updated by the OSD health monitor
+			cephClusterStorage.DeviceHealth = cephCluster.Status.CephStorage.DeviceHealth
+		}
 		cephCluster.Status.CephStorage = &cephClusterStorage
 
 		if cephx != nil {
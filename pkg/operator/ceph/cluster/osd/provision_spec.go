@@ -242,6 +242,9 @@ func (c *Cluster) provisionOSDContainer(osdProps osdProperties, copyBinariesMoun
 	if osdProps.metadataDevice != "" {
 		envVars = append(envVars, metadataDeviceEnvVar(osdProps.metadataDevice))
 	}
+	if len(osdProps.storeConfig.MetadataDevices) > 0 {
+		envVars = append(envVars, metadataDevicesEnvVars(osdProps.storeConfig.MetadataDevices, osdProps.storeConfig.MetadataDeviceRatio)...)
+	}
 
 	volumeMounts := append(opcontroller.CephVolumeMounts(provisionConfig.DataPathMap, true), []v1.VolumeMount{
 		{Name: "devices", MountPath: "/dev"},
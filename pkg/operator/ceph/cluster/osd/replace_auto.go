@@ -0,0 +1,431 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	discoverDaemon "github.com/rook/rook/pkg/daemon/discover"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/log"
+	"github.com/rook/rook/pkg/util/sys"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+)
+
+// maxOSDReplacements is the number of replacements kept in the CephCluster status
+const maxOSDReplacements = 10
+
+// processOSDReplacementPolicy requests the replacement of the OSDs with a failed disk when the
+// autoReplaceFailedOSDs policy is enabled, and records all the replacements in the CephCluster
+// status. The OSDs it requests a replacement for are added to osdsUnderReplacement so that the
+// normal health processing leaves them to the replacement flow.
+func (m *OSDHealthMonitor) processOSDReplacementPolicy(osdsUnderReplacement map[int]struct{}) error {
+	// The policy is read from the CephCluster rather than from the spec captured when the monitor
+	// started, so that enabling or disabling it takes effect on the next health check.
+	cephCluster := &cephv1.CephCluster{}
+	if err := m.context.Client.Get(m.clusterInfo.Context, m.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		return errors.Wrapf(err, "failed to get cluster %v", m.clusterInfo.NamespacedName())
+	}
+	// OSD replacement is host-based only
+	if len(cephCluster.Spec.Storage.StorageClassDeviceSets) > 0 {
+		return nil
+	}
+
+	deployments, err := m.cluster.getOSDDeployments()
+	if err != nil {
+		return errors.Wrap(err, "failed to list OSD deployments for the replacement status")
+	}
+
+	var requested []cephv1.OSDReplacementStatus
+	if cephCluster.Spec.Storage.AutoReplaceFailedOSDs {
+		requested, err = m.requestFailedOSDReplacements(deployments, osdsUnderReplacement)
+		if err != nil {
+			// the replacements requested before the error are still recorded
+			log.NamespacedWarning(m.clusterInfo.Namespace, logger, "failed to check for OSDs with a failed disk. %v", err)
+		}
+	}
+
+	return m.updateOSDReplacementStatus(deployments.Items, requested)
+}
+
+// requestFailedOSDReplacements sets the replace annotation on the deployments of the OSDs that are
+// down and out with a backing device that is missing from the devices reported by the discovery
+// daemon on their node. The controller validates the request on the reconcile triggered by the
+// annotation and hands the OSD to processOSDsDestroyForReplacement, exactly as for a request made
+// by the user.
+func (m *OSDHealthMonitor) requestFailedOSDReplacements(deployments *appsv1.DeploymentList, osdsUnderReplacement map[int]struct{}) ([]cephv1.OSDReplacementStatus, error) {
+	var requested []cephv1.OSDReplacementStatus
+	var osdDump *cephclient.OSDDump
+	var osdMetadata []cephclient.OSDMetadata
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		if _, ok := d.Annotations[cephv1.ReplaceOSDAnnotationKey]; ok || d.Annotations[cephv1.ReplaceInProgressOSDAnnotationKey] == "true" {
+			continue
+		}
+		// an OSD fenced by the user is left alone
+		if _, ok := d.Labels[cephv1.SkipReconcileLabelKey]; ok {
+			continue
+		}
+		if _, isPVC := d.Labels[OSDOverPVCLabelKey]; isPVC {
+			continue
+		}
+		osdID, err := GetOSDID(d)
+		if err != nil {
+			continue
+		}
+
+		if osdDump == nil {
+			osdDump, err = cephclient.GetOSDDump(m.context, m.clusterInfo)
+			if err != nil {
+				return requested, errors.Wrap(err, "failed to get osd dump")
+			}
+		}
+		status, in, err := osdDump.StatusByID(int64(osdID))
+		if err != nil || status == upStatus || in == inStatus {
+			continue
+		}
+
+		// The metadata of a down OSD is the one it reported when it last started
+		if osdMetadata == nil {
+			metadata, err := cephclient.GetOSDMetadata(m.context, m.clusterInfo)
+			if err != nil {
+				return requested, errors.Wrap(err, "failed to get osd metadata")
+			}
+			osdMetadata = *metadata
+		}
+		nodeName, err := m.replaceOSDNodeName(d, osdID)
+		if err != nil {
+			log.NamespacedWarning(m.clusterInfo.Namespace, logger, "failed to check the devices of down and out osd.%d. %v", osdID, err)
+			continue
+		}
+		discovered, err := m.discoveredDevices(nodeName)
+		if err != nil {
+			log.NamespacedWarning(m.clusterInfo.Namespace, logger, "failed to check the devices of down and out osd.%d. %v", osdID, err)
+			continue
+		}
+		if discovered == nil {
+			log.NamespacedDebug(m.clusterInfo.Namespace, logger, "no devices reported by the discovery daemon on node %q; not checking the devices of down and out osd.%d", nodeName, osdID)
+			continue
+		}
+		missing, err := missingOSDDevices(osdID, osdMetadata, discovered)
+		if err != nil {
+			log.NamespacedWarning(m.clusterInfo.Namespace, logger, "not replacing down and out osd.%d automatically. %v", osdID, err)
+			continue
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		log.NamespacedInfo(m.clusterInfo.Namespace, logger,
+			"osd.%d is down and out and its device(s) %v are missing on node %q; requesting its replacement", osdID, missing, nodeName)
+		k8sutil.AddAnnotationToDeployment(cephv1.ReplaceOSDAnnotationKey, fmt.Sprintf(cephv1.ReplaceOSDAnnotationValueFmt, osdID), d)
+		updated, err := m.context.Clientset.AppsV1().Deployments(m.clusterInfo.Namespace).Update(m.clusterInfo.Context, d, metav1.UpdateOptions{})
+		if err != nil {
+			log.NamespacedWarning(m.clusterInfo.Namespace, logger, "failed to request the replacement of osd.%d. %v", osdID, err)
+			continue
+		}
+		*d = *updated
+		osdsUnderReplacement[osdID] = struct{}{}
+		requested = append(requested, cephv1.OSDReplacementStatus{ID: osdID, Host: osdHostName(d), Devices: missing, Automatic: true})
+	}
+	return requested, nil
+}
+
+// discoveredDevices returns the devices the discovery daemon reports on the node, or nil if the
+// discovery daemon does not report the devices of the node
+func (m *OSDHealthMonitor) discoveredDevices(nodeName string) ([]sys.LocalDisk, error) {
+	selector := fmt.Sprintf("%s=%s,%s=%s", k8sutil.AppAttr, discoverDaemon.AppName, discoverDaemon.NodeAttr, nodeName)
+	// the discovery daemon runs in the operator namespace
+	cms, err := m.context.Clientset.CoreV1().ConfigMaps(os.Getenv(k8sutil.PodNamespaceEnvVar)).List(m.clusterInfo.Context, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the device configmap of node %q", nodeName)
+	}
+	if len(cms.Items) == 0 {
+		return nil, nil
+	}
+	devices := []sys.LocalDisk{}
+	if err := json.Unmarshal([]byte(cms.Items[0].Data[discoverDaemon.LocalDiskCMData]), &devices); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the devices of node %q", nodeName)
+	}
+	return devices, nil
+}
+
+// missingOSDDevices returns the devices backing the OSD that are not discovered on its host. The
+// devices are matched by the stable device ID reported by the OSD, since the kernel name of a
+// device can be reused by another disk. A device without an ID, such as a virtual disk without a
+// serial, is matched by its kernel name. A missing device that also backs another OSD on the host,
+// such as a shared metadata device, is an error: replacing it would destroy all the OSDs using it,
+// which the replacement does not support.
+func missingOSDDevices(osdID int, osdMetadata []cephclient.OSDMetadata, discovered []sys.LocalDisk) ([]string, error) {
+	var target *cephclient.OSDMetadata
+	for i := range osdMetadata {
+		if osdMetadata[i].Id == osdID {
+			target = &osdMetadata[i]
+			break
+		}
+	}
+	if target == nil || target.Devices == "" {
+		return nil, nil
+	}
+
+	var missing []string
+	deviceIDs := osdDeviceIDs(*target)
+	for _, device := range strings.Split(target.Devices, ",") {
+		if deviceDiscovered(device, deviceIDs[device], discovered) {
+			continue
+		}
+		for _, other := range osdMetadata {
+			if other.Id == osdID || other.HostName != target.HostName {
+				continue
+			}
+			if sharesDevice(other, device, deviceIDs[device]) {
+				return nil, errors.Errorf("missing device %q of OSD %d also backs OSD %d on host %q", device, osdID, other.Id, target.HostName)
+			}
+		}
+		missing = append(missing, device)
+	}
+	return missing, nil
+}
+
+// osdDeviceIDs returns the stable IDs of the devices of the OSD by kernel name
+func osdDeviceIDs(metadata cephclient.OSDMetadata) map[string]string {
+	ids := map[string]string{}
+	for _, entry := range strings.Split(metadata.DeviceIDs, ",") {
+		device, id, ok := strings.Cut(entry, "=")
+		if ok && id != "" {
+			ids[device] = id
+		}
+	}
+	return ids
+}
+
+// deviceDiscovered returns true if the device of an OSD is among the discovered devices
+func deviceDiscovered(device, deviceID string, discovered []sys.LocalDisk) bool {
+	for _, disk := range discovered {
+		if deviceID == "" {
+			if disk.Name == device {
+				return true
+			}
+			continue
+		}
+		if matchesDeviceID(deviceID, disk) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesDeviceID returns true if the ceph device ID, "[<vendor>_]<model>_<serial>", is the one of the
+// disk. The udev serial of the disk and the names of its /dev/disk/by-id links, without their bus
+// prefix, end with the model and serial of the disk.
+func matchesDeviceID(deviceID string, disk sys.LocalDisk) bool {
+	var candidates []string
+	if disk.Serial != "" {
+		candidates = append(candidates, disk.Serial)
+	}
+	for _, link := range strings.Fields(disk.DevLinks) {
+		name, ok := strings.CutPrefix(link, "/dev/disk/by-id/")
+		if !ok || strings.HasPrefix(name, "wwn-") {
+			continue
+		}
+		if _, id, ok := strings.Cut(name, "-"); ok && id != "" && !strings.Contains(id, "-part") {
+			candidates = append(candidates, id)
+		}
+	}
+	for _, candidate := range candidates {
+		candidate = strings.ReplaceAll(candidate, " ", "_")
+		if deviceID == candidate || strings.HasSuffix(deviceID, "_"+candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// sharesDevice returns true if the device also backs the other OSD, matched by the device ID if the
+// device has one
+func sharesDevice(other cephclient.OSDMetadata, device, deviceID string) bool {
+	if deviceID != "" {
+		for _, id := range osdDeviceIDs(other) {
+			if id == deviceID {
+				return true
+			}
+		}
+		return false
+	}
+	return sets.New(strings.Split(other.Devices, ",")...).Has(device)
+}
+
+// updateOSDReplacementStatus records the replacements in the CephCluster status
+func (m *OSDHealthMonitor) updateOSDReplacementStatus(deployments []appsv1.Deployment, requested []cephv1.OSDReplacementStatus) error {
+	var osdDump *cephclient.OSDDump
+	osdUp := func(osdID int) bool {
+		if osdDump == nil {
+			dump, err := cephclient.GetOSDDump(m.context, m.clusterInfo)
+			if err != nil {
+				log.NamespacedDebug(m.clusterInfo.Namespace, logger, "failed to get osd dump to check the replaced OSDs. %v", err)
+				return false
+			}
+			osdDump = dump
+		}
+		status, in, err := osdDump.StatusByID(int64(osdID))
+		return err == nil && status == upStatus && in == inStatus
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cephCluster := &cephv1.CephCluster{}
+		if err := m.context.Client.Get(m.clusterInfo.Context, m.clusterInfo.NamespacedName(), cephCluster); err != nil {
+			return errors.Wrapf(err, "failed to get cluster %v", m.clusterInfo.NamespacedName())
+		}
+		var replacements []cephv1.OSDReplacementStatus
+		if cephCluster.Status.CephStorage != nil {
+			replacements = cephCluster.Status.CephStorage.OSD.Replacements
+		}
+		replacements, changed := updateOSDReplacements(replacements, requested, deployments, osdUp, time.Now())
+		if !changed {
+			return nil
+		}
+		if cephCluster.Status.CephStorage == nil {
+			cephCluster.Status.CephStorage = &cephv1.CephStorage{}
+		}
+		cephCluster.Status.CephStorage.OSD.Replacements = replacements
+		return reporting.UpdateStatus(m.context.Client, cephCluster)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update the OSD replacement status")
+	}
+	return nil
+}
+
+// updateOSDReplacements updates the replacements from the replacement markers on the OSD deployments.
+// The requested replacements are added first. A replacement whose markers are gone was cancelled if
+// the OSD was not destroyed yet, failed if the OSD deployment was removed, and is completed once the
+// OSD is up and in. Only the most recent finished replacements are kept. Returns true if the
+// replacements changed.
+func updateOSDReplacements(replacements, requested []cephv1.OSDReplacementStatus, deployments []appsv1.Deployment, osdUp func(osdID int) bool, now time.Time) ([]cephv1.OSDReplacementStatus, bool) {
+	changed := false
+	for _, r := range requested {
+		r.StartTime = &metav1.Time{Time: now}
+		replacements = append(replacements, r)
+		changed = true
+	}
+
+	existing := sets.New[int]()
+	marked := sets.New[int]()
+	for i := range deployments {
+		d := &deployments[i]
+		osdID, err := GetOSDID(d)
+		if err != nil {
+			continue
+		}
+		existing.Insert(osdID)
+		_, requested := d.Annotations[cephv1.ReplaceOSDAnnotationKey]
+		if !requested && d.Annotations[cephv1.ReplaceInProgressOSDAnnotationKey] != "true" {
+			continue
+		}
+		marked.Insert(osdID)
+
+		phase, message := replacementPhase(d)
+		i := activeOSDReplacement(replacements, osdID)
+		if i < 0 {
+			replacements = append(replacements, cephv1.OSDReplacementStatus{ID: osdID, Host: osdHostName(d), StartTime: &metav1.Time{Time: now}})
+			i = len(replacements) - 1
+		}
+		if replacements[i].Phase != phase {
+			replacements[i].Phase = phase
+			replacements[i].Message = message
+			changed = true
+		}
+	}
+
+	for i := range replacements {
+		r := &replacements[i]
+		if osdReplacementFinished(r.Phase) || marked.Has(r.ID) {
+			continue
+		}
+		switch {
+		case r.Phase != cephv1.OSDReplacementReadyForSwap:
+			r.Phase = cephv1.OSDReplacementCancelled
+			r.Message = "the replacement was cancelled before the OSD was destroyed"
+		case !existing.Has(r.ID):
+			r.Phase = cephv1.OSDReplacementFailed
+			r.Message = "the OSD deployment was removed before the replacement completed"
+		case osdUp(r.ID):
+			r.Phase = cephv1.OSDReplacementCompleted
+			r.Message = "the OSD is up and in on the new disk"
+		default:
+			// the OSD is being recreated on the new disk
+			continue
+		}
+		r.EndTime = &metav1.Time{Time: now}
+		changed = true
+	}
+
+	for len(replacements) > maxOSDReplacements {
+		i := 0
+		for i < len(replacements) && !osdReplacementFinished(replacements[i].Phase) {
+			i++
+		}
+		if i == len(replacements) {
+			break
+		}
+		replacements = append(replacements[:i], replacements[i+1:]...)
+		changed = true
+	}
+	return replacements, changed
+}
+
+// replacementPhase returns the phase of the replacement from the markers on the OSD deployment
+func replacementPhase(d *appsv1.Deployment) (cephv1.OSDReplacementPhase, string) {
+	switch {
+	case isWaitingForDiskSwap(d):
+		return cephv1.OSDReplacementReadyForSwap, "the OSD is destroyed and the disk may be swapped"
+	case d.Annotations[cephv1.ReplaceInProgressOSDAnnotationKey] == "true":
+		return cephv1.OSDReplacementDraining, "the OSD is out and waits to be safe to destroy"
+	default:
+		return cephv1.OSDReplacementRequested, "the replacement request waits to be validated"
+	}
+}
+
+// activeOSDReplacement returns the index of the replacement of the OSD that is not finished, or -1
+func activeOSDReplacement(replacements []cephv1.OSDReplacementStatus, osdID int) int {
+	for i := len(replacements) - 1; i >= 0; i-- {
+		if replacements[i].ID == osdID && !osdReplacementFinished(replacements[i].Phase) {
+			return i
+		}
+	}
+	return -1
+}
+
+func osdReplacementFinished(phase cephv1.OSDReplacementPhase) bool {
+	return phase == cephv1.OSDReplacementCompleted || phase == cephv1.OSDReplacementCancelled || phase == cephv1.OSDReplacementFailed
+}
+
+// osdHostName returns the host the OSD deployment is pinned to
+func osdHostName(d *appsv1.Deployment) string {
+	return d.Spec.Template.Spec.NodeSelector[k8sutil.LabelHostname()]
+}
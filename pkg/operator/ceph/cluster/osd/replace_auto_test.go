@@ -0,0 +1,264 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	discoverDaemon "github.com/rook/rook/pkg/daemon/discover"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/rook/rook/pkg/util/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMissingOSDDevices(t *testing.T) {
+	var metadata []cephclient.OSDMetadata
+	require.NoError(t, json.Unmarshal([]byte(osdMetadataJSON(map[int]string{
+		0: "node-1|vdb",
+		1: "node-1|nvme0n1,vdc",
+		2: "node-1|nvme0n1,vdd",
+		3: "node-2|vdb",
+	})), &metadata))
+	disks := func(names ...string) []sys.LocalDisk {
+		var disks []sys.LocalDisk
+		for _, name := range names {
+			disks = append(disks, sys.LocalDisk{Name: name})
+		}
+		return disks
+	}
+
+	missing, err := missingOSDDevices(0, metadata, disks("vdc", "vdd", "nvme0n1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"vdb"}, missing)
+
+	// all the devices are discovered
+	missing, err = missingOSDDevices(1, metadata, disks("vdc", "vdd", "nvme0n1"))
+	assert.NoError(t, err)
+	assert.Empty(t, missing)
+
+	// the data device of an OSD with a shared metadata device is missing
+	missing, err = missingOSDDevices(1, metadata, disks("vdd", "nvme0n1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"vdc"}, missing)
+
+	// the shared metadata device is missing
+	_, err = missingOSDDevices(1, metadata, disks("vdc", "vdd"))
+	assert.Error(t, err)
+
+	// no metadata for the OSD
+	missing, err = missingOSDDevices(9, metadata, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, missing)
+
+	t.Run("match by device id", func(t *testing.T) {
+		metadata := []cephclient.OSDMetadata{
+			{Id: 0, HostName: "node-1", Devices: "sdb", DeviceIDs: "sdb=ATA_ST4000NM0035_ZC1ABCDE"},
+			{Id: 1, HostName: "node-1", Devices: "nvme0n1,sdc", DeviceIDs: "nvme0n1=Samsung_SSD_970_EVO_1TB_S467NX0M123,sdc=ATA_ST4000NM0035_ZC1FGHIJ"},
+			{Id: 2, HostName: "node-1", Devices: "nvme0n1,sdd", DeviceIDs: "nvme0n1=Samsung_SSD_970_EVO_1TB_S467NX0M123,sdd=ATA_ST4000NM0035_ZC1KLMNO"},
+		}
+		// the disk of osd.0 was replaced by another disk that took its kernel name
+		discovered := []sys.LocalDisk{
+			{Name: "sdb", Serial: "ST4000NM0035_ZC1NEWDI"},
+			{Name: "sdc", DevLinks: "/dev/disk/by-path/pci-0000:00:1f.2-ata-2 /dev/disk/by-id/ata-ST4000NM0035_ZC1FGHIJ /dev/disk/by-id/wwn-0x5000c500a1b2c3d4"},
+			{Name: "sdd", Serial: "ST4000NM0035_ZC1KLMNO"},
+			{Name: "nvme1n1", Serial: "Samsung SSD 970 EVO 1TB_S467NX0M123"},
+		}
+		missing, err := missingOSDDevices(0, metadata, discovered)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"sdb"}, missing)
+
+		// the devices are discovered by their by-id link and serial, also when their kernel name changed
+		missing, err = missingOSDDevices(1, metadata, discovered)
+		assert.NoError(t, err)
+		assert.Empty(t, missing)
+
+		// the shared metadata device is matched by its id
+		_, err = missingOSDDevices(1, metadata, discovered[:3])
+		assert.Error(t, err)
+	})
+}
+
+func TestUpdateOSDReplacements(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	up := false
+	osdUp := func(osdID int) bool { return up }
+
+	// the requested replacement is added
+	requested := []cephv1.OSDReplacementStatus{{ID: 5, Host: "node-1", Devices: []string{"vdb"}, Automatic: true}}
+	deployments := []appsv1.Deployment{*osdDeployment(5, map[string]string{cephv1.ReplaceOSDAnnotationKey: "yes-really-replace-osd-5"}, nil)}
+	replacements, changed := updateOSDReplacements(nil, requested, deployments, osdUp, now)
+	assert.True(t, changed)
+	require.Len(t, replacements, 1)
+	assert.Equal(t, cephv1.OSDReplacementRequested, replacements[0].Phase)
+	assert.True(t, replacements[0].Automatic)
+	assert.Equal(t, now, replacements[0].StartTime.Time)
+	_, changed = updateOSDReplacements(replacements, nil, deployments, osdUp, now)
+	assert.False(t, changed)
+
+	// the replacement is validated, then the OSD is destroyed
+	deployments = []appsv1.Deployment{*replaceMarkedDep(5)}
+	replacements, changed = updateOSDReplacements(replacements, nil, deployments, osdUp, now)
+	assert.True(t, changed)
+	assert.Equal(t, cephv1.OSDReplacementDraining, replacements[0].Phase)
+	deployments[0].Annotations[cephv1.ReadyForSwapOSDAnnotationKey] = "true"
+	replacements, _ = updateOSDReplacements(replacements, nil, deployments, osdUp, now)
+	assert.Equal(t, cephv1.OSDReplacementReadyForSwap, replacements[0].Phase)
+
+	// the deployment is recreated for the new disk and the replacement completes when the OSD is up
+	deployments = []appsv1.Deployment{*osdDeployment(5, nil, nil)}
+	_, changed = updateOSDReplacements(replacements, nil, deployments, osdUp, now)
+	assert.False(t, changed)
+	up = true
+	replacements, changed = updateOSDReplacements(replacements, nil, deployments, osdUp, now.Add(time.Hour))
+	assert.True(t, changed)
+	assert.Equal(t, cephv1.OSDReplacementCompleted, replacements[0].Phase)
+	assert.Equal(t, now.Add(time.Hour), replacements[0].EndTime.Time)
+
+	// a replacement requested by the user is added, then cancelled before the OSD is destroyed
+	deployments = []appsv1.Deployment{*osdDeployment(5, nil, nil), *replaceMarkedDep(6)}
+	replacements, _ = updateOSDReplacements(replacements, nil, deployments, osdUp, now)
+	require.Len(t, replacements, 2)
+	assert.Equal(t, 6, replacements[1].ID)
+	assert.False(t, replacements[1].Automatic)
+	assert.Equal(t, cephv1.OSDReplacementDraining, replacements[1].Phase)
+	deployments[1] = *osdDeployment(6, nil, nil)
+	replacements, _ = updateOSDReplacements(replacements, nil, deployments, osdUp, now)
+	assert.Equal(t, cephv1.OSDReplacementCancelled, replacements[1].Phase)
+
+	// a replacement whose deployment is removed after the OSD was destroyed failed
+	replacements = append(replacements, cephv1.OSDReplacementStatus{ID: 7, Phase: cephv1.OSDReplacementReadyForSwap})
+	replacements, _ = updateOSDReplacements(replacements, nil, deployments, osdUp, now)
+	assert.Equal(t, cephv1.OSDReplacementFailed, replacements[2].Phase)
+
+	// the oldest finished replacements are dropped
+	for i := 0; i < maxOSDReplacements; i++ {
+		replacements = append(replacements, cephv1.OSDReplacementStatus{ID: 10 + i, Phase: cephv1.OSDReplacementCompleted})
+	}
+	replacements = append(replacements, cephv1.OSDReplacementStatus{ID: 5, Phase: cephv1.OSDReplacementReadyForSwap})
+	deployments = []appsv1.Deployment{*osdDeployment(5, map[string]string{cephv1.ReadyForSwapOSDAnnotationKey: "true", cephv1.ReplaceInProgressOSDAnnotationKey: "true"}, nil)}
+	replacements, changed = updateOSDReplacements(replacements, nil, deployments, osdUp, now)
+	assert.True(t, changed)
+	assert.Len(t, replacements, maxOSDReplacements)
+	assert.Equal(t, 11, replacements[0].ID)
+	assert.Equal(t, cephv1.OSDReplacementReadyForSwap, replacements[maxOSDReplacements-1].Phase)
+}
+
+func TestProcessOSDReplacementPolicy(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	t.Setenv(k8sutil.PodNamespaceEnvVar, "rook-operator")
+
+	// osd.5 is down and out, osd.6 is down and in, osd.7 is up
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "osd" && args[1] == "dump":
+				return `{"osds":[{"osd":5,"up":0,"in":0},{"osd":6,"up":0,"in":1},{"osd":7,"up":1,"in":1}]}`, nil
+			case args[0] == "osd" && args[1] == "metadata":
+				return osdMetadataJSON(map[int]string{5: "node-1|vdb", 6: "node-1|vdc", 7: "node-1|vdd"}), nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+	}
+	devices, err := json.Marshal([]sys.LocalDisk{{Name: "vdd"}})
+	require.NoError(t, err)
+	deviceCM := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "local-device-node-1",
+			Namespace: "rook-operator",
+			Labels:    map[string]string{k8sutil.AppAttr: discoverDaemon.AppName, discoverDaemon.NodeAttr: "node-1"},
+		},
+		Data: map[string]string{discoverDaemon.LocalDiskCMData: string(devices)},
+	}
+	var objects []runtime.Object
+	for _, osdID := range []int{5, 6, 7} {
+		d := withOSDContainer(osdDeployment(osdID, nil, nil), "node-1", false)
+		d.Spec.Template.Spec.NodeSelector = map[string]string{k8sutil.LabelHostname(): "node-1"}
+		objects = append(objects, d)
+	}
+	clientset := fake.NewClientset(append(objects, deviceCM)...)
+
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: namespace}}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+	cl := clientfake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(cephCluster).WithStatusSubresource(cephCluster).Build()
+
+	clusterInfo := cephclient.AdminTestClusterInfo(namespace)
+	clusterInfo.SetName("my-cluster")
+	clusterInfo.Context = ctx
+	m := NewOSDHealthMonitor(&clusterd.Context{Executor: executor, Clientset: clientset, Client: cl}, clusterInfo, false, cephv1.CephClusterHealthCheckSpec{}, cephv1.ClusterSpec{}, "rook/ceph:test")
+
+	getDep := func(osdID int) *appsv1.Deployment {
+		d, err := clientset.AppsV1().Deployments(namespace).Get(ctx, fmt.Sprintf(osdAppNameFmt, osdID), metav1.GetOptions{})
+		require.NoError(t, err)
+		return d
+	}
+
+	t.Run("policy disabled", func(t *testing.T) {
+		osdsUnderReplacement := map[int]struct{}{}
+		require.NoError(t, m.processOSDReplacementPolicy(osdsUnderReplacement))
+		assert.Empty(t, osdsUnderReplacement)
+		assert.NotContains(t, getDep(5).Annotations, cephv1.ReplaceOSDAnnotationKey)
+	})
+
+	t.Run("policy enabled", func(t *testing.T) {
+		require.NoError(t, cl.Get(ctx, clusterInfo.NamespacedName(), cephCluster))
+		cephCluster.Spec.Storage.AutoReplaceFailedOSDs = true
+		require.NoError(t, cl.Update(ctx, cephCluster))
+
+		osdsUnderReplacement := map[int]struct{}{}
+		require.NoError(t, m.processOSDReplacementPolicy(osdsUnderReplacement))
+		// only the down and out OSD with a missing device is replaced
+		assert.Equal(t, map[int]struct{}{5: {}}, osdsUnderReplacement)
+		assert.Equal(t, "yes-really-replace-osd-5", getDep(5).Annotations[cephv1.ReplaceOSDAnnotationKey])
+		assert.NotContains(t, getDep(6).Annotations, cephv1.ReplaceOSDAnnotationKey)
+		assert.NotContains(t, getDep(7).Annotations, cephv1.ReplaceOSDAnnotationKey)
+
+		require.NoError(t, cl.Get(ctx, clusterInfo.NamespacedName(), cephCluster))
+		require.NotNil(t, cephCluster.Status.CephStorage)
+		replacements := cephCluster.Status.CephStorage.OSD.Replacements
+		require.Len(t, replacements, 1)
+		assert.Equal(t, 5, replacements[0].ID)
+		assert.Equal(t, "node-1", replacements[0].Host)
+		assert.Equal(t, []string{"vdb"}, replacements[0].Devices)
+		assert.True(t, replacements[0].Automatic)
+		assert.Equal(t, cephv1.OSDReplacementRequested, replacements[0].Phase)
+
+		// the replacement is requested once
+		osdsUnderReplacement = map[int]struct{}{}
+		require.NoError(t, m.processOSDReplacementPolicy(osdsUnderReplacement))
+		require.NoError(t, cl.Get(ctx, clusterInfo.NamespacedName(), cephCluster))
+		assert.Len(t, cephCluster.Status.CephStorage.OSD.Replacements, 1)
+	})
+}
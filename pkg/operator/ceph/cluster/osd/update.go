@@ -53,6 +53,7 @@ type updateConfig struct {
 	deployments         *existenceList   // these OSDs have existing deployments
 	osdsToSkipReconcile sets.Set[string] // these OSDs should not be updated during reconcile
 	osdDesiredState     map[int]*OSDInfo // the desired state of the OSDs determined during the reconcile
+	canary              *canaryUpgrade   // holds back OSDs with the Canary upgrade strategy, if enabled
 }
 
 func (c *Cluster) newUpdateConfig(
@@ -69,6 +70,7 @@ func (c *Cluster) newUpdateConfig(
 		deployments,
 		osdsToSkipReconcile,
 		map[int]*OSDInfo{},
+		nil,
 	}
 }
 
@@ -77,13 +79,18 @@ func (c *updateConfig) progress() (completed, initial int) {
 }
 
 func (c *updateConfig) doneUpdating() bool {
-	return c.queue.Len() == 0
+	return c.queue.Len() == 0 && (c.canary == nil || c.canary.blocked())
 }
 
 func (c *updateConfig) updateExistingOSDs(errs *provisionErrors) {
 	if c.doneUpdating() {
 		return // no more OSDs to update
 	}
+	if c.queue.Len() == 0 {
+		// the released OSDs of the canary upgrade are updated
+		c.canary.advance()
+		return
+	}
 	if !c.cluster.spec.SkipUpgradeChecks && c.cluster.spec.UpgradeOSDRequiresHealthyPGs {
 		pgHealthMsg, pgClean, err := cephclient.IsClusterClean(c.cluster.context, c.cluster.clusterInfo, c.cluster.spec.DisruptionManagement.PGHealthyRegex)
 		if err != nil {
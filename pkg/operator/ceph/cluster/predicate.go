@@ -282,6 +282,25 @@ func watchControllerPredicate[T *cephv1.CephCluster](ctx context.Context, c clie
 				log.NamespacedDebug(objNew.Namespace, logger, "object %q matched on update but %q label is set, doing nothing", opcontroller.DoNotReconcileLabelName, objNew.Name)
 				return false
 			}
+			// A reconcile waiting for the mons to form quorum would never pick up a mon backup
+			// restore or a mon quorum recovery, so the ongoing orchestration is stopped as for a spec change
+			if monBackupRestoreRequested(objOld, objNew) {
+				log.NamespacedInfo(objNew.Namespace, logger, "mon backup restore requested on %q, cancelling any ongoing orchestration", objNew.Name)
+				opcontroller.ReloadManager()
+				return false
+			}
+			if monQuorumRecoveryRequested(objOld, objNew) {
+				log.NamespacedInfo(objNew.Namespace, logger, "mon quorum recovery requested on %q, cancelling any ongoing orchestration", objNew.Name)
+				opcontroller.ReloadManager()
+				return false
+			}
+
+			// the OSD upgrade waiting for the approval continues in a new reconcile
+			if osdUpgradeApproved(objOld, objNew) {
+				log.NamespacedInfo(objNew.Namespace, logger, "osd upgrade to image %q approved on %q", objNew.GetAnnotations()[cephv1.ApproveOSDUpgradeAnnotationKey], objNew.Name)
+				return true
+			}
+
 			diff := cmp.Diff(objOld.Spec, objNew.Spec, resourceQtyComparer)
 			if diff != "" {
 				log.NamespacedInfo(objNew.Namespace, logger, "CR has changed for %q. diff=%s", objNew.Name, diff)
@@ -316,3 +335,24 @@ func watchControllerPredicate[T *cephv1.CephCluster](ctx context.Context, c clie
 		},
 	}
 }
+
+// monBackupRestoreRequested reports whether the mon backup restore annotation was set or changed
+func monBackupRestoreRequested(objOld, objNew *cephv1.CephCluster) bool {
+	backupName := objNew.GetAnnotations()[cephv1.RestoreMonBackupAnnotationKey]
+	return backupName != "" && backupName != objOld.GetAnnotations()[cephv1.RestoreMonBackupAnnotationKey]
+}
+
+// monQuorumRecoveryRequested reports whether the mon quorum recovery annotation was set to the
+// confirmation value. Any other value is ignored by the recovery, so it does not reload the manager.
+func monQuorumRecoveryRequested(objOld, objNew *cephv1.CephCluster) bool {
+	if objNew.GetAnnotations()[cephv1.RecoverMonQuorumAnnotationKey] != cephv1.RecoverMonQuorumAnnotationValue {
+		return false
+	}
+	return objOld.GetAnnotations()[cephv1.RecoverMonQuorumAnnotationKey] != cephv1.RecoverMonQuorumAnnotationValue
+}
+
+// osdUpgradeApproved reports whether the OSD upgrade approval annotation was set to a new image
+func osdUpgradeApproved(objOld, objNew *cephv1.CephCluster) bool {
+	image := objNew.GetAnnotations()[cephv1.ApproveOSDUpgradeAnnotationKey]
+	return image != "" && image != objOld.GetAnnotations()[cephv1.ApproveOSDUpgradeAnnotationKey]
+}
@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -191,3 +192,51 @@ func TestPredicateForNodeWatcherUpdate(t *testing.T) {
 		assert.False(t, reconcile)
 	})
 }
+
+func TestMonBackupRestoreRequested(t *testing.T) {
+	withRestore := func(backupName string) *cephv1.CephCluster {
+		cluster := &cephv1.CephCluster{}
+		if backupName != "" {
+			cluster.Annotations = map[string]string{cephv1.RestoreMonBackupAnnotationKey: backupName}
+		}
+		return cluster
+	}
+
+	assert.False(t, monBackupRestoreRequested(withRestore(""), withRestore("")))
+	assert.True(t, monBackupRestoreRequested(withRestore(""), withRestore("mon-backup-20260101-020000")))
+	assert.False(t, monBackupRestoreRequested(withRestore("mon-backup-20260101-020000"), withRestore("mon-backup-20260101-020000")))
+	assert.True(t, monBackupRestoreRequested(withRestore("mon-backup-20260101-020000"), withRestore("mon-backup-20260102-020000")))
+	// removing the annotation when the restore is done does not trigger a restore
+	assert.False(t, monBackupRestoreRequested(withRestore("mon-backup-20260101-020000"), withRestore("")))
+}
+
+func TestMonQuorumRecoveryRequested(t *testing.T) {
+	withAnnotation := func(value string) *cephv1.CephCluster {
+		return &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{cephv1.RecoverMonQuorumAnnotationKey: value}}}
+	}
+	noAnnotation := &cephv1.CephCluster{}
+
+	assert.False(t, monQuorumRecoveryRequested(noAnnotation, noAnnotation))
+	assert.True(t, monQuorumRecoveryRequested(noAnnotation, withAnnotation(cephv1.RecoverMonQuorumAnnotationValue)))
+	assert.True(t, monQuorumRecoveryRequested(withAnnotation("no"), withAnnotation(cephv1.RecoverMonQuorumAnnotationValue)))
+	assert.False(t, monQuorumRecoveryRequested(withAnnotation(cephv1.RecoverMonQuorumAnnotationValue), withAnnotation(cephv1.RecoverMonQuorumAnnotationValue)))
+	// a value other than the confirmation is ignored
+	assert.False(t, monQuorumRecoveryRequested(noAnnotation, withAnnotation("yes")))
+	assert.False(t, monQuorumRecoveryRequested(withAnnotation("no"), withAnnotation("yes")))
+	assert.False(t, monQuorumRecoveryRequested(withAnnotation(cephv1.RecoverMonQuorumAnnotationValue), withAnnotation("yes")))
+	// removing the annotation after the recovery does not trigger a reconcile
+	assert.False(t, monQuorumRecoveryRequested(withAnnotation(cephv1.RecoverMonQuorumAnnotationValue), noAnnotation))
+}
+
+func TestOSDUpgradeApproved(t *testing.T) {
+	withAnnotation := func(value string) *cephv1.CephCluster {
+		return &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{cephv1.ApproveOSDUpgradeAnnotationKey: value}}}
+	}
+	noAnnotation := &cephv1.CephCluster{}
+
+	assert.False(t, osdUpgradeApproved(noAnnotation, noAnnotation))
+	assert.True(t, osdUpgradeApproved(noAnnotation, withAnnotation("quay.io/ceph/ceph:v20.2.1")))
+	assert.True(t, osdUpgradeApproved(withAnnotation("quay.io/ceph/ceph:v20.2.0"), withAnnotation("quay.io/ceph/ceph:v20.2.1")))
+	assert.False(t, osdUpgradeApproved(withAnnotation("quay.io/ceph/ceph:v20.2.1"), withAnnotation("quay.io/ceph/ceph:v20.2.1")))
+	assert.False(t, osdUpgradeApproved(withAnnotation("quay.io/ceph/ceph:v20.2.1"), noAnnotation))
+}
@@ -0,0 +1,347 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	// the time zones of the recovery windows must not depend on the zoneinfo of the operator image
+	_ "time/tzdata"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/util/log"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// defaultRecoveryWindowName is the window shown in the status outside of the recovery windows
+	defaultRecoveryWindowName = "default"
+	// forceFastRecoveryWindowName is the window shown in the status when the fast recovery is forced
+	forceFastRecoveryWindowName = "force-fast-recovery"
+
+	mclockProfileOption              = "osd_mclock_profile"
+	mclockOverrideRecoveryOption     = "osd_mclock_override_recovery_settings"
+	maxBackfillsOption               = "osd_max_backfills"
+	recoveryMaxActiveOption          = "osd_recovery_max_active"
+	recoveryScheduleWho              = "osd"
+	recoveryWindowTimeLayout         = "15:04"
+	defaultRecoveryScheduleTimeZone  = "UTC"
+	forceFastRecoveryAnnotationValue = "true"
+)
+
+// defaultRecoveryScheduleInterval is the interval to check which recovery profile must be applied
+var defaultRecoveryScheduleInterval = time.Minute
+
+// recoveryScheduleOptions are the OSD options managed by the recovery schedule
+var recoveryScheduleOptions = []string{mclockProfileOption, mclockOverrideRecoveryOption, maxBackfillsOption, recoveryMaxActiveOption}
+
+// fastRecoveryProfile is the profile applied when the fast recovery is forced with an annotation
+var fastRecoveryProfile = cephv1.RecoveryProfile{MClockProfile: cephv1.MClockProfileHighRecoveryOps}
+
+// recoveryScheduler periodically applies the recovery profile of the current time window to the OSDs
+type recoveryScheduler struct {
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+	interval    time.Duration
+}
+
+// newRecoveryScheduler creates a new recoveryScheduler object
+func newRecoveryScheduler(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo) *recoveryScheduler {
+	return &recoveryScheduler{
+		context:     context,
+		clusterInfo: clusterInfo,
+		interval:    defaultRecoveryScheduleInterval,
+	}
+}
+
+// checkRecoverySchedule periodically applies the recovery profile of the current time window
+func (r *recoveryScheduler) checkRecoverySchedule(monitoringRoutines *sync.Map, daemon string) {
+	for {
+		// We must perform this check otherwise the case will check an index that does not exist anymore and
+		// we will get an invalid pointer error and the go routine will panic
+		v, ok := monitoringRoutines.Load(daemon)
+		if !ok {
+			log.NamespacedInfo(r.clusterInfo.Namespace, logger, "ceph cluster %q has been deleted. stopping the recovery schedule", r.clusterInfo.Namespace)
+			return
+		}
+		health := v.(*opcontroller.ClusterHealth)
+		select {
+		case <-health.InternalCtx.Done():
+			log.NamespacedInfo(r.clusterInfo.Namespace, logger, "stopping the recovery schedule")
+			monitoringRoutines.Delete(daemon)
+			return
+
+		case <-time.After(r.interval):
+			if err := r.applySchedule(time.Now()); err != nil {
+				if strings.Contains(err.Error(), opcontroller.UninitializedCephConfigError) {
+					log.NamespacedInfo(r.clusterInfo.Namespace, logger, "skipping the recovery schedule since operator is still initializing")
+					continue
+				}
+				log.NamespacedError(r.clusterInfo.Namespace, logger, "failed to apply the recovery schedule. %v", err)
+			}
+		}
+	}
+}
+
+// applySchedule applies the recovery profile expected at the given time. The profile is applied on
+// every check so that the options changed outside of the schedule are restored. Only the options set
+// by the schedule are removed when the profile changes or the schedule is removed.
+func (r *recoveryScheduler) applySchedule(now time.Time) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := r.context.Client.Get(r.clusterInfo.Context, r.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			log.NamespacedDebug(r.clusterInfo.Namespace, logger, "CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get cluster %v", r.clusterInfo.NamespacedName())
+	}
+
+	window, profile, err := expectedRecoveryProfile(cephCluster, now)
+	if err != nil {
+		return err
+	}
+	current := cephCluster.Status.Recovery
+	if profile == nil && current == nil {
+		return nil
+	}
+	// the options set by the schedule are the ones of the profile applied last
+	applied := map[string]string{}
+	if current != nil && current.Profile != nil {
+		applied = recoveryProfileSettings(current.Profile)
+	}
+
+	monStore := config.GetMonStore(r.context, r.clusterInfo)
+	if profile == nil {
+		// the schedule was removed, go back to the ceph defaults
+		log.NamespacedInfo(r.clusterInfo.Namespace, logger, "removing the recovery profile of window %q", current.Window)
+		if err := deleteRecoveryOptions(monStore, unsetRecoveryOptions(applied, nil)); err != nil {
+			return err
+		}
+		return r.updateStatus(nil)
+	}
+
+	changed := current == nil || current.Window != window || !reflect.DeepEqual(current.Profile, profile)
+	if changed {
+		log.NamespacedInfo(r.clusterInfo.Namespace, logger, "applying the recovery profile of window %q: %s", window, formatRecoveryProfile(profile))
+	}
+	settings := recoveryProfileSettings(profile)
+	if len(settings) > 0 {
+		if err := monStore.SetAll(recoveryScheduleWho, settings); err != nil {
+			return errors.Wrapf(err, "failed to apply the recovery profile of window %q", window)
+		}
+	}
+	if err := deleteRecoveryOptions(monStore, unsetRecoveryOptions(applied, settings)); err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+
+	return r.updateStatus(&cephv1.RecoveryStatus{
+		Window:             window,
+		Profile:            profile,
+		LastTransitionTime: &metav1.Time{Time: now},
+	})
+}
+
+// unsetRecoveryOptions returns the options set by the schedule that are not in the new settings
+func unsetRecoveryOptions(applied, settings map[string]string) []string {
+	unset := []string{}
+	for _, option := range recoveryScheduleOptions {
+		_, wasApplied := applied[option]
+		_, isSet := settings[option]
+		if wasApplied && !isSet {
+			unset = append(unset, option)
+		}
+	}
+	return unset
+}
+
+// deleteRecoveryOptions removes the given recovery options of the OSDs from the central ceph config
+func deleteRecoveryOptions(monStore *config.MonStore, options []string) error {
+	if len(options) == 0 {
+		return nil
+	}
+	toDelete := []config.Option{}
+	for _, option := range options {
+		toDelete = append(toDelete, config.Option{Who: recoveryScheduleWho, Option: option})
+	}
+	if err := monStore.DeleteAll(toDelete...); err != nil {
+		return errors.Wrap(err, "failed to remove the recovery options")
+	}
+	return nil
+}
+
+// updateStatus sets the recovery status of the CephCluster
+func (r *recoveryScheduler) updateStatus(status *cephv1.RecoveryStatus) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cephCluster := &cephv1.CephCluster{}
+		if err := r.context.Client.Get(r.clusterInfo.Context, r.clusterInfo.NamespacedName(), cephCluster); err != nil {
+			return errors.Wrapf(err, "failed to get cluster %v", r.clusterInfo.NamespacedName())
+		}
+		cephCluster.Status.Recovery = status
+		return reporting.UpdateStatus(r.context.Client, cephCluster)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update the recovery status")
+	}
+	return nil
+}
+
+// expectedRecoveryProfile returns the window and the recovery profile to apply at the given time.
+// The profile is nil when the ceph defaults must be used.
+func expectedRecoveryProfile(cephCluster *cephv1.CephCluster, now time.Time) (string, *cephv1.RecoveryProfile, error) {
+	if cephCluster.Annotations[cephv1.ForceFastRecoveryAnnotationKey] == forceFastRecoveryAnnotationValue {
+		profile := fastRecoveryProfile
+		return forceFastRecoveryWindowName, &profile, nil
+	}
+
+	schedule := cephCluster.Spec.RecoverySchedule
+	window, err := activeRecoveryWindow(schedule, now)
+	if err != nil {
+		return "", nil, err
+	}
+	if window != nil {
+		profile := window.Profile
+		return window.Name, &profile, nil
+	}
+	if schedule.Default != nil {
+		profile := *schedule.Default
+		return defaultRecoveryWindowName, &profile, nil
+	}
+	return "", nil, nil
+}
+
+// activeRecoveryWindow returns the first window of the schedule that contains the given time, or
+// nil if the time is outside of all the windows
+func activeRecoveryWindow(schedule cephv1.RecoveryScheduleSpec, now time.Time) (*cephv1.RecoveryWindow, error) {
+	timeZone := schedule.TimeZone
+	if timeZone == "" {
+		timeZone = defaultRecoveryScheduleTimeZone
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load the time zone %q of the recovery schedule", timeZone)
+	}
+	now = now.In(location)
+	minute := now.Hour()*60 + now.Minute()
+	today := recoveryWindowDay(now.Weekday())
+	yesterday := recoveryWindowDay(now.AddDate(0, 0, -1).Weekday())
+
+	for i := range schedule.Windows {
+		window := &schedule.Windows[i]
+		start, err := minuteOfDay(window.Start)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid start of recovery window %q", window.Name)
+		}
+		end, err := minuteOfDay(window.End)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid end of recovery window %q", window.Name)
+		}
+
+		if start < end {
+			if isRecoveryWindowDay(window, today) && minute >= start && minute < end {
+				return window, nil
+			}
+			continue
+		}
+		// the window ends on the next day
+		if (isRecoveryWindowDay(window, today) && minute >= start) || (isRecoveryWindowDay(window, yesterday) && minute < end) {
+			return window, nil
+		}
+	}
+	return nil, nil
+}
+
+func isRecoveryWindowDay(window *cephv1.RecoveryWindow, day cephv1.RecoveryWindowDay) bool {
+	return len(window.Days) == 0 || slices.Contains(window.Days, day)
+}
+
+func recoveryWindowDay(day time.Weekday) cephv1.RecoveryWindowDay {
+	return cephv1.RecoveryWindowDay(day.String()[:3])
+}
+
+// minuteOfDay returns the number of minutes since midnight of a "HH:MM" time
+func minuteOfDay(hhmm string) (int, error) {
+	t, err := time.Parse(recoveryWindowTimeLayout, hhmm)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// recoveryProfileSettings returns the OSD options of a recovery profile
+func recoveryProfileSettings(profile *cephv1.RecoveryProfile) map[string]string {
+	settings := map[string]string{}
+	if profile.MClockProfile != "" {
+		settings[mclockProfileOption] = string(profile.MClockProfile)
+	}
+	if profile.MaxBackfills != nil {
+		settings[maxBackfillsOption] = strconv.Itoa(*profile.MaxBackfills)
+	}
+	if profile.RecoveryMaxActive != nil {
+		settings[recoveryMaxActiveOption] = strconv.Itoa(*profile.RecoveryMaxActive)
+	}
+	// the mclock scheduler ignores the recovery limits unless they are overridden
+	if profile.MaxBackfills != nil || profile.RecoveryMaxActive != nil {
+		settings[mclockOverrideRecoveryOption] = "true"
+	}
+	return settings
+}
+
+func formatRecoveryProfile(profile *cephv1.RecoveryProfile) string {
+	settings := recoveryProfileSettings(profile)
+	if len(settings) == 0 {
+		return "ceph defaults"
+	}
+	items := []string{}
+	for _, option := range recoveryScheduleOptions {
+		if value, ok := settings[option]; ok {
+			items = append(items, fmt.Sprintf("%s=%s", option, value))
+		}
+	}
+	return strings.Join(items, ", ")
+}
+
+// withoutRecoveryOptions returns the ceph config options without the ones managed by the recovery
+// schedule, so that the profile applied by the schedule is not seen as a drift
+func withoutRecoveryOptions(options config.CephConfigOptionsMap) config.CephConfigOptionsMap {
+	normalizer := strings.NewReplacer(" ", "_", "-", "_")
+	filtered := config.CephConfigOptionsMap{}
+	for who, settings := range options {
+		filtered[who] = map[string]string{}
+		for key, value := range settings {
+			if !slices.Contains(recoveryScheduleOptions, normalizer.Replace(key)) {
+				filtered[who][key] = value
+			}
+		}
+	}
+	return filtered
+}
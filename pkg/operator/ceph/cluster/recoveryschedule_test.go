@@ -0,0 +1,247 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestRecoverySchedule() cephv1.RecoveryScheduleSpec {
+	two := 2
+	eight := 8
+	return cephv1.RecoveryScheduleSpec{
+		TimeZone: "Europe/Paris",
+		Windows: []cephv1.RecoveryWindow{
+			{
+				Name:    "business-hours",
+				Days:    []cephv1.RecoveryWindowDay{"Mon", "Tue", "Wed", "Thu", "Fri"},
+				Start:   "08:00",
+				End:     "18:00",
+				Profile: cephv1.RecoveryProfile{MClockProfile: cephv1.MClockProfileHighClientOps, MaxBackfills: &two},
+			},
+			{
+				Name:    "night",
+				Days:    []cephv1.RecoveryWindowDay{"Fri"},
+				Start:   "22:00",
+				End:     "06:00",
+				Profile: cephv1.RecoveryProfile{MClockProfile: cephv1.MClockProfileHighRecoveryOps, MaxBackfills: &eight, RecoveryMaxActive: &eight},
+			},
+		},
+		Default: &cephv1.RecoveryProfile{MClockProfile: cephv1.MClockProfileBalanced},
+	}
+}
+
+func TestActiveRecoveryWindow(t *testing.T) {
+	schedule := newTestRecoverySchedule()
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		time time.Time
+		want string
+	}{
+		// 2026-10-16 is a Friday
+		{"business hours", time.Date(2026, 10, 16, 9, 30, 0, 0, paris), "business-hours"},
+		{"start is included", time.Date(2026, 10, 16, 8, 0, 0, 0, paris), "business-hours"},
+		{"end is excluded", time.Date(2026, 10, 16, 18, 0, 0, 0, paris), ""},
+		{"night", time.Date(2026, 10, 16, 23, 0, 0, 0, paris), "night"},
+		{"night on the next day", time.Date(2026, 10, 17, 5, 59, 0, 0, paris), "night"},
+		{"after the night", time.Date(2026, 10, 17, 6, 0, 0, 0, paris), ""},
+		{"week end", time.Date(2026, 10, 17, 9, 30, 0, 0, paris), ""},
+		{"night of another day", time.Date(2026, 10, 14, 23, 0, 0, 0, paris), ""},
+		// 07:30 UTC is 09:30 in Paris
+		{"time zone", time.Date(2026, 10, 16, 7, 30, 0, 0, time.UTC), "business-hours"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := activeRecoveryWindow(schedule, tt.time)
+			assert.NoError(t, err)
+			if tt.want == "" {
+				assert.Nil(t, window)
+			} else {
+				require.NotNil(t, window)
+				assert.Equal(t, tt.want, window.Name)
+			}
+		})
+	}
+
+	t.Run("utc by default", func(t *testing.T) {
+		schedule := newTestRecoverySchedule()
+		schedule.TimeZone = ""
+		window, err := activeRecoveryWindow(schedule, time.Date(2026, 10, 16, 7, 30, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.Nil(t, window)
+	})
+
+	t.Run("unknown time zone", func(t *testing.T) {
+		schedule := newTestRecoverySchedule()
+		schedule.TimeZone = "Nowhere/Town"
+		_, err := activeRecoveryWindow(schedule, time.Now())
+		assert.Error(t, err)
+	})
+}
+
+func TestRecoveryProfileSettings(t *testing.T) {
+	assert.Equal(t, map[string]string{"osd_mclock_profile": "balanced"},
+		recoveryProfileSettings(&cephv1.RecoveryProfile{MClockProfile: cephv1.MClockProfileBalanced}))
+
+	four := 4
+	assert.Equal(t, map[string]string{"osd_max_backfills": "4", "osd_mclock_override_recovery_settings": "true"},
+		recoveryProfileSettings(&cephv1.RecoveryProfile{MaxBackfills: &four}))
+}
+
+func TestWithoutRecoveryOptions(t *testing.T) {
+	options := map[string]map[string]string{
+		"osd":    {"osd max backfills": "1", "osd_op_num_shards": "8"},
+		"global": {"osd_mclock_profile": "balanced"},
+	}
+	assert.Equal(t, map[string]map[string]string{
+		"osd":    {"osd_op_num_shards": "8"},
+		"global": {},
+	}, withoutRecoveryOptions(options))
+}
+
+func TestApplyRecoverySchedule(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+
+	osdConfig := map[string]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "config" && args[1] == "assimilate-conf":
+				input, err := os.ReadFile(args[3])
+				require.NoError(t, err)
+				file, err := ini.Load(input)
+				require.NoError(t, err)
+				for _, key := range file.Section("osd").Keys() {
+					osdConfig[key.Name()] = key.Value()
+				}
+			case args[0] == "config" && args[1] == "rm":
+				assert.Equal(t, "osd", args[2])
+				delete(osdConfig, args[3])
+			}
+			return "", nil
+		},
+	}
+
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: namespace},
+		Spec:       cephv1.ClusterSpec{RecoverySchedule: newTestRecoverySchedule()},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(cephCluster).WithStatusSubresource(cephCluster).Build()
+
+	clusterInfo := cephclient.AdminTestClusterInfo(namespace)
+	clusterInfo.SetName("my-cluster")
+	scheduler := newRecoveryScheduler(&clusterd.Context{Client: cl, Executor: executor, ConfigDir: t.TempDir()}, clusterInfo)
+	assert.Equal(t, defaultRecoveryScheduleInterval, scheduler.interval)
+
+	getStatus := func() *cephv1.RecoveryStatus {
+		cluster := &cephv1.CephCluster{}
+		require.NoError(t, cl.Get(ctx, clusterInfo.NamespacedName(), cluster))
+		return cluster.Status.Recovery
+	}
+	updateCluster := func(update func(cluster *cephv1.CephCluster)) {
+		cluster := &cephv1.CephCluster{}
+		require.NoError(t, cl.Get(ctx, clusterInfo.NamespacedName(), cluster))
+		update(cluster)
+		require.NoError(t, cl.Update(ctx, cluster))
+	}
+	friday := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	t.Run("window", func(t *testing.T) {
+		require.NoError(t, scheduler.applySchedule(friday.Add(21*time.Hour)))
+		assert.Equal(t, map[string]string{
+			"osd_mclock_profile": "high_recovery_ops", "osd_max_backfills": "8", "osd_recovery_max_active": "8", "osd_mclock_override_recovery_settings": "true",
+		}, osdConfig)
+		status := getStatus()
+		require.NotNil(t, status)
+		assert.Equal(t, "night", status.Window)
+		assert.Equal(t, cephv1.MClockProfileHighRecoveryOps, status.Profile.MClockProfile)
+		transition := status.LastTransitionTime
+
+		// the profile is applied again in the same window to restore the changed options
+		osdConfig["osd_max_backfills"] = "3"
+		require.NoError(t, scheduler.applySchedule(friday.Add(22*time.Hour)))
+		assert.Equal(t, "8", osdConfig["osd_max_backfills"])
+		assert.Equal(t, transition, getStatus().LastTransitionTime)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		// 12:00 UTC on saturday is outside of the windows
+		require.NoError(t, scheduler.applySchedule(friday.Add(36*time.Hour)))
+		assert.Equal(t, map[string]string{"osd_mclock_profile": "balanced"}, osdConfig)
+		assert.Equal(t, "default", getStatus().Window)
+
+		// an option that is not set by the profile is not removed
+		osdConfig["osd_max_backfills"] = "3"
+		require.NoError(t, scheduler.applySchedule(friday.Add(37*time.Hour)))
+		assert.Equal(t, map[string]string{"osd_mclock_profile": "balanced", "osd_max_backfills": "3"}, osdConfig)
+	})
+
+	t.Run("force fast recovery", func(t *testing.T) {
+		updateCluster(func(cluster *cephv1.CephCluster) {
+			cluster.Annotations = map[string]string{cephv1.ForceFastRecoveryAnnotationKey: "true"}
+		})
+		require.NoError(t, scheduler.applySchedule(friday.Add(36*time.Hour)))
+		assert.Equal(t, map[string]string{"osd_mclock_profile": "high_recovery_ops", "osd_max_backfills": "3"}, osdConfig)
+		assert.Equal(t, "force-fast-recovery", getStatus().Window)
+
+		updateCluster(func(cluster *cephv1.CephCluster) {
+			cluster.Annotations = nil
+		})
+		require.NoError(t, scheduler.applySchedule(friday.Add(36*time.Hour)))
+		assert.Equal(t, "default", getStatus().Window)
+	})
+
+	t.Run("schedule removed", func(t *testing.T) {
+		updateCluster(func(cluster *cephv1.CephCluster) {
+			cluster.Spec.RecoverySchedule = cephv1.RecoveryScheduleSpec{}
+		})
+		// only the options set by the schedule are removed
+		require.NoError(t, scheduler.applySchedule(friday.Add(36*time.Hour)))
+		assert.Equal(t, map[string]string{"osd_max_backfills": "3"}, osdConfig)
+		assert.Nil(t, getStatus())
+
+		// nothing to do without a schedule
+		osdConfig["osd_max_backfills"] = "4"
+		require.NoError(t, scheduler.applySchedule(friday.Add(36*time.Hour)))
+		assert.Equal(t, "4", osdConfig["osd_max_backfills"])
+	})
+}
+
+func TestRecoveryScheduleMonitoringEnabled(t *testing.T) {
+	assert.True(t, isMonitoringEnabled("recovery", &cephv1.ClusterSpec{}))
+	assert.False(t, isMonitoringEnabled("recovery", &cephv1.ClusterSpec{External: cephv1.ExternalSpec{Enable: true}}))
+}
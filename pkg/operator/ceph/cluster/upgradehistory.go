@@ -0,0 +1,303 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/log"
+	rookversion "github.com/rook/rook/pkg/version"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// maxUpgradeHistoryEntries is the number of upgrades kept in the CephCluster status
+	maxUpgradeHistoryEntries = 10
+	// maxUpgradeHealthWarnings is the number of health checks recorded for an upgrade
+	maxUpgradeHealthWarnings = 20
+)
+
+// upgradeDaemonTypes are the daemon types timed in the upgrade history, in the order of the upgrade
+var upgradeDaemonTypes = []string{config.MonType, config.MgrType, config.OsdType, config.MdsType, config.RgwType}
+
+// recordUpgradeStart adds an entry for the upgrade to the target version to the upgrade history.
+// The upgrade is recorded as failed if the upgrade checks refused it. An upgrade that is still in
+// progress for the same image is resumed, and an upgrade in progress for another image is marked as
+// superseded.
+func (c *cluster) recordUpgradeStart(target cephver.CephVersion, runningVersions cephv1.CephDaemonsVersions, refused error) error {
+	entry, err := c.newUpgradeHistoryEntry(target, runningVersions, c.daemonsRookVersion())
+	if err != nil {
+		return err
+	}
+	if refused != nil {
+		entry.Result = cephv1.UpgradeFailed
+		entry.EndTime = entry.StartTime
+		entry.Message = refused.Error()
+	}
+	return c.addUpgradeHistoryEntry(entry)
+}
+
+// recordRookUpgradeStart adds an entry to the upgrade history if the daemons were last updated by
+// another Rook version while the Ceph version does not change
+func (c *cluster) recordRookUpgradeStart(version cephver.CephVersion, runningVersions cephv1.CephDaemonsVersions) error {
+	fromRookVersion := c.daemonsRookVersion()
+	if fromRookVersion == "" || fromRookVersion == rookversion.Version {
+		return nil
+	}
+	entry, err := c.newUpgradeHistoryEntry(version, runningVersions, fromRookVersion)
+	if err != nil {
+		return err
+	}
+	// the daemons are not timed since their ceph version does not change
+	entry.Daemons = nil
+	return c.addUpgradeHistoryEntry(entry)
+}
+
+// recordRookUpgradeEnd marks the Rook upgrade in progress, if any, as succeeded once all the daemons
+// were reconciled
+func (c *cluster) recordRookUpgradeEnd() error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cephCluster := &cephv1.CephCluster{}
+		if err := c.context.Client.Get(c.ClusterInfo.Context, c.namespacedName, cephCluster); err != nil {
+			return errors.Wrapf(err, "failed to get cluster %v", c.namespacedName)
+		}
+		history := cephCluster.Status.UpgradeHistory
+		n := len(history)
+		if n == 0 || history[n-1].Result != cephv1.UpgradeInProgress || !isRookUpgrade(history[n-1]) {
+			return nil
+		}
+		history[n-1].Result = cephv1.UpgradeSucceeded
+		history[n-1].EndTime = &metav1.Time{Time: time.Now()}
+		return reporting.UpdateStatus(c.context.Client, cephCluster)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to record the end of the rook upgrade")
+	}
+	return nil
+}
+
+// newUpgradeHistoryEntry returns an entry for an upgrade in progress to the target version
+func (c *cluster) newUpgradeHistoryEntry(target cephver.CephVersion, runningVersions cephv1.CephDaemonsVersions, fromRookVersion string) (cephv1.UpgradeHistoryEntry, error) {
+	from, err := oldestCephVersion(runningVersions.Overall)
+	if err != nil {
+		return cephv1.UpgradeHistoryEntry{}, err
+	}
+	entry := cephv1.UpgradeHistoryEntry{
+		Image:           c.Spec.CephVersion.Image,
+		FromCephVersion: opcontroller.GetCephVersionLabel(*from),
+		ToCephVersion:   opcontroller.GetCephVersionLabel(target),
+		FromRookVersion: fromRookVersion,
+		ToRookVersion:   rookversion.Version,
+		StartTime:       &metav1.Time{Time: time.Now()},
+		Result:          cephv1.UpgradeInProgress,
+	}
+	for _, daemonType := range upgradeDaemonTypes {
+		if len(daemonTypeVersions(&runningVersions, daemonType)) > 0 {
+			entry.Daemons = append(entry.Daemons, cephv1.UpgradeDaemonTiming{DaemonType: daemonType})
+		}
+	}
+	return entry, nil
+}
+
+// addUpgradeHistoryEntry adds the upgrade to the upgrade history in the CephCluster status
+func (c *cluster) addUpgradeHistoryEntry(entry cephv1.UpgradeHistoryEntry) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cephCluster := &cephv1.CephCluster{}
+		if err := c.context.Client.Get(c.ClusterInfo.Context, c.namespacedName, cephCluster); err != nil {
+			return errors.Wrapf(err, "failed to get cluster %v", c.namespacedName)
+		}
+		cephCluster.Status.UpgradeHistory = addUpgradeHistoryEntry(cephCluster.Status.UpgradeHistory, entry)
+		return reporting.UpdateStatus(c.context.Client, cephCluster)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to record the start of the upgrade")
+	}
+	return nil
+}
+
+// recordUpgradeProgress updates the upgrade in progress with the running versions of the daemons
+func (c *cluster) recordUpgradeProgress(versions *cephv1.CephDaemonsVersions) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cephCluster := &cephv1.CephCluster{}
+		if err := c.context.Client.Get(c.ClusterInfo.Context, c.namespacedName, cephCluster); err != nil {
+			return errors.Wrapf(err, "failed to get cluster %v", c.namespacedName)
+		}
+		if !updateUpgradeHistory(cephCluster.Status.UpgradeHistory, versions, nil, time.Now()) {
+			return nil
+		}
+		return reporting.UpdateStatus(c.context.Client, cephCluster)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to record the progress of the upgrade")
+	}
+	return nil
+}
+
+// daemonsRookVersion returns the Rook version that last updated the mons
+func (c *cluster) daemonsRookVersion() string {
+	selector := fmt.Sprintf("%s=%s", k8sutil.AppAttr, mon.AppName)
+	deployments, err := k8sutil.GetDeployments(c.ClusterInfo.Context, c.context.Clientset, c.Namespace, selector)
+	if err != nil {
+		log.NamespacedDebug(c.Namespace, logger, "failed to get the rook version of the mons. %v", err)
+		return ""
+	}
+	for _, d := range deployments.Items {
+		if version, ok := d.Labels[k8sutil.RookVersionLabelKey]; ok {
+			return version
+		}
+	}
+	return ""
+}
+
+// addUpgradeHistoryEntry appends the upgrade to the history and drops the oldest upgrades. An upgrade
+// in progress for the same image is resumed, or marked as failed if the new entry failed. An upgrade
+// that keeps being refused is recorded only once.
+func addUpgradeHistoryEntry(history []cephv1.UpgradeHistoryEntry, entry cephv1.UpgradeHistoryEntry) []cephv1.UpgradeHistoryEntry {
+	if n := len(history); n > 0 {
+		last := &history[n-1]
+		switch {
+		case last.Image == entry.Image && last.Result == cephv1.UpgradeInProgress:
+			// the upgrade continues in a later reconcile
+			if entry.Result == cephv1.UpgradeFailed {
+				last.Result = cephv1.UpgradeFailed
+				last.EndTime = entry.EndTime
+				last.Message = entry.Message
+			}
+			return history
+		case last.Image == entry.Image && last.Result == cephv1.UpgradeFailed && entry.Result == cephv1.UpgradeFailed:
+			// the upgrade is still refused
+			last.Message = entry.Message
+			return history
+		case last.Result == cephv1.UpgradeInProgress:
+			last.Result = cephv1.UpgradeSuperseded
+			last.EndTime = entry.StartTime
+		}
+	}
+	history = append(history, entry)
+	if len(history) > maxUpgradeHistoryEntries {
+		history = history[len(history)-maxUpgradeHistoryEntries:]
+	}
+	return history
+}
+
+// isRookUpgrade returns true if the upgrade only changed the Rook version of the daemons
+func isRookUpgrade(entry cephv1.UpgradeHistoryEntry) bool {
+	return entry.FromCephVersion == entry.ToCephVersion
+}
+
+// updateUpgradeHistory updates the upgrade in progress, if any, with the running versions of the
+// daemons and the health checks of the cluster. The time spent by each daemon type is counted from
+// the end of the previous daemon type, or from the start of the upgrade. Returns true if the history
+// changed.
+func updateUpgradeHistory(history []cephv1.UpgradeHistoryEntry, versions *cephv1.CephDaemonsVersions, status *cephclient.CephStatus, now time.Time) bool {
+	n := len(history)
+	if n == 0 || history[n-1].Result != cephv1.UpgradeInProgress {
+		return false
+	}
+	entry := &history[n-1]
+	target, err := opcontroller.ExtractCephVersionFromLabel(entry.ToCephVersion)
+	if err != nil {
+		return false
+	}
+	changed := false
+
+	if status != nil {
+		for name, check := range status.Health.Checks {
+			if !strings.HasPrefix(check.Severity, "HEALTH_") || slices.Contains(entry.HealthWarnings, name) || len(entry.HealthWarnings) >= maxUpgradeHealthWarnings {
+				continue
+			}
+			entry.HealthWarnings = append(entry.HealthWarnings, name)
+			changed = true
+		}
+		sort.Strings(entry.HealthWarnings)
+	}
+
+	previousEnd := entry.StartTime
+	for i := range entry.Daemons {
+		daemon := &entry.Daemons[i]
+		updated, total := countDaemonsRunning(daemonTypeVersions(versions, daemon.DaemonType), *target)
+		if daemon.StartTime == nil && updated > 0 {
+			daemon.StartTime = &metav1.Time{Time: now}
+			changed = true
+		}
+		if daemon.EndTime == nil && total > 0 && updated == total {
+			daemon.EndTime = &metav1.Time{Time: now}
+			start := now
+			if previousEnd != nil {
+				start = previousEnd.Time
+			}
+			daemon.Duration = &metav1.Duration{Duration: max(now.Sub(start), 0).Round(time.Second)}
+			changed = true
+		}
+		if daemon.EndTime != nil && (previousEnd == nil || daemon.EndTime.After(previousEnd.Time)) {
+			previousEnd = daemon.EndTime
+		}
+	}
+
+	// a rook upgrade ends when all the daemons were reconciled
+	updated, total := countDaemonsRunning(versions.Overall, *target)
+	if total > 0 && updated == total && !isRookUpgrade(*entry) {
+		entry.Result = cephv1.UpgradeSucceeded
+		entry.EndTime = &metav1.Time{Time: now}
+		changed = true
+	}
+	return changed
+}
+
+// daemonTypeVersions returns the versions of the daemons of a type
+func daemonTypeVersions(versions *cephv1.CephDaemonsVersions, daemonType string) map[string]int {
+	switch daemonType {
+	case config.MonType:
+		return versions.Mon
+	case config.MgrType:
+		return versions.Mgr
+	case config.OsdType:
+		return versions.Osd
+	case config.MdsType:
+		return versions.Mds
+	case config.RgwType:
+		return versions.Rgw
+	}
+	return nil
+}
+
+// countDaemonsRunning returns the number of daemons running the version and the number of daemons.
+// The commit of the versions is not compared since it is not part of the version label.
+func countDaemonsRunning(versions map[string]int, target cephver.CephVersion) (updated, total int) {
+	for v, count := range versions {
+		total += count
+		version, err := cephver.ExtractCephVersion(v)
+		if err == nil && version.Major == target.Major && version.Minor == target.Minor && version.Extra == target.Extra && version.Build == target.Build {
+			updated += count
+		}
+	}
+	return updated, total
+}
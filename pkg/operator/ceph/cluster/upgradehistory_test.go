@@ -0,0 +1,212 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testop "github.com/rook/rook/pkg/operator/test"
+	rookversion "github.com/rook/rook/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	squidVersion    = "ceph version 19.2.3 (c92aebb279828e9c3c1f5d24613efca272649e62) squid (stable)"
+	tentacleVersion = "ceph version 20.2.0 (69f84cc2651aa259a15bc192ddaabd3baba07489) tentacle (stable)"
+)
+
+func TestRecordUpgradeStart(t *testing.T) {
+	ctx := context.TODO()
+	clientset := testop.New(t, 1)
+	_, err := clientset.AppsV1().Deployments("rook-ceph").Create(ctx, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:   "rook-ceph-mon-a",
+		Labels: map[string]string{k8sutil.AppAttr: "rook-ceph-mon", k8sutil.RookVersionLabelKey: "v1.18.0"},
+	}}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	cl := newTestCephClusterClient("rook-ceph")
+	c := &cluster{
+		context:        &clusterd.Context{Client: cl, Clientset: clientset},
+		ClusterInfo:    newTestClusterInfo("rook-ceph"),
+		Namespace:      "rook-ceph",
+		namespacedName: types.NamespacedName{Namespace: "rook-ceph", Name: "my-cluster"},
+		Spec:           &cephv1.ClusterSpec{CephVersion: cephv1.CephVersionSpec{Image: "quay.io/ceph/ceph:v20.2.0"}},
+	}
+	c.ClusterInfo.Context = ctx
+	running := cephv1.CephDaemonsVersions{
+		Mon:     map[string]int{squidVersion: 3},
+		Mgr:     map[string]int{squidVersion: 2},
+		Osd:     map[string]int{squidVersion: 6},
+		Overall: map[string]int{squidVersion: 11},
+	}
+
+	// the upgrade refused by the health check is recorded as failed, and only once
+	for i := 0; i < 2; i++ {
+		require.NoError(t, c.recordUpgradeStart(cephver.CephVersion{Major: 20, Minor: 2}, running, errors.New("not healthy")))
+	}
+	cephCluster := &cephv1.CephCluster{}
+	require.NoError(t, cl.Get(ctx, c.namespacedName, cephCluster))
+	require.Len(t, cephCluster.Status.UpgradeHistory, 1)
+	entry := cephCluster.Status.UpgradeHistory[0]
+	assert.Equal(t, cephv1.UpgradeFailed, entry.Result)
+	assert.Equal(t, "not healthy", entry.Message)
+	assert.Equal(t, entry.StartTime, entry.EndTime)
+
+	// the upgrade is retried once the health check passes
+	require.NoError(t, c.recordUpgradeStart(cephver.CephVersion{Major: 20, Minor: 2}, running, nil))
+	require.NoError(t, cl.Get(ctx, c.namespacedName, cephCluster))
+	require.Len(t, cephCluster.Status.UpgradeHistory, 2)
+	entry = cephCluster.Status.UpgradeHistory[1]
+	assert.Equal(t, "quay.io/ceph/ceph:v20.2.0", entry.Image)
+	assert.Equal(t, "19.2.3-0", entry.FromCephVersion)
+	assert.Equal(t, "20.2.0-0", entry.ToCephVersion)
+	assert.Equal(t, "v1.18.0", entry.FromRookVersion)
+	assert.Equal(t, rookversion.Version, entry.ToRookVersion)
+	assert.NotNil(t, entry.StartTime)
+	assert.Equal(t, cephv1.UpgradeInProgress, entry.Result)
+	assert.Equal(t, []cephv1.UpgradeDaemonTiming{{DaemonType: "mon"}, {DaemonType: "mgr"}, {DaemonType: "osd"}}, entry.Daemons)
+
+	// the upgrade is complete when all the daemons run the new version
+	running = cephv1.CephDaemonsVersions{
+		Mon:     map[string]int{tentacleVersion: 3},
+		Mgr:     map[string]int{tentacleVersion: 2},
+		Osd:     map[string]int{tentacleVersion: 6},
+		Overall: map[string]int{tentacleVersion: 11},
+	}
+	require.NoError(t, c.recordUpgradeProgress(&running))
+	require.NoError(t, cl.Get(ctx, c.namespacedName, cephCluster))
+	assert.Equal(t, cephv1.UpgradeSucceeded, cephCluster.Status.UpgradeHistory[1].Result)
+	assert.NotNil(t, cephCluster.Status.UpgradeHistory[1].EndTime)
+
+	// the rook upgrade is recorded when the mons were updated by another rook version
+	require.NoError(t, c.recordRookUpgradeStart(cephver.CephVersion{Major: 20, Minor: 2}, running))
+	require.NoError(t, c.recordUpgradeProgress(&running))
+	require.NoError(t, cl.Get(ctx, c.namespacedName, cephCluster))
+	require.Len(t, cephCluster.Status.UpgradeHistory, 3)
+	entry = cephCluster.Status.UpgradeHistory[2]
+	assert.Equal(t, "20.2.0-0", entry.FromCephVersion)
+	assert.Equal(t, "20.2.0-0", entry.ToCephVersion)
+	assert.Equal(t, "v1.18.0", entry.FromRookVersion)
+	assert.Empty(t, entry.Daemons)
+	assert.Equal(t, cephv1.UpgradeInProgress, entry.Result)
+
+	// the rook upgrade ends when all the daemons were reconciled
+	require.NoError(t, c.recordRookUpgradeEnd())
+	require.NoError(t, cl.Get(ctx, c.namespacedName, cephCluster))
+	assert.Equal(t, cephv1.UpgradeSucceeded, cephCluster.Status.UpgradeHistory[2].Result)
+	assert.NotNil(t, cephCluster.Status.UpgradeHistory[2].EndTime)
+}
+
+func TestAddUpgradeHistoryEntry(t *testing.T) {
+	now := &metav1.Time{Time: time.Now()}
+	history := []cephv1.UpgradeHistoryEntry{}
+	for i := 0; i < maxUpgradeHistoryEntries; i++ {
+		history = addUpgradeHistoryEntry(history, cephv1.UpgradeHistoryEntry{Image: fmt.Sprintf("image-%d", i), Result: cephv1.UpgradeSucceeded})
+	}
+	assert.Len(t, history, maxUpgradeHistoryEntries)
+
+	// the oldest upgrade is dropped
+	history = addUpgradeHistoryEntry(history, cephv1.UpgradeHistoryEntry{Image: "image-a", StartTime: now, Result: cephv1.UpgradeInProgress})
+	assert.Len(t, history, maxUpgradeHistoryEntries)
+	assert.Equal(t, "image-1", history[0].Image)
+	assert.Equal(t, "image-a", history[maxUpgradeHistoryEntries-1].Image)
+
+	// the upgrade in progress for the same image is resumed
+	history = addUpgradeHistoryEntry(history, cephv1.UpgradeHistoryEntry{Image: "image-a", Result: cephv1.UpgradeInProgress})
+	assert.Equal(t, now, history[maxUpgradeHistoryEntries-1].StartTime)
+	assert.Equal(t, "image-1", history[0].Image)
+
+	// the upgrade in progress is superseded by an upgrade to another image
+	later := &metav1.Time{Time: now.Add(time.Hour)}
+	history = addUpgradeHistoryEntry(history, cephv1.UpgradeHistoryEntry{Image: "image-b", StartTime: later, Result: cephv1.UpgradeInProgress})
+	assert.Equal(t, cephv1.UpgradeSuperseded, history[maxUpgradeHistoryEntries-2].Result)
+	assert.Equal(t, later, history[maxUpgradeHistoryEntries-2].EndTime)
+	assert.Equal(t, cephv1.UpgradeInProgress, history[maxUpgradeHistoryEntries-1].Result)
+
+	// the upgrade in progress fails when the upgrade checks refuse it later
+	failed := cephv1.UpgradeHistoryEntry{Image: "image-b", StartTime: later, EndTime: later, Result: cephv1.UpgradeFailed, Message: "not healthy"}
+	history = addUpgradeHistoryEntry(history, failed)
+	assert.Equal(t, "image-b", history[maxUpgradeHistoryEntries-1].Image)
+	assert.Equal(t, cephv1.UpgradeFailed, history[maxUpgradeHistoryEntries-1].Result)
+	assert.Equal(t, "not healthy", history[maxUpgradeHistoryEntries-1].Message)
+	assert.Equal(t, cephv1.UpgradeSuperseded, history[maxUpgradeHistoryEntries-2].Result)
+}
+
+func TestUpdateUpgradeHistory(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := []cephv1.UpgradeHistoryEntry{{
+		ToCephVersion: "20.2.0-0",
+		StartTime:     &metav1.Time{Time: start},
+		Result:        cephv1.UpgradeInProgress,
+		Daemons:       []cephv1.UpgradeDaemonTiming{{DaemonType: "mon"}, {DaemonType: "mgr"}, {DaemonType: "osd"}},
+	}}
+	status := &cephclient.CephStatus{}
+	status.Health.Checks = map[string]cephclient.CheckMessage{
+		"OSD_DOWN": {Severity: "HEALTH_WARN"},
+		"error":    {Severity: "Urgent"},
+	}
+
+	// the mons are updated
+	versions := &cephv1.CephDaemonsVersions{
+		Mon:     map[string]int{tentacleVersion: 3},
+		Mgr:     map[string]int{squidVersion: 2},
+		Osd:     map[string]int{squidVersion: 6},
+		Overall: map[string]int{tentacleVersion: 3, squidVersion: 8},
+	}
+	assert.True(t, updateUpgradeHistory(history, versions, status, start.Add(3*time.Minute)))
+	entry := history[0]
+	assert.Equal(t, []string{"OSD_DOWN"}, entry.HealthWarnings)
+	assert.Equal(t, 3*time.Minute, entry.Daemons[0].Duration.Duration)
+	assert.Nil(t, entry.Daemons[1].StartTime)
+	assert.Equal(t, cephv1.UpgradeInProgress, entry.Result)
+	assert.False(t, updateUpgradeHistory(history, versions, status, start.Add(4*time.Minute)))
+
+	// the mgrs are updated and the osds are being updated
+	versions.Mgr = map[string]int{tentacleVersion: 2}
+	versions.Osd = map[string]int{tentacleVersion: 2, squidVersion: 4}
+	status.Health.Checks["PG_DEGRADED"] = cephclient.CheckMessage{Severity: "HEALTH_WARN"}
+	assert.True(t, updateUpgradeHistory(history, versions, status, start.Add(5*time.Minute)))
+	entry = history[0]
+	assert.Equal(t, []string{"OSD_DOWN", "PG_DEGRADED"}, entry.HealthWarnings)
+	assert.Equal(t, 2*time.Minute, entry.Daemons[1].Duration.Duration)
+	assert.Equal(t, start.Add(5*time.Minute), entry.Daemons[2].StartTime.Time)
+	assert.Nil(t, entry.Daemons[2].EndTime)
+
+	// the upgrade succeeds when all the daemons are updated
+	versions.Osd = map[string]int{tentacleVersion: 6}
+	versions.Overall = map[string]int{tentacleVersion: 11}
+	assert.True(t, updateUpgradeHistory(history, versions, nil, start.Add(25*time.Minute)))
+	entry = history[0]
+	assert.Equal(t, 20*time.Minute, entry.Daemons[2].Duration.Duration)
+	assert.Equal(t, cephv1.UpgradeSucceeded, entry.Result)
+	assert.Equal(t, start.Add(25*time.Minute), entry.EndTime.Time)
+
+	// the completed upgrade is not changed
+	assert.False(t, updateUpgradeHistory(history, versions, status, start.Add(time.Hour)))
+}
@@ -0,0 +1,383 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/util/log"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// maxReleasesPerUpgrade is the number of releases that ceph supports upgrading across at once
+const maxReleasesPerUpgrade = 2
+
+// cephReleases are the major versions of the ceph releases by name, as reported by require_osd_release
+var cephReleases = map[string]int{
+	"pacific":  16,
+	"quincy":   17,
+	"reef":     18,
+	"squid":    cephver.Squid.Major,
+	"tentacle": cephver.Tentacle.Major,
+	"umbrella": cephver.Umbrella.Major,
+}
+
+// removedMgrModules are the mgr modules removed from ceph, by the major version of the removal
+var removedMgrModules = map[string]int{
+	"restful": cephver.Tentacle.Major,
+	"zabbix":  cephver.Tentacle.Major,
+}
+
+// deprecatedSetting is a value of a ceph config option that a ceph release does not support anymore
+type deprecatedSetting struct {
+	option    string
+	value     string
+	removedIn int
+	message   string
+}
+
+var deprecatedSettings = []deprecatedSetting{
+	{option: "rgw_frontends", value: "civetweb", removedIn: 17, message: "the civetweb frontend was removed in quincy, use beast instead"},
+	{option: "osd_objectstore", value: "filestore", removedIn: 18, message: "filestore was removed in reef, use bluestore instead"},
+}
+
+// runUpgradePreflight runs all the upgrade checks up front without updating any daemon, and reports
+// the result in the CephCluster status
+func (c *cluster) runUpgradePreflight(target cephver.CephVersion, runningVersions cephv1.CephDaemonsVersions) error {
+	log.NamespacedInfo(c.Namespace, logger, "running the upgrade preflight checks for ceph version %q without updating the daemons", target.String())
+	report := c.upgradePreflightReport(target, runningVersions)
+	for _, check := range report.Checks {
+		log.NamespacedInfo(c.Namespace, logger, "upgrade preflight check %q: %s. %s", check.Name, check.Result, check.Message)
+	}
+	return c.updateUpgradePreflightStatus(report)
+}
+
+// useRunningCephImage sets the image of the running ceph daemons in the cluster spec, so the cluster
+// is reconciled without updating the daemons while the upgrade preflight runs. Returns the running
+// ceph version.
+func (c *cluster) useRunningCephImage() (*cephver.CephVersion, error) {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.ClusterInfo.Context, c.namespacedName, cephCluster); err != nil {
+		return nil, errors.Wrapf(err, "failed to get cluster %v", c.namespacedName)
+	}
+	running := cephCluster.Status.CephVersion
+	if running == nil || running.Image == "" || running.Version == "" {
+		return nil, errors.New("the running ceph image is unknown, the cluster cannot be reconciled while cephVersion.preflightOnly is set")
+	}
+	version, err := cephver.ExtractCephVersion("ceph version " + running.Version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the running ceph version %q", running.Version)
+	}
+	log.NamespacedInfo(c.Namespace, logger, "reconciling the cluster with the running image %q since cephVersion.preflightOnly is set", running.Image)
+	c.Spec.CephVersion.Image = running.Image
+	return version, nil
+}
+
+// upgradePreflightReport runs the upgrade checks
+func (c *cluster) upgradePreflightReport(target cephver.CephVersion, runningVersions cephv1.CephDaemonsVersions) *cephv1.UpgradePreflightStatus {
+	report := &cephv1.UpgradePreflightStatus{
+		Image:         c.Spec.CephVersion.Image,
+		TargetVersion: target.String(),
+		LastChecked:   &metav1.Time{Time: time.Now()},
+	}
+
+	running, err := oldestCephVersion(runningVersions.Overall)
+	if err != nil {
+		report.Checks = append(report.Checks, preflightCheck("VersionCompatibility", cephv1.UpgradePreflightFailed, "%v", err))
+	} else {
+		report.RunningVersion = running.String()
+		report.Checks = append(report.Checks, c.versionCompatibilityCheck(target, *running, len(runningVersions.Overall)))
+	}
+	report.Checks = append(report.Checks, c.cephHealthCheck(), c.pgHealthCheck(), c.requireOSDReleaseCheck(target))
+	report.Checks = append(report.Checks, c.monOkToStopChecks()...)
+	report.Checks = append(report.Checks, c.osdOkToStopChecks()...)
+	report.Checks = append(report.Checks, c.deprecatedSettingsCheck(target), c.removedFeaturesCheck(target))
+
+	report.Result = cephv1.UpgradePreflightPassed
+	for _, check := range report.Checks {
+		if check.Result == cephv1.UpgradePreflightFailed {
+			report.Result = cephv1.UpgradePreflightFailed
+			break
+		}
+		if check.Result == cephv1.UpgradePreflightWarning {
+			report.Result = cephv1.UpgradePreflightWarning
+		}
+	}
+	return report
+}
+
+func preflightCheck(name string, result cephv1.UpgradePreflightResult, message string, args ...any) cephv1.UpgradePreflightCheck {
+	return cephv1.UpgradePreflightCheck{Name: name, Result: result, Message: fmt.Sprintf(message, args...)}
+}
+
+// oldestCephVersion returns the oldest version of the "ceph versions" output
+func oldestCephVersion(versions map[string]int) (*cephver.CephVersion, error) {
+	var oldest *cephver.CephVersion
+	for v := range versions {
+		version, err := cephver.ExtractCephVersion(v)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to extract the running ceph version")
+		}
+		if oldest == nil || cephver.IsInferior(*version, *oldest) {
+			oldest = version
+		}
+	}
+	if oldest == nil {
+		return nil, errors.New("failed to find the running ceph version")
+	}
+	return oldest, nil
+}
+
+// versionCompatibilityCheck checks that ceph supports the upgrade from the running version
+func (c *cluster) versionCompatibilityCheck(target, running cephver.CephVersion, runningVersionCount int) cephv1.UpgradePreflightCheck {
+	const name = "VersionCompatibility"
+	if cephver.IsInferior(target, running) {
+		return preflightCheck(name, cephv1.UpgradePreflightFailed, "downgrading from %q to %q is not supported", running.String(), target.String())
+	}
+	if target.Major-running.Major > maxReleasesPerUpgrade {
+		return preflightCheck(name, cephv1.UpgradePreflightFailed, "upgrading from %q to %q skips more than %d releases, upgrade to an intermediate release first",
+			running.String(), target.String(), maxReleasesPerUpgrade)
+	}
+	if runningVersionCount > 1 {
+		return preflightCheck(name, cephv1.UpgradePreflightWarning, "%d ceph versions are running, the oldest is %q", runningVersionCount, running.String())
+	}
+	if !target.Supported() {
+		return preflightCheck(name, cephv1.UpgradePreflightWarning, "ceph version %q is not supported, it is allowed by allowUnsupported", target.String())
+	}
+	return preflightCheck(name, cephv1.UpgradePreflightPassed, "upgrading from %q to %q is supported", running.String(), target.String())
+}
+
+// cephHealthCheck checks the health of ceph, which must not be in error for the upgrade to start
+func (c *cluster) cephHealthCheck() cephv1.UpgradePreflightCheck {
+	const name = "CephHealth"
+	status, err := cephclient.Status(c.context, c.ClusterInfo)
+	if err != nil {
+		return preflightCheck(name, cephv1.UpgradePreflightFailed, "failed to get the ceph status. %v", err)
+	}
+	checks := []string{}
+	for check := range status.Health.Checks {
+		checks = append(checks, check)
+	}
+	slices.Sort(checks)
+
+	switch status.Health.Status {
+	case "HEALTH_OK":
+		return preflightCheck(name, cephv1.UpgradePreflightPassed, "ceph is healthy")
+	case "HEALTH_WARN":
+		return preflightCheck(name, cephv1.UpgradePreflightWarning, "ceph health is HEALTH_WARN: %s", strings.Join(checks, ", "))
+	}
+	if c.Spec.SkipUpgradeChecks {
+		return preflightCheck(name, cephv1.UpgradePreflightWarning, "ceph health is %s, the upgrade is forced by skipUpgradeChecks: %s", status.Health.Status, strings.Join(checks, ", "))
+	}
+	return preflightCheck(name, cephv1.UpgradePreflightFailed, "ceph health is %s, the upgrade is refused: %s", status.Health.Status, strings.Join(checks, ", "))
+}
+
+// pgHealthCheck checks that the PGs are clean, since the daemons are only updated while the PGs are clean
+func (c *cluster) pgHealthCheck() cephv1.UpgradePreflightCheck {
+	const name = "PGHealth"
+	msg, clean, err := cephclient.IsClusterClean(c.context, c.ClusterInfo, c.Spec.DisruptionManagement.PGHealthyRegex)
+	if err != nil {
+		return preflightCheck(name, cephv1.UpgradePreflightFailed, "%s. %v", msg, err)
+	}
+	if !clean {
+		return preflightCheck(name, cephv1.UpgradePreflightFailed, "%s", msg)
+	}
+	return preflightCheck(name, cephv1.UpgradePreflightPassed, "%s", msg)
+}
+
+// requireOSDReleaseCheck checks that the OSDs do not run a release too old for the target version
+func (c *cluster) requireOSDReleaseCheck(target cephver.CephVersion) cephv1.UpgradePreflightCheck {
+	const name = "RequireOSDRelease"
+	osdDump, err := cephclient.GetOSDDump(c.context, c.ClusterInfo)
+	if err != nil {
+		return preflightCheck(name, cephv1.UpgradePreflightFailed, "failed to get the osd dump. %v", err)
+	}
+	release, ok := cephReleases[osdDump.RequireOSDRelease]
+	if !ok {
+		return preflightCheck(name, cephv1.UpgradePreflightWarning, "unknown require_osd_release %q", osdDump.RequireOSDRelease)
+	}
+	if target.Major-release > maxReleasesPerUpgrade {
+		return preflightCheck(name, cephv1.UpgradePreflightFailed, "require_osd_release is %q, upgrading to %q requires at least the release %d",
+			osdDump.RequireOSDRelease, target.String(), target.Major-maxReleasesPerUpgrade)
+	}
+	return preflightCheck(name, cephv1.UpgradePreflightPassed, "require_osd_release is %q", osdDump.RequireOSDRelease)
+}
+
+// monOkToStopChecks checks that each mon can be stopped without losing the quorum
+func (c *cluster) monOkToStopChecks() []cephv1.UpgradePreflightCheck {
+	monStatus, err := cephclient.GetMonQuorumStatus(c.context, c.ClusterInfo)
+	if err != nil {
+		return []cephv1.UpgradePreflightCheck{preflightCheck("OkToStop/mon", cephv1.UpgradePreflightFailed, "failed to get the mon quorum status. %v", err)}
+	}
+	if len(monStatus.MonMap.Mons) < 3 {
+		return []cephv1.UpgradePreflightCheck{preflightCheck("OkToStop/mon", cephv1.UpgradePreflightWarning,
+			"the cluster has fewer than 3 mons, the mons are updated in best-effort")}
+	}
+
+	checks := []cephv1.UpgradePreflightCheck{}
+	for _, mon := range monStatus.MonMap.Mons {
+		name := "OkToStop/mon/" + mon.Name
+		if err := cephclient.OkToStopDaemons(c.context, c.ClusterInfo, "mon", []string{mon.Name}); err != nil {
+			checks = append(checks, preflightCheck(name, cephv1.UpgradePreflightFailed, "%v", err))
+			continue
+		}
+		checks = append(checks, preflightCheck(name, cephv1.UpgradePreflightPassed, "mon %q is ok to stop", mon.Name))
+	}
+	return checks
+}
+
+// osdOkToStopChecks checks that the OSDs of each host can be stopped together without making PGs unavailable
+func (c *cluster) osdOkToStopChecks() []cephv1.UpgradePreflightCheck {
+	if !cephclient.OSDUpdateShouldCheckOkToStop(c.context, c.ClusterInfo) {
+		return []cephv1.UpgradePreflightCheck{preflightCheck("OkToStop/osd", cephv1.UpgradePreflightWarning,
+			"the cluster has fewer than 3 osds, the osds are updated in best-effort")}
+	}
+	tree, err := cephclient.HostTree(c.context, c.ClusterInfo)
+	if err != nil {
+		return []cephv1.UpgradePreflightCheck{preflightCheck("OkToStop/osd", cephv1.UpgradePreflightFailed, "failed to get the osd tree. %v", err)}
+	}
+
+	checks := []cephv1.UpgradePreflightCheck{}
+	for _, node := range tree.Nodes {
+		if node.Type != "host" || len(node.Children) == 0 {
+			continue
+		}
+		osdIDs := []string{}
+		for _, id := range node.Children {
+			osdIDs = append(osdIDs, strconv.Itoa(id))
+		}
+		name := "OkToStop/host/" + node.Name
+		if err := cephclient.OkToStopDaemons(c.context, c.ClusterInfo, "osd", osdIDs); err != nil {
+			checks = append(checks, preflightCheck(name, cephv1.UpgradePreflightFailed, "%v", err))
+			continue
+		}
+		checks = append(checks, preflightCheck(name, cephv1.UpgradePreflightPassed, "the %d osds of host %q are ok to stop", len(osdIDs), node.Name))
+	}
+	slices.SortFunc(checks, func(a, b cephv1.UpgradePreflightCheck) int { return strings.Compare(a.Name, b.Name) })
+	return checks
+}
+
+// deprecatedSettingsCheck checks the central ceph config for settings that the target version does not support
+func (c *cluster) deprecatedSettingsCheck(target cephver.CephVersion) cephv1.UpgradePreflightCheck {
+	const name = "DeprecatedSettings"
+	options, err := config.GetMonStore(c.context, c.ClusterInfo).GetAll()
+	if err != nil {
+		return preflightCheck(name, cephv1.UpgradePreflightFailed, "failed to get the central ceph config. %v", err)
+	}
+
+	result := cephv1.UpgradePreflightPassed
+	messages := []string{}
+	for _, option := range options {
+		for _, setting := range deprecatedSettings {
+			if strings.ReplaceAll(option.Option, " ", "_") != setting.option || !strings.Contains(option.Value, setting.value) {
+				continue
+			}
+			if target.Major >= setting.removedIn {
+				result = cephv1.UpgradePreflightFailed
+			} else if result == cephv1.UpgradePreflightPassed {
+				result = cephv1.UpgradePreflightWarning
+			}
+			messages = append(messages, fmt.Sprintf("%s/%s: %s", option.Who, option.Option, setting.message))
+		}
+	}
+	if len(messages) == 0 {
+		return preflightCheck(name, result, "no deprecated settings")
+	}
+	return preflightCheck(name, result, "%s", strings.Join(messages, "; "))
+}
+
+// removedFeaturesCheck checks for the features in use that are removed in the target version
+func (c *cluster) removedFeaturesCheck(target cephver.CephVersion) cephv1.UpgradePreflightCheck {
+	const name = "RemovedFeatures"
+	messages := []string{}
+
+	modules, err := cephclient.GetMgrModules(c.context, c.ClusterInfo)
+	if err != nil {
+		return preflightCheck(name, cephv1.UpgradePreflightFailed, "failed to list the mgr modules. %v", err)
+	}
+	for _, module := range modules.EnabledModules {
+		if removedIn, ok := removedMgrModules[module]; ok && target.Major >= removedIn {
+			messages = append(messages, fmt.Sprintf("the mgr module %q is enabled but removed in the target release", module))
+		}
+	}
+
+	osdMetadata, err := cephclient.GetOSDMetadata(c.context, c.ClusterInfo)
+	if err != nil {
+		return preflightCheck(name, cephv1.UpgradePreflightFailed, "failed to get the osd metadata. %v", err)
+	}
+	filestoreOSDs := []string{}
+	for _, osd := range *osdMetadata {
+		if osd.ObjectStore == "filestore" {
+			filestoreOSDs = append(filestoreOSDs, strconv.Itoa(osd.Id))
+		}
+	}
+	if len(filestoreOSDs) > 0 {
+		messages = append(messages, fmt.Sprintf("the filestore osds %s must be migrated to bluestore", strings.Join(filestoreOSDs, ", ")))
+	}
+
+	if len(messages) == 0 {
+		return preflightCheck(name, cephv1.UpgradePreflightPassed, "no removed features in use")
+	}
+	return preflightCheck(name, cephv1.UpgradePreflightFailed, "%s", strings.Join(messages, "; "))
+}
+
+// updateUpgradePreflightStatus sets the upgrade preflight report and condition of the CephCluster
+func (c *cluster) updateUpgradePreflightStatus(report *cephv1.UpgradePreflightStatus) error {
+	condition := cephv1.Condition{
+		Type:    cephv1.ConditionUpgradePreflight,
+		Status:  v1.ConditionFalse,
+		Reason:  cephv1.UpgradePreflightPassedReason,
+		Message: fmt.Sprintf("The upgrade preflight checks for ceph version %q passed with result %s", report.TargetVersion, report.Result),
+	}
+	if report.Result == cephv1.UpgradePreflightFailed {
+		failed := []string{}
+		for _, check := range report.Checks {
+			if check.Result == cephv1.UpgradePreflightFailed {
+				failed = append(failed, check.Name)
+			}
+		}
+		condition.Status = v1.ConditionTrue
+		condition.Reason = cephv1.UpgradePreflightFailedReason
+		condition.Message = fmt.Sprintf("The upgrade preflight checks for ceph version %q failed: %s", report.TargetVersion, strings.Join(failed, ", "))
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cephCluster := &cephv1.CephCluster{}
+		if err := c.context.Client.Get(c.ClusterInfo.Context, c.namespacedName, cephCluster); err != nil {
+			return errors.Wrapf(err, "failed to get cluster %v", c.namespacedName)
+		}
+		cephCluster.Status.UpgradePreflight = report
+		cephv1.SetStatusCondition(&cephCluster.Status.Conditions, condition)
+		return reporting.UpdateStatus(c.context.Client, cephCluster)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update the upgrade preflight status")
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client/fake"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestUpgradePreflightReport(t *testing.T) {
+	healthStatus := `{"health":{"status":"HEALTH_OK","checks":{}},"pgmap":{"num_pgs":10,"pgs_by_state":[{"state_name":"active+clean","count":10}]}}`
+	requireOSDRelease := "squid"
+	enabledModules := `["pg_autoscaler"]`
+	okToStop := map[string]bool{"a": true, "b": true, "c": true, "0 3": true, "1 4": true, "2 5": true}
+	objectStore := "bluestore"
+	centralConfig := `[]`
+
+	execute := func(args ...string) (string, error) {
+		switch {
+		case args[0] == "status":
+			return healthStatus, nil
+		case args[0] == "osd" && args[1] == "dump":
+			return `{"require_osd_release":"` + requireOSDRelease + `"}`, nil
+		case args[0] == "quorum_status":
+			return `{"quorum":[0,1,2],"monmap":{"mons":[{"name":"a"},{"name":"b"},{"name":"c"}]}}`, nil
+		case args[0] == "osd" && args[1] == "ls":
+			return fake.OsdLsOutput(6), nil
+		case args[0] == "osd" && args[1] == "tree":
+			return fake.OsdTreeOutput(3, 2), nil
+		case args[1] == "ok-to-stop":
+			daemons := []string{}
+			for _, arg := range args[2:] {
+				if strings.HasPrefix(arg, "--") {
+					break
+				}
+				daemons = append(daemons, arg)
+			}
+			if okToStop[strings.Join(daemons, " ")] {
+				return "", nil
+			}
+			return "unsafe to stop", errors.New("exit status 16")
+		case args[0] == "config" && args[1] == "dump":
+			return centralConfig, nil
+		case args[0] == "versions":
+			return `{"mgr":{"ceph version 19.2.3 (c92aebb279828e9c3c1f5d24613efca272649e62) squid (stable)":1}}`, nil
+		case args[0] == "mgr" && args[1] == "dump":
+			return `{"modules":` + enabledModules + `}`, nil
+		case args[0] == "osd" && args[1] == "metadata":
+			return `[{"id":0,"osd_objectstore":"` + objectStore + `"},{"id":1,"osd_objectstore":"bluestore"}]`, nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			return execute(args...)
+		},
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			return execute(args...)
+		},
+	}
+	c := &cluster{
+		context:     &clusterd.Context{Clientset: testop.New(t, 1), Executor: executor},
+		ClusterInfo: newTestClusterInfo("rook-ceph"),
+		Namespace:   "rook-ceph",
+		Spec:        &cephv1.ClusterSpec{CephVersion: cephv1.CephVersionSpec{Image: "quay.io/ceph/ceph:v20.2.0"}},
+	}
+	target := cephver.CephVersion{Major: 20, Minor: 2, Extra: 0}
+	running := cephv1.CephDaemonsVersions{Overall: map[string]int{
+		"ceph version 19.2.3 (c92aebb279828e9c3c1f5d24613efca272649e62) squid (stable)": 9,
+	}}
+	checkResults := func(report *cephv1.UpgradePreflightStatus) map[string]cephv1.UpgradePreflightResult {
+		results := map[string]cephv1.UpgradePreflightResult{}
+		for _, check := range report.Checks {
+			results[check.Name] = check.Result
+		}
+		return results
+	}
+
+	t.Run("all checks pass", func(t *testing.T) {
+		report := c.upgradePreflightReport(target, running)
+		assert.Equal(t, "quay.io/ceph/ceph:v20.2.0", report.Image)
+		assert.Equal(t, "20.2.0-0 tentacle", report.TargetVersion)
+		assert.Equal(t, "19.2.3-0 squid", report.RunningVersion)
+		assert.NotNil(t, report.LastChecked)
+		assert.Equal(t, cephv1.UpgradePreflightPassed, report.Result)
+		names := []string{}
+		for _, check := range report.Checks {
+			names = append(names, check.Name)
+			assert.Equal(t, cephv1.UpgradePreflightPassed, check.Result, check.Name)
+		}
+		assert.Equal(t, []string{
+			"VersionCompatibility", "CephHealth", "PGHealth", "RequireOSDRelease",
+			"OkToStop/mon/a", "OkToStop/mon/b", "OkToStop/mon/c",
+			"OkToStop/host/node0", "OkToStop/host/node1", "OkToStop/host/node2",
+			"DeprecatedSettings", "RemovedFeatures",
+		}, names)
+	})
+
+	t.Run("failed checks", func(t *testing.T) {
+		healthStatus = `{"health":{"status":"HEALTH_WARN","checks":{"OSD_NEARFULL":{}}},"pgmap":{"num_pgs":10,"pgs_by_state":[{"state_name":"active+clean","count":8},{"state_name":"active+undersized+degraded","count":2}]}}`
+		requireOSDRelease = "quincy"
+		okToStop["1 4"] = false
+		enabledModules = `["pg_autoscaler","restful"]`
+		objectStore = "filestore"
+		centralConfig = `[{"section":"client.rgw.my.store.a","name":"rgw_frontends","value":"civetweb port=80","mask":""}]`
+
+		report := c.upgradePreflightReport(target, running)
+		assert.Equal(t, cephv1.UpgradePreflightFailed, report.Result)
+		results := checkResults(report)
+		assert.Equal(t, cephv1.UpgradePreflightPassed, results["VersionCompatibility"])
+		assert.Equal(t, cephv1.UpgradePreflightWarning, results["CephHealth"])
+		assert.Equal(t, cephv1.UpgradePreflightFailed, results["PGHealth"])
+		assert.Equal(t, cephv1.UpgradePreflightFailed, results["RequireOSDRelease"])
+		assert.Equal(t, cephv1.UpgradePreflightPassed, results["OkToStop/host/node0"])
+		assert.Equal(t, cephv1.UpgradePreflightFailed, results["OkToStop/host/node1"])
+		assert.Equal(t, cephv1.UpgradePreflightFailed, results["DeprecatedSettings"])
+		assert.Equal(t, cephv1.UpgradePreflightFailed, results["RemovedFeatures"])
+		for _, check := range report.Checks {
+			if check.Name == "RemovedFeatures" {
+				assert.Equal(t, `the mgr module "restful" is enabled but removed in the target release; the filestore osds 0 must be migrated to bluestore`, check.Message)
+			}
+		}
+	})
+
+	t.Run("version compatibility", func(t *testing.T) {
+		assert.Equal(t, cephv1.UpgradePreflightFailed, c.versionCompatibilityCheck(cephver.CephVersion{Major: 19, Minor: 2, Extra: 0}, cephver.CephVersion{Major: 19, Minor: 2, Extra: 3}, 1).Result)
+		assert.Equal(t, cephv1.UpgradePreflightFailed, c.versionCompatibilityCheck(target, cephver.CephVersion{Major: 17, Minor: 2, Extra: 8}, 1).Result)
+		assert.Equal(t, cephv1.UpgradePreflightPassed, c.versionCompatibilityCheck(target, cephver.CephVersion{Major: 18, Minor: 2, Extra: 7}, 1).Result)
+		assert.Equal(t, cephv1.UpgradePreflightWarning, c.versionCompatibilityCheck(target, cephver.CephVersion{Major: 19, Minor: 2, Extra: 3}, 2).Result)
+	})
+}
+
+func TestUpdateUpgradePreflightStatus(t *testing.T) {
+	cl := newTestCephClusterClient("rook-ceph")
+	c := &cluster{
+		context:        &clusterd.Context{Client: cl},
+		ClusterInfo:    newTestClusterInfo("rook-ceph"),
+		namespacedName: types.NamespacedName{Namespace: "rook-ceph", Name: "my-cluster"},
+	}
+	c.ClusterInfo.Context = context.TODO()
+	report := &cephv1.UpgradePreflightStatus{
+		TargetVersion: "20.2.0-0",
+		Result:        cephv1.UpgradePreflightFailed,
+		Checks: []cephv1.UpgradePreflightCheck{
+			{Name: "CephHealth", Result: cephv1.UpgradePreflightPassed},
+			{Name: "PGHealth", Result: cephv1.UpgradePreflightFailed},
+			{Name: "OkToStop/host/node1", Result: cephv1.UpgradePreflightFailed},
+		},
+	}
+	require.NoError(t, c.updateUpgradePreflightStatus(report))
+
+	cephCluster := &cephv1.CephCluster{}
+	require.NoError(t, cl.Get(context.TODO(), c.namespacedName, cephCluster))
+	assert.Equal(t, report, cephCluster.Status.UpgradePreflight)
+	condition := cephv1.FindStatusCondition(cephCluster.Status.Conditions, cephv1.ConditionUpgradePreflight)
+	require.NotNil(t, condition)
+	assert.Equal(t, v1.ConditionTrue, condition.Status)
+	assert.Equal(t, cephv1.UpgradePreflightFailedReason, condition.Reason)
+	assert.Equal(t, `The upgrade preflight checks for ceph version "20.2.0-0" failed: PGHealth, OkToStop/host/node1`, condition.Message)
+}
+
+func TestUseRunningCephImage(t *testing.T) {
+	cl := newTestCephClusterClient("rook-ceph")
+	c := &cluster{
+		context:        &clusterd.Context{Client: cl},
+		ClusterInfo:    newTestClusterInfo("rook-ceph"),
+		Namespace:      "rook-ceph",
+		namespacedName: types.NamespacedName{Namespace: "rook-ceph", Name: "my-cluster"},
+		Spec:           &cephv1.ClusterSpec{CephVersion: cephv1.CephVersionSpec{Image: "quay.io/ceph/ceph:v20.2.0", PreflightOnly: true}},
+	}
+	c.ClusterInfo.Context = context.TODO()
+
+	t.Run("running image unknown", func(t *testing.T) {
+		_, err := c.useRunningCephImage()
+		assert.ErrorContains(t, err, "the running ceph image is unknown")
+		assert.Equal(t, "quay.io/ceph/ceph:v20.2.0", c.Spec.CephVersion.Image)
+	})
+
+	t.Run("running image", func(t *testing.T) {
+		cephCluster := &cephv1.CephCluster{}
+		require.NoError(t, cl.Get(context.TODO(), c.namespacedName, cephCluster))
+		cephCluster.Status.CephVersion = &cephv1.ClusterVersion{Image: "quay.io/ceph/ceph:v19.2.3", Version: "19.2.3-0"}
+		require.NoError(t, cl.Status().Update(context.TODO(), cephCluster))
+
+		version, err := c.useRunningCephImage()
+		require.NoError(t, err)
+		assert.Equal(t, cephver.CephVersion{Major: 19, Minor: 2, Extra: 3}, *version)
+		assert.Equal(t, "quay.io/ceph/ceph:v19.2.3", c.Spec.CephVersion.Image)
+	})
+}
@@ -46,6 +46,15 @@ func (c *ClusterController) detectAndValidateCephVersion(cluster *cluster) (*cep
 		return nil, cluster.isUpgrade, err
 	}
 
+	// The daemons keep running the current version while only the upgrade preflight runs
+	if cluster.upgradePreflightOnly {
+		runningVersion, err := cluster.useRunningCephImage()
+		if err != nil {
+			return nil, false, err
+		}
+		return runningVersion, false, nil
+	}
+
 	// Update ceph version field in cluster object status
 	c.updateClusterCephVersion(cluster, *version)
 
@@ -58,6 +67,9 @@ func (c *cluster) printOverallCephVersion() {
 		log.NamespacedError(c.Namespace, logger, "failed to get ceph daemons versions. %v", err)
 		return
 	}
+	if err := c.recordUpgradeProgress(versions); err != nil {
+		log.NamespacedWarning(c.Namespace, logger, "failed to record the upgrade progress in the upgrade history. %v", err)
+	}
 
 	if len(versions.Overall) == 1 {
 		for v := range versions.Overall {
@@ -121,6 +133,7 @@ func (c *cluster) diffImageSpecAndClusterRunningVersion(imageSpecVersion cephver
 }
 
 func (c *cluster) validateCephVersion(version *cephver.CephVersion) error {
+	c.upgradePreflightOnly = false
 	if !c.Spec.External.Enable {
 		if !version.IsAtLeast(cephver.Minimum) {
 			return errors.Errorf("the version does not meet the minimum version %q", cephver.Minimum.String())
@@ -193,19 +206,35 @@ func (c *cluster) validateCephVersion(version *cephver.CephVersion) error {
 	}
 
 	if differentImages {
+		// Only report the upgrade checks if requested, the daemons are not updated
+		if c.Spec.CephVersion.PreflightOnly {
+			c.upgradePreflightOnly = true
+			return c.runUpgradePreflight(*version, runningVersions)
+		}
+
 		// If the image version changed let's make sure we can safely upgrade
 		// check ceph's status, if not healthy we fail
+		var refused error
 		cephHealthy := daemonclient.IsCephHealthy(c.context, c.ClusterInfo)
 		if !cephHealthy {
 			if c.Spec.SkipUpgradeChecks {
 				log.NamespacedWarning(c.Namespace, logger, "ceph is not healthy but SkipUpgradeChecks is set, forcing upgrade.")
 			} else {
-				return errors.Errorf("ceph status in namespace %s is not healthy, refusing to upgrade. Either fix the health issue or force an update by setting skipUpgradeChecks to true in the cluster CR", c.Namespace)
+				refused = errors.Errorf("ceph status in namespace %s is not healthy, refusing to upgrade. Either fix the health issue or force an update by setting skipUpgradeChecks to true in the cluster CR", c.Namespace)
 			}
 		}
+		// The upgrade is recorded in the upgrade history also when it is refused
+		if err := c.recordUpgradeStart(*version, runningVersions, refused); err != nil {
+			log.NamespacedWarning(c.Namespace, logger, "failed to record the upgrade in the upgrade history. %v", err)
+		}
+		if refused != nil {
+			return refused
+		}
 		// This is an upgrade
 		log.NamespacedInfo(c.Namespace, logger, "upgrading ceph cluster to %q", version.String())
 		c.isUpgrade = true
+	} else if err := c.recordRookUpgradeStart(*version, runningVersions); err != nil {
+		log.NamespacedWarning(c.Namespace, logger, "failed to record the rook upgrade in the upgrade history. %v", err)
 	}
 
 	return nil
@@ -18,6 +18,9 @@ limitations under the License.
 package config
 
 import (
+	"strconv"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/operator/ceph/version"
 )
 
@@ -62,6 +65,14 @@ func DefaultCentralizedConfigs(cephVersion version.CephVersion) map[string]strin
 	return overrides
 }
 
+// ClusterDefaultConfigs returns the options Rook sets in Ceph's centralized config store for the
+// cluster by default, as applied by SetOrRemoveDefaultConfigs.
+func ClusterDefaultConfigs(cephVersion version.CephVersion, clusterSpec cephv1.ClusterSpec) CephConfigOptionsMap {
+	global := DefaultCentralizedConfigs(cephVersion)
+	global["log to file"] = strconv.FormatBool(clusterSpec.LogCollector.Enabled)
+	return CephConfigOptionsMap{"global": global}
+}
+
 // LegacyConfigs represents old configuration that were applied to a cluster and not needed anymore
 func LegacyConfigs() []Option {
 	return []Option{
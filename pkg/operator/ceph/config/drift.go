@@ -0,0 +1,226 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DriftedOption is an option of the central config that differs from its expected value. The
+// embedded Option holds the expected value.
+type DriftedOption struct {
+	Option
+
+	// Actual is the value in the central config
+	Actual string
+
+	// Missing is true if the option is not set in the central config
+	Missing bool
+}
+
+// FindDrift compares the options in the central config with the expected options, and returns the
+// expected options that are missing or have a different value, sorted by target and option name.
+// The options in the central config that are not expected are ignored since Ceph and the mgr
+// modules set options of their own. Ceph stores the values in a normalized form, like "true" for a
+// bool set to "1", so the values that differ are compared again as values of the option type
+// returned by optionType. An empty type compares the values as strings.
+func FindDrift(expected CephConfigOptionsMap, actual []Option, optionType func(option string) string) []DriftedOption {
+	current := map[string]map[string]string{}
+	for _, option := range actual {
+		who := normalizeWho(option.Who)
+		if _, ok := current[who]; !ok {
+			current[who] = map[string]string{}
+		}
+		current[who][normalizeKey(option.Option)] = option.Value
+	}
+
+	drift := []DriftedOption{}
+	for who, options := range expected {
+		for key, value := range options {
+			actualValue, ok := current[normalizeWho(who)][normalizeKey(key)]
+			if ok && (actualValue == value || SameValue(optionType(key), value, actualValue)) {
+				continue
+			}
+			drift = append(drift, DriftedOption{
+				Option:  Option{Who: who, Option: key, Value: value},
+				Actual:  actualValue,
+				Missing: !ok,
+			})
+		}
+	}
+	slices.SortFunc(drift, func(a, b DriftedOption) int {
+		return cmp.Or(cmp.Compare(a.Who, b.Who), cmp.Compare(a.Option.Option, b.Option.Option))
+	})
+	return drift
+}
+
+// MergeOptions merges the options maps in order, an option in a later map overriding the same
+// option in an earlier map even when the option names are written differently
+func MergeOptions(maps ...CephConfigOptionsMap) CephConfigOptionsMap {
+	merged := CephConfigOptionsMap{}
+	for _, options := range maps {
+		for who, settings := range options {
+			if _, ok := merged[who]; !ok {
+				merged[who] = map[string]string{}
+			}
+			for key, value := range settings {
+				for existing := range merged[who] {
+					if normalizeKey(existing) == normalizeKey(key) {
+						delete(merged[who], existing)
+					}
+				}
+				merged[who][key] = value
+			}
+		}
+	}
+	return merged
+}
+
+// HasOption returns true if the options map has the option for the target, regardless of how the
+// option name is written
+func HasOption(options CephConfigOptionsMap, who, option string) bool {
+	for key := range options[who] {
+		if normalizeKey(key) == normalizeKey(option) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeWho converts the target of all the daemons of a type like "osd.*" to the type like "osd"
+func normalizeWho(who string) string {
+	return strings.TrimSuffix(who, ".*")
+}
+
+// SameValue returns true if both values are the same value of an option of the type. The values
+// that cannot be parsed as the type are compared as strings.
+func SameValue(optionType, a, b string) bool {
+	if a == b {
+		return true
+	}
+	switch optionType {
+	case "bool":
+		x, errA := ParseBool(a)
+		y, errB := ParseBool(b)
+		return errA == nil && errB == nil && x == y
+	case "int", "uint", "float":
+		x, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+		y, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+		return errA == nil && errB == nil && x == y
+	case "size":
+		x, okA := parseSize(a)
+		y, okB := parseSize(b)
+		return okA && okB && x == y
+	case "secs", "millisecs":
+		unit := time.Second
+		if optionType == "millisecs" {
+			unit = time.Millisecond
+		}
+		x, okA := parseTimespan(a, unit)
+		y, okB := parseTimespan(b, unit)
+		return okA && okB && x == y
+	}
+	return false
+}
+
+// ParseBool parses a value of a bool option the way ceph does: "true" or "false" in any case, or
+// a 32-bit integer that is true if not zero. Like ceph, the integer has no "+" sign or spaces.
+func ParseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if strings.HasPrefix(value, "+") {
+		return false, errors.Errorf("invalid bool %q", value)
+	}
+	i, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return false, errors.Errorf("invalid bool %q", value)
+	}
+	return i != 0, nil
+}
+
+// parseSize parses a value of a size option in bytes, with an optional unit like "4K" or "1Gi".
+// Like ceph, the units are powers of 1024.
+func parseSize(value string) (float64, bool) {
+	value = strings.TrimSuffix(strings.TrimSpace(value), "B")
+	value = strings.TrimSuffix(value, "i")
+	multiplier := 1.0
+	if i := strings.IndexAny(value, "KMGTPE"); i >= 0 && i == len(value)-1 {
+		multiplier = math.Pow(1024, float64(strings.Index("KMGTPE", value[i:])+1))
+		value = value[:i]
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return number * multiplier, true
+}
+
+// timespanUnits are the units of the time span values, as parsed by ceph
+var timespanUnits = map[string]time.Duration{
+	"ms": time.Millisecond, "msec": time.Millisecond, "millisecond": time.Millisecond, "milliseconds": time.Millisecond,
+	"s": time.Second, "sec": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour, "wk": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+}
+
+// parseTimespan parses a value of a time span option like "90", "1h" or "1h 30m". A number without
+// unit is in the default unit of the option.
+func parseTimespan(value string, defaultUnit time.Duration) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(number * float64(defaultUnit)), true
+	}
+	var total time.Duration
+	for value != "" {
+		end := strings.IndexFunc(value, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if end <= 0 {
+			return 0, false
+		}
+		number, err := strconv.ParseFloat(value[:end], 64)
+		if err != nil {
+			return 0, false
+		}
+		value = strings.TrimLeft(value[end:], " ")
+		unitEnd := strings.IndexFunc(value, func(r rune) bool { return r < 'a' || r > 'z' })
+		if unitEnd < 0 {
+			unitEnd = len(value)
+		}
+		unit, ok := timespanUnits[value[:unitEnd]]
+		if !ok {
+			return 0, false
+		}
+		total += time.Duration(number * float64(unit))
+		value = strings.TrimLeft(value[unitEnd:], " ")
+	}
+	return total, true
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindDrift(t *testing.T) {
+	noType := func(option string) string { return "" }
+	actual := []Option{
+		{"global", "mon_allow_pool_delete", "true"},
+		{"global", "osd_pool_default_size", "3"},
+		{"osd", "osd_max_backfills", "16"},
+		{"osd/class:ssd", "osd_max_scrubs", "2"},
+		{"mgr", "mgr/dashboard/ssl", "true"},
+	}
+
+	t.Run("no drift", func(t *testing.T) {
+		expected := CephConfigOptionsMap{
+			"global":        {"mon allow pool delete": "true", "osd-pool-default-size": "3"},
+			"osd.*":         {"osd_max_backfills": "16"},
+			"osd/class:ssd": {"osd_max_scrubs": "2"},
+		}
+		assert.Empty(t, FindDrift(expected, actual, noType))
+	})
+
+	t.Run("changed and removed options", func(t *testing.T) {
+		expected := CephConfigOptionsMap{
+			"global": {"osd_pool_default_size": "3", "mon_warn_on_pool_no_redundancy": "false"},
+			"osd":    {"osd max backfills": "1"},
+		}
+		assert.Equal(t, []DriftedOption{
+			{Option: Option{"global", "mon_warn_on_pool_no_redundancy", "false"}, Missing: true},
+			{Option: Option{"osd", "osd max backfills", "1"}, Actual: "16"},
+		}, FindDrift(expected, actual, noType))
+	})
+
+	t.Run("values normalized by ceph", func(t *testing.T) {
+		optionTypes := map[string]string{"mon_allow_pool_delete": "bool", "osd_max_backfills": "uint", "osd_max_scrubs": "uint"}
+		optionType := func(option string) string { return optionTypes[normalizeKey(option)] }
+		expected := CephConfigOptionsMap{
+			"global":        {"mon_allow_pool_delete": "1"},
+			"osd":           {"osd_max_backfills": "016"},
+			"osd/class:ssd": {"osd_max_scrubs": "3"},
+		}
+		assert.Equal(t, []DriftedOption{
+			{Option: Option{"osd/class:ssd", "osd_max_scrubs", "3"}, Actual: "2"},
+		}, FindDrift(expected, actual, optionType))
+		// the values are compared as strings without the type
+		assert.Len(t, FindDrift(expected, actual, noType), 3)
+	})
+
+	t.Run("option of another target", func(t *testing.T) {
+		expected := CephConfigOptionsMap{"osd.0": {"osd_max_backfills": "16"}}
+		assert.Equal(t, []DriftedOption{
+			{Option: Option{"osd.0", "osd_max_backfills", "16"}, Missing: true},
+		}, FindDrift(expected, actual, noType))
+	})
+}
+
+func TestMergeOptions(t *testing.T) {
+	merged := MergeOptions(
+		CephConfigOptionsMap{"global": {"mon allow pool delete": "true", "log_to_file": "false"}},
+		CephConfigOptionsMap{"global": {"mon_allow_pool_delete": "false"}, "osd": {"osd_max_backfills": "1"}},
+		nil,
+	)
+	assert.Equal(t, CephConfigOptionsMap{
+		"global": {"mon_allow_pool_delete": "false", "log_to_file": "false"},
+		"osd":    {"osd_max_backfills": "1"},
+	}, merged)
+}
+
+func TestHasOption(t *testing.T) {
+	options := CephConfigOptionsMap{"osd": {"osd max backfills": "1"}}
+	assert.True(t, HasOption(options, "osd", "osd_max_backfills"))
+	assert.False(t, HasOption(options, "osd", "osd_max_scrubs"))
+	assert.False(t, HasOption(options, "global", "osd_max_backfills"))
+}
+
+func TestSameValue(t *testing.T) {
+	tests := []struct {
+		optionType string
+		a, b       string
+		same       bool
+	}{
+		{"bool", "true", "true", true},
+		{"bool", "1", "true", true},
+		{"bool", "TRUE", "true", true},
+		{"bool", "0", "false", true},
+		{"bool", "yes", "true", false},
+		{"bool", "+1", "true", false},
+		{"bool", "0", "true", false},
+		{"uint", "016", "16", true},
+		{"int", "-1", "-1.0", true},
+		{"int", "1", "2", false},
+		{"float", "0.50", ".5", true},
+		{"size", "4K", "4096", true},
+		{"size", "1Gi", "1073741824", true},
+		{"size", "1G", "1M", false},
+		{"secs", "1h", "3600", true},
+		{"secs", "1h 30m", "5400", true},
+		{"secs", "90", "1m", false},
+		{"millisecs", "2s", "2000", true},
+		{"str", "a", "A", false},
+		{"", "1", "01", false},
+		{"uint", "abc", "1", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.same, SameValue(tt.optionType, tt.a, tt.b), "%s %q %q", tt.optionType, tt.a, tt.b)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/rook/rook/cmd/rook/rook"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/util"
 	"github.com/rook/rook/pkg/util/exec"
 	"gopkg.in/ini.v1"
 )
@@ -161,6 +162,35 @@ func (m *MonStore) GetDaemon(who string) ([]Option, error) {
 	return daemonOptions, nil
 }
 
+// GetAll retrieves all the configs in the centralized mon configuration database. The target of
+// an option with a mask is written as "<section>/<mask>" like "osd/class:ssd".
+func (m *MonStore) GetAll() ([]Option, error) {
+	args := []string{"config", "dump"}
+	cephCmd := client.NewCephCommand(m.context, m.clusterInfo, args)
+	out, err := cephCmd.RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		return []Option{}, errors.Wrapf(err, "failed to dump the config. output: %s", string(out))
+	}
+	var result []struct {
+		Section string `json:"section"`
+		Name    string `json:"name"`
+		Value   string `json:"value"`
+		Mask    string `json:"mask"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return []Option{}, errors.Wrapf(err, "failed to parse json config dump. json: %s", string(out))
+	}
+	options := make([]Option, 0, len(result))
+	for _, entry := range result {
+		who := entry.Section
+		if entry.Mask != "" {
+			who += "/" + entry.Mask
+		}
+		options = append(options, Option{Who: who, Option: entry.Name, Value: entry.Value})
+	}
+	return options, nil
+}
+
 // DeleteDaemon delete all configs for a specific daemon in the centralized mon configuration database.
 func (m *MonStore) DeleteDaemon(who string) error {
 	configOptions, err := m.GetDaemon(who)
@@ -213,6 +243,36 @@ func (m *MonStore) SetKeyValue(key, value string) error {
 	return nil
 }
 
+// GetKeyValue gets the value of a key in Ceph's general purpose key/value store.
+func (m *MonStore) GetKeyValue(key string) (string, error) {
+	args := []string{"config-key", "get", key}
+	cephCmd := client.NewCephCommand(m.context, m.clusterInfo, args)
+	out, err := cephCmd.RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get %q from the mon config-key store", key)
+	}
+	return string(out), nil
+}
+
+// SetSecretKeyValue sets a secret value in Ceph's general purpose key/value store. Unlike
+// SetKeyValue, the value is passed to Ceph in a file so that it never shows in the command line
+// or in the logs.
+func (m *MonStore) SetSecretKeyValue(key, value string) error {
+	logger.Debugf("setting secret %q in the mon config-key store", key)
+	file, err := util.CreateTempFile(value)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the value file of %q", key)
+	}
+	defer os.Remove(file.Name())
+
+	args := []string{"config-key", "set", key, "-i", file.Name()}
+	cephCmd := client.NewCephCommand(m.context, m.clusterInfo, args)
+	if _, err := cephCmd.RunWithTimeout(exec.CephCommandsTimeout); err != nil {
+		return errors.Wrapf(err, "failed to set secret %q in the mon config-key store", key)
+	}
+	return nil
+}
+
 func (m *MonStore) SetAllMultiple(settings map[string]map[string]string) error {
 	for who, options := range settings {
 		if err := m.SetAll(who, options); err != nil {
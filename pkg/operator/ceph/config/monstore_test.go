@@ -17,6 +17,7 @@ limitations under the License.
 package config
 
 import (
+	"os"
 	"reflect"
 	"strings"
 	"testing"
@@ -74,6 +75,40 @@ func TestMonStore_Set(t *testing.T) {
 	assert.Contains(t, execedCmd, " config set mon.* unknown_setting 10 ")
 }
 
+func TestMonStore_KeyValue(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	ctx := &clusterd.Context{Executor: executor}
+
+	store := map[string]string{}
+	executor.MockExecuteCommandWithTimeout = func(timeout time.Duration, command string, args ...string) (string, error) {
+		switch {
+		case args[0] == "config-key" && args[1] == "get":
+			value, ok := store[args[2]]
+			if !ok {
+				return "", errors.New("mocked not found")
+			}
+			return value, nil
+		case args[0] == "config-key" && args[1] == "set" && args[3] == "-i":
+			value, err := os.ReadFile(args[4])
+			if err != nil {
+				return "", err
+			}
+			store[args[2]] = string(value)
+			return "", nil
+		}
+		return "", errors.Errorf("unexpected command %v", args)
+	}
+
+	monStore := GetMonStore(ctx, client.AdminTestClusterInfo("mycluster"))
+	assert.NoError(t, monStore.SetSecretKeyValue("dm-crypt/osd/abc/luks", "secret"))
+	value, err := monStore.GetKeyValue("dm-crypt/osd/abc/luks")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", value)
+
+	_, err = monStore.GetKeyValue("dm-crypt/osd/def/luks")
+	assert.Error(t, err)
+}
+
 func TestMonStore_Delete(t *testing.T) {
 	executor := &exectest.MockExecutor{}
 	clientset := testop.New(t, 1)
@@ -154,6 +189,32 @@ func TestMonStore_GetDaemon(t *testing.T) {
 	assert.Contains(t, execedCmd, " config get mon.* ")
 }
 
+func TestMonStore_GetAll(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	ctx := &clusterd.Context{
+		Clientset: testop.New(t, 1),
+		Executor:  executor,
+	}
+
+	execedCmd := ""
+	execReturn := `[{"section":"global","name":"mon_allow_pool_delete","value":"true","level":"advanced","can_update_at_runtime":true,"mask":""},` +
+		`{"section":"osd","name":"osd_max_backfills","value":"4","level":"advanced","can_update_at_runtime":true,"mask":"class:ssd"}]`
+	executor.MockExecuteCommandWithTimeout = func(timeout time.Duration, command string, args ...string) (string, error) {
+		execedCmd = command + " " + strings.Join(args, " ")
+		return execReturn, nil
+	}
+
+	monStore := GetMonStore(ctx, client.AdminTestClusterInfo("mycluster"))
+	options, err := monStore.GetAll()
+	assert.NoError(t, err)
+	assert.Contains(t, execedCmd, "ceph config dump")
+	assert.Equal(t, []Option{{"global", "mon_allow_pool_delete", "true"}, {"osd/class:ssd", "osd_max_backfills", "4"}}, options)
+
+	execReturn = "bad json output"
+	_, err = monStore.GetAll()
+	assert.ErrorContains(t, err, "failed to parse json config dump")
+}
+
 func TestMonStore_DeleteDaemon(t *testing.T) {
 	executor := &exectest.MockExecutor{}
 	clientset := testop.New(t, 1)
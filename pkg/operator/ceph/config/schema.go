@@ -0,0 +1,247 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/util/exec"
+)
+
+// OptionSchema is the schema of a Ceph config option as reported by "ceph config help"
+type OptionSchema struct {
+	Name               string   `json:"name"`
+	Type               string   `json:"type"`
+	EnumValues         []string `json:"enum_values"`
+	Min                any      `json:"min"`
+	Max                any      `json:"max"`
+	CanUpdateAtRuntime bool     `json:"can_update_at_runtime"`
+}
+
+// RejectedOption is a config option that does not match the option schema of the cluster
+type RejectedOption struct {
+	Who    string
+	Option string
+	Reason string
+}
+
+// ValidationResult is the result of the validation of config options against the option schema
+type ValidationResult struct {
+	// Valid are the options that match the option schema
+	Valid CephConfigOptionsMap
+
+	// Rejected are the options that do not match the option schema, sorted by target and option name
+	Rejected []RejectedOption
+
+	// RestartRequired are the valid options that cannot change at runtime, so they only take effect
+	// when the daemons restart. The options are sorted by target and option name.
+	RestartRequired []Option
+}
+
+// optionSchemas are the option names and schemas of a ceph version
+type optionSchemas struct {
+	names   []string
+	schemas map[string]*OptionSchema
+}
+
+var (
+	// the option schemas only change with the ceph version, so they are cached per version instead
+	// of being read from the mons on each reconcile
+	optionSchemaCache      = map[string]*optionSchemas{}
+	optionSchemaCacheMutex sync.Mutex
+)
+
+// cachedOptionSchemas returns the cached option schemas of the ceph version of the cluster, or nil
+// if the ceph version is not known yet
+func (m *MonStore) cachedOptionSchemas() *optionSchemas {
+	if m.clusterInfo.CephVersion.Major == 0 {
+		return nil
+	}
+	version := m.clusterInfo.CephVersion.String()
+	if _, ok := optionSchemaCache[version]; !ok {
+		optionSchemaCache[version] = &optionSchemas{schemas: map[string]*OptionSchema{}}
+	}
+	return optionSchemaCache[version]
+}
+
+// ListOptionNames returns the names of all the config options of the cluster
+func (m *MonStore) ListOptionNames() ([]string, error) {
+	optionSchemaCacheMutex.Lock()
+	defer optionSchemaCacheMutex.Unlock()
+	cache := m.cachedOptionSchemas()
+	if cache != nil && cache.names != nil {
+		return cache.names, nil
+	}
+
+	args := []string{"config", "ls"}
+	cephCmd := client.NewCephCommand(m.context, m.clusterInfo, args)
+	out, err := cephCmd.RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the config options. output: %s", string(out))
+	}
+	var names []string
+	if err := json.Unmarshal(out, &names); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse json config options list. json: %s", string(out))
+	}
+	if cache != nil {
+		cache.names = names
+	}
+	return names, nil
+}
+
+// GetOptionSchema returns the schema of a config option
+func (m *MonStore) GetOptionSchema(option string) (*OptionSchema, error) {
+	optionSchemaCacheMutex.Lock()
+	defer optionSchemaCacheMutex.Unlock()
+	cache := m.cachedOptionSchemas()
+	if cache != nil {
+		if schema, ok := cache.schemas[normalizeKey(option)]; ok {
+			return schema, nil
+		}
+	}
+
+	args := []string{"config", "help", normalizeKey(option)}
+	cephCmd := client.NewCephCommand(m.context, m.clusterInfo, args)
+	out, err := cephCmd.RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the schema of config option %q. output: %s", option, string(out))
+	}
+	var schema OptionSchema
+	if err := json.Unmarshal(out, &schema); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse json schema of config option %q. json: %s", option, string(out))
+	}
+	if cache != nil {
+		cache.schemas[normalizeKey(option)] = &schema
+	}
+	return &schema, nil
+}
+
+// ValidateOptions checks the name, the type, the allowed values and the bounds of each option
+// against the option schema of the running cluster. The options of the mgr modules like
+// "mgr/dashboard/ssl" are not validated since their schema depends on the modules of the active mgr.
+// The values are not part of the rejection reasons since they might come from secrets.
+func (m *MonStore) ValidateOptions(options CephConfigOptionsMap) (*ValidationResult, error) {
+	result := &ValidationResult{Valid: CephConfigOptionsMap{}}
+	if len(options) == 0 {
+		return result, nil
+	}
+
+	names, err := m.ListOptionNames()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(names))
+	for _, name := range names {
+		known[name] = true
+	}
+
+	for who, settings := range options {
+		for key, value := range settings {
+			if !strings.Contains(key, "/") {
+				if !known[normalizeKey(key)] {
+					result.Rejected = append(result.Rejected, RejectedOption{Who: who, Option: key, Reason: "unknown option"})
+					continue
+				}
+				schema, err := m.GetOptionSchema(key)
+				if err != nil {
+					return nil, err
+				}
+				if err := ValidateValue(schema.Type, schema.EnumValues, schema.Min, schema.Max, value); err != nil {
+					result.Rejected = append(result.Rejected, RejectedOption{Who: who, Option: key, Reason: err.Error()})
+					continue
+				}
+				if !schema.CanUpdateAtRuntime {
+					result.RestartRequired = append(result.RestartRequired, Option{Who: who, Option: key, Value: value})
+				}
+			}
+			if _, ok := result.Valid[who]; !ok {
+				result.Valid[who] = map[string]string{}
+			}
+			result.Valid[who][key] = value
+		}
+	}
+
+	slices.SortFunc(result.Rejected, func(a, b RejectedOption) int {
+		return cmp.Or(cmp.Compare(a.Who, b.Who), cmp.Compare(a.Option, b.Option))
+	})
+	slices.SortFunc(result.RestartRequired, func(a, b Option) int {
+		return cmp.Or(cmp.Compare(a.Who, b.Who), cmp.Compare(a.Option, b.Option))
+	})
+	return result, nil
+}
+
+// ValidateValue checks the value against the type, the allowed values, and the bounds of an
+// option. Values of the types that ceph parses with units, such as "secs" and "size", are left to ceph.
+func ValidateValue(optionType string, enumValues []string, minimum, maximum any, value string) error {
+	if len(enumValues) > 0 && !slices.Contains(enumValues, value) {
+		return errors.Errorf("value must be one of %v", enumValues)
+	}
+
+	var number float64
+	var err error
+	switch optionType {
+	case "bool":
+		if _, err := ParseBool(value); err != nil {
+			return errors.New("value must be a boolean")
+		}
+		return nil
+	case "int":
+		var i int64
+		i, err = strconv.ParseInt(value, 10, 64)
+		number = float64(i)
+	case "uint":
+		var u uint64
+		u, err = strconv.ParseUint(value, 10, 64)
+		number = float64(u)
+	case "float":
+		number, err = strconv.ParseFloat(value, 64)
+	default:
+		return nil
+	}
+	if err != nil {
+		return errors.Errorf("value must be of type %q", optionType)
+	}
+
+	if bound, ok := optionBound(minimum); ok && number < bound {
+		return errors.Errorf("value must be at least %v", minimum)
+	}
+	if bound, ok := optionBound(maximum); ok && number > bound {
+		return errors.Errorf("value must be at most %v", maximum)
+	}
+	return nil
+}
+
+// optionBound parses the min or max of an option, which ceph reports either as a number or as a
+// string that is empty when the option has no bound
+func optionBound(bound any) (float64, bool) {
+	if bound == nil {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(fmt.Sprint(bound), 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonStore_ValidateOptions(t *testing.T) {
+	schemas := map[string]string{
+		"osd_max_backfills":     `{"name":"osd_max_backfills","type":"uint","min":"","max":"","enum_values":[],"can_update_at_runtime":true}`,
+		"osd_op_queue":          `{"name":"osd_op_queue","type":"str","min":"","max":"","enum_values":["wpq","mclock_scheduler","debug_random"],"can_update_at_runtime":false}`,
+		"osd_pool_default_size": `{"name":"osd_pool_default_size","type":"uint","min":0,"max":10,"enum_values":[],"can_update_at_runtime":true}`,
+		"mon_allow_pool_delete": `{"name":"mon_allow_pool_delete","type":"bool","min":"","max":"","enum_values":[],"can_update_at_runtime":true}`,
+	}
+	commands := 0
+	execInjectErr := false
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			commands++
+			if execInjectErr {
+				return "output from cmd with error", errors.New("mocked error")
+			}
+			switch {
+			case args[0] == "config" && args[1] == "ls":
+				return `["osd_max_backfills","osd_op_queue","osd_pool_default_size","mon_allow_pool_delete"]`, nil
+			case args[0] == "config" && args[1] == "help":
+				return schemas[args[2]], nil
+			}
+			return "", errors.Errorf("unexpected command %v", args)
+		},
+	}
+	monStore := GetMonStore(&clusterd.Context{Executor: executor}, client.AdminTestClusterInfo("mycluster"))
+
+	t.Run("no options", func(t *testing.T) {
+		result, err := monStore.ValidateOptions(nil)
+		assert.NoError(t, err)
+		assert.Empty(t, result.Valid)
+		assert.Equal(t, 0, commands)
+	})
+
+	t.Run("valid and rejected options", func(t *testing.T) {
+		result, err := monStore.ValidateOptions(CephConfigOptionsMap{
+			"global": {
+				"mon allow pool delete": "true",
+				"osd_pool_default_size": "11",
+				"osd_max_backfils":      "1",
+			},
+			"osd": {
+				"osd-max-backfills": "-1",
+				"osd_op_queue":      "mclock_scheduler",
+			},
+			"osd.0": {"osd_op_queue": "fifo"},
+			"mgr":   {"mgr/balancer/mode": "upmap"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, CephConfigOptionsMap{
+			"global": {"mon allow pool delete": "true"},
+			"osd":    {"osd_op_queue": "mclock_scheduler"},
+			"mgr":    {"mgr/balancer/mode": "upmap"},
+		}, result.Valid)
+		assert.Equal(t, []RejectedOption{
+			{Who: "global", Option: "osd_max_backfils", Reason: "unknown option"},
+			{Who: "global", Option: "osd_pool_default_size", Reason: "value must be at most 10"},
+			{Who: "osd", Option: "osd-max-backfills", Reason: `value must be of type "uint"`},
+			{Who: "osd.0", Option: "osd_op_queue", Reason: "value must be one of [wpq mclock_scheduler debug_random]"},
+		}, result.Rejected)
+		assert.Equal(t, []Option{{Who: "osd", Option: "osd_op_queue", Value: "mclock_scheduler"}}, result.RestartRequired)
+	})
+
+	t.Run("failure to read the schema", func(t *testing.T) {
+		execInjectErr = true
+		_, err := monStore.ValidateOptions(CephConfigOptionsMap{"global": {"osd_max_backfills": "1"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestMonStore_OptionSchemaCache(t *testing.T) {
+	t.Cleanup(func() { optionSchemaCache = map[string]*optionSchemas{} })
+	commands := 0
+	execInjectErr := false
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			commands++
+			if execInjectErr {
+				return "output from cmd with error", errors.New("mocked error")
+			}
+			switch {
+			case args[0] == "config" && args[1] == "ls":
+				return `["osd_max_backfills"]`, nil
+			case args[0] == "config" && args[1] == "help":
+				return `{"name":"osd_max_backfills","type":"uint","min":"","max":"","enum_values":[],"can_update_at_runtime":true}`, nil
+			}
+			return "", errors.Errorf("unexpected command %v", args)
+		},
+	}
+	clusterInfo := client.AdminTestClusterInfo("mycluster")
+	clusterInfo.CephVersion = cephver.Squid
+	monStore := GetMonStore(&clusterd.Context{Executor: executor}, clusterInfo)
+	options := CephConfigOptionsMap{"osd": {"osd max backfills": "1"}}
+
+	_, err := monStore.ValidateOptions(options)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, commands)
+
+	// the schema of the same version is cached
+	_, err = monStore.ValidateOptions(options)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, commands)
+
+	// the schema of another version is read again
+	clusterInfo.CephVersion = cephver.Tentacle
+	_, err = monStore.ValidateOptions(options)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, commands)
+
+	// failures are not cached
+	clusterInfo.CephVersion = cephver.CephVersion{Major: 20, Minor: 2, Extra: 1}
+	execInjectErr = true
+	_, err = monStore.ValidateOptions(options)
+	assert.Error(t, err)
+	execInjectErr = false
+	_, err = monStore.ValidateOptions(options)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, commands)
+}
+
+func TestValidateValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		optionType string
+		enumValues []string
+		min, max   any
+		value      string
+		err        string
+	}{
+		{name: "bool", optionType: "bool", value: "true"},
+		{name: "bool as number", optionType: "bool", value: "0"},
+		{name: "bool in any case", optionType: "bool", value: "TRUE"},
+		{name: "negative bool", optionType: "bool", value: "-1"},
+		{name: "invalid bool", optionType: "bool", value: "yes", err: "value must be a boolean"},
+		{name: "bool with a sign", optionType: "bool", value: "+1", err: "value must be a boolean"},
+		{name: "bool with spaces", optionType: "bool", value: " 1", err: "value must be a boolean"},
+		{name: "bool out of range", optionType: "bool", value: "4294967296", err: "value must be a boolean"},
+		{name: "empty bool", optionType: "bool", value: "", err: "value must be a boolean"},
+		{name: "int", optionType: "int", value: "-5", min: "", max: ""},
+		{name: "invalid int", optionType: "int", value: "5.5", err: `value must be of type "int"`},
+		{name: "negative uint", optionType: "uint", value: "-1", err: `value must be of type "uint"`},
+		{name: "float", optionType: "float", value: "0.5", min: 0.0, max: 1.0},
+		{name: "below min", optionType: "float", value: "-0.5", min: 0.0, err: "value must be at least 0"},
+		{name: "above max", optionType: "uint", value: "11", max: "10", err: "value must be at most 10"},
+		{name: "enum", optionType: "str", enumValues: []string{"wpq", "mclock_scheduler"}, value: "wpq"},
+		{name: "invalid enum", optionType: "str", enumValues: []string{"wpq"}, value: "fifo", err: "value must be one of [wpq]"},
+		{name: "size left to ceph", optionType: "size", value: "4G"},
+		{name: "secs left to ceph", optionType: "secs", value: "1h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateValue(tt.optionType, tt.enumValues, tt.min, tt.max, tt.value)
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
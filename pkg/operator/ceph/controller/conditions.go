@@ -21,6 +21,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/operator/ceph/reporting"
@@ -29,6 +30,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 )
 
 // UpdateCondition function will export each condition into the cluster custom resource
@@ -60,6 +62,11 @@ func UpdateClusterCondition(c *clusterd.Context, cluster *cephv1.CephCluster, na
 			condition.Reason == cephv1.ClusterCreatedReason ||
 			condition.Reason == cephv1.ClusterConnectedReason ||
 			condition.Type == cephv1.ConditionDeleting ||
+			condition.Type == cephv1.ConditionMonBackupRestore ||
+			condition.Type == cephv1.ConditionMonQuorumRecovery ||
+			condition.Type == cephv1.ConditionCephConfigDrift ||
+			condition.Type == cephv1.ConditionCephConfigInvalid ||
+			condition.Type == cephv1.ConditionUpgradePreflight ||
 			condition.Type == cephv1.ConditionDeletionIsBlocked {
 			if conditionType != condition.Type {
 				conditions = append(conditions, condition)
@@ -110,6 +117,17 @@ func UpdateClusterCondition(c *clusterd.Context, cluster *cephv1.CephCluster, na
 	}
 }
 
+// SetClusterCondition sets a condition of the cluster custom resource without changing the phase of the cluster
+func SetClusterCondition(ctx context.Context, c *clusterd.Context, namespaceName types.NamespacedName, condition cephv1.Condition) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster := &cephv1.CephCluster{}
+		if err := c.Client.Get(ctx, namespaceName, cluster); err != nil {
+			return errors.Wrapf(err, "failed to get cluster %v to set the %q condition", namespaceName, condition.Type)
+		}
+		return reporting.UpdateStatusCondition(c.Client, cluster, condition)
+	})
+}
+
 // translatePhasetoState convert the Phases to corresponding State
 // 1. We still need to set the State in case someone is still using it
 // instead of Phase. If we stopped setting the State it would be a
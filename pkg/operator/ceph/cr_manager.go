@@ -27,11 +27,13 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/cluster/rbd"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/ceph/csi"
+	"github.com/rook/rook/pkg/operator/ceph/dashboarduser"
 	"github.com/rook/rook/pkg/operator/ceph/disruption/clusterdisruption"
 	"github.com/rook/rook/pkg/operator/ceph/disruption/controllerconfig"
 	"github.com/rook/rook/pkg/operator/ceph/file"
 	"github.com/rook/rook/pkg/operator/ceph/file/mirror"
 	"github.com/rook/rook/pkg/operator/ceph/file/subvolumegroup"
+	"github.com/rook/rook/pkg/operator/ceph/mgrmodule"
 	"github.com/rook/rook/pkg/operator/ceph/nfs"
 	"github.com/rook/rook/pkg/operator/ceph/nvmeof"
 	"github.com/rook/rook/pkg/operator/ceph/object"
@@ -44,7 +46,10 @@ import (
 	objectuser "github.com/rook/rook/pkg/operator/ceph/object/user"
 	"github.com/rook/rook/pkg/operator/ceph/object/zone"
 	"github.com/rook/rook/pkg/operator/ceph/object/zonegroup"
+	"github.com/rook/rook/pkg/operator/ceph/osdremoval"
 	"github.com/rook/rook/pkg/operator/ceph/pool"
+	"github.com/rook/rook/pkg/operator/ceph/pool/crushrule"
+	"github.com/rook/rook/pkg/operator/ceph/pool/ecprofile"
 	"github.com/rook/rook/pkg/operator/ceph/pool/radosnamespace"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -95,6 +100,11 @@ var AddToManagerFuncs = []func(manager.Manager, *clusterd.Context, context.Conte
 	radosnamespace.Add,
 	cosi.Add,
 	objectaccount.Add,
+	mgrmodule.Add,
+	dashboarduser.Add,
+	osdremoval.Add,
+	ecprofile.Add,
+	crushrule.Add,
 }
 
 // AddToManagerOpFunc is a list of functions to add all Controllers to the Manager (entrypoint for
@@ -0,0 +1,394 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dashboarduser to manage the dashboard users of a rook cluster.
+package dashboarduser
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/log"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-dashboard-user-controller"
+	// the dashboard admin user is managed by the mgr controller of the CephCluster
+	adminUsername = "admin"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       reflect.TypeFor[cephv1.CephDashboardUser]().Name(),
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileCephDashboardUser reconciles a CephDashboardUser object
+type ReconcileCephDashboardUser struct {
+	client           client.Client
+	context          *clusterd.Context
+	clusterInfo      *cephclient.ClusterInfo
+	opManagerContext context.Context
+	recorder         events.EventRecorder
+}
+
+// Add creates a new CephDashboardUser Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	return add(mgr, newReconciler(mgr, context, opManagerContext))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context) reconcile.Reconciler {
+	return &ReconcileCephDashboardUser{
+		client:           mgr.GetClient(),
+		context:          context,
+		opManagerContext: opManagerContext,
+		recorder:         mgr.GetEventRecorder("rook-" + controllerName),
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephDashboardUser CRD object
+	err = c.Watch(
+		source.Kind(
+			mgr.GetCache(),
+			&cephv1.CephDashboardUser{TypeMeta: controllerTypeMeta},
+			&handler.TypedEnqueueRequestForObject[*cephv1.CephDashboardUser]{},
+			opcontroller.WatchControllerPredicate[*cephv1.CephDashboardUser](mgr.GetScheme()),
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the password secrets so a new password is applied
+	return c.Watch(
+		source.Kind(
+			mgr.GetCache(),
+			&corev1.Secret{TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: corev1.SchemeGroupVersion.String()}},
+			handler.TypedEnqueueRequestsFromMapFunc(
+				func(ctx context.Context, secret *corev1.Secret) []reconcile.Request {
+					users := &cephv1.CephDashboardUserList{}
+					if err := mgr.GetClient().List(ctx, users, client.InNamespace(secret.GetNamespace())); err != nil {
+						return nil
+					}
+					return passwordSecretRequests(secret, users)
+				},
+			),
+			predicate.TypedResourceVersionChangedPredicate[*corev1.Secret]{},
+		),
+	)
+}
+
+// passwordSecretRequests returns the requests for the dashboard users with their password in the secret
+func passwordSecretRequests(secret *corev1.Secret, users *cephv1.CephDashboardUserList) []reconcile.Request {
+	requests := []reconcile.Request{}
+	for _, user := range users.Items {
+		if user.Namespace == secret.Namespace && user.Spec.PasswordSecret.Name == secret.Name {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: user.Namespace, Name: user.Name}})
+		}
+	}
+	return requests
+}
+
+// Reconcile reads that state of the cluster for a CephDashboardUser object and makes changes based on the state read
+// and what is in the CephDashboardUser.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileCephDashboardUser) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer opcontroller.RecoverAndLogException()
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
+	reconcileResponse, dashboardUser, err := r.reconcile(request)
+	return reporting.ReportReconcileResult(logger, r.recorder, request, &dashboardUser, reconcileResponse, err)
+}
+
+func (r *ReconcileCephDashboardUser) reconcile(request reconcile.Request) (reconcile.Result, cephv1.CephDashboardUser, error) {
+	// Fetch the CephDashboardUser instance
+	dashboardUser := &cephv1.CephDashboardUser{}
+	err := r.client.Get(r.opManagerContext, request.NamespacedName, dashboardUser)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			log.NamedDebug(request.NamespacedName, logger, "cephDashboardUser resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, *dashboardUser, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, *dashboardUser, errors.Wrap(err, "failed to get cephDashboardUser")
+	}
+	// update observedGeneration local variable with current generation value,
+	// because generation can be changed before reconcile got completed
+	// CR status will be updated at end of reconcile, so to reflect the reconcile has finished
+	observedGeneration := dashboardUser.ObjectMeta.Generation
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	generationUpdated, err := opcontroller.AddFinalizerIfNotPresent(r.opManagerContext, r.client, dashboardUser)
+	if err != nil {
+		return reconcile.Result{}, *dashboardUser, errors.Wrap(err, "failed to add finalizer")
+	}
+	if generationUpdated {
+		log.NamedInfo(request.NamespacedName, logger, "reconciling the dashboard user after adding finalizer")
+		return reconcile.Result{}, *dashboardUser, nil
+	}
+
+	// The CR was just created, initializing status fields
+	if dashboardUser.Status == nil {
+		dashboardUser.Status = &cephv1.CephDashboardUserStatus{Phase: cephv1.ConditionProgressing}
+		if err := r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, dashboardUser.Status); err != nil {
+			return reconcile.Result{}, *dashboardUser, errors.Wrapf(err, "failed to initialize dashboard user %q status", request.NamespacedName)
+		}
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	cephCluster, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.opManagerContext, r.client, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		// Only remove the finalizer if the CephCluster is gone
+		if !dashboardUser.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, dashboardUser)
+			if err != nil {
+				return opcontroller.ImmediateRetryResult, *dashboardUser, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, *dashboardUser, nil
+		}
+		return reconcileResponse, *dashboardUser, nil
+	}
+
+	// Populate clusterInfo during each reconcile
+	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, r.opManagerContext, request.NamespacedName.Namespace, &cephCluster.Spec)
+	if err != nil {
+		return reconcile.Result{}, *dashboardUser, errors.Wrap(err, "failed to populate cluster info")
+	}
+	r.clusterInfo.Context = r.opManagerContext
+
+	// DELETE: the CR was deleted
+	if !dashboardUser.GetDeletionTimestamp().IsZero() {
+		log.NamedDebug(request.NamespacedName, logger, "deleting dashboard user")
+		// the user is gone with the dashboard if the dashboard is disabled
+		if cephCluster.Spec.Dashboard.Enabled && username(dashboardUser) != adminUsername {
+			if err := r.deleteUser(dashboardUser); err != nil {
+				return reconcile.Result{}, *dashboardUser, errors.Wrapf(err, "failed to delete dashboard user %q", username(dashboardUser))
+			}
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, dashboardUser)
+		if err != nil {
+			return reconcile.Result{}, *dashboardUser, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, *dashboardUser, nil
+	}
+
+	// Create or update the user
+	err = r.reconcileUser(dashboardUser, cephCluster)
+	if err != nil {
+		if strings.Contains(err.Error(), opcontroller.UninitializedCephConfigError) {
+			log.NamedInfo(request.NamespacedName, logger, opcontroller.OperatorNotInitializedMessage)
+			return opcontroller.WaitForRequeueIfOperatorNotInitialized, *dashboardUser, nil
+		}
+		dashboardUser.Status.Phase = cephv1.ConditionFailure
+		dashboardUser.Status.Message = err.Error()
+		if statusErr := r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, dashboardUser.Status); statusErr != nil {
+			return reconcile.Result{}, *dashboardUser, errors.Wrapf(statusErr, "failed to set failed status for dashboard user %q", request.NamespacedName)
+		}
+		return reconcile.Result{}, *dashboardUser, errors.Wrapf(err, "failed to reconcile dashboard user %q", username(dashboardUser))
+	}
+
+	// update status with latest ObservedGeneration value at the end of reconcile
+	dashboardUser.Status.Phase = cephv1.ConditionReady
+	dashboardUser.Status.Message = ""
+	err = r.updateStatus(observedGeneration, request.NamespacedName, dashboardUser.Status)
+	if err != nil {
+		return reconcile.Result{}, *dashboardUser, errors.Wrapf(err, "failed to set final status for dashboard user %q", request.NamespacedName)
+	}
+
+	log.NamedDebug(request.NamespacedName, logger, "done reconciling")
+	return reconcile.Result{}, *dashboardUser, nil
+}
+
+// reconcileUser creates the dashboard user or updates its password, roles, and login
+func (r *ReconcileCephDashboardUser) reconcileUser(dashboardUser *cephv1.CephDashboardUser, cephCluster cephv1.CephCluster) error {
+	name := username(dashboardUser)
+	nsName := opcontroller.NsName(dashboardUser.Namespace, dashboardUser.Name)
+	if !cephCluster.Spec.Dashboard.Enabled {
+		return errors.New("the dashboard is not enabled in the CephCluster")
+	}
+	if name == adminUsername {
+		return errors.Errorf("the dashboard user %q is managed by the operator", adminUsername)
+	}
+
+	secret, err := r.context.Clientset.CoreV1().Secrets(dashboardUser.Namespace).Get(r.opManagerContext, dashboardUser.Spec.PasswordSecret.Name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get password secret %q", dashboardUser.Spec.PasswordSecret.Name)
+	}
+	password, ok := secret.Data[dashboardUser.Spec.PasswordSecret.Key]
+	if !ok || len(password) == 0 {
+		return errors.Errorf("key %q not found in password secret %q", dashboardUser.Spec.PasswordSecret.Key, secret.Name)
+	}
+
+	var expirationDate *time.Time
+	if dashboardUser.Spec.PasswordExpirationDate != nil {
+		expirationDate = &dashboardUser.Spec.PasswordExpirationDate.Time
+	}
+
+	users, err := cephclient.ListDashboardUsers(r.context, r.clusterInfo)
+	if err != nil {
+		return err
+	}
+	var user *cephclient.DashboardUser
+	if slices.Contains(users, name) {
+		user, err = cephclient.GetDashboardUser(r.context, r.clusterInfo, name)
+		if err != nil {
+			return err
+		}
+		// the expiration date can only be set when the user is created
+		if expirationDate != nil && (user.PwdExpirationDate == nil || *user.PwdExpirationDate != expirationDate.Unix()) {
+			log.NamedInfo(nsName, logger, "recreating dashboard user %q to set the password expiration date", name)
+			if err := cephclient.DeleteDashboardUser(r.context, r.clusterInfo, name); err != nil {
+				return err
+			}
+			user = nil
+		}
+	}
+
+	if user == nil {
+		if err := cephclient.CreateDashboardUser(r.context, r.clusterInfo, name, string(password), dashboardUser.Spec.Roles, expirationDate); err != nil {
+			return err
+		}
+		log.NamedInfo(nsName, logger, "created dashboard user %q", name)
+		user = &cephclient.DashboardUser{Username: name, Roles: dashboardUser.Spec.Roles, Enabled: true}
+	} else if dashboardUser.Status.PasswordSecretVersion != secret.ResourceVersion {
+		if err := cephclient.SetDashboardUserPassword(r.context, r.clusterInfo, name, string(password)); err != nil {
+			return err
+		}
+		log.NamedInfo(nsName, logger, "set the password of dashboard user %q", name)
+	}
+	dashboardUser.Status.PasswordSecretVersion = secret.ResourceVersion
+
+	if !sameItems(user.Roles, dashboardUser.Spec.Roles) {
+		if err := cephclient.SetDashboardUserRoles(r.context, r.clusterInfo, name, dashboardUser.Spec.Roles); err != nil {
+			return err
+		}
+		log.NamedInfo(nsName, logger, "set roles %v of dashboard user %q", dashboardUser.Spec.Roles, name)
+	}
+
+	enabled := dashboardUser.Spec.Enabled == nil || *dashboardUser.Spec.Enabled
+	if user.Enabled != enabled {
+		if err := cephclient.EnableDashboardUser(r.context, r.clusterInfo, name, enabled); err != nil {
+			return err
+		}
+		log.NamedInfo(nsName, logger, "set login of dashboard user %q to enabled=%t", name, enabled)
+	}
+	return nil
+}
+
+// deleteUser deletes the dashboard user if it exists
+func (r *ReconcileCephDashboardUser) deleteUser(dashboardUser *cephv1.CephDashboardUser) error {
+	name := username(dashboardUser)
+	users, err := cephclient.ListDashboardUsers(r.context, r.clusterInfo)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(users, name) {
+		return nil
+	}
+	if err := cephclient.DeleteDashboardUser(r.context, r.clusterInfo, name); err != nil {
+		return err
+	}
+	log.NamedInfo(opcontroller.NsName(dashboardUser.Namespace, dashboardUser.Name), logger, "deleted dashboard user %q", name)
+	return nil
+}
+
+func username(dashboardUser *cephv1.CephDashboardUser) string {
+	if dashboardUser.Spec.Username != "" {
+		return dashboardUser.Spec.Username
+	}
+	return dashboardUser.Name
+}
+
+// sameItems returns true if both lists have the same items regardless of their order
+func sameItems(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// updateStatus updates an object with a given status
+func (r *ReconcileCephDashboardUser) updateStatus(observedGeneration int64, name types.NamespacedName, status *cephv1.CephDashboardUserStatus) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		dashboardUser := &cephv1.CephDashboardUser{}
+		if err := r.client.Get(r.opManagerContext, name, dashboardUser); err != nil {
+			if kerrors.IsNotFound(err) {
+				log.NamedDebug(name, logger, "CephDashboardUser resource not found. Ignoring since object must be deleted.")
+				return nil
+			}
+			return errors.Wrapf(err, "failed to retrieve dashboard user %q to update status to %q", name, status.Phase)
+		}
+
+		dashboardUser.Status = status.DeepCopy()
+		if observedGeneration != k8sutil.ObservedGenerationNotAvailable {
+			dashboardUser.Status.ObservedGeneration = observedGeneration
+		}
+		if err := reporting.UpdateStatus(r.client, dashboardUser); err != nil {
+			return errors.Wrapf(err, "failed to set dashboard user %q status to %q", name, status.Phase)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.NamedDebug(name, logger, "dashboard user status updated to %q", status.Phase)
+	return nil
+}
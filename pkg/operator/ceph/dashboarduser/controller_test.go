@@ -0,0 +1,348 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dashboarduser
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// fakeDashboard simulates the user management of the dashboard module
+type fakeDashboard struct {
+	users     map[string]*cephclient.DashboardUser
+	passwords map[string]string
+	commands  []string
+}
+
+func (d *fakeDashboard) execute(t *testing.T, args []string) (string, error) {
+	if args[0] != "dashboard" {
+		return "", nil
+	}
+	if i := slices.Index(args, "--format"); i >= 0 {
+		args = args[:i]
+	}
+	args = slices.Clone(args[1:])
+	var expirationDate *int64
+	args = slices.DeleteFunc(args, func(arg string) bool {
+		if value, ok := strings.CutPrefix(arg, "--pwd_expiration_date="); ok {
+			date, err := strconv.ParseInt(value, 10, 64)
+			assert.NoError(t, err)
+			expirationDate = &date
+		}
+		return strings.HasPrefix(arg, "--")
+	})
+	password := ""
+	if i := slices.Index(args, "-i"); i >= 0 {
+		input, err := os.ReadFile(args[i+1])
+		assert.NoError(t, err)
+		password = string(input)
+		args = slices.Delete(args, i, i+2)
+	}
+
+	switch args[0] {
+	case "ac-user-show":
+		if len(args) == 1 {
+			names := []string{"admin"}
+			for name := range d.users {
+				names = append(names, name)
+			}
+			out, _ := json.Marshal(names)
+			return string(out), nil
+		}
+		out, _ := json.Marshal(d.users[args[1]])
+		return string(out), nil
+	case "ac-user-create":
+		d.users[args[1]] = &cephclient.DashboardUser{Username: args[1], Roles: []string{}, Enabled: true, PwdExpirationDate: expirationDate}
+		d.passwords[args[1]] = password
+	case "ac-user-set-password":
+		d.passwords[args[1]] = password
+	case "ac-user-set-roles":
+		d.users[args[1]].Roles = args[2:]
+	case "ac-user-enable":
+		d.users[args[1]].Enabled = true
+	case "ac-user-disable":
+		d.users[args[1]].Enabled = false
+	case "ac-user-delete":
+		delete(d.users, args[1])
+		delete(d.passwords, args[1])
+	default:
+		return "", nil
+	}
+	d.commands = append(d.commands, strings.Join(args, " "))
+	return "", nil
+}
+
+func TestCephDashboardUserController(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+
+	newCephCluster := func(dashboardEnabled bool) *cephv1.CephCluster {
+		return &cephv1.CephCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace},
+			Spec:       cephv1.ClusterSpec{Dashboard: cephv1.DashboardSpec{Enabled: dashboardEnabled}},
+			Status: cephv1.ClusterStatus{
+				Phase:      cephv1.ConditionReady,
+				CephStatus: &cephv1.CephStatus{Health: "HEALTH_OK"},
+			},
+		}
+	}
+	newDashboardUser := func(spec cephv1.DashboardUserSpec) *cephv1.CephDashboardUser {
+		spec.PasswordSecret = v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "alice-password"}, Key: "password"}
+		return &cephv1.CephDashboardUser{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "alice",
+				Namespace:  namespace,
+				Finalizers: []string{"cephdashboarduser.ceph.rook.io"},
+			},
+			TypeMeta: metav1.TypeMeta{Kind: "CephDashboardUser"},
+			Spec:     spec,
+		}
+	}
+
+	setup := func(t *testing.T, dashboardUser *cephv1.CephDashboardUser, cephCluster *cephv1.CephCluster) (*ReconcileCephDashboardUser, *fakeDashboard) {
+		dashboard := &fakeDashboard{users: map[string]*cephclient.DashboardUser{}, passwords: map[string]string{}}
+		executor := &exectest.MockExecutor{
+			MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+				return dashboard.execute(t, args)
+			},
+		}
+
+		s := scheme.Scheme
+		s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephDashboardUser{}, &cephv1.CephDashboardUserList{}, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+		cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(dashboardUser, cephCluster).WithStatusSubresource(dashboardUser).Build()
+		c := &clusterd.Context{
+			Executor:  executor,
+			Clientset: testop.New(t, 1),
+			Client:    cl,
+			ConfigDir: t.TempDir(),
+		}
+		for _, secret := range []*v1.Secret{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon", Namespace: namespace},
+				Data: map[string][]byte{
+					"fsid":         []byte("fsid"),
+					"mon-secret":   []byte("monsecret"),
+					"admin-secret": []byte("adminsecret"),
+				},
+				Type: k8sutil.RookType,
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "alice-password", Namespace: namespace, ResourceVersion: "1"},
+				Data:       map[string][]byte{"password": []byte("secret1")},
+			},
+		} {
+			_, err := c.Clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+			require.NoError(t, err)
+		}
+
+		return &ReconcileCephDashboardUser{
+			client:           cl,
+			context:          c,
+			opManagerContext: ctx,
+			recorder:         events.NewFakeRecorder(50),
+		}, dashboard
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "alice", Namespace: namespace}}
+	getStatus := func(t *testing.T, r *ReconcileCephDashboardUser) *cephv1.CephDashboardUserStatus {
+		dashboardUser := &cephv1.CephDashboardUser{}
+		require.NoError(t, r.client.Get(ctx, req.NamespacedName, dashboardUser))
+		return dashboardUser.Status
+	}
+	updateSpec := func(t *testing.T, r *ReconcileCephDashboardUser, update func(spec *cephv1.DashboardUserSpec)) {
+		dashboardUser := &cephv1.CephDashboardUser{}
+		require.NoError(t, r.client.Get(ctx, req.NamespacedName, dashboardUser))
+		update(&dashboardUser.Spec)
+		require.NoError(t, r.client.Update(ctx, dashboardUser))
+	}
+
+	t.Run("create and update the user", func(t *testing.T) {
+		expiration := metav1.NewTime(time.Unix(1900000000, 0))
+		r, dashboard := setup(t, newDashboardUser(cephv1.DashboardUserSpec{
+			Roles:                  []string{"read-only"},
+			PasswordExpirationDate: &expiration,
+		}), newCephCluster(true))
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		require.Contains(t, dashboard.users, "alice")
+		assert.Equal(t, []string{"read-only"}, dashboard.users["alice"].Roles)
+		assert.Equal(t, int64(1900000000), *dashboard.users["alice"].PwdExpirationDate)
+		assert.Equal(t, "secret1", dashboard.passwords["alice"])
+		status := getStatus(t, r)
+		assert.Equal(t, cephv1.ConditionReady, status.Phase)
+		assert.Equal(t, "1", status.PasswordSecretVersion)
+
+		// nothing changed
+		dashboard.commands = nil
+		_, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Empty(t, dashboard.commands)
+
+		// a new version of the password secret
+		_, err = r.context.Clientset.CoreV1().Secrets(namespace).Update(ctx, &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "alice-password", Namespace: namespace, ResourceVersion: "2"},
+			Data:       map[string][]byte{"password": []byte("secret2")},
+		}, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		_, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ac-user-set-password alice"}, dashboard.commands)
+		assert.Equal(t, "secret2", dashboard.passwords["alice"])
+		assert.Equal(t, "2", getStatus(t, r).PasswordSecretVersion)
+
+		// new roles and a disabled login
+		updateSpec(t, r, func(spec *cephv1.DashboardUserSpec) {
+			spec.Roles = []string{"read-only", "pool-manager"}
+			spec.Enabled = new(bool)
+		})
+		dashboard.commands = nil
+		_, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ac-user-set-roles alice read-only pool-manager", "ac-user-disable alice"}, dashboard.commands)
+		assert.False(t, dashboard.users["alice"].Enabled)
+
+		// the user is recreated to change the expiration date
+		updateSpec(t, r, func(spec *cephv1.DashboardUserSpec) {
+			later := metav1.NewTime(time.Unix(2000000000, 0))
+			spec.PasswordExpirationDate = &later
+		})
+		dashboard.commands = nil
+		_, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"ac-user-delete alice",
+			"ac-user-create alice",
+			"ac-user-set-roles alice read-only pool-manager",
+			"ac-user-disable alice",
+		}, dashboard.commands)
+		assert.Equal(t, int64(2000000000), *dashboard.users["alice"].PwdExpirationDate)
+		assert.Equal(t, "secret2", dashboard.passwords["alice"])
+	})
+
+	t.Run("username in the spec", func(t *testing.T) {
+		r, dashboard := setup(t, newDashboardUser(cephv1.DashboardUserSpec{Username: "alice.smith", Roles: []string{"read-only"}}), newCephCluster(true))
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Contains(t, dashboard.users, "alice.smith")
+		assert.NotContains(t, dashboard.users, "alice")
+	})
+
+	t.Run("admin user from the name of the CR", func(t *testing.T) {
+		dashboardUser := newDashboardUser(cephv1.DashboardUserSpec{Roles: []string{"read-only"}})
+		dashboardUser.Name = "admin"
+		r, dashboard := setup(t, dashboardUser, newCephCluster(true))
+		adminReq := reconcile.Request{NamespacedName: types.NamespacedName{Name: "admin", Namespace: namespace}}
+
+		_, err := r.Reconcile(ctx, adminReq)
+		assert.ErrorContains(t, err, `the dashboard user "admin" is managed by the operator`)
+		assert.Empty(t, dashboard.commands)
+
+		// the admin user is not deleted with the CR
+		dashboard.users["admin"] = &cephclient.DashboardUser{Username: "admin", Roles: []string{"administrator"}, Enabled: true}
+		dashboardUser = &cephv1.CephDashboardUser{}
+		require.NoError(t, r.client.Get(ctx, adminReq.NamespacedName, dashboardUser))
+		require.NoError(t, r.client.Delete(ctx, dashboardUser))
+		_, err = r.Reconcile(ctx, adminReq)
+		assert.NoError(t, err)
+		assert.Empty(t, dashboard.commands)
+		assert.Contains(t, dashboard.users, "admin")
+	})
+
+	t.Run("dashboard not enabled", func(t *testing.T) {
+		r, dashboard := setup(t, newDashboardUser(cephv1.DashboardUserSpec{Roles: []string{"read-only"}}), newCephCluster(false))
+
+		_, err := r.Reconcile(ctx, req)
+		assert.ErrorContains(t, err, "the dashboard is not enabled")
+		assert.Empty(t, dashboard.commands)
+		status := getStatus(t, r)
+		assert.Equal(t, cephv1.ConditionFailure, status.Phase)
+		assert.Contains(t, status.Message, "the dashboard is not enabled")
+	})
+
+	t.Run("missing password key", func(t *testing.T) {
+		dashboardUser := newDashboardUser(cephv1.DashboardUserSpec{Roles: []string{"read-only"}})
+		dashboardUser.Spec.PasswordSecret.Key = "unknown"
+		r, dashboard := setup(t, dashboardUser, newCephCluster(true))
+
+		_, err := r.Reconcile(ctx, req)
+		assert.ErrorContains(t, err, `key "unknown" not found in password secret "alice-password"`)
+		assert.Empty(t, dashboard.commands)
+		assert.Equal(t, cephv1.ConditionFailure, getStatus(t, r).Phase)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		dashboardUser := newDashboardUser(cephv1.DashboardUserSpec{Roles: []string{"read-only"}})
+		dashboardUser.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		dashboardUser.Status = &cephv1.CephDashboardUserStatus{Phase: cephv1.ConditionReady}
+		r, dashboard := setup(t, dashboardUser, newCephCluster(true))
+		dashboard.users["alice"] = &cephclient.DashboardUser{Username: "alice", Roles: []string{"read-only"}, Enabled: true}
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ac-user-delete alice"}, dashboard.commands)
+		assert.Empty(t, dashboard.users)
+	})
+}
+
+func TestPasswordSecretRequests(t *testing.T) {
+	newUser := func(name, namespace, secretName string) cephv1.CephDashboardUser {
+		return cephv1.CephDashboardUser{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: cephv1.DashboardUserSpec{
+				PasswordSecret: v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: secretName}, Key: "password"},
+			},
+		}
+	}
+	users := &cephv1.CephDashboardUserList{Items: []cephv1.CephDashboardUser{
+		newUser("alice", "rook-ceph", "passwords"),
+		newUser("bob", "rook-ceph", "bob-password"),
+		newUser("carol", "rook-ceph", "passwords"),
+		newUser("dave", "other", "passwords"),
+	}}
+	secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "passwords", Namespace: "rook-ceph"}}
+
+	assert.Equal(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: "alice", Namespace: "rook-ceph"}},
+		{NamespacedName: types.NamespacedName{Name: "carol", Namespace: "rook-ceph"}},
+	}, passwordSecretRequests(secret, users))
+
+	secret.Name = "unknown"
+	assert.Empty(t, passwordSecretRequests(secret, users))
+}
@@ -0,0 +1,392 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mgrmodule to manage the mgr modules of a rook cluster.
+package mgrmodule
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mgr"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/log"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-mgr-module-controller"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+// the module health is refreshed at this interval since it changes without any change to the CR
+var healthCheckInterval = time.Minute
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       reflect.TypeFor[cephv1.CephMgrModule]().Name(),
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileCephMgrModule reconciles a CephMgrModule object
+type ReconcileCephMgrModule struct {
+	client           client.Client
+	context          *clusterd.Context
+	clusterInfo      *cephclient.ClusterInfo
+	opManagerContext context.Context
+	recorder         events.EventRecorder
+}
+
+// Add creates a new CephMgrModule Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	return add(mgr, newReconciler(mgr, context, opManagerContext))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context) reconcile.Reconciler {
+	return &ReconcileCephMgrModule{
+		client:           mgr.GetClient(),
+		context:          context,
+		opManagerContext: opManagerContext,
+		recorder:         mgr.GetEventRecorder("rook-" + controllerName),
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephMgrModule CRD object
+	return c.Watch(
+		source.Kind(
+			mgr.GetCache(),
+			&cephv1.CephMgrModule{TypeMeta: controllerTypeMeta},
+			&handler.TypedEnqueueRequestForObject[*cephv1.CephMgrModule]{},
+			opcontroller.WatchControllerPredicate[*cephv1.CephMgrModule](mgr.GetScheme()),
+		),
+	)
+}
+
+// Reconcile reads that state of the cluster for a CephMgrModule object and makes changes based on the state read
+// and what is in the CephMgrModule.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileCephMgrModule) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer opcontroller.RecoverAndLogException()
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
+	reconcileResponse, mgrModule, err := r.reconcile(request)
+	return reporting.ReportReconcileResult(logger, r.recorder, request, &mgrModule, reconcileResponse, err)
+}
+
+func (r *ReconcileCephMgrModule) reconcile(request reconcile.Request) (reconcile.Result, cephv1.CephMgrModule, error) {
+	// Fetch the CephMgrModule instance
+	mgrModule := &cephv1.CephMgrModule{}
+	err := r.client.Get(r.opManagerContext, request.NamespacedName, mgrModule)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			log.NamedDebug(request.NamespacedName, logger, "cephMgrModule resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, *mgrModule, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, *mgrModule, errors.Wrap(err, "failed to get cephMgrModule")
+	}
+	// update observedGeneration local variable with current generation value,
+	// because generation can be changed before reconcile got completed
+	// CR status will be updated at end of reconcile, so to reflect the reconcile has finished
+	observedGeneration := mgrModule.ObjectMeta.Generation
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	generationUpdated, err := opcontroller.AddFinalizerIfNotPresent(r.opManagerContext, r.client, mgrModule)
+	if err != nil {
+		return reconcile.Result{}, *mgrModule, errors.Wrap(err, "failed to add finalizer")
+	}
+	if generationUpdated {
+		log.NamedInfo(request.NamespacedName, logger, "reconciling the mgr module after adding finalizer")
+		return reconcile.Result{}, *mgrModule, nil
+	}
+
+	// The CR was just created, initializing status fields
+	if mgrModule.Status == nil {
+		mgrModule.Status = &cephv1.CephMgrModuleStatus{Phase: cephv1.ConditionProgressing}
+		if err := r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, mgrModule.Status); err != nil {
+			return reconcile.Result{}, *mgrModule, errors.Wrapf(err, "failed to initialize mgr module %q status", request.NamespacedName)
+		}
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	cephCluster, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.opManagerContext, r.client, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		// Only remove the finalizer if the CephCluster is gone
+		if !mgrModule.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, mgrModule)
+			if err != nil {
+				return opcontroller.ImmediateRetryResult, *mgrModule, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, *mgrModule, nil
+		}
+		return reconcileResponse, *mgrModule, nil
+	}
+
+	// Populate clusterInfo during each reconcile
+	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, r.opManagerContext, request.NamespacedName.Namespace, &cephCluster.Spec)
+	if err != nil {
+		return reconcile.Result{}, *mgrModule, errors.Wrap(err, "failed to populate cluster info")
+	}
+	r.clusterInfo.Context = r.opManagerContext
+
+	modules, err := cephclient.GetMgrModules(r.context, r.clusterInfo)
+	if err != nil {
+		if strings.Contains(err.Error(), opcontroller.UninitializedCephConfigError) {
+			log.NamedInfo(request.NamespacedName, logger, opcontroller.OperatorNotInitializedMessage)
+			return opcontroller.WaitForRequeueIfOperatorNotInitialized, *mgrModule, nil
+		}
+		return reconcile.Result{}, *mgrModule, errors.Wrap(err, "failed to get mgr modules")
+	}
+
+	// DELETE: the CR was deleted
+	if !mgrModule.GetDeletionTimestamp().IsZero() {
+		log.NamedDebug(request.NamespacedName, logger, "deleting mgr module")
+		if err := r.deleteModule(mgrModule, modules, &cephCluster.Spec); err != nil {
+			return reconcile.Result{}, *mgrModule, errors.Wrapf(err, "failed to delete mgr module %q", mgrModule.Name)
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, mgrModule)
+		if err != nil {
+			return reconcile.Result{}, *mgrModule, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, *mgrModule, nil
+	}
+
+	// Enable the module and apply its settings
+	err = r.reconcileModule(mgrModule, modules)
+	if err != nil {
+		mgrModule.Status.Phase = cephv1.ConditionFailure
+		mgrModule.Status.Message = err.Error()
+		if statusErr := r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, mgrModule.Status); statusErr != nil {
+			return reconcile.Result{}, *mgrModule, errors.Wrapf(statusErr, "failed to set failed status for mgr module %q", request.NamespacedName)
+		}
+		return reconcile.Result{}, *mgrModule, errors.Wrapf(err, "failed to reconcile mgr module %q", mgrModule.Name)
+	}
+
+	// The module was enabled above, so read the modules again for its health
+	modules, err = cephclient.GetMgrModules(r.context, r.clusterInfo)
+	if err != nil {
+		return reconcile.Result{}, *mgrModule, errors.Wrap(err, "failed to get mgr modules")
+	}
+	status, err := cephclient.Status(r.context, r.clusterInfo)
+	if err != nil {
+		return reconcile.Result{}, *mgrModule, errors.Wrap(err, "failed to get ceph status")
+	}
+
+	// update status with latest ObservedGeneration value at the end of reconcile
+	mgrModule.Status.Phase = cephv1.ConditionReady
+	mgrModule.Status.Health, mgrModule.Status.Message = moduleHealth(moduleName(mgrModule), modules, status)
+	err = r.updateStatus(observedGeneration, request.NamespacedName, mgrModule.Status)
+	if err != nil {
+		return reconcile.Result{}, *mgrModule, errors.Wrapf(err, "failed to set final status for mgr module %q", request.NamespacedName)
+	}
+
+	// Requeue to keep the health of the module up to date
+	log.NamedDebug(request.NamespacedName, logger, "done reconciling")
+	return reconcile.Result{RequeueAfter: healthCheckInterval}, *mgrModule, nil
+}
+
+// reconcileModule enables the module, sets its settings, and removes the settings that were set
+// before but are no longer in the spec
+func (r *ReconcileCephMgrModule) reconcileModule(mgrModule *cephv1.CephMgrModule, modules *cephclient.MgrModules) error {
+	name := moduleName(mgrModule)
+	nsName := opcontroller.NsName(mgrModule.Namespace, mgrModule.Name)
+	module := modules.Module(name)
+	if module == nil {
+		return errors.Errorf("mgr module %q not found", name)
+	}
+	if err := validateSettings(module, mgrModule.Spec.Settings); err != nil {
+		return errors.Wrapf(err, "failed to validate the settings of mgr module %q", name)
+	}
+
+	if !modules.IsEnabled(name) {
+		log.NamedInfo(nsName, logger, "enabling mgr module %q", name)
+		if err := cephclient.MgrEnableModule(r.context, r.clusterInfo, name, false); err != nil {
+			return errors.Wrapf(err, "failed to enable mgr module %q", name)
+		}
+	}
+
+	monStore := config.GetMonStore(r.context, r.clusterInfo)
+	settings := map[string]string{}
+	for option, value := range mgrModule.Spec.Settings {
+		settings[settingKey(name, option)] = value
+	}
+	if len(settings) > 0 {
+		if err := monStore.SetAll("mgr", settings); err != nil {
+			return errors.Wrapf(err, "failed to set the settings of mgr module %q", name)
+		}
+	}
+
+	// remove the settings that were removed from the spec
+	for _, option := range mgrModule.Status.Settings {
+		if _, ok := mgrModule.Spec.Settings[option]; ok {
+			continue
+		}
+		log.NamedInfo(nsName, logger, "removing setting %q of mgr module %q", option, name)
+		if err := monStore.Delete("mgr", settingKey(name, option)); err != nil {
+			return errors.Wrapf(err, "failed to remove setting %q of mgr module %q", option, name)
+		}
+	}
+
+	mgrModule.Status.Settings = []string{}
+	for option := range mgrModule.Spec.Settings {
+		mgrModule.Status.Settings = append(mgrModule.Status.Settings, option)
+	}
+	slices.Sort(mgrModule.Status.Settings)
+	return nil
+}
+
+// deleteModule removes the settings of the module and disables the module, unless the module is
+// always on or is also enabled by the CephCluster
+func (r *ReconcileCephMgrModule) deleteModule(mgrModule *cephv1.CephMgrModule, modules *cephclient.MgrModules, clusterSpec *cephv1.ClusterSpec) error {
+	name := moduleName(mgrModule)
+	nsName := opcontroller.NsName(mgrModule.Namespace, mgrModule.Name)
+
+	monStore := config.GetMonStore(r.context, r.clusterInfo)
+	for _, option := range mgrModule.Status.Settings {
+		if err := monStore.Delete("mgr", settingKey(name, option)); err != nil {
+			return errors.Wrapf(err, "failed to remove setting %q of mgr module %q", option, name)
+		}
+	}
+
+	if modules.IsAlwaysOn(name) || !modules.IsEnabled(name) {
+		return nil
+	}
+	if enabledByCluster(name, clusterSpec) {
+		log.NamedInfo(nsName, logger, "not disabling mgr module %q since it is enabled by the CephCluster", name)
+		return nil
+	}
+	log.NamedInfo(nsName, logger, "disabling mgr module %q", name)
+	if err := cephclient.MgrDisableModule(r.context, r.clusterInfo, name); err != nil {
+		return errors.Wrapf(err, "failed to disable mgr module %q", name)
+	}
+	return nil
+}
+
+// enabledByCluster returns whether the CephCluster enables the module, either in the mgr modules of
+// its spec or as the prometheus and dashboard modules that the mgr reconcile manages
+func enabledByCluster(name string, clusterSpec *cephv1.ClusterSpec) bool {
+	switch name {
+	case mgr.PrometheusModuleName:
+		return !clusterSpec.Monitoring.MetricsDisabled
+	case "dashboard":
+		return clusterSpec.Dashboard.Enabled
+	}
+	return slices.ContainsFunc(clusterSpec.Mgr.Modules, func(module cephv1.Module) bool {
+		return module.Name == name && module.Enabled
+	})
+}
+
+// moduleHealth returns the health of the module and the reason if the module is unhealthy
+func moduleHealth(name string, modules *cephclient.MgrModules, status cephclient.CephStatus) (cephv1.MgrModuleHealth, string) {
+	module := modules.Module(name)
+	if module == nil {
+		return cephv1.MgrModuleUnhealthy, "module not found"
+	}
+	if !module.CanRun {
+		return cephv1.MgrModuleUnhealthy, module.ErrorString
+	}
+	if !modules.IsEnabled(name) {
+		return cephv1.MgrModuleUnhealthy, "module is not enabled"
+	}
+	// the health checks name the failed modules, for example "Module 'balancer' has failed: ..."
+	for _, checkName := range []string{"MGR_MODULE_ERROR", "MGR_MODULE_DEPENDENCY"} {
+		check, ok := status.Health.Checks[checkName]
+		if ok && strings.Contains(check.Summary.Message, fmt.Sprintf("Module '%s'", name)) {
+			return cephv1.MgrModuleUnhealthy, check.Summary.Message
+		}
+	}
+	return cephv1.MgrModuleHealthy, ""
+}
+
+func moduleName(mgrModule *cephv1.CephMgrModule) string {
+	if mgrModule.Spec.Name != "" {
+		return mgrModule.Spec.Name
+	}
+	return mgrModule.Name
+}
+
+func settingKey(module, option string) string {
+	return fmt.Sprintf("mgr/%s/%s", module, option)
+}
+
+// updateStatus updates an object with a given status
+func (r *ReconcileCephMgrModule) updateStatus(observedGeneration int64, name types.NamespacedName, status *cephv1.CephMgrModuleStatus) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		mgrModule := &cephv1.CephMgrModule{}
+		if err := r.client.Get(r.opManagerContext, name, mgrModule); err != nil {
+			if kerrors.IsNotFound(err) {
+				log.NamedDebug(name, logger, "CephMgrModule resource not found. Ignoring since object must be deleted.")
+				return nil
+			}
+			return errors.Wrapf(err, "failed to retrieve mgr module %q to update status to %q", name, status.Phase)
+		}
+
+		mgrModule.Status = status.DeepCopy()
+		if observedGeneration != k8sutil.ObservedGenerationNotAvailable {
+			mgrModule.Status.ObservedGeneration = observedGeneration
+		}
+		if err := reporting.UpdateStatus(r.client, mgrModule); err != nil {
+			return errors.Wrapf(err, "failed to set mgr module %q status to %q", name, status.Phase)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.NamedDebug(name, logger, "mgr module status updated to %q", status.Phase)
+	return nil
+}
@@ -0,0 +1,320 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgrmodule
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const mgrDumpOutput = `{"modules":["iostat"],"always_on_modules":{"squid":["balancer","pg_autoscaler"]},
+"available_modules":[
+{"name":"balancer","can_run":true,"error_string":"","module_options":{
+  "min_score":{"name":"min_score","type":"float","min":"","max":"","enum_allowed":[]},
+  "mode":{"name":"mode","type":"str","min":"","max":"","enum_allowed":["crush-compat","none","upmap"]}}},
+{"name":"iostat","can_run":true,"error_string":"","module_options":{}},
+{"name":"telemetry","can_run":true,"error_string":"","module_options":{
+  "interval":{"name":"interval","type":"int","min":8,"max":"","enum_allowed":[]},
+  "enabled":{"name":"enabled","type":"bool","min":"","max":"","enum_allowed":[]}}},
+{"name":"k8sevents","can_run":false,"error_string":"kubernetes module not found","module_options":{}}]}`
+
+func TestValidateSettings(t *testing.T) {
+	module := &cephclient.MgrModuleInfo{
+		Name: "test",
+		ModuleOptions: map[string]cephclient.MgrModuleOption{
+			"str":   {Type: "str"},
+			"enum":  {Type: "str", EnumAllowed: []string{"a", "b"}},
+			"bool":  {Type: "bool"},
+			"int":   {Type: "int", Min: float64(-5), Max: "10"},
+			"uint":  {Type: "uint"},
+			"float": {Type: "float", Min: "0.5", Max: ""},
+			"secs":  {Type: "secs"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		settings map[string]string
+		err      string
+	}{
+		{"no settings", nil, ""},
+		{"valid settings", map[string]string{"str": "x", "enum": "b", "bool": "True", "int": "-5", "uint": "3", "float": "0.5", "secs": "1h"}, ""},
+		{"unknown option", map[string]string{"unknown": "x"}, `option "unknown" not found in mgr module "test"`},
+		{"value not allowed", map[string]string{"enum": "c"}, "value must be one of [a b]"},
+		{"invalid bool", map[string]string{"bool": "yes"}, "value must be a boolean"},
+		{"numeric bool", map[string]string{"bool": "0"}, ""},
+		{"invalid int", map[string]string{"int": "1.5"}, `value must be of type "int"`},
+		{"negative uint", map[string]string{"uint": "-1"}, `value must be of type "uint"`},
+		{"below min", map[string]string{"int": "-6"}, "value must be at least -5"},
+		{"above max", map[string]string{"int": "11"}, "value must be at most 10"},
+		{"float below min", map[string]string{"float": "0.1"}, "value must be at least 0.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSettings(module, tt.settings)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestModuleHealth(t *testing.T) {
+	context := &clusterd.Context{Executor: &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			return mgrDumpOutput, nil
+		},
+	}}
+	modules, err := cephclient.GetMgrModules(context, cephclient.AdminTestClusterInfo("ns"))
+	assert.NoError(t, err)
+
+	status := cephclient.CephStatus{}
+	health, message := moduleHealth("balancer", modules, status)
+	assert.Equal(t, cephv1.MgrModuleHealthy, health)
+	assert.Empty(t, message)
+
+	health, message = moduleHealth("k8sevents", modules, status)
+	assert.Equal(t, cephv1.MgrModuleUnhealthy, health)
+	assert.Equal(t, "kubernetes module not found", message)
+
+	health, message = moduleHealth("telemetry", modules, status)
+	assert.Equal(t, cephv1.MgrModuleUnhealthy, health)
+	assert.Equal(t, "module is not enabled", message)
+
+	health, _ = moduleHealth("unknown", modules, status)
+	assert.Equal(t, cephv1.MgrModuleUnhealthy, health)
+
+	status.Health.Checks = map[string]cephclient.CheckMessage{
+		"MGR_MODULE_ERROR": {Severity: "HEALTH_ERR", Summary: cephclient.Summary{Message: "Module 'iostat' has failed: boom"}},
+	}
+	health, message = moduleHealth("iostat", modules, status)
+	assert.Equal(t, cephv1.MgrModuleUnhealthy, health)
+	assert.Equal(t, "Module 'iostat' has failed: boom", message)
+	health, _ = moduleHealth("balancer", modules, status)
+	assert.Equal(t, cephv1.MgrModuleHealthy, health)
+}
+
+func TestCephMgrModuleController(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	os.Setenv("ROOK_LOG_LEVEL", "DEBUG")
+
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace},
+		Status: cephv1.ClusterStatus{
+			Phase:      cephv1.ConditionReady,
+			CephStatus: &cephv1.CephStatus{Health: "HEALTH_OK"},
+		},
+	}
+	newMgrModule := func(name string, spec cephv1.MgrModuleSpec) *cephv1.CephMgrModule {
+		return &cephv1.CephMgrModule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  namespace,
+				Finalizers: []string{"cephmgrmodule.ceph.rook.io"},
+			},
+			TypeMeta: metav1.TypeMeta{Kind: "CephMgrModule"},
+			Spec:     spec,
+		}
+	}
+
+	setup := func(t *testing.T, mgrModule *cephv1.CephMgrModule) (*ReconcileCephMgrModule, *[]string) {
+		commands := []string{}
+		mgrDump := mgrDumpOutput
+		executor := &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				switch {
+				case args[0] == "mgr" && args[1] == "dump":
+					return mgrDump, nil
+				case args[0] == "status":
+					return `{"health":{"checks":{},"status":"HEALTH_OK"}}`, nil
+				case args[0] == "versions":
+					return `{"mgr":{"ceph version 19.2.0 (0000000000000000000000000000000000000000) squid (stable)":1}}`, nil
+				case args[0] == "mgr" && args[1] == "module":
+					commands = append(commands, strings.Join(args[:4], " "))
+					if args[2] == "enable" {
+						mgrDump = strings.Replace(mgrDump, `"modules":["iostat"]`, `"modules":["iostat","`+args[3]+`"]`, 1)
+					}
+					return "", nil
+				case args[0] == "config" && args[1] == "rm":
+					commands = append(commands, strings.Join(args[:4], " "))
+					return "", nil
+				}
+				return "", nil
+			},
+			MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+				if args[0] == "config" && args[1] == "rm" {
+					commands = append(commands, strings.Join(args[:4], " "))
+					return "", nil
+				}
+				if args[0] == "config" && args[1] == "assimilate-conf" {
+					input, err := os.ReadFile(args[3])
+					assert.NoError(t, err)
+					commands = append(commands, "config assimilate-conf "+strings.Join(strings.Fields(string(input)), " "))
+					return "", nil
+				}
+				return "", errors.Errorf("unexpected command %v", args)
+			},
+		}
+
+		s := scheme.Scheme
+		s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephMgrModule{}, &cephv1.CephMgrModuleList{}, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+		cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(mgrModule, cephCluster).WithStatusSubresource(mgrModule).Build()
+		c := &clusterd.Context{
+			Executor:  executor,
+			Clientset: testop.New(t, 1),
+			Client:    cl,
+			ConfigDir: t.TempDir(),
+		}
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon", Namespace: namespace},
+			Data: map[string][]byte{
+				"fsid":         []byte("fsid"),
+				"mon-secret":   []byte("monsecret"),
+				"admin-secret": []byte("adminsecret"),
+			},
+			Type: k8sutil.RookType,
+		}
+		_, err := c.Clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+		assert.NoError(t, err)
+
+		return &ReconcileCephMgrModule{
+			client:           cl,
+			context:          c,
+			opManagerContext: ctx,
+			recorder:         events.NewFakeRecorder(50),
+		}, &commands
+	}
+	req := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
+	}
+
+	t.Run("enable the module and apply its settings", func(t *testing.T) {
+		mgrModule := newMgrModule("telemetry", cephv1.MgrModuleSpec{Settings: map[string]string{"interval": "24"}})
+		r, commands := setup(t, mgrModule)
+
+		res, err := r.Reconcile(ctx, req("telemetry"))
+		assert.NoError(t, err)
+		assert.Equal(t, healthCheckInterval, res.RequeueAfter)
+		assert.Equal(t, []string{"mgr module enable telemetry", "config assimilate-conf [mgr] mgr/telemetry/interval = 24"}, *commands)
+
+		assert.NoError(t, r.client.Get(ctx, req("telemetry").NamespacedName, mgrModule))
+		assert.Equal(t, cephv1.ConditionReady, mgrModule.Status.Phase)
+		assert.Equal(t, cephv1.MgrModuleHealthy, mgrModule.Status.Health)
+		assert.Equal(t, []string{"interval"}, mgrModule.Status.Settings)
+
+		// a setting removed from the spec is removed from the ceph config
+		mgrModule.Spec.Settings = map[string]string{"enabled": "true"}
+		assert.NoError(t, r.client.Update(ctx, mgrModule))
+		*commands = []string{}
+		_, err = r.Reconcile(ctx, req("telemetry"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"config assimilate-conf [mgr] mgr/telemetry/enabled = true", "config rm mgr mgr/telemetry/interval"}, *commands)
+		assert.NoError(t, r.client.Get(ctx, req("telemetry").NamespacedName, mgrModule))
+		assert.Equal(t, []string{"enabled"}, mgrModule.Status.Settings)
+	})
+
+	t.Run("module name in the spec", func(t *testing.T) {
+		mgrModule := newMgrModule("autoscaler", cephv1.MgrModuleSpec{Name: "balancer", Settings: map[string]string{"mode": "upmap"}})
+		r, commands := setup(t, mgrModule)
+
+		_, err := r.Reconcile(ctx, req("autoscaler"))
+		assert.NoError(t, err)
+		// the balancer is always on
+		assert.Equal(t, []string{"config assimilate-conf [mgr] mgr/balancer/mode = upmap"}, *commands)
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		mgrModule := newMgrModule("balancer", cephv1.MgrModuleSpec{Settings: map[string]string{"mode": "fast"}})
+		r, commands := setup(t, mgrModule)
+
+		_, err := r.Reconcile(ctx, req("balancer"))
+		assert.ErrorContains(t, err, `invalid value "fast" for option "mode"`)
+		assert.Empty(t, *commands)
+		assert.NoError(t, r.client.Get(ctx, req("balancer").NamespacedName, mgrModule))
+		assert.Equal(t, cephv1.ConditionFailure, mgrModule.Status.Phase)
+		assert.Contains(t, mgrModule.Status.Message, "value must be one of")
+	})
+
+	t.Run("unknown module", func(t *testing.T) {
+		mgrModule := newMgrModule("unknown", cephv1.MgrModuleSpec{})
+		r, _ := setup(t, mgrModule)
+
+		_, err := r.Reconcile(ctx, req("unknown"))
+		assert.ErrorContains(t, err, `mgr module "unknown" not found`)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		mgrModule := newMgrModule("iostat", cephv1.MgrModuleSpec{})
+		mgrModule.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		mgrModule.Status = &cephv1.CephMgrModuleStatus{Phase: cephv1.ConditionReady, Settings: []string{"interval"}}
+		r, commands := setup(t, mgrModule)
+
+		_, err := r.Reconcile(ctx, req("iostat"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"config rm mgr mgr/iostat/interval", "mgr module disable iostat"}, *commands)
+	})
+
+	t.Run("delete a module enabled by the cluster", func(t *testing.T) {
+		cephCluster.Spec.Mgr.Modules = []cephv1.Module{{Name: "iostat", Enabled: true}}
+		defer func() { cephCluster.Spec.Mgr.Modules = nil }()
+		mgrModule := newMgrModule("iostat", cephv1.MgrModuleSpec{})
+		mgrModule.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		mgrModule.Status = &cephv1.CephMgrModuleStatus{Phase: cephv1.ConditionReady, Settings: []string{"interval"}}
+		r, commands := setup(t, mgrModule)
+
+		_, err := r.Reconcile(ctx, req("iostat"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"config rm mgr mgr/iostat/interval"}, *commands)
+	})
+}
+
+func TestEnabledByCluster(t *testing.T) {
+	spec := &cephv1.ClusterSpec{}
+	assert.True(t, enabledByCluster("prometheus", spec))
+	assert.False(t, enabledByCluster("dashboard", spec))
+	assert.False(t, enabledByCluster("iostat", spec))
+
+	spec.Monitoring.MetricsDisabled = true
+	spec.Dashboard.Enabled = true
+	spec.Mgr.Modules = []cephv1.Module{{Name: "iostat", Enabled: true}, {Name: "nfs", Enabled: false}}
+	assert.False(t, enabledByCluster("prometheus", spec))
+	assert.True(t, enabledByCluster("dashboard", spec))
+	assert.True(t, enabledByCluster("iostat", spec))
+	assert.False(t, enabledByCluster("nfs", spec))
+}
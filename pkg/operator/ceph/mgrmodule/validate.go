@@ -0,0 +1,52 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgrmodule
+
+import (
+	"slices"
+
+	"github.com/pkg/errors"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+)
+
+// validateSettings checks that each setting is an option of the module with a valid value
+func validateSettings(module *cephclient.MgrModuleInfo, settings map[string]string) error {
+	// sort the options for a stable error message
+	options := make([]string, 0, len(settings))
+	for option := range settings {
+		options = append(options, option)
+	}
+	slices.Sort(options)
+
+	for _, name := range options {
+		option, ok := module.ModuleOptions[name]
+		if !ok {
+			return errors.Errorf("option %q not found in mgr module %q", name, module.Name)
+		}
+		if err := validateSetting(option, settings[name]); err != nil {
+			return errors.Wrapf(err, "invalid value %q for option %q", settings[name], name)
+		}
+	}
+	return nil
+}
+
+// validateSetting checks the value against the type, the allowed values, and the bounds of the
+// option
+func validateSetting(option cephclient.MgrModuleOption, value string) error {
+	return config.ValidateValue(option.Type, option.EnumAllowed, option.Min, option.Max, value)
+}
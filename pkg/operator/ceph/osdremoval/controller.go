@@ -0,0 +1,385 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package osdremoval to manage the removal of OSDs from a rook cluster.
+package osdremoval
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	daemonosd "github.com/rook/rook/pkg/daemon/ceph/osd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/log"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-osd-removal-controller"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+// drainCheckInterval is how often the progress of draining OSDs is checked
+var drainCheckInterval = 30 * time.Second
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       reflect.TypeFor[cephv1.CephOSDRemoval]().Name(),
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileCephOSDRemoval reconciles a CephOSDRemoval object
+type ReconcileCephOSDRemoval struct {
+	client           client.Client
+	context          *clusterd.Context
+	clusterInfo      *cephclient.ClusterInfo
+	opManagerContext context.Context
+	recorder         events.EventRecorder
+}
+
+// Add creates a new CephOSDRemoval Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	return add(mgr, newReconciler(mgr, context, opManagerContext))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context) reconcile.Reconciler {
+	return &ReconcileCephOSDRemoval{
+		client:           mgr.GetClient(),
+		context:          context,
+		opManagerContext: opManagerContext,
+		recorder:         mgr.GetEventRecorder("rook-" + controllerName),
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephOSDRemoval CRD object
+	return c.Watch(
+		source.Kind(
+			mgr.GetCache(),
+			&cephv1.CephOSDRemoval{TypeMeta: controllerTypeMeta},
+			&handler.TypedEnqueueRequestForObject[*cephv1.CephOSDRemoval]{},
+			opcontroller.WatchControllerPredicate[*cephv1.CephOSDRemoval](mgr.GetScheme()),
+		),
+	)
+}
+
+// Reconcile reads that state of the cluster for a CephOSDRemoval object and makes changes based on the state read
+// and what is in the CephOSDRemoval.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileCephOSDRemoval) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer opcontroller.RecoverAndLogException()
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
+	reconcileResponse, osdRemoval, err := r.reconcile(request)
+	return reporting.ReportReconcileResult(logger, r.recorder, request, &osdRemoval, reconcileResponse, err)
+}
+
+func (r *ReconcileCephOSDRemoval) reconcile(request reconcile.Request) (reconcile.Result, cephv1.CephOSDRemoval, error) {
+	// Fetch the CephOSDRemoval instance
+	osdRemoval := &cephv1.CephOSDRemoval{}
+	err := r.client.Get(r.opManagerContext, request.NamespacedName, osdRemoval)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			log.NamedDebug(request.NamespacedName, logger, "cephOSDRemoval resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, *osdRemoval, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, *osdRemoval, errors.Wrap(err, "failed to get cephOSDRemoval")
+	}
+	observedGeneration := osdRemoval.ObjectMeta.Generation
+
+	// Nothing is cleaned up when the CR is deleted, the OSDs that were not purged yet stay out
+	if !osdRemoval.GetDeletionTimestamp().IsZero() {
+		log.NamedDebug(request.NamespacedName, logger, "osd removal is being deleted")
+		return reconcile.Result{}, *osdRemoval, nil
+	}
+
+	// The CR was just created, initializing status fields
+	if osdRemoval.Status == nil {
+		osdRemoval.Status = &cephv1.CephOSDRemovalStatus{Phase: cephv1.OSDRemovalDraining}
+		if err := r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, osdRemoval.Status); err != nil {
+			return reconcile.Result{}, *osdRemoval, errors.Wrapf(err, "failed to initialize osd removal %q status", request.NamespacedName)
+		}
+	}
+
+	// The OSDs cannot be changed, so a finished removal has nothing left to do
+	if isFinished(osdRemoval.Status.Phase) {
+		log.NamedDebug(request.NamespacedName, logger, "osd removal is %s", osdRemoval.Status.Phase)
+		return reconcile.Result{}, *osdRemoval, nil
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	cephCluster, isReadyToReconcile, _, reconcileResponse := opcontroller.IsReadyToReconcile(r.opManagerContext, r.client, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		return reconcileResponse, *osdRemoval, nil
+	}
+
+	// Populate clusterInfo during each reconcile
+	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, r.opManagerContext, request.NamespacedName.Namespace, &cephCluster.Spec)
+	if err != nil {
+		return reconcile.Result{}, *osdRemoval, errors.Wrap(err, "failed to populate cluster info")
+	}
+	r.clusterInfo.Context = r.opManagerContext
+
+	err = r.reconcileOSDs(osdRemoval, cephCluster.Spec.DisruptionManagement.PGHealthyRegex)
+	if err != nil {
+		if strings.Contains(err.Error(), opcontroller.UninitializedCephConfigError) {
+			log.NamedInfo(request.NamespacedName, logger, opcontroller.OperatorNotInitializedMessage)
+			return opcontroller.WaitForRequeueIfOperatorNotInitialized, *osdRemoval, nil
+		}
+		osdRemoval.Status.Message = err.Error()
+		if statusErr := r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, osdRemoval.Status); statusErr != nil {
+			return reconcile.Result{}, *osdRemoval, errors.Wrapf(statusErr, "failed to set status for osd removal %q", request.NamespacedName)
+		}
+		return reconcile.Result{}, *osdRemoval, errors.Wrapf(err, "failed to reconcile osd removal %q", request.NamespacedName)
+	}
+
+	err = r.updateStatus(observedGeneration, request.NamespacedName, osdRemoval.Status)
+	if err != nil {
+		return reconcile.Result{}, *osdRemoval, errors.Wrapf(err, "failed to set status for osd removal %q", request.NamespacedName)
+	}
+
+	if !isFinished(osdRemoval.Status.Phase) {
+		log.NamedDebug(request.NamespacedName, logger, "checking the osd removal progress again in %s", drainCheckInterval)
+		return reconcile.Result{RequeueAfter: drainCheckInterval}, *osdRemoval, nil
+	}
+
+	log.NamedInfo(request.NamespacedName, logger, "osd removal %s. %s", strings.ToLower(string(osdRemoval.Status.Phase)), osdRemoval.Status.Message)
+	return reconcile.Result{}, *osdRemoval, nil
+}
+
+// reconcileOSDs marks the OSDs out, updates their progress in the status and purges each OSD that is
+// ready to be removed
+func (r *ReconcileCephOSDRemoval) reconcileOSDs(osdRemoval *cephv1.CephOSDRemoval, pgHealthyRegex string) error {
+	nsName := opcontroller.NsName(osdRemoval.Namespace, osdRemoval.Name)
+	status := osdRemoval.Status
+	if status.StartTime == nil {
+		status.StartTime = &metav1.Time{Time: time.Now()}
+	}
+
+	osdDump, err := cephclient.GetOSDDump(r.context, r.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get osd dump")
+	}
+	osdUsage, err := cephclient.GetOSDUsage(r.context, r.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get osd usage")
+	}
+	status.CleanPGsPercent, err = cephclient.CleanPGsPercent(r.context, r.clusterInfo, pgHealthyRegex)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the clean PGs")
+	}
+	waitForRebalance := osdRemoval.Spec.WaitForRebalance == nil || *osdRemoval.Spec.WaitForRebalance
+
+	osds := make([]cephv1.OSDRemovalOSDStatus, 0, len(osdRemoval.Spec.OSDIDs))
+	for _, id := range osdRemoval.Spec.OSDIDs {
+		osd := osdStatus(status.OSDs, id)
+		if !isFinished(osd.Phase) {
+			r.reconcileOSD(nsName, osdRemoval.Spec, &osd, osdDump, osdUsage, status.CleanPGsPercent, waitForRebalance)
+		}
+		osds = append(osds, osd)
+	}
+	status.OSDs = osds
+
+	status.Phase, status.Message = removalPhase(osds)
+	if isFinished(status.Phase) && status.CompletionTime == nil {
+		status.CompletionTime = &metav1.Time{Time: time.Now()}
+	}
+	return nil
+}
+
+// reconcileOSD moves a single OSD forward in its removal. Errors are reported in the OSD status and
+// retried at the next check so that one OSD does not block the others.
+func (r *ReconcileCephOSDRemoval) reconcileOSD(nsName types.NamespacedName, spec cephv1.OSDRemovalSpec, osd *cephv1.OSDRemovalOSDStatus,
+	osdDump *cephclient.OSDDump, osdUsage *cephclient.OSDUsage, cleanPGsPercent int, waitForRebalance bool,
+) {
+	_, in, err := osdDump.StatusByID(int64(osd.ID))
+	if err != nil {
+		if osd.Phase == cephv1.OSDRemovalDraining {
+			// the OSD was purged but the status could not be updated before
+			osd.Phase = cephv1.OSDRemovalCompleted
+			osd.Message = "the osd was purged"
+			return
+		}
+		osd.Phase = cephv1.OSDRemovalFailed
+		osd.Message = "the osd does not exist"
+		return
+	}
+
+	pgs := osdPGs(osdUsage, osd.ID)
+	if osd.Phase == "" {
+		osd.Phase = cephv1.OSDRemovalDraining
+		osd.InitialPGs = pgs
+	}
+	osd.PGs = pgs
+	osd.MigratedPercent = migratedPercent(osd.InitialPGs, pgs)
+
+	if in == 1 {
+		if err := cephclient.OSDOut(r.context, r.clusterInfo, osd.ID); err != nil {
+			osd.Message = err.Error()
+			return
+		}
+		log.NamedInfo(nsName, logger, "marked osd.%d out to move its %d PGs to other osds", osd.ID, pgs)
+	}
+
+	if !spec.ForceRemoval {
+		safeToDestroy, err := cephclient.OsdSafeToDestroy(r.context, r.clusterInfo, osd.ID)
+		if err != nil {
+			osd.Message = err.Error()
+			return
+		}
+		if !safeToDestroy {
+			osd.Message = "waiting for the osd to be safe to destroy"
+			return
+		}
+		if waitForRebalance && cleanPGsPercent < 100 {
+			osd.Message = "waiting for all the PGs to be clean"
+			return
+		}
+	}
+
+	log.NamedInfo(nsName, logger, "purging osd.%d", osd.ID)
+	if err := daemonosd.PurgeOSD(r.context, r.clusterInfo, osd.ID, spec.PreservePVC); err != nil {
+		osd.Message = err.Error()
+		return
+	}
+	osd.Phase = cephv1.OSDRemovalCompleted
+	osd.Message = "the osd was purged"
+	log.NamedInfo(nsName, logger, "removed osd.%d", osd.ID)
+}
+
+// osdStatus returns the status of the OSD with the given ID, or a new status if the OSD is not in the list
+func osdStatus(osds []cephv1.OSDRemovalOSDStatus, id int) cephv1.OSDRemovalOSDStatus {
+	for _, osd := range osds {
+		if osd.ID == id {
+			return osd
+		}
+	}
+	return cephv1.OSDRemovalOSDStatus{ID: id}
+}
+
+// osdPGs returns the number of PGs on the OSD from the osd usage
+func osdPGs(osdUsage *cephclient.OSDUsage, id int) int {
+	for _, node := range osdUsage.OSDNodes {
+		if node.ID != id {
+			continue
+		}
+		pgs, err := node.Pgs.Int64()
+		if err != nil {
+			return 0
+		}
+		return int(pgs)
+	}
+	return 0
+}
+
+// migratedPercent returns the percentage of the initial PGs that are no longer on the OSD
+func migratedPercent(initialPGs, pgs int) int {
+	if initialPGs == 0 {
+		return 100
+	}
+	if pgs >= initialPGs {
+		return 0
+	}
+	return (initialPGs - pgs) * 100 / initialPGs
+}
+
+// removalPhase returns the phase of the whole removal from the phases of the OSDs. The removal is
+// draining until every OSD is either completed or failed.
+func removalPhase(osds []cephv1.OSDRemovalOSDStatus) (cephv1.OSDRemovalPhase, string) {
+	completed := 0
+	failed := []string{}
+	for _, osd := range osds {
+		switch osd.Phase {
+		case cephv1.OSDRemovalCompleted:
+			completed++
+		case cephv1.OSDRemovalFailed:
+			failed = append(failed, fmt.Sprintf("osd.%d", osd.ID))
+		}
+	}
+
+	switch {
+	case completed+len(failed) < len(osds):
+		return cephv1.OSDRemovalDraining, fmt.Sprintf("%d of %d osds removed", completed, len(osds))
+	case len(failed) > 0:
+		return cephv1.OSDRemovalFailed, fmt.Sprintf("failed to remove %s", strings.Join(failed, ", "))
+	default:
+		return cephv1.OSDRemovalCompleted, "all osds removed"
+	}
+}
+
+func isFinished(phase cephv1.OSDRemovalPhase) bool {
+	return phase == cephv1.OSDRemovalCompleted || phase == cephv1.OSDRemovalFailed
+}
+
+// updateStatus updates an object with a given status
+func (r *ReconcileCephOSDRemoval) updateStatus(observedGeneration int64, name types.NamespacedName, status *cephv1.CephOSDRemovalStatus) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		osdRemoval := &cephv1.CephOSDRemoval{}
+		if err := r.client.Get(r.opManagerContext, name, osdRemoval); err != nil {
+			if kerrors.IsNotFound(err) {
+				log.NamedDebug(name, logger, "CephOSDRemoval resource not found. Ignoring since object must be deleted.")
+				return nil
+			}
+			return errors.Wrapf(err, "failed to retrieve osd removal %q to update status to %q", name, status.Phase)
+		}
+
+		osdRemoval.Status = status.DeepCopy()
+		if observedGeneration != k8sutil.ObservedGenerationNotAvailable {
+			osdRemoval.Status.ObservedGeneration = observedGeneration
+		}
+		if err := reporting.UpdateStatus(r.client, osdRemoval); err != nil {
+			return errors.Wrapf(err, "failed to set osd removal %q status to %q", name, status.Phase)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.NamedDebug(name, logger, "osd removal status updated to %q", status.Phase)
+	return nil
+}
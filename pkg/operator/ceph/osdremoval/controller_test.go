@@ -0,0 +1,321 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osdremoval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type fakeOSD struct {
+	up   bool
+	in   bool
+	pgs  int
+	safe bool
+}
+
+// fakeCeph simulates the osd commands used to remove osds
+type fakeCeph struct {
+	osds      map[int]*fakeOSD
+	uncleanPG int
+	commands  []string
+}
+
+func (c *fakeCeph) execute(args []string) (string, error) {
+	if i := slices.IndexFunc(args, func(arg string) bool { return strings.HasPrefix(arg, "--") }); i >= 0 {
+		args = args[:i]
+	}
+	toJSON := func(v any) (string, error) {
+		out, err := json.Marshal(v)
+		return string(out), err
+	}
+	osdID := func(arg string) int {
+		id, _ := strconv.Atoi(strings.TrimPrefix(arg, "osd."))
+		return id
+	}
+
+	if args[0] == "status" {
+		return toJSON(cephclient.CephStatus{PgMap: cephclient.PgMap{
+			PgsByState: []cephclient.PgStateEntry{{StateName: "active+clean", Count: 100 - c.uncleanPG}, {StateName: "active+remapped", Count: c.uncleanPG}},
+			NumPgs:     100,
+		}})
+	}
+
+	switch strings.Join(args[:2], " ") {
+	case "osd dump":
+		dump := map[string][]map[string]int{"osds": {}}
+		for id, osd := range c.osds {
+			dump["osds"] = append(dump["osds"], map[string]int{"osd": id, "up": boolToInt(osd.up), "in": boolToInt(osd.in)})
+		}
+		return toJSON(dump)
+	case "osd df":
+		usage := map[string][]map[string]int{"nodes": {}}
+		for id, osd := range c.osds {
+			usage["nodes"] = append(usage["nodes"], map[string]int{"id": id, "pgs": osd.pgs})
+		}
+		return toJSON(usage)
+	case "osd safe-to-destroy":
+		id := osdID(args[2])
+		if c.osds[id].safe {
+			return toJSON(cephclient.SafeToDestroyStatus{SafeToDestroy: []int{id}})
+		}
+		return toJSON(cephclient.SafeToDestroyStatus{})
+	case "osd find":
+		return `{"crush_location":{"host":"node-a"}}`, nil
+	case "osd out":
+		c.osds[osdID(args[2])].in = false
+	case "osd down":
+		c.osds[osdID(args[2])].up = false
+	case "osd crush":
+		// removing the host of the osd
+	case "osd purge":
+		delete(c.osds, osdID(args[2]))
+	case "crash ls":
+		return "[]", nil
+	default:
+		return "", nil
+	}
+	c.commands = append(c.commands, strings.Join(args, " "))
+	return "", nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func TestCephOSDRemovalController(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	drainCheckInterval = time.Millisecond
+
+	newOSDRemoval := func(spec cephv1.OSDRemovalSpec) *cephv1.CephOSDRemoval {
+		return &cephv1.CephOSDRemoval{
+			ObjectMeta: metav1.ObjectMeta{Name: "retire", Namespace: namespace},
+			TypeMeta:   metav1.TypeMeta{Kind: "CephOSDRemoval"},
+			Spec:       spec,
+		}
+	}
+
+	setup := func(t *testing.T, osdRemoval *cephv1.CephOSDRemoval, ceph *fakeCeph) *ReconcileCephOSDRemoval {
+		executor := &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				return ceph.execute(args)
+			},
+			MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+				return ceph.execute(args)
+			},
+		}
+		cephCluster := &cephv1.CephCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace},
+			Status: cephv1.ClusterStatus{
+				Phase:      cephv1.ConditionReady,
+				CephStatus: &cephv1.CephStatus{Health: "HEALTH_OK"},
+			},
+		}
+
+		s := scheme.Scheme
+		s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephOSDRemoval{}, &cephv1.CephOSDRemovalList{}, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+		cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(osdRemoval, cephCluster).WithStatusSubresource(osdRemoval).Build()
+		c := &clusterd.Context{
+			Executor:  executor,
+			Clientset: testop.New(t, 1),
+			Client:    cl,
+			ConfigDir: t.TempDir(),
+		}
+		_, err := c.Clientset.CoreV1().Secrets(namespace).Create(ctx, &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon", Namespace: namespace},
+			Data: map[string][]byte{
+				"fsid":         []byte("fsid"),
+				"mon-secret":   []byte("monsecret"),
+				"admin-secret": []byte("adminsecret"),
+			},
+			Type: k8sutil.RookType,
+		}, metav1.CreateOptions{})
+		require.NoError(t, err)
+		for id := range ceph.osds {
+			_, err := c.Clientset.AppsV1().Deployments(namespace).Create(ctx, &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("rook-ceph-osd-%d", id), Namespace: namespace},
+			}, metav1.CreateOptions{})
+			require.NoError(t, err)
+		}
+
+		return &ReconcileCephOSDRemoval{
+			client:           cl,
+			context:          c,
+			opManagerContext: ctx,
+			recorder:         events.NewFakeRecorder(50),
+		}
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "retire", Namespace: namespace}}
+	getStatus := func(t *testing.T, r *ReconcileCephOSDRemoval) *cephv1.CephOSDRemovalStatus {
+		osdRemoval := &cephv1.CephOSDRemoval{}
+		require.NoError(t, r.client.Get(ctx, req.NamespacedName, osdRemoval))
+		return osdRemoval.Status
+	}
+	deploymentExists := func(t *testing.T, r *ReconcileCephOSDRemoval, id int) bool {
+		_, err := r.context.Clientset.AppsV1().Deployments(namespace).Get(ctx, fmt.Sprintf("rook-ceph-osd-%d", id), metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+
+	t.Run("drain and purge the osds", func(t *testing.T) {
+		ceph := &fakeCeph{osds: map[int]*fakeOSD{
+			1: {up: true, in: true, pgs: 40},
+			2: {up: true, in: true, pgs: 20},
+			3: {up: true, in: true, pgs: 30},
+		}}
+		r := setup(t, newOSDRemoval(cephv1.OSDRemovalSpec{OSDIDs: []int{1, 2}}), ceph)
+
+		// the osds are marked out and start draining
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, drainCheckInterval, res.RequeueAfter)
+		assert.Equal(t, []string{"osd out 1", "osd out 2"}, ceph.commands)
+		status := getStatus(t, r)
+		assert.Equal(t, cephv1.OSDRemovalDraining, status.Phase)
+		assert.Equal(t, "0 of 2 osds removed", status.Message)
+		assert.NotNil(t, status.StartTime)
+		assert.Equal(t, 100, status.CleanPGsPercent)
+		require.Len(t, status.OSDs, 2)
+		assert.Equal(t, cephv1.OSDRemovalOSDStatus{ID: 1, Phase: cephv1.OSDRemovalDraining, InitialPGs: 40, PGs: 40, Message: "waiting for the osd to be safe to destroy"}, status.OSDs[0])
+
+		// osd.1 moved its PGs, but the cluster is not clean yet
+		ceph.commands = nil
+		ceph.osds[1].pgs = 0
+		ceph.osds[1].safe = true
+		ceph.osds[2].pgs = 5
+		ceph.uncleanPG = 10
+		_, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Empty(t, ceph.commands)
+		status = getStatus(t, r)
+		assert.Equal(t, 90, status.CleanPGsPercent)
+		assert.Equal(t, 100, status.OSDs[0].MigratedPercent)
+		assert.Equal(t, "waiting for all the PGs to be clean", status.OSDs[0].Message)
+		assert.Equal(t, 75, status.OSDs[1].MigratedPercent)
+		assert.Equal(t, 5, status.OSDs[1].PGs)
+
+		// osd.1 is purged once the cluster is clean
+		ceph.uncleanPG = 0
+		_, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"osd down 1", "osd purge osd.1", "osd crush rm node-a"}, ceph.commands)
+		assert.False(t, deploymentExists(t, r, 1))
+		assert.True(t, deploymentExists(t, r, 2))
+		status = getStatus(t, r)
+		assert.Equal(t, cephv1.OSDRemovalDraining, status.Phase)
+		assert.Equal(t, cephv1.OSDRemovalCompleted, status.OSDs[0].Phase)
+		assert.Equal(t, "1 of 2 osds removed", status.Message)
+
+		// osd.2 is purged and the removal is completed
+		ceph.commands = nil
+		ceph.osds[2].pgs = 0
+		ceph.osds[2].safe = true
+		res, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Zero(t, res.RequeueAfter)
+		assert.Equal(t, []string{"osd down 2", "osd purge osd.2", "osd crush rm node-a"}, ceph.commands)
+		assert.Contains(t, ceph.osds, 3)
+		status = getStatus(t, r)
+		assert.Equal(t, cephv1.OSDRemovalCompleted, status.Phase)
+		assert.Equal(t, "all osds removed", status.Message)
+		assert.NotNil(t, status.CompletionTime)
+
+		// nothing more is done once completed
+		ceph.commands = nil
+		_, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Empty(t, ceph.commands)
+	})
+
+	t.Run("force removal does not wait", func(t *testing.T) {
+		ceph := &fakeCeph{osds: map[int]*fakeOSD{
+			1: {up: false, in: false, pgs: 40},
+		}, uncleanPG: 40}
+		r := setup(t, newOSDRemoval(cephv1.OSDRemovalSpec{OSDIDs: []int{1}, ForceRemoval: true}), ceph)
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"osd down 1", "osd purge osd.1", "osd crush rm node-a"}, ceph.commands)
+		assert.Equal(t, cephv1.OSDRemovalCompleted, getStatus(t, r).Phase)
+	})
+
+	t.Run("do not wait for rebalance", func(t *testing.T) {
+		ceph := &fakeCeph{osds: map[int]*fakeOSD{
+			1: {up: true, in: false, safe: true},
+		}, uncleanPG: 5}
+		waitForRebalance := false
+		r := setup(t, newOSDRemoval(cephv1.OSDRemovalSpec{OSDIDs: []int{1}, WaitForRebalance: &waitForRebalance}), ceph)
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, cephv1.OSDRemovalCompleted, getStatus(t, r).Phase)
+	})
+
+	t.Run("unknown osd fails", func(t *testing.T) {
+		ceph := &fakeCeph{osds: map[int]*fakeOSD{
+			1: {up: true, in: false, safe: true},
+		}}
+		r := setup(t, newOSDRemoval(cephv1.OSDRemovalSpec{OSDIDs: []int{1, 9}}), ceph)
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		status := getStatus(t, r)
+		assert.Equal(t, cephv1.OSDRemovalFailed, status.Phase)
+		assert.Equal(t, "failed to remove osd.9", status.Message)
+		assert.Equal(t, cephv1.OSDRemovalCompleted, status.OSDs[0].Phase)
+		assert.Equal(t, "the osd does not exist", status.OSDs[1].Message)
+	})
+}
+
+func TestMigratedPercent(t *testing.T) {
+	assert.Equal(t, 0, migratedPercent(40, 40))
+	assert.Equal(t, 0, migratedPercent(40, 45))
+	assert.Equal(t, 25, migratedPercent(40, 30))
+	assert.Equal(t, 100, migratedPercent(40, 0))
+	assert.Equal(t, 100, migratedPercent(0, 0))
+}
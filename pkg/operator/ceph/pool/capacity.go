@@ -0,0 +1,186 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/util/log"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var defaultCapacityCheckInterval = 1 * time.Minute
+
+// capacityChecker periodically reports the usage and placement groups of a pool in the CephBlockPool status
+type capacityChecker struct {
+	context        *clusterd.Context
+	client         client.Client
+	clusterInfo    *cephclient.ClusterInfo
+	namespacedName types.NamespacedName
+	poolName       string
+	interval       time.Duration
+}
+
+func newCapacityChecker(context *clusterd.Context, client client.Client, clusterInfo *cephclient.ClusterInfo, cephBlockPool *cephv1.CephBlockPool) *capacityChecker {
+	c := &capacityChecker{
+		context:        context,
+		client:         client,
+		clusterInfo:    clusterInfo,
+		namespacedName: types.NamespacedName{Name: cephBlockPool.Name, Namespace: cephBlockPool.Namespace},
+		poolName:       cephBlockPool.ToNamedPoolSpec().Name,
+		interval:       defaultCapacityCheckInterval,
+	}
+
+	// allow overriding the check interval
+	if checkInterval := cephBlockPool.Spec.StatusCheck.Capacity.Interval; checkInterval != nil {
+		c.interval = checkInterval.Duration
+	}
+	return c
+}
+
+// checkCapacity refreshes the capacity status until the context is canceled
+func (c *capacityChecker) checkCapacity(ctx context.Context) {
+	// refresh the capacity immediately before starting the loop
+	c.refresh()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.NamedDebug(c.namespacedName, logger, "stopping monitoring the pool capacity")
+			return
+
+		case <-time.After(c.interval):
+			c.refresh()
+		}
+	}
+}
+
+func (c *capacityChecker) refresh() {
+	capacity, err := getPoolCapacity(c.context, c.clusterInfo, c.poolName)
+	message := ""
+	if err != nil {
+		log.NamedDebug(c.namespacedName, logger, "failed to get the pool capacity. %v", err)
+		message = err.Error()
+	}
+	if err := c.updateStatus(capacity, message); err != nil {
+		log.NamedError(c.namespacedName, logger, "failed to update the pool capacity status. %v", err)
+	}
+}
+
+// updateStatus sets the capacity in the CephBlockPool status. If the capacity could not be
+// retrieved, the previous values are kept with the error message.
+func (c *capacityChecker) updateStatus(capacity *cephv1.PoolCapacityStatus, message string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cephBlockPool := &cephv1.CephBlockPool{}
+		if err := c.client.Get(c.clusterInfo.Context, c.namespacedName, cephBlockPool); err != nil {
+			if kerrors.IsNotFound(err) {
+				log.NamedDebug(c.namespacedName, logger, "CephBlockPool resource not found for updating the capacity status, ignoring.")
+				return nil
+			}
+			return errors.Wrap(err, "failed to get pool")
+		}
+		if cephBlockPool.Status == nil {
+			cephBlockPool.Status = &cephv1.CephBlockPoolStatus{}
+		}
+
+		if capacity == nil {
+			capacity = cephBlockPool.Status.Capacity.DeepCopy()
+			if capacity == nil {
+				capacity = &cephv1.PoolCapacityStatus{}
+			}
+		}
+		capacity.LastChecked = time.Now().UTC().Format(time.RFC3339)
+		capacity.Message = message
+		cephBlockPool.Status.Capacity = capacity
+		return reporting.UpdateStatus(c.client, cephBlockPool)
+	})
+}
+
+// getPoolCapacity returns the usage and placement groups of the pool
+func getPoolCapacity(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, poolName string) (*cephv1.PoolCapacityStatus, error) {
+	stats, err := cephclient.GetPoolStats(context, clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	capacity := &cephv1.PoolCapacityStatus{}
+	found := false
+	for _, pool := range stats.Pools {
+		if pool.Name != poolName {
+			continue
+		}
+		found = true
+		capacity.StoredBytes = int64(pool.Stats.Stored)
+		capacity.UsedRawBytes = int64(pool.Stats.BytesUsed)
+		capacity.PercentUsed = int(math.Round(pool.Stats.PercentUsed * 100))
+		capacity.MaxAvailableBytes = int64(pool.Stats.MaxAvail)
+		capacity.Objects = int64(pool.Stats.Objects)
+		if pool.Stats.QuotaBytes > 0 || pool.Stats.QuotaObjects > 0 {
+			capacity.Quota = &cephv1.PoolQuotaStatus{
+				MaxBytes:           int64(pool.Stats.QuotaBytes),
+				BytesPercentUsed:   percentOf(pool.Stats.Stored, pool.Stats.QuotaBytes),
+				MaxObjects:         int64(pool.Stats.QuotaObjects),
+				ObjectsPercentUsed: percentOf(pool.Stats.Objects, pool.Stats.QuotaObjects),
+			}
+		}
+		if pool.Stats.CompressBytesUsed > 0 {
+			ratio := math.Round(pool.Stats.CompressUnderBytes/pool.Stats.CompressBytesUsed*100) / 100
+			capacity.CompressionRatio = &ratio
+		}
+	}
+	if !found {
+		return nil, errors.Errorf("pool %q not found in the pool stats", poolName)
+	}
+
+	pgs, err := cephclient.GetPoolPlacementGroups(context, clusterInfo, poolName)
+	if err != nil {
+		return nil, err
+	}
+	capacity.PlacementGroups.PGNum = pgs.PGNum
+	capacity.PlacementGroups.TargetPGNum = pgs.PGNumTarget
+
+	// the autoscaler status is only available when the mgr is running
+	autoscaleStatus, err := cephclient.GetPoolAutoscaleStatus(context, clusterInfo)
+	if err != nil {
+		log.NamespacedDebug(clusterInfo.Namespace, logger, "failed to get the pg autoscaler status of pool %q. %v", poolName, err)
+		return capacity, nil
+	}
+	for _, status := range autoscaleStatus {
+		if status.Name == poolName {
+			capacity.PlacementGroups.AutoscaleMode = status.AutoscaleMode
+			capacity.PlacementGroups.RecommendedPGNum = status.PGNumFinal
+		}
+	}
+	return capacity, nil
+}
+
+// percentOf returns the rounded percentage of the quota that is used, or 0 if there is no quota
+func percentOf(used, quota float64) int {
+	if quota <= 0 {
+		return 0
+	}
+	return int(math.Round(used / quota * 100))
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testPoolDFDetail = `{"pools":[
+{"name":".mgr","id":1,"stats":{"stored":1000,"bytes_used":3000,"percent_used":0.0001,"max_avail":1000000,"objects":2}},
+{"name":"replicapool","id":2,"stats":{"stored":4000000,"bytes_used":12000000,"percent_used":0.2857,"max_avail":10000000,
+"objects":1200,"quota_bytes":8000000,"quota_objects":0,"compress_bytes_used":500000,"compress_under_bytes":1250000}}]}`
+
+func newCapacityTestExecutor(autoscaleErr error) *exectest.MockExecutor {
+	return &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "df" && args[1] == "detail":
+				return testPoolDFDetail, nil
+			case args[0] == "osd" && args[1] == "pool" && args[2] == "ls":
+				return `[{"pool_name":"replicapool","pg_num":24,"pg_num_target":32}]`, nil
+			case args[0] == "osd" && args[1] == "pool" && args[2] == "autoscale-status":
+				if autoscaleErr != nil {
+					return "", autoscaleErr
+				}
+				return `[{"pool_name":"replicapool","pg_autoscale_mode":"on","pg_num_target":32,"pg_num_final":64}]`, nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+	}
+}
+
+func TestGetPoolCapacity(t *testing.T) {
+	clusterInfo := cephclient.AdminTestClusterInfo("mycluster")
+
+	t.Run("pool capacity", func(t *testing.T) {
+		context := &clusterd.Context{Executor: newCapacityTestExecutor(nil)}
+		capacity, err := getPoolCapacity(context, clusterInfo, "replicapool")
+		assert.NoError(t, err)
+		ratio := 2.5
+		assert.Equal(t, &cephv1.PoolCapacityStatus{
+			StoredBytes:       4000000,
+			UsedRawBytes:      12000000,
+			PercentUsed:       29,
+			MaxAvailableBytes: 10000000,
+			Objects:           1200,
+			Quota:             &cephv1.PoolQuotaStatus{MaxBytes: 8000000, BytesPercentUsed: 50},
+			CompressionRatio:  &ratio,
+			PlacementGroups: cephv1.PoolPlacementGroupsStatus{
+				PGNum:            24,
+				TargetPGNum:      32,
+				AutoscaleMode:    "on",
+				RecommendedPGNum: 64,
+			},
+		}, capacity)
+	})
+
+	t.Run("autoscaler not available", func(t *testing.T) {
+		context := &clusterd.Context{Executor: newCapacityTestExecutor(errors.New("mgr not available"))}
+		capacity, err := getPoolCapacity(context, clusterInfo, "replicapool")
+		assert.NoError(t, err)
+		assert.Equal(t, cephv1.PoolPlacementGroupsStatus{PGNum: 24, TargetPGNum: 32}, capacity.PlacementGroups)
+	})
+
+	t.Run("pool not found", func(t *testing.T) {
+		context := &clusterd.Context{Executor: newCapacityTestExecutor(nil)}
+		_, err := getPoolCapacity(context, clusterInfo, "missing")
+		assert.ErrorContains(t, err, `pool "missing" not found`)
+	})
+}
+
+func TestCapacityCheckerUpdateStatus(t *testing.T) {
+	cephBlockPool := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: "rook-ceph"},
+		Status:     &cephv1.CephBlockPoolStatus{Phase: cephv1.ConditionReady},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephBlockPool{}, &cephv1.CephBlockPoolList{})
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(cephBlockPool).WithStatusSubresource(cephBlockPool).Build()
+
+	clusterInfo := cephclient.AdminTestClusterInfo("rook-ceph")
+	clusterInfo.Context = context.TODO()
+	executor := newCapacityTestExecutor(nil)
+	checker := newCapacityChecker(&clusterd.Context{Executor: executor}, cl, clusterInfo, cephBlockPool)
+	assert.Equal(t, defaultCapacityCheckInterval, checker.interval)
+
+	getStatus := func() *cephv1.PoolCapacityStatus {
+		pool := &cephv1.CephBlockPool{}
+		require.NoError(t, cl.Get(clusterInfo.Context, checker.namespacedName, pool))
+		return pool.Status.Capacity
+	}
+
+	checker.refresh()
+	capacity := getStatus()
+	require.NotNil(t, capacity)
+	assert.Equal(t, int64(4000000), capacity.StoredBytes)
+	assert.Equal(t, 64, capacity.PlacementGroups.RecommendedPGNum)
+	assert.NotEmpty(t, capacity.LastChecked)
+	assert.Empty(t, capacity.Message)
+
+	// the previous values are kept when the capacity cannot be retrieved
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		return "", errors.New("timed out")
+	}
+	checker.refresh()
+	capacity = getStatus()
+	require.NotNil(t, capacity)
+	assert.Equal(t, int64(4000000), capacity.StoredBytes)
+	assert.Contains(t, capacity.Message, "timed out")
+}
@@ -24,6 +24,7 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/coreos/pkg/capnslog"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
@@ -76,6 +77,7 @@ type ReconcileCephBlockPool struct {
 	context                 *clusterd.Context
 	clusterInfo             *cephclient.ClusterInfo
 	blockPoolMirrorContexts map[string]*blockPoolHealth
+	capacityMonitors        map[string]*capacityMonitor
 	opManagerContext        context.Context
 	recorder                events.EventRecorder
 	opConfig                opcontroller.OperatorConfig
@@ -87,6 +89,12 @@ type blockPoolHealth struct {
 	started        bool
 }
 
+// capacityMonitor is the routine refreshing the capacity status of a pool
+type capacityMonitor struct {
+	cancel   context.CancelFunc
+	interval time.Duration
+}
+
 // Add creates a new CephBlockPool Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
@@ -100,6 +108,7 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 		scheme:                  mgr.GetScheme(),
 		context:                 context,
 		blockPoolMirrorContexts: make(map[string]*blockPoolHealth),
+		capacityMonitors:        make(map[string]*capacityMonitor),
 		opManagerContext:        opManagerContext,
 		recorder:                mgr.GetEventRecorder("rook-" + controllerName),
 		opConfig:                opConfig,
@@ -204,6 +213,7 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 			cephBlockPool.Name = request.Name
 			cephBlockPool.Namespace = request.Namespace
 			r.cancelMirrorMonitoring(cephBlockPool)
+			r.cancelCapacityMonitoring(cephBlockPool)
 			return reconcile.Result{}, *cephBlockPool, nil
 		}
 		// Error reading the object - requeue the request.
@@ -246,6 +256,7 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 		if !cephBlockPool.GetDeletionTimestamp().IsZero() && !cephClusterExists {
 			// don't leak the health checker routine if we are force-deleting
 			r.cancelMirrorMonitoring(cephBlockPool)
+			r.cancelCapacityMonitoring(cephBlockPool)
 
 			// Remove finalizer
 			err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, cephBlockPool)
@@ -276,6 +287,7 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 		// If the ceph block pool is still in the map, we must remove it during CR deletion
 		// We must remove it first otherwise the checker will panic since the status/info will be nil
 		r.cancelMirrorMonitoring(cephBlockPool)
+		r.cancelCapacityMonitoring(cephBlockPool)
 
 		r.recorder.Eventf(cephBlockPool, nil, corev1.EventTypeNormal, string(cephv1.ReconcileStarted), string(cephv1.ReconcileStarted), "starting blockpool deletion")
 
@@ -319,6 +331,20 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 	}
 	r.clusterInfo.CephVersion = *cephVersion
 
+	// MIGRATE: the images are moved to the new pool, the pool keeps being reconciled with its current
+	// layout until the new pool takes its name
+	migrationResponse := reconcile.Result{}
+	if migrationInProgress(cephBlockPool) {
+		migrationResponse, err = r.reconcileMigration(clusterInfo, &cephCluster.Spec, cephBlockPool)
+		if err != nil {
+			return migrationResponse, *cephBlockPool, err
+		}
+	} else if cephBlockPool.Spec.Migration == nil && cephBlockPool.Status != nil && cephBlockPool.Status.Migration != nil {
+		if err := r.updateMigrationStatus(cephBlockPool, nil); err != nil {
+			return opcontroller.ImmediateRetryResult, *cephBlockPool, err
+		}
+	}
+
 	// CREATE/UPDATE
 	reconcileResponse, err = r.reconcileCreatePool(clusterInfo, &cephCluster.Spec, cephBlockPool)
 	if err != nil {
@@ -352,6 +378,13 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 		return opcontroller.ImmediateRetryResult, *cephBlockPool, errors.Wrapf(statusErr, "failed to update status of pool %q to %q.", cephBlockPool.Name, cephv1.ConditionReady)
 	}
 
+	r.startCapacityMonitoring(cephBlockPool)
+
+	if migrationInProgress(cephBlockPool) {
+		// follow the progress of the images being moved
+		return migrationResponse, *cephBlockPool, nil
+	}
+
 	// Return and do not requeue
 	log.NamedDebug(request.NamespacedName, logger, "done reconciling")
 	return reconcile.Result{}, *cephBlockPool, nil
@@ -525,7 +558,7 @@ func (r *ReconcileCephBlockPool) handleDeletionBlocked(cephBlockPool *cephv1.Cep
 }
 
 func (r *ReconcileCephBlockPool) reconcileCreatePool(clusterInfo *cephclient.ClusterInfo, cephCluster *cephv1.ClusterSpec, cephBlockPool *cephv1.CephBlockPool) (reconcile.Result, error) {
-	poolSpec := cephBlockPool.ToNamedPoolSpec()
+	poolSpec := desiredPoolSpec(cephBlockPool)
 	err := createPool(r.context, clusterInfo, cephCluster, &poolSpec)
 	if err != nil {
 		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to configure pool %q.", cephBlockPool.GetName())
@@ -591,8 +624,9 @@ func deletePool(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo,
 	}
 
 	// Clean up the erasure code profile for EC pools so that recreating with
-	// different settings does not fail
-	if p.IsErasureCoded() {
+	// different settings does not fail. A profile referenced by the pool is
+	// owned by its CephErasureCodeProfile and is not deleted with the pool.
+	if p.IsErasureCoded() && p.ErasureCoded.Profile == "" {
 		ecProfileName := cephclient.GetErasureCodeProfileForPool(p.Name)
 		if err := cephclient.DeleteErasureCodeProfile(context, clusterInfo, ecProfileName); err != nil {
 			logger.Warningf("failed to delete erasure code profile %q for pool %q: %v", ecProfileName, p.Name, err)
@@ -693,6 +727,37 @@ func (r *ReconcileCephBlockPool) cancelMirrorMonitoring(cephBlockPool *cephv1.Ce
 	}
 }
 
+// startCapacityMonitoring starts refreshing the capacity status of the pool, or restarts it if the
+// interval changed. The monitoring is stopped if the capacity status check is disabled.
+func (r *ReconcileCephBlockPool) startCapacityMonitoring(cephBlockPool *cephv1.CephBlockPool) {
+	nsName := opcontroller.NsName(cephBlockPool.Namespace, cephBlockPool.Name)
+	if cephBlockPool.Spec.StatusCheck.Capacity.Disabled {
+		r.cancelCapacityMonitoring(cephBlockPool)
+		return
+	}
+
+	checker := newCapacityChecker(r.context, r.client, r.clusterInfo, cephBlockPool)
+	monitor, ok := r.capacityMonitors[blockPoolChannelKeyName(cephBlockPool)]
+	if ok && monitor.interval == checker.interval {
+		return
+	}
+	r.cancelCapacityMonitoring(cephBlockPool)
+
+	log.NamedDebug(nsName, logger, "refreshing the pool capacity every %s", checker.interval.String())
+	internalCtx, internalCancel := context.WithCancel(r.opManagerContext)
+	r.capacityMonitors[blockPoolChannelKeyName(cephBlockPool)] = &capacityMonitor{cancel: internalCancel, interval: checker.interval}
+	go checker.checkCapacity(internalCtx)
+}
+
+// cancelCapacityMonitoring stops refreshing the capacity status. This is a noop if monitoring is not running.
+func (r *ReconcileCephBlockPool) cancelCapacityMonitoring(cephBlockPool *cephv1.CephBlockPool) {
+	channelKey := blockPoolChannelKeyName(cephBlockPool)
+	if monitor, ok := r.capacityMonitors[channelKey]; ok {
+		monitor.cancel()
+		delete(r.capacityMonitors, channelKey)
+	}
+}
+
 func (r *ReconcileCephBlockPool) disableMirroring(pool string) error {
 	nsName := opcontroller.NsName(r.clusterInfo.Namespace, pool)
 	mirrorInfo, err := cephclient.GetPoolMirroringInfo(r.context, r.clusterInfo, pool)
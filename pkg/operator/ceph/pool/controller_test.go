@@ -206,7 +206,10 @@ func TestDeletePool(t *testing.T) {
 			return "", errors.Errorf("unexpected rbd command %q", args)
 		},
 	}
-	context := &clusterd.Context{Executor: executor}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCrushRule{}, &cephv1.CephCrushRuleList{})
+	cl := fake.NewClientBuilder().WithScheme(s).Build()
+	context := &clusterd.Context{Executor: executor, Client: cl}
 
 	// delete a pool that exists
 	p := &cephv1.NamedPoolSpec{Name: "mypool"}
@@ -0,0 +1,300 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crushrule to manage the CRUSH rules of a rook cluster.
+package crushrule
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/log"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-crush-rule-controller"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+// the rule is checked at this interval since the crush map is changed without any change to the CR,
+// for example when the last pool using the rule is deleted
+var ruleRefreshInterval = time.Minute
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       reflect.TypeFor[cephv1.CephCrushRule]().Name(),
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileCephCrushRule reconciles a CephCrushRule object
+type ReconcileCephCrushRule struct {
+	client           client.Client
+	context          *clusterd.Context
+	clusterInfo      *cephclient.ClusterInfo
+	opManagerContext context.Context
+	recorder         events.EventRecorder
+}
+
+// Add creates a new CephCrushRule Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	return add(mgr, newReconciler(mgr, context, opManagerContext))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context) reconcile.Reconciler {
+	return &ReconcileCephCrushRule{
+		client:           mgr.GetClient(),
+		context:          context,
+		opManagerContext: opManagerContext,
+		recorder:         mgr.GetEventRecorder("rook-" + controllerName),
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephCrushRule CRD object
+	return c.Watch(
+		source.Kind(
+			mgr.GetCache(),
+			&cephv1.CephCrushRule{TypeMeta: controllerTypeMeta},
+			&handler.TypedEnqueueRequestForObject[*cephv1.CephCrushRule]{},
+			opcontroller.WatchControllerPredicate[*cephv1.CephCrushRule](mgr.GetScheme()),
+		),
+	)
+}
+
+// Reconcile reads that state of the cluster for a CephCrushRule object and makes changes based on the state read
+// and what is in the CephCrushRule.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileCephCrushRule) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer opcontroller.RecoverAndLogException()
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
+	reconcileResponse, crushRule, err := r.reconcile(request)
+	return reporting.ReportReconcileResult(logger, r.recorder, request, &crushRule, reconcileResponse, err)
+}
+
+func (r *ReconcileCephCrushRule) reconcile(request reconcile.Request) (reconcile.Result, cephv1.CephCrushRule, error) {
+	// Fetch the CephCrushRule instance
+	crushRule := &cephv1.CephCrushRule{}
+	err := r.client.Get(r.opManagerContext, request.NamespacedName, crushRule)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			log.NamedDebug(request.NamespacedName, logger, "cephCrushRule resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, *crushRule, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, *crushRule, errors.Wrap(err, "failed to get cephCrushRule")
+	}
+	// update observedGeneration local variable with current generation value,
+	// because generation can be changed before reconcile got completed
+	// CR status will be updated at end of reconcile, so to reflect the reconcile has finished
+	observedGeneration := crushRule.ObjectMeta.Generation
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	generationUpdated, err := opcontroller.AddFinalizerIfNotPresent(r.opManagerContext, r.client, crushRule)
+	if err != nil {
+		return reconcile.Result{}, *crushRule, errors.Wrap(err, "failed to add finalizer")
+	}
+	if generationUpdated {
+		log.NamedInfo(request.NamespacedName, logger, "reconciling the crush rule after adding finalizer")
+		return reconcile.Result{}, *crushRule, nil
+	}
+
+	// The CR was just created, initializing status fields
+	if crushRule.Status == nil {
+		crushRule.Status = &cephv1.CephCrushRuleStatus{Phase: cephv1.ConditionProgressing}
+		if err := r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, crushRule.Status); err != nil {
+			return reconcile.Result{}, *crushRule, errors.Wrapf(err, "failed to initialize crush rule %q status", request.NamespacedName)
+		}
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	cephCluster, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.opManagerContext, r.client, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		// Only remove the finalizer if the CephCluster is gone
+		if !crushRule.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, crushRule)
+			if err != nil {
+				return opcontroller.ImmediateRetryResult, *crushRule, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, *crushRule, nil
+		}
+		return reconcileResponse, *crushRule, nil
+	}
+
+	// Populate clusterInfo during each reconcile
+	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, r.opManagerContext, request.NamespacedName.Namespace, &cephCluster.Spec)
+	if err != nil {
+		return reconcile.Result{}, *crushRule, errors.Wrap(err, "failed to populate cluster info")
+	}
+	r.clusterInfo.Context = r.opManagerContext
+
+	crushMap, err := cephclient.GetCrushMap(r.context, r.clusterInfo)
+	if err != nil {
+		if strings.Contains(err.Error(), opcontroller.UninitializedCephConfigError) {
+			log.NamedInfo(request.NamespacedName, logger, opcontroller.OperatorNotInitializedMessage)
+			return opcontroller.WaitForRequeueIfOperatorNotInitialized, *crushRule, nil
+		}
+		return reconcile.Result{}, *crushRule, errors.Wrap(err, "failed to get crush map")
+	}
+	ruleName := crushRule.RuleName()
+
+	// DELETE: the CR was deleted
+	if !crushRule.GetDeletionTimestamp().IsZero() {
+		if ruleID, ok := findRuleID(crushMap, ruleName); ok {
+			// the rule cannot be removed from the crush map while pools use it
+			pools, err := cephclient.GetPoolsUsingCrushRule(r.context, r.clusterInfo, ruleID)
+			if err != nil {
+				return reconcile.Result{}, *crushRule, errors.Wrapf(err, "failed to get the pools using crush rule %q", ruleName)
+			}
+			if len(pools) > 0 {
+				crushRule.Status.Phase = cephv1.ConditionDeletionIsBlocked
+				crushRule.Status.Message = fmt.Sprintf("crush rule is used by pools %s", strings.Join(pools, ", "))
+				log.NamedInfo(request.NamespacedName, logger, "deletion of the %s", crushRule.Status.Message)
+				if err := r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, crushRule.Status); err != nil {
+					return reconcile.Result{}, *crushRule, errors.Wrapf(err, "failed to set deletion blocked status for crush rule %q", request.NamespacedName)
+				}
+				return opcontroller.WaitForRequeueIfFinalizerBlocked, *crushRule, nil
+			}
+
+			log.NamedInfo(request.NamespacedName, logger, "removing crush rule %q", ruleName)
+			if err := cephclient.DeleteCrushRule(r.context, r.clusterInfo, ruleName); err != nil {
+				return reconcile.Result{}, *crushRule, errors.Wrapf(err, "failed to delete crush rule %q", ruleName)
+			}
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, crushRule)
+		if err != nil {
+			return reconcile.Result{}, *crushRule, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, *crushRule, nil
+	}
+
+	// Add the rule to the crush map or update its steps
+	ruleID, err := r.reconcileRule(crushRule, crushMap)
+	if err != nil {
+		crushRule.Status.Phase = cephv1.ConditionFailure
+		crushRule.Status.Message = err.Error()
+		if statusErr := r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, crushRule.Status); statusErr != nil {
+			return reconcile.Result{}, *crushRule, errors.Wrapf(statusErr, "failed to set failed status for crush rule %q", request.NamespacedName)
+		}
+		return reconcile.Result{}, *crushRule, errors.Wrapf(err, "failed to reconcile crush rule %q", ruleName)
+	}
+
+	// update status with latest ObservedGeneration value at the end of reconcile
+	crushRule.Status.Phase = cephv1.ConditionReady
+	crushRule.Status.Message = ""
+	crushRule.Status.RuleID = &ruleID
+	err = r.updateStatus(observedGeneration, request.NamespacedName, crushRule.Status)
+	if err != nil {
+		return reconcile.Result{}, *crushRule, errors.Wrapf(err, "failed to set final status for crush rule %q", request.NamespacedName)
+	}
+
+	// Requeue to restore the rule if it was removed from the crush map
+	log.NamedDebug(request.NamespacedName, logger, "done reconciling")
+	return reconcile.Result{RequeueAfter: ruleRefreshInterval}, *crushRule, nil
+}
+
+// reconcileRule validates the steps of the rule against the crush map and applies them. Ceph applies
+// the changed steps to the pools using the rule, which moves their data.
+func (r *ReconcileCephCrushRule) reconcileRule(crushRule *cephv1.CephCrushRule, crushMap cephclient.CrushMap) (int, error) {
+	ruleName := crushRule.RuleName()
+	if err := validateRule(crushRule.Spec, crushMap); err != nil {
+		return 0, errors.Wrapf(err, "invalid crush rule %q", ruleName)
+	}
+
+	ruleID, err := cephclient.ApplyCrushRule(r.context, r.clusterInfo, ruleName, crushRule.Spec.Type, crushRule.Spec.Steps, crushRule.Spec.TestSize)
+	if err != nil {
+		return 0, err
+	}
+	return ruleID, nil
+}
+
+// findRuleID returns the ID of the rule with the name in the crush map
+func findRuleID(crushMap cephclient.CrushMap, name string) (int, bool) {
+	for _, rule := range crushMap.Rules {
+		if rule.Name == name {
+			return rule.ID, true
+		}
+	}
+	return 0, false
+}
+
+// updateStatus updates an object with a given status
+func (r *ReconcileCephCrushRule) updateStatus(observedGeneration int64, name types.NamespacedName, status *cephv1.CephCrushRuleStatus) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		crushRule := &cephv1.CephCrushRule{}
+		if err := r.client.Get(r.opManagerContext, name, crushRule); err != nil {
+			if kerrors.IsNotFound(err) {
+				log.NamedDebug(name, logger, "CephCrushRule resource not found. Ignoring since object must be deleted.")
+				return nil
+			}
+			return errors.Wrapf(err, "failed to retrieve crush rule %q to update status to %q", name, status.Phase)
+		}
+
+		crushRule.Status = status.DeepCopy()
+		if observedGeneration != k8sutil.ObservedGenerationNotAvailable {
+			crushRule.Status.ObservedGeneration = observedGeneration
+		}
+		if err := reporting.UpdateStatus(r.client, crushRule); err != nil {
+			return errors.Wrapf(err, "failed to set crush rule %q status to %q", name, status.Phase)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.NamedDebug(name, logger, "crush rule status updated to %q", status.Phase)
+	return nil
+}
@@ -0,0 +1,276 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crushrule
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const testCrushMap = `{
+"devices":[{"id":0,"name":"osd.0","class":"ssd"},{"id":1,"name":"osd.1","class":"hdd"}],
+"types":[{"type_id":0,"name":"osd"},{"type_id":1,"name":"host"},{"type_id":8,"name":"datacenter"},{"type_id":11,"name":"root"}],
+"buckets":[{"id":-1,"name":"default"},{"id":-2,"name":"dc1"},{"id":-3,"name":"dc2"},{"id":-4,"name":"node1"}],
+"rules":[{"rule_id":0,"rule_name":"replicated_rule","type":1}%s]}`
+
+const twoDCRule = `,{"rule_id":3,"rule_name":"two-dc","type":1,"steps":[
+{"op":"take","item":-2,"item_name":"dc1"},{"op":"chooseleaf_firstn","num":2,"type":"host"},{"op":"emit"},
+{"op":"take","item":-3,"item_name":"dc2"},{"op":"chooseleaf_firstn","num":1,"type":"host"},{"op":"emit"}]}`
+
+// twoDCSteps places 2 copies in dc1 and 1 copy in dc2
+var twoDCSteps = []cephv1.CrushRuleStep{
+	{Op: cephv1.CrushRuleStepTake, Item: "dc1"},
+	{Op: cephv1.CrushRuleStepChooseLeaf, Count: 2, Type: "host"},
+	{Op: cephv1.CrushRuleStepEmit},
+	{Op: cephv1.CrushRuleStepTake, Item: "dc2"},
+	{Op: cephv1.CrushRuleStepChooseLeaf, Count: 1, Type: "host"},
+	{Op: cephv1.CrushRuleStepEmit},
+}
+
+func TestValidateRule(t *testing.T) {
+	var crushMap cephclient.CrushMap
+	assert.NoError(t, json.Unmarshal([]byte(strings.Replace(testCrushMap, "%s", "", 1)), &crushMap))
+
+	take := cephv1.CrushRuleStep{Op: cephv1.CrushRuleStepTake, Item: "default"}
+	chooseDC := cephv1.CrushRuleStep{Op: cephv1.CrushRuleStepChoose, Count: 2, Type: "datacenter"}
+	chooseLeaf := cephv1.CrushRuleStep{Op: cephv1.CrushRuleStepChooseLeaf, Count: 2, Type: "host"}
+	emit := cephv1.CrushRuleStep{Op: cephv1.CrushRuleStepEmit}
+
+	tests := []struct {
+		name  string
+		steps []cephv1.CrushRuleStep
+		err   string
+	}{
+		{"two datacenters", twoDCSteps, ""},
+		{"nested choose", []cephv1.CrushRuleStep{take, chooseDC, chooseLeaf, emit}, ""},
+		{"device class", []cephv1.CrushRuleStep{{Op: cephv1.CrushRuleStepTake, Item: "default", DeviceClass: "ssd"}, chooseLeaf, emit}, ""},
+		{"no steps", nil, "the rule has no steps"},
+		{"no take", []cephv1.CrushRuleStep{chooseLeaf, emit}, `the first step must be "take"`},
+		{"no emit", []cephv1.CrushRuleStep{take, chooseLeaf}, `the last step must be "emit"`},
+		{"emit without choose", []cephv1.CrushRuleStep{take, emit}, `step 1: "emit" must follow "choose" or "chooseleaf"`},
+		{"choose after chooseleaf", []cephv1.CrushRuleStep{take, chooseLeaf, chooseDC, emit}, `step 2: "choose" must follow "take" or "choose"`},
+		{"take before emit", []cephv1.CrushRuleStep{take, chooseLeaf, take, chooseLeaf, emit}, `step 2: "take" must be the first step or follow "emit"`},
+		{"take without item", []cephv1.CrushRuleStep{{Op: cephv1.CrushRuleStepTake}, chooseLeaf, emit}, `step 0: "take" requires the item`},
+		{"take with type", []cephv1.CrushRuleStep{{Op: cephv1.CrushRuleStepTake, Item: "default", Type: "host"}, chooseLeaf, emit}, "does not support the type, count or mode"},
+		{"unknown bucket", []cephv1.CrushRuleStep{{Op: cephv1.CrushRuleStepTake, Item: "dc3"}, chooseLeaf, emit}, `bucket "dc3" not found in the crush map`},
+		{"unknown device class", []cephv1.CrushRuleStep{{Op: cephv1.CrushRuleStepTake, Item: "default", DeviceClass: "nvme"}, chooseLeaf, emit}, `no OSD has the device class "nvme"`},
+		{"choose without type", []cephv1.CrushRuleStep{take, {Op: cephv1.CrushRuleStepChooseLeaf, Count: 2}, emit}, `step 1: "chooseleaf" requires the type`},
+		{"unknown type", []cephv1.CrushRuleStep{take, {Op: cephv1.CrushRuleStepChooseLeaf, Type: "rack"}, emit}, `type "rack" not found in the crush map`},
+		{"choose with item", []cephv1.CrushRuleStep{take, {Op: cephv1.CrushRuleStepChooseLeaf, Type: "host", Item: "dc1"}, emit}, "does not support the item or device class"},
+		{"emit with count", []cephv1.CrushRuleStep{take, chooseLeaf, {Op: cephv1.CrushRuleStepEmit, Count: 1}}, `"emit" does not support any setting`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRule(cephv1.CrushRuleSpec{Steps: tt.steps}, crushMap)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCephCrushRuleController(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	os.Setenv("ROOK_LOG_LEVEL", "DEBUG")
+
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace},
+		Status: cephv1.ClusterStatus{
+			Phase:      cephv1.ConditionReady,
+			CephStatus: &cephv1.CephStatus{Health: "HEALTH_OK"},
+		},
+	}
+	newCrushRule := func(spec cephv1.CrushRuleSpec) *cephv1.CephCrushRule {
+		return &cephv1.CephCrushRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "stretched",
+				Namespace:  namespace,
+				Finalizers: []string{"cephcrushrule.ceph.rook.io"},
+			},
+			TypeMeta: metav1.TypeMeta{Kind: "CephCrushRule"},
+			Spec:     spec,
+		}
+	}
+
+	// the rules in the crush map and the pools listed by "osd pool ls detail"
+	type cephState struct {
+		rules    string
+		pools    string
+		commands []string
+	}
+	setup := func(t *testing.T, crushRule *cephv1.CephCrushRule, state *cephState) *ReconcileCephCrushRule {
+		executor := &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				switch {
+				case command == "crushtool" && args[0] == "--decompile":
+					return "", os.WriteFile(args[3], []byte("# begin crush map\n"), 0o600)
+				case command == "crushtool":
+					return "", nil
+				case args[0] == "osd" && args[1] == "crush" && args[2] == "dump":
+					return strings.Replace(testCrushMap, "%s", state.rules, 1), nil
+				case args[0] == "osd" && args[1] == "pool" && args[2] == "ls":
+					return state.pools, nil
+				case args[0] == "osd" && args[1] == "setcrushmap":
+					state.commands = append(state.commands, "osd setcrushmap")
+				case args[0] == "osd" && args[1] == "crush" && args[2] == "rule":
+					state.commands = append(state.commands, strings.Join(args[:5], " "))
+				}
+				return "", nil
+			},
+			MockExecuteCommandWithCombinedOutput: func(command string, args ...string) (string, error) {
+				return "", nil
+			},
+		}
+
+		s := scheme.Scheme
+		s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCrushRule{}, &cephv1.CephCrushRuleList{}, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+		cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(crushRule, cephCluster).WithStatusSubresource(crushRule).Build()
+		c := &clusterd.Context{
+			Executor:  executor,
+			Clientset: testop.New(t, 1),
+			Client:    cl,
+		}
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon", Namespace: namespace},
+			Data: map[string][]byte{
+				"fsid":         []byte("fsid"),
+				"mon-secret":   []byte("monsecret"),
+				"admin-secret": []byte("adminsecret"),
+			},
+			Type: k8sutil.RookType,
+		}
+		_, err := c.Clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+		assert.NoError(t, err)
+
+		return &ReconcileCephCrushRule{
+			client:           cl,
+			context:          c,
+			opManagerContext: ctx,
+			recorder:         events.NewFakeRecorder(50),
+		}
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "stretched", Namespace: namespace}}
+	twoDCPools := `[{"pool_name":"rbd","crush_rule":3},{"pool_name":".mgr","crush_rule":0}]`
+
+	t.Run("add the rule", func(t *testing.T) {
+		crushRule := newCrushRule(cephv1.CrushRuleSpec{Name: "two-dc", Steps: twoDCSteps})
+		state := &cephState{pools: "[]"}
+		r := setup(t, crushRule, state)
+
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, ruleRefreshInterval, res.RequeueAfter)
+		assert.Equal(t, []string{"osd setcrushmap"}, state.commands)
+
+		assert.NoError(t, r.client.Get(ctx, req.NamespacedName, crushRule))
+		assert.Equal(t, cephv1.ConditionReady, crushRule.Status.Phase)
+		assert.Equal(t, 1, *crushRule.Status.RuleID)
+	})
+
+	t.Run("keep an unchanged rule", func(t *testing.T) {
+		crushRule := newCrushRule(cephv1.CrushRuleSpec{Name: "two-dc", Steps: twoDCSteps})
+		state := &cephState{rules: twoDCRule, pools: twoDCPools}
+		r := setup(t, crushRule, state)
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Empty(t, state.commands)
+
+		assert.NoError(t, r.client.Get(ctx, req.NamespacedName, crushRule))
+		assert.Equal(t, cephv1.ConditionReady, crushRule.Status.Phase)
+		assert.Equal(t, 3, *crushRule.Status.RuleID)
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		steps := append([]cephv1.CrushRuleStep{}, twoDCSteps...)
+		steps[3] = cephv1.CrushRuleStep{Op: cephv1.CrushRuleStepTake, Item: "dc3"}
+		crushRule := newCrushRule(cephv1.CrushRuleSpec{Name: "two-dc", Steps: steps})
+		state := &cephState{rules: twoDCRule, pools: twoDCPools}
+		r := setup(t, crushRule, state)
+
+		_, err := r.Reconcile(ctx, req)
+		assert.ErrorContains(t, err, `bucket "dc3" not found in the crush map`)
+		assert.Empty(t, state.commands)
+
+		assert.NoError(t, r.client.Get(ctx, req.NamespacedName, crushRule))
+		assert.Equal(t, cephv1.ConditionFailure, crushRule.Status.Phase)
+	})
+
+	t.Run("deletion is blocked by the pools", func(t *testing.T) {
+		crushRule := newCrushRule(cephv1.CrushRuleSpec{Name: "two-dc", Steps: twoDCSteps})
+		crushRule.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		crushRule.Status = &cephv1.CephCrushRuleStatus{Phase: cephv1.ConditionReady}
+		state := &cephState{rules: twoDCRule, pools: twoDCPools}
+		r := setup(t, crushRule, state)
+
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, opcontroller.WaitForRequeueIfFinalizerBlocked, res)
+		assert.Empty(t, state.commands)
+
+		assert.NoError(t, r.client.Get(ctx, req.NamespacedName, crushRule))
+		assert.Equal(t, cephv1.ConditionDeletionIsBlocked, crushRule.Status.Phase)
+		assert.Equal(t, "crush rule is used by pools rbd", crushRule.Status.Message)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		crushRule := newCrushRule(cephv1.CrushRuleSpec{Name: "two-dc", Steps: twoDCSteps})
+		crushRule.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		crushRule.Status = &cephv1.CephCrushRuleStatus{Phase: cephv1.ConditionReady}
+		state := &cephState{rules: twoDCRule, pools: "[]"}
+		r := setup(t, crushRule, state)
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"osd crush rule rm two-dc"}, state.commands)
+	})
+
+	t.Run("delete a rule missing from the crush map", func(t *testing.T) {
+		crushRule := newCrushRule(cephv1.CrushRuleSpec{Name: "two-dc", Steps: twoDCSteps})
+		crushRule.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		crushRule.Status = &cephv1.CephCrushRuleStatus{Phase: cephv1.ConditionReady}
+		state := &cephState{pools: "[]"}
+		r := setup(t, crushRule, state)
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Empty(t, state.commands)
+	})
+}
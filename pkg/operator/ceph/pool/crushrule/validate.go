@@ -0,0 +1,110 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crushrule
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+)
+
+// validateRule checks the order of the steps of the rule and that the buckets, types and device
+// classes they refer to are in the crush map
+func validateRule(spec cephv1.CrushRuleSpec, crushMap cephclient.CrushMap) error {
+	if len(spec.Steps) == 0 {
+		return errors.New("the rule has no steps")
+	}
+	if spec.Steps[0].Op != cephv1.CrushRuleStepTake {
+		return errors.Errorf("the first step must be %q", cephv1.CrushRuleStepTake)
+	}
+	if spec.Steps[len(spec.Steps)-1].Op != cephv1.CrushRuleStepEmit {
+		return errors.Errorf("the last step must be %q", cephv1.CrushRuleStepEmit)
+	}
+
+	var previous cephv1.CrushRuleStepOp
+	for i, step := range spec.Steps {
+		switch step.Op {
+		case cephv1.CrushRuleStepTake:
+			if previous != "" && previous != cephv1.CrushRuleStepEmit {
+				return errors.Errorf("step %d: %q must be the first step or follow %q", i, step.Op, cephv1.CrushRuleStepEmit)
+			}
+			if step.Item == "" {
+				return errors.Errorf("step %d: %q requires the item", i, step.Op)
+			}
+			if step.Type != "" || step.Count != 0 || step.Mode != "" {
+				return errors.Errorf("step %d: %q does not support the type, count or mode", i, step.Op)
+			}
+			if !hasBucket(crushMap, step.Item) {
+				return errors.Errorf("step %d: bucket %q not found in the crush map", i, step.Item)
+			}
+			if step.DeviceClass != "" && !hasDeviceClass(crushMap, step.DeviceClass) {
+				return errors.Errorf("step %d: no OSD has the device class %q", i, step.DeviceClass)
+			}
+		case cephv1.CrushRuleStepChoose, cephv1.CrushRuleStepChooseLeaf:
+			if previous != cephv1.CrushRuleStepTake && previous != cephv1.CrushRuleStepChoose {
+				return errors.Errorf("step %d: %q must follow %q or %q", i, step.Op, cephv1.CrushRuleStepTake, cephv1.CrushRuleStepChoose)
+			}
+			if step.Type == "" {
+				return errors.Errorf("step %d: %q requires the type", i, step.Op)
+			}
+			if step.Item != "" || step.DeviceClass != "" {
+				return errors.Errorf("step %d: %q does not support the item or device class", i, step.Op)
+			}
+			if !hasType(crushMap, step.Type) {
+				return errors.Errorf("step %d: type %q not found in the crush map", i, step.Type)
+			}
+		case cephv1.CrushRuleStepEmit:
+			if previous != cephv1.CrushRuleStepChoose && previous != cephv1.CrushRuleStepChooseLeaf {
+				return errors.Errorf("step %d: %q must follow %q or %q", i, step.Op, cephv1.CrushRuleStepChoose, cephv1.CrushRuleStepChooseLeaf)
+			}
+			if step.Item != "" || step.DeviceClass != "" || step.Type != "" || step.Count != 0 || step.Mode != "" {
+				return errors.Errorf("step %d: %q does not support any setting", i, step.Op)
+			}
+		default:
+			return errors.Errorf("step %d: unsupported operation %q", i, step.Op)
+		}
+		previous = step.Op
+	}
+	return nil
+}
+
+func hasBucket(crushMap cephclient.CrushMap, name string) bool {
+	for _, bucket := range crushMap.Buckets {
+		if bucket.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasType(crushMap cephclient.CrushMap, name string) bool {
+	for _, t := range crushMap.Types {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasDeviceClass(crushMap cephclient.CrushMap, class string) bool {
+	for _, device := range crushMap.Devices {
+		if device.Class == class {
+			return true
+		}
+	}
+	return false
+}
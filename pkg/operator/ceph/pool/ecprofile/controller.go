@@ -0,0 +1,346 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ecprofile to manage the erasure code profiles of a rook cluster.
+package ecprofile
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/log"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-erasure-code-profile-controller"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+// the pools using the profile are refreshed at this interval since pools are created and deleted
+// without any change to the CR
+var poolsRefreshInterval = time.Minute
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       reflect.TypeFor[cephv1.CephErasureCodeProfile]().Name(),
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileCephErasureCodeProfile reconciles a CephErasureCodeProfile object
+type ReconcileCephErasureCodeProfile struct {
+	client           client.Client
+	context          *clusterd.Context
+	clusterInfo      *cephclient.ClusterInfo
+	opManagerContext context.Context
+	recorder         events.EventRecorder
+}
+
+// Add creates a new CephErasureCodeProfile Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	return add(mgr, newReconciler(mgr, context, opManagerContext))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context) reconcile.Reconciler {
+	return &ReconcileCephErasureCodeProfile{
+		client:           mgr.GetClient(),
+		context:          context,
+		opManagerContext: opManagerContext,
+		recorder:         mgr.GetEventRecorder("rook-" + controllerName),
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephErasureCodeProfile CRD object
+	return c.Watch(
+		source.Kind(
+			mgr.GetCache(),
+			&cephv1.CephErasureCodeProfile{TypeMeta: controllerTypeMeta},
+			&handler.TypedEnqueueRequestForObject[*cephv1.CephErasureCodeProfile]{},
+			opcontroller.WatchControllerPredicate[*cephv1.CephErasureCodeProfile](mgr.GetScheme()),
+		),
+	)
+}
+
+// Reconcile reads that state of the cluster for a CephErasureCodeProfile object and makes changes based on the state read
+// and what is in the CephErasureCodeProfile.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileCephErasureCodeProfile) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer opcontroller.RecoverAndLogException()
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
+	reconcileResponse, ecProfile, err := r.reconcile(request)
+	return reporting.ReportReconcileResult(logger, r.recorder, request, &ecProfile, reconcileResponse, err)
+}
+
+func (r *ReconcileCephErasureCodeProfile) reconcile(request reconcile.Request) (reconcile.Result, cephv1.CephErasureCodeProfile, error) {
+	// Fetch the CephErasureCodeProfile instance
+	ecProfile := &cephv1.CephErasureCodeProfile{}
+	err := r.client.Get(r.opManagerContext, request.NamespacedName, ecProfile)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			log.NamedDebug(request.NamespacedName, logger, "cephErasureCodeProfile resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, *ecProfile, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, *ecProfile, errors.Wrap(err, "failed to get cephErasureCodeProfile")
+	}
+	// update observedGeneration local variable with current generation value,
+	// because generation can be changed before reconcile got completed
+	// CR status will be updated at end of reconcile, so to reflect the reconcile has finished
+	observedGeneration := ecProfile.ObjectMeta.Generation
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	generationUpdated, err := opcontroller.AddFinalizerIfNotPresent(r.opManagerContext, r.client, ecProfile)
+	if err != nil {
+		return reconcile.Result{}, *ecProfile, errors.Wrap(err, "failed to add finalizer")
+	}
+	if generationUpdated {
+		log.NamedInfo(request.NamespacedName, logger, "reconciling the erasure code profile after adding finalizer")
+		return reconcile.Result{}, *ecProfile, nil
+	}
+
+	// The CR was just created, initializing status fields
+	if ecProfile.Status == nil {
+		ecProfile.Status = &cephv1.CephErasureCodeProfileStatus{Phase: cephv1.ConditionProgressing}
+		if err := r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, ecProfile.Status); err != nil {
+			return reconcile.Result{}, *ecProfile, errors.Wrapf(err, "failed to initialize erasure code profile %q status", request.NamespacedName)
+		}
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	cephCluster, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.opManagerContext, r.client, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		// Only remove the finalizer if the CephCluster is gone
+		if !ecProfile.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, ecProfile)
+			if err != nil {
+				return opcontroller.ImmediateRetryResult, *ecProfile, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, *ecProfile, nil
+		}
+		return reconcileResponse, *ecProfile, nil
+	}
+
+	// Populate clusterInfo during each reconcile
+	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, r.opManagerContext, request.NamespacedName.Namespace, &cephCluster.Spec)
+	if err != nil {
+		return reconcile.Result{}, *ecProfile, errors.Wrap(err, "failed to populate cluster info")
+	}
+	r.clusterInfo.Context = r.opManagerContext
+
+	pools, err := cephclient.GetPoolsUsingErasureCodeProfile(r.context, r.clusterInfo, ecProfile.Name)
+	if err != nil {
+		if strings.Contains(err.Error(), opcontroller.UninitializedCephConfigError) {
+			log.NamedInfo(request.NamespacedName, logger, opcontroller.OperatorNotInitializedMessage)
+			return opcontroller.WaitForRequeueIfOperatorNotInitialized, *ecProfile, nil
+		}
+		return reconcile.Result{}, *ecProfile, errors.Wrapf(err, "failed to get the pools using erasure code profile %q", ecProfile.Name)
+	}
+	ecProfile.Status.Pools = pools
+
+	// DELETE: the CR was deleted
+	if !ecProfile.GetDeletionTimestamp().IsZero() {
+		// the profile cannot be removed from ceph while pools use it
+		if len(pools) > 0 {
+			ecProfile.Status.Phase = cephv1.ConditionDeletionIsBlocked
+			ecProfile.Status.Message = fmt.Sprintf("erasure code profile is used by pools %s", strings.Join(pools, ", "))
+			log.NamedInfo(request.NamespacedName, logger, "deletion of the %s", ecProfile.Status.Message)
+			if err := r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, ecProfile.Status); err != nil {
+				return reconcile.Result{}, *ecProfile, errors.Wrapf(err, "failed to set deletion blocked status for erasure code profile %q", request.NamespacedName)
+			}
+			return opcontroller.WaitForRequeueIfFinalizerBlocked, *ecProfile, nil
+		}
+
+		// the reserved profiles are not managed by the CR
+		if err := validateProfileName(ecProfile.Name); err != nil {
+			log.NamedInfo(request.NamespacedName, logger, "not deleting erasure code profile. %v", err)
+		} else {
+			log.NamedDebug(request.NamespacedName, logger, "deleting erasure code profile")
+			if err := r.deleteProfile(ecProfile); err != nil {
+				return reconcile.Result{}, *ecProfile, errors.Wrapf(err, "failed to delete erasure code profile %q", ecProfile.Name)
+			}
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, ecProfile)
+		if err != nil {
+			return reconcile.Result{}, *ecProfile, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, *ecProfile, nil
+	}
+
+	// Create or update the profile in ceph
+	err = r.reconcileProfile(ecProfile, pools)
+	if err != nil {
+		ecProfile.Status.Phase = cephv1.ConditionFailure
+		ecProfile.Status.Message = err.Error()
+		if statusErr := r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, ecProfile.Status); statusErr != nil {
+			return reconcile.Result{}, *ecProfile, errors.Wrapf(statusErr, "failed to set failed status for erasure code profile %q", request.NamespacedName)
+		}
+		return reconcile.Result{}, *ecProfile, errors.Wrapf(err, "failed to reconcile erasure code profile %q", ecProfile.Name)
+	}
+
+	// update status with latest ObservedGeneration value at the end of reconcile
+	ecProfile.Status.Phase = cephv1.ConditionReady
+	ecProfile.Status.Message = ""
+	err = r.updateStatus(observedGeneration, request.NamespacedName, ecProfile.Status)
+	if err != nil {
+		return reconcile.Result{}, *ecProfile, errors.Wrapf(err, "failed to set final status for erasure code profile %q", request.NamespacedName)
+	}
+
+	// Requeue to keep the pools using the profile up to date
+	log.NamedDebug(request.NamespacedName, logger, "done reconciling")
+	return reconcile.Result{RequeueAfter: poolsRefreshInterval}, *ecProfile, nil
+}
+
+// reconcileProfile creates the profile, or updates the profile if no pool uses it. Ceph does not
+// apply the changes of a profile to the pools that were created with it, so the changes of a profile
+// in use are refused.
+func (r *ReconcileCephErasureCodeProfile) reconcileProfile(ecProfile *cephv1.CephErasureCodeProfile, pools []string) error {
+	nsName := opcontroller.NsName(ecProfile.Namespace, ecProfile.Name)
+	if err := validateProfileName(ecProfile.Name); err != nil {
+		return errors.Wrapf(err, "invalid erasure code profile %q", ecProfile.Name)
+	}
+	if err := validateProfile(ecProfile.Spec); err != nil {
+		return errors.Wrapf(err, "invalid erasure code profile %q", ecProfile.Name)
+	}
+	desired, err := profileSettings(ecProfile.Spec)
+	if err != nil {
+		return err
+	}
+
+	current, err := cephclient.GetErasureCodeProfile(r.context, r.clusterInfo, ecProfile.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get erasure code profile %q", ecProfile.Name)
+	}
+	if current != nil {
+		changes := changedSettings(desired, current)
+		if len(changes) == 0 {
+			return nil
+		}
+		if len(pools) > 0 {
+			return errors.Errorf("cannot change %s of erasure code profile %q since it is used by pools %s. create a new profile for the changes instead",
+				strings.Join(changes, ", "), ecProfile.Name, strings.Join(pools, ", "))
+		}
+		log.NamedInfo(nsName, logger, "updating %s of erasure code profile %q", strings.Join(changes, ", "), ecProfile.Name)
+	} else {
+		log.NamedInfo(nsName, logger, "creating erasure code profile %q", ecProfile.Name)
+	}
+
+	if err := cephclient.SetErasureCodeProfile(r.context, r.clusterInfo, ecProfile.Name, desired); err != nil {
+		return errors.Wrapf(err, "failed to set erasure code profile %q", ecProfile.Name)
+	}
+	return nil
+}
+
+// deleteProfile removes the profile from ceph if it exists
+func (r *ReconcileCephErasureCodeProfile) deleteProfile(ecProfile *cephv1.CephErasureCodeProfile) error {
+	current, err := cephclient.GetErasureCodeProfile(r.context, r.clusterInfo, ecProfile.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get erasure code profile %q", ecProfile.Name)
+	}
+	if current == nil {
+		return nil
+	}
+	log.NamedInfo(opcontroller.NsName(ecProfile.Namespace, ecProfile.Name), logger, "removing erasure code profile %q", ecProfile.Name)
+	return cephclient.DeleteErasureCodeProfile(r.context, r.clusterInfo, ecProfile.Name)
+}
+
+// changedSettings returns the sorted names of the settings that differ between the desired and the
+// current profile. Settings that ceph fills in with a default, like the technique, are only
+// compared when they are in the spec.
+func changedSettings(desired, current map[string]string) []string {
+	changes := []string{}
+	for key, value := range desired {
+		if current[key] != value {
+			changes = append(changes, key)
+		}
+	}
+	for _, key := range clearableSettings {
+		if _, ok := desired[key]; !ok && current[key] != "" {
+			changes = append(changes, key)
+		}
+	}
+	slices.Sort(changes)
+	return changes
+}
+
+// updateStatus updates an object with a given status
+func (r *ReconcileCephErasureCodeProfile) updateStatus(observedGeneration int64, name types.NamespacedName, status *cephv1.CephErasureCodeProfileStatus) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ecProfile := &cephv1.CephErasureCodeProfile{}
+		if err := r.client.Get(r.opManagerContext, name, ecProfile); err != nil {
+			if kerrors.IsNotFound(err) {
+				log.NamedDebug(name, logger, "CephErasureCodeProfile resource not found. Ignoring since object must be deleted.")
+				return nil
+			}
+			return errors.Wrapf(err, "failed to retrieve erasure code profile %q to update status to %q", name, status.Phase)
+		}
+
+		ecProfile.Status = status.DeepCopy()
+		if observedGeneration != k8sutil.ObservedGenerationNotAvailable {
+			ecProfile.Status.ObservedGeneration = observedGeneration
+		}
+		if err := reporting.UpdateStatus(r.client, ecProfile); err != nil {
+			return errors.Wrapf(err, "failed to set erasure code profile %q status to %q", name, status.Phase)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.NamedDebug(name, logger, "erasure code profile status updated to %q", status.Phase)
+	return nil
+}
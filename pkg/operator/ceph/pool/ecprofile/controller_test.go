@@ -0,0 +1,302 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ecprofile
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestValidateProfile(t *testing.T) {
+	locality := func(l uint) *uint { return &l }
+	stripeUnit := resource.MustParse("64Ki")
+
+	tests := []struct {
+		name string
+		spec cephv1.ErasureCodeProfileSpec
+		err  string
+	}{
+		{"default plugin", cephv1.ErasureCodeProfileSpec{DataChunks: 4, CodingChunks: 2}, ""},
+		{"jerasure technique", cephv1.ErasureCodeProfileSpec{Plugin: "jerasure", Technique: "cauchy_good", DataChunks: 4, CodingChunks: 2, StripeUnit: &stripeUnit}, ""},
+		{"isa technique", cephv1.ErasureCodeProfileSpec{Plugin: "isa", Technique: "cauchy", DataChunks: 4, CodingChunks: 2}, ""},
+		{"invalid technique", cephv1.ErasureCodeProfileSpec{Plugin: "isa", Technique: "liberation", DataChunks: 4, CodingChunks: 2}, `technique "liberation" is not supported by plugin "isa"`},
+		{"raid6 technique", cephv1.ErasureCodeProfileSpec{Technique: "liberation", DataChunks: 4, CodingChunks: 3}, "requires codingChunks to be 2"},
+		{"clay technique", cephv1.ErasureCodeProfileSpec{Plugin: "clay", Technique: "single", DataChunks: 4, CodingChunks: 2}, `plugin "clay" does not support a technique`},
+		{"clay", cephv1.ErasureCodeProfileSpec{Plugin: "clay", DataChunks: 8, CodingChunks: 4}, ""},
+		{"shec", cephv1.ErasureCodeProfileSpec{Plugin: "shec", Technique: "multiple", DataChunks: 4, CodingChunks: 3}, ""},
+		{"unknown plugin", cephv1.ErasureCodeProfileSpec{Plugin: "zfec", DataChunks: 4, CodingChunks: 2}, `unsupported erasure code plugin "zfec"`},
+		{"too few data chunks", cephv1.ErasureCodeProfileSpec{DataChunks: 1, CodingChunks: 2}, "dataChunks needs minimum value of 2"},
+		{"no coding chunks", cephv1.ErasureCodeProfileSpec{DataChunks: 2}, "codingChunks needs minimum value of 1"},
+		{"lrc", cephv1.ErasureCodeProfileSpec{Plugin: "lrc", DataChunks: 4, CodingChunks: 2, Locality: locality(3)}, ""},
+		{"lrc without locality", cephv1.ErasureCodeProfileSpec{Plugin: "lrc", DataChunks: 4, CodingChunks: 2}, `plugin "lrc" requires the locality`},
+		{"lrc invalid locality", cephv1.ErasureCodeProfileSpec{Plugin: "lrc", DataChunks: 4, CodingChunks: 2, Locality: locality(4)}, "must be a multiple of the locality 4"},
+		{"locality without lrc", cephv1.ErasureCodeProfileSpec{Plugin: "isa", DataChunks: 4, CodingChunks: 2, Locality: locality(3)}, `locality is only supported by plugin "lrc"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateProfile(tt.spec)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidateProfileName(t *testing.T) {
+	assert.NoError(t, validateProfileName("ec-4-2"))
+	assert.ErrorContains(t, validateProfileName("default"), "reserved for the default profile of ceph")
+	assert.ErrorContains(t, validateProfileName("replicapool_ecprofile"), "reserved for the profiles rook creates")
+}
+
+func TestProfileSettings(t *testing.T) {
+	l := uint(3)
+	stripeUnit := resource.MustParse("16Ki")
+	settings, err := profileSettings(cephv1.ErasureCodeProfileSpec{
+		Plugin: "lrc", DataChunks: 4, CodingChunks: 2, Locality: &l,
+		FailureDomain: "rack", DeviceClass: "hdd", StripeUnit: &stripeUnit,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"plugin": "lrc", "k": "4", "m": "2", "l": "3",
+		"crush-failure-domain": "rack", "crush-device-class": "hdd", "stripe_unit": "16384",
+	}, settings)
+
+	settings, err = profileSettings(cephv1.ErasureCodeProfileSpec{DataChunks: 2, CodingChunks: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"plugin": "jerasure", "k": "2", "m": "1", "crush-failure-domain": "host"}, settings)
+}
+
+func TestChangedSettings(t *testing.T) {
+	current := map[string]string{
+		"plugin": "jerasure", "technique": "reed_sol_van", "w": "8", "k": "4", "m": "2",
+		"crush-failure-domain": "host", "crush-root": "default", "crush-device-class": "",
+	}
+	desired := map[string]string{"plugin": "jerasure", "k": "4", "m": "2", "crush-failure-domain": "host"}
+	assert.Empty(t, changedSettings(desired, current))
+
+	desired["m"] = "3"
+	desired["technique"] = "cauchy_good"
+	assert.Equal(t, []string{"m", "technique"}, changedSettings(desired, current))
+
+	// removing the device class from the spec is a change
+	desired = map[string]string{"plugin": "jerasure", "k": "4", "m": "2", "crush-failure-domain": "host"}
+	current["crush-device-class"] = "ssd"
+	assert.Equal(t, []string{"crush-device-class"}, changedSettings(desired, current))
+}
+
+func TestCephErasureCodeProfileController(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	os.Setenv("ROOK_LOG_LEVEL", "DEBUG")
+
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace},
+		Status: cephv1.ClusterStatus{
+			Phase:      cephv1.ConditionReady,
+			CephStatus: &cephv1.CephStatus{Health: "HEALTH_OK"},
+		},
+	}
+	newProfile := func(name string, spec cephv1.ErasureCodeProfileSpec) *cephv1.CephErasureCodeProfile {
+		return &cephv1.CephErasureCodeProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  namespace,
+				Finalizers: []string{"cepherasurecodeprofile.ceph.rook.io"},
+			},
+			TypeMeta: metav1.TypeMeta{Kind: "CephErasureCodeProfile"},
+			Spec:     spec,
+		}
+	}
+
+	// the profiles in ceph and the pools listed by "osd pool ls detail"
+	type cephState struct {
+		profiles map[string]string
+		pools    string
+		commands []string
+	}
+	setup := func(t *testing.T, ecProfile *cephv1.CephErasureCodeProfile, state *cephState) *ReconcileCephErasureCodeProfile {
+		executor := &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				switch {
+				case args[0] == "osd" && args[1] == "pool" && args[2] == "ls":
+					return state.pools, nil
+				case args[0] == "osd" && args[1] == "erasure-code-profile" && args[2] == "ls":
+					names := []string{}
+					for name := range state.profiles {
+						names = append(names, `"`+name+`"`)
+					}
+					return "[" + strings.Join(names, ",") + "]", nil
+				case args[0] == "osd" && args[1] == "erasure-code-profile" && args[2] == "get":
+					return state.profiles[args[3]], nil
+				case args[0] == "osd" && args[1] == "erasure-code-profile":
+					state.commands = append(state.commands, strings.Join(args[:4], " "))
+					if args[2] == "rm" {
+						delete(state.profiles, args[3])
+					}
+					return "", nil
+				}
+				return "", nil
+			},
+		}
+
+		s := scheme.Scheme
+		s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephErasureCodeProfile{}, &cephv1.CephErasureCodeProfileList{}, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+		cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(ecProfile, cephCluster).WithStatusSubresource(ecProfile).Build()
+		c := &clusterd.Context{
+			Executor:  executor,
+			Clientset: testop.New(t, 1),
+			Client:    cl,
+		}
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon", Namespace: namespace},
+			Data: map[string][]byte{
+				"fsid":         []byte("fsid"),
+				"mon-secret":   []byte("monsecret"),
+				"admin-secret": []byte("adminsecret"),
+			},
+			Type: k8sutil.RookType,
+		}
+		_, err := c.Clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+		assert.NoError(t, err)
+
+		return &ReconcileCephErasureCodeProfile{
+			client:           cl,
+			context:          c,
+			opManagerContext: ctx,
+			recorder:         events.NewFakeRecorder(50),
+		}
+	}
+	req := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
+	}
+	archivePools := `[{"pool_name":"archive","erasure_code_profile":"archive"},{"pool_name":"replicapool","erasure_code_profile":""}]`
+	archiveProfile := `{"plugin":"clay","k":"8","m":"4","d":"11","crush-failure-domain":"host","crush-root":"default","crush-device-class":""}`
+
+	t.Run("create the profile", func(t *testing.T) {
+		ecProfile := newProfile("archive", cephv1.ErasureCodeProfileSpec{Plugin: "clay", DataChunks: 8, CodingChunks: 4})
+		state := &cephState{profiles: map[string]string{"default": "{}"}, pools: "[]"}
+		r := setup(t, ecProfile, state)
+
+		res, err := r.Reconcile(ctx, req("archive"))
+		assert.NoError(t, err)
+		assert.Equal(t, poolsRefreshInterval, res.RequeueAfter)
+		assert.Equal(t, []string{"osd erasure-code-profile set archive"}, state.commands)
+
+		assert.NoError(t, r.client.Get(ctx, req("archive").NamespacedName, ecProfile))
+		assert.Equal(t, cephv1.ConditionReady, ecProfile.Status.Phase)
+		assert.Empty(t, ecProfile.Status.Pools)
+	})
+
+	t.Run("report the pools and keep an unchanged profile", func(t *testing.T) {
+		ecProfile := newProfile("archive", cephv1.ErasureCodeProfileSpec{Plugin: "clay", DataChunks: 8, CodingChunks: 4})
+		state := &cephState{profiles: map[string]string{"archive": archiveProfile}, pools: archivePools}
+		r := setup(t, ecProfile, state)
+
+		_, err := r.Reconcile(ctx, req("archive"))
+		assert.NoError(t, err)
+		assert.Empty(t, state.commands)
+
+		assert.NoError(t, r.client.Get(ctx, req("archive").NamespacedName, ecProfile))
+		assert.Equal(t, cephv1.ConditionReady, ecProfile.Status.Phase)
+		assert.Equal(t, []string{"archive"}, ecProfile.Status.Pools)
+	})
+
+	t.Run("refuse to change a profile in use", func(t *testing.T) {
+		ecProfile := newProfile("archive", cephv1.ErasureCodeProfileSpec{Plugin: "clay", DataChunks: 8, CodingChunks: 3})
+		state := &cephState{profiles: map[string]string{"archive": archiveProfile}, pools: archivePools}
+		r := setup(t, ecProfile, state)
+
+		_, err := r.Reconcile(ctx, req("archive"))
+		assert.ErrorContains(t, err, `cannot change m of erasure code profile "archive" since it is used by pools archive`)
+		assert.Empty(t, state.commands)
+
+		assert.NoError(t, r.client.Get(ctx, req("archive").NamespacedName, ecProfile))
+		assert.Equal(t, cephv1.ConditionFailure, ecProfile.Status.Phase)
+		assert.Contains(t, ecProfile.Status.Message, "create a new profile")
+		assert.Equal(t, []string{"archive"}, ecProfile.Status.Pools)
+	})
+
+	t.Run("change a profile not in use", func(t *testing.T) {
+		ecProfile := newProfile("archive", cephv1.ErasureCodeProfileSpec{Plugin: "clay", DataChunks: 8, CodingChunks: 3})
+		state := &cephState{profiles: map[string]string{"archive": archiveProfile}, pools: "[]"}
+		r := setup(t, ecProfile, state)
+
+		_, err := r.Reconcile(ctx, req("archive"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"osd erasure-code-profile set archive"}, state.commands)
+	})
+
+	t.Run("invalid profile", func(t *testing.T) {
+		ecProfile := newProfile("archive", cephv1.ErasureCodeProfileSpec{Plugin: "lrc", DataChunks: 4, CodingChunks: 2})
+		state := &cephState{profiles: map[string]string{}, pools: "[]"}
+		r := setup(t, ecProfile, state)
+
+		_, err := r.Reconcile(ctx, req("archive"))
+		assert.ErrorContains(t, err, `plugin "lrc" requires the locality`)
+		assert.Empty(t, state.commands)
+	})
+
+	t.Run("deletion is blocked by the pools", func(t *testing.T) {
+		ecProfile := newProfile("archive", cephv1.ErasureCodeProfileSpec{Plugin: "clay", DataChunks: 8, CodingChunks: 4})
+		ecProfile.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		ecProfile.Status = &cephv1.CephErasureCodeProfileStatus{Phase: cephv1.ConditionReady}
+		state := &cephState{profiles: map[string]string{"archive": archiveProfile}, pools: archivePools}
+		r := setup(t, ecProfile, state)
+
+		res, err := r.Reconcile(ctx, req("archive"))
+		assert.NoError(t, err)
+		assert.Equal(t, opcontroller.WaitForRequeueIfFinalizerBlocked, res)
+		assert.Empty(t, state.commands)
+
+		assert.NoError(t, r.client.Get(ctx, req("archive").NamespacedName, ecProfile))
+		assert.Equal(t, cephv1.ConditionDeletionIsBlocked, ecProfile.Status.Phase)
+		assert.Equal(t, "erasure code profile is used by pools archive", ecProfile.Status.Message)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		ecProfile := newProfile("archive", cephv1.ErasureCodeProfileSpec{Plugin: "clay", DataChunks: 8, CodingChunks: 4})
+		ecProfile.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		ecProfile.Status = &cephv1.CephErasureCodeProfileStatus{Phase: cephv1.ConditionReady}
+		state := &cephState{profiles: map[string]string{"archive": archiveProfile}, pools: "[]"}
+		r := setup(t, ecProfile, state)
+
+		_, err := r.Reconcile(ctx, req("archive"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"osd erasure-code-profile rm archive"}, state.commands)
+	})
+}
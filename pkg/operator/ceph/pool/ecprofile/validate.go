@@ -0,0 +1,146 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ecprofile
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+)
+
+const (
+	defaultPlugin        = "jerasure"
+	defaultFailureDomain = "host"
+	lrcPlugin            = "lrc"
+	// defaultProfileName is the profile ceph creates
+	defaultProfileName = "default"
+)
+
+// pluginTechniques are the techniques supported by each plugin. The lrc and clay plugins do not
+// have a technique.
+var pluginTechniques = map[string][]string{
+	"jerasure": {"reed_sol_van", "reed_sol_r6_op", "cauchy_orig", "cauchy_good", "liberation", "blaum_roth", "liber8tion"},
+	"isa":      {"reed_sol_van", "cauchy"},
+	"shec":     {"single", "multiple"},
+	"lrc":      {},
+	"clay":     {},
+}
+
+// raid6Techniques are the jerasure techniques that only support two coding chunks
+var raid6Techniques = []string{"reed_sol_r6_op", "liberation", "blaum_roth", "liber8tion"}
+
+// clearableSettings are the settings that ceph leaves empty when they are not set, so removing
+// them from the spec is a change of the profile
+var clearableSettings = []string{"crush-device-class", "stripe_unit"}
+
+// validateProfileName checks that the profile is not one of the profiles that ceph and rook create:
+// the default profile of ceph and the profiles of the erasure coded pools of rook
+func validateProfileName(name string) error {
+	if name == defaultProfileName {
+		return errors.Errorf("the name %q is reserved for the default profile of ceph", name)
+	}
+	if strings.HasSuffix(name, cephclient.GetErasureCodeProfileForPool("")) {
+		return errors.Errorf("the name %q is reserved for the profiles rook creates for the erasure coded pools", name)
+	}
+	return nil
+}
+
+// validateProfile checks that ceph can create a profile with the settings of the spec
+func validateProfile(spec cephv1.ErasureCodeProfileSpec) error {
+	plugin := profilePlugin(spec)
+	techniques, ok := pluginTechniques[plugin]
+	if !ok {
+		return errors.Errorf("unsupported erasure code plugin %q", plugin)
+	}
+	if spec.Technique != "" {
+		if len(techniques) == 0 {
+			return errors.Errorf("plugin %q does not support a technique", plugin)
+		}
+		if !slices.Contains(techniques, spec.Technique) {
+			return errors.Errorf("technique %q is not supported by plugin %q, expected one of %v", spec.Technique, plugin, techniques)
+		}
+		if slices.Contains(raid6Techniques, spec.Technique) && spec.CodingChunks != 2 {
+			return errors.Errorf("technique %q requires codingChunks to be 2", spec.Technique)
+		}
+	}
+
+	if spec.DataChunks < 2 {
+		return errors.New("dataChunks needs minimum value of 2")
+	}
+	if spec.CodingChunks < 1 {
+		return errors.New("codingChunks needs minimum value of 1")
+	}
+
+	if plugin == lrcPlugin {
+		if spec.Locality == nil || *spec.Locality == 0 {
+			return errors.New("plugin \"lrc\" requires the locality")
+		}
+		if (spec.DataChunks+spec.CodingChunks)%*spec.Locality != 0 {
+			return errors.Errorf("dataChunks + codingChunks (%d) must be a multiple of the locality %d", spec.DataChunks+spec.CodingChunks, *spec.Locality)
+		}
+	} else if spec.Locality != nil {
+		return errors.Errorf("locality is only supported by plugin %q", lrcPlugin)
+	}
+
+	if spec.StripeUnit != nil && !spec.StripeUnit.IsZero() {
+		if _, ok := spec.StripeUnit.AsInt64(); !ok {
+			return errors.Errorf("stripeUnit %q cannot be represented as a byte stripe for Ceph", spec.StripeUnit.String())
+		}
+	}
+	return nil
+}
+
+// profileSettings returns the key/value pairs of the profile in ceph
+func profileSettings(spec cephv1.ErasureCodeProfileSpec) (map[string]string, error) {
+	settings := map[string]string{
+		"plugin":               profilePlugin(spec),
+		"k":                    fmt.Sprintf("%d", spec.DataChunks),
+		"m":                    fmt.Sprintf("%d", spec.CodingChunks),
+		"crush-failure-domain": defaultFailureDomain,
+	}
+	if spec.FailureDomain != "" {
+		settings["crush-failure-domain"] = spec.FailureDomain
+	}
+	if spec.Technique != "" {
+		settings["technique"] = spec.Technique
+	}
+	if spec.Locality != nil {
+		settings["l"] = fmt.Sprintf("%d", *spec.Locality)
+	}
+	if spec.DeviceClass != "" {
+		settings["crush-device-class"] = spec.DeviceClass
+	}
+	if spec.StripeUnit != nil && !spec.StripeUnit.IsZero() {
+		stripeBytes, ok := spec.StripeUnit.AsInt64()
+		if !ok {
+			return nil, errors.Errorf("stripeUnit %q cannot be represented as a byte stripe for Ceph", spec.StripeUnit.String())
+		}
+		settings["stripe_unit"] = fmt.Sprintf("%d", stripeBytes)
+	}
+	return settings, nil
+}
+
+func profilePlugin(spec cephv1.ErasureCodeProfileSpec) string {
+	if spec.Plugin != "" {
+		return spec.Plugin
+	}
+	return defaultPlugin
+}
@@ -0,0 +1,452 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/csi/peermap"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/util/log"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	migrationTargetPoolSuffix = "-migration"
+	// the length of the uuid at the end of the name of the images and journals created by ceph-csi
+	csiUUIDLength = 36
+	csiImageIDKey = "csi.imageid"
+)
+
+// the pool migration is reconciled again while images are moved to follow their progress
+var waitForRequeueIfPoolMigrating = reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+
+// csiJournal is the omap layout of the ceph-csi journal of a volume or a snapshot
+type csiJournal struct {
+	objectPrefix string
+	directory    string
+	nameKey      string
+}
+
+var csiJournals = []csiJournal{
+	{objectPrefix: "csi.volume.", directory: "csi.volumes.default", nameKey: "csi.volname"},
+	{objectPrefix: "csi.snap.", directory: "csi.snaps.default", nameKey: "csi.snapname"},
+}
+
+// poolMigrator moves the images of a pool to a new pool with the layout of the migration target
+type poolMigrator struct {
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+	clusterSpec *cephv1.ClusterSpec
+	pool        *cephv1.CephBlockPool
+	poolName    string
+}
+
+// migrationInProgress returns whether the images of the pool are being moved to a new pool
+func migrationInProgress(cephBlockPool *cephv1.CephBlockPool) bool {
+	if cephBlockPool.Spec.Migration == nil {
+		return false
+	}
+	return cephBlockPool.Status == nil || cephBlockPool.Status.Migration == nil || cephBlockPool.Status.Migration.Phase != cephv1.PoolMigrationCompleted
+}
+
+// desiredPoolSpec returns the pool spec to reconcile, which is the migration target once the migration completed
+func desiredPoolSpec(cephBlockPool *cephv1.CephBlockPool) cephv1.NamedPoolSpec {
+	poolSpec := cephBlockPool.ToNamedPoolSpec()
+	migration := cephBlockPool.Spec.Migration
+	if migration != nil && migration.Target != nil && cephBlockPool.Status != nil && cephBlockPool.Status.Migration != nil &&
+		cephBlockPool.Status.Migration.Phase == cephv1.PoolMigrationCompleted {
+		poolSpec.PoolSpec = *migration.Target
+	}
+	return poolSpec
+}
+
+// reconcileMigration runs a step of the migration of the images and saves its progress in the status
+func (r *ReconcileCephBlockPool) reconcileMigration(clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec, cephBlockPool *cephv1.CephBlockPool) (reconcile.Result, error) {
+	nsName := opcontroller.NsName(cephBlockPool.Namespace, cephBlockPool.Name)
+	m := &poolMigrator{
+		context:     r.context,
+		clusterInfo: clusterInfo,
+		clusterSpec: clusterSpec,
+		pool:        cephBlockPool,
+		poolName:    cephBlockPool.ToNamedPoolSpec().Name,
+	}
+
+	status := &cephv1.PoolMigrationStatus{}
+	if cephBlockPool.Status != nil && cephBlockPool.Status.Migration != nil {
+		status = cephBlockPool.Status.Migration.DeepCopy()
+	}
+	err := m.migrate(status)
+	if err != nil {
+		status.Message = err.Error()
+	} else {
+		status.Message = ""
+	}
+	if statusErr := r.updateMigrationStatus(cephBlockPool, status); statusErr != nil {
+		return opcontroller.ImmediateRetryResult, statusErr
+	}
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to migrate pool %q", m.poolName)
+	}
+	if status.Phase != cephv1.PoolMigrationCompleted {
+		log.NamedInfo(nsName, logger, "migrating pool to %q: %d/%d images completed", status.TargetPool, status.CompletedImages, status.TotalImages)
+		return waitForRequeueIfPoolMigrating, nil
+	}
+	log.NamedInfo(nsName, logger, "pool migration completed")
+	return reconcile.Result{}, nil
+}
+
+// updateMigrationStatus sets the migration status of the pool, and the pool ID once the new pool took the name of the pool
+func (r *ReconcileCephBlockPool) updateMigrationStatus(cephBlockPool *cephv1.CephBlockPool, migration *cephv1.PoolMigrationStatus) error {
+	nsName := opcontroller.NsName(cephBlockPool.Namespace, cephBlockPool.Name)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pool := &cephv1.CephBlockPool{}
+		if err := r.client.Get(r.opManagerContext, nsName, pool); err != nil {
+			if kerrors.IsNotFound(err) {
+				log.NamedDebug(nsName, logger, "CephBlockPool resource not found for updating the migration status, ignoring.")
+				return nil
+			}
+			return errors.Wrap(err, "failed to get pool")
+		}
+		if pool.Status == nil {
+			pool.Status = &cephv1.CephBlockPoolStatus{}
+		}
+		if migration != nil && migration.Phase == cephv1.PoolMigrationCompleted {
+			pool.Status.PoolID = migration.TargetPoolID
+		}
+		pool.Status.Migration = migration
+		return reporting.UpdateStatus(r.client, pool)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update the migration status of pool %q", cephBlockPool.Name)
+	}
+	// keep the status of the reconciled object in sync for the rest of the reconcile
+	if cephBlockPool.Status == nil {
+		cephBlockPool.Status = &cephv1.CephBlockPoolStatus{}
+	}
+	cephBlockPool.Status.Migration = migration
+	return nil
+}
+
+// migrate runs the next step of the migration according to its phase
+func (m *poolMigrator) migrate(status *cephv1.PoolMigrationStatus) error {
+	switch status.Phase {
+	case "":
+		if err := m.start(status); err != nil {
+			return err
+		}
+		fallthrough
+	case cephv1.PoolMigrationMigrating:
+		done, err := m.migrateImages(status)
+		if err != nil || !done {
+			return err
+		}
+		status.Phase = cephv1.PoolMigrationSwapping
+		fallthrough
+	case cephv1.PoolMigrationSwapping:
+		if err := m.swapPools(status); err != nil {
+			return err
+		}
+		status.Phase = cephv1.PoolMigrationCompleted
+	}
+	return nil
+}
+
+// start creates the target pool and maps the ID of the pool to the target pool for ceph-csi
+func (m *poolMigrator) start(status *cephv1.PoolMigrationStatus) error {
+	namespaces, err := cephclient.ListRadosNamespacesInPool(m.context, m.clusterInfo, m.poolName)
+	if err != nil {
+		return errors.Wrap(err, "failed to list rados namespaces")
+	}
+	if len(namespaces) > 0 {
+		return errors.Errorf("pool with rados namespaces %v cannot be migrated", namespaces)
+	}
+	if dataPool := m.pool.Spec.Migration.DataPool; dataPool != "" {
+		if _, err := cephclient.GetPoolDetails(m.context, m.clusterInfo, dataPool); err != nil {
+			return errors.Wrapf(err, "failed to get migration data pool %q", dataPool)
+		}
+	}
+
+	source, err := cephclient.GetPoolDetails(m.context, m.clusterInfo, m.poolName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get pool %q", m.poolName)
+	}
+
+	target := cephv1.NamedPoolSpec{Name: m.poolName + migrationTargetPoolSuffix, PoolSpec: m.pool.Spec.PoolSpec}
+	if m.pool.Spec.Migration.Target != nil {
+		target.PoolSpec = *m.pool.Spec.Migration.Target
+	}
+	if err := createPool(m.context, m.clusterInfo, m.clusterSpec, &target); err != nil {
+		return errors.Wrapf(err, "failed to create migration target pool %q", target.Name)
+	}
+	targetDetails, err := cephclient.GetPoolDetails(m.context, m.clusterInfo, target.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get migration target pool %q", target.Name)
+	}
+
+	// the volume handles of ceph-csi keep the ID of the pool, which must resolve to the target pool
+	// once the images are moved
+	mappings := &peermap.PeerIDMappings{{
+		ClusterIDMapping: map[string]string{m.clusterInfo.Namespace: m.clusterInfo.Namespace},
+		RBDPoolIDMapping: []map[string]string{{strconv.Itoa(source.Number): strconv.Itoa(targetDetails.Number)}},
+	}}
+	if err := peermap.CreateOrUpdateConfig(m.clusterInfo.Context, m.context, mappings); err != nil {
+		return errors.Wrap(err, "failed to map the pool ID to the migration target pool for ceph-csi")
+	}
+
+	status.Phase = cephv1.PoolMigrationMigrating
+	status.TargetPool = target.Name
+	status.SourcePoolID = source.Number
+	status.TargetPoolID = targetDetails.Number
+	return nil
+}
+
+// migrateImages moves the data of the images of the pool to the target pool in batches of at most
+// maxConcurrentImages. Only the images of the batch and the images that wait for their clients or
+// failed are checked and listed in the status, the others are only counted. It returns true once
+// all the images are in the target pool.
+func (m *poolMigrator) migrateImages(status *cephv1.PoolMigrationStatus) (bool, error) {
+	maxConcurrentImages := max(m.pool.Spec.Migration.MaxConcurrentImages, 1)
+	targetImages, err := listImageNames(m.context, m.clusterInfo, status.TargetPool)
+	if err != nil {
+		return false, err
+	}
+	sourceImages, err := listImageNames(m.context, m.clusterInfo, m.poolName)
+	if err != nil {
+		return false, err
+	}
+
+	tracked := map[string]cephv1.PoolMigrationImageStatus{}
+	for _, image := range status.Images {
+		tracked[image.Name] = image
+	}
+	pending := []string{}
+	for _, image := range sourceImages {
+		if !slices.Contains(targetImages, image) {
+			pending = append(pending, image)
+		}
+	}
+	// the batch is the images of the target pool that are not committed yet. Once no image is left
+	// in the pool, all the images of the target pool are checked before the migration completes in
+	// case the status of an image was not saved.
+	batch := []string{}
+	for _, image := range targetImages {
+		if _, ok := tracked[image]; ok {
+			batch = append(batch, image)
+		}
+	}
+	checkAll := len(pending) == 0 && len(batch) == 0
+	if checkAll {
+		batch = targetImages
+	}
+
+	tasks, err := cephclient.ListRBDTasks(m.context, m.clusterInfo)
+	if err != nil {
+		return false, err
+	}
+
+	images := []cephv1.PoolMigrationImageStatus{}
+	completed := len(targetImages) - len(batch)
+	active := 0
+	for _, image := range batch {
+		imageStatus := cephv1.PoolMigrationImageStatus{Name: image, State: cephv1.PoolMigrationImageMigrating}
+		rbdStatus, err := cephclient.GetRBDImageStatus(m.context, m.clusterInfo, status.TargetPool, image, "")
+		if err != nil {
+			imageStatus.State = cephv1.PoolMigrationImageFailed
+			imageStatus.Message = err.Error()
+			images = append(images, imageStatus)
+			continue
+		}
+		migration := rbdStatus.Migration
+		if migration == nil {
+			completed++
+			continue
+		}
+
+		switch migration.State {
+		case cephclient.RBDMigrationPrepared, cephclient.RBDMigrationExecuting:
+			active++
+			task := findMigrationTask(tasks, status.TargetPool, image)
+			if task != nil {
+				imageStatus.Progress = int(math.Round(task.Progress * 100))
+				break
+			}
+			// an interrupted copy is resumed by a new task
+			if err := m.executeImageMigration(migration); err != nil {
+				imageStatus.State = cephv1.PoolMigrationImageFailed
+				imageStatus.Message = err.Error()
+			}
+		case cephclient.RBDMigrationExecuted:
+			if err := cephclient.CommitImageMigration(m.context, m.clusterInfo, status.TargetPool, image); err != nil {
+				active++
+				imageStatus.State = cephv1.PoolMigrationImageFailed
+				imageStatus.Message = err.Error()
+				break
+			}
+			completed++
+			continue
+		default:
+			// the migration must be aborted by the admin, which moves the image back to the pool
+			imageStatus.State = cephv1.PoolMigrationImageFailed
+			imageStatus.Message = fmt.Sprintf("migration in state %q: %s", migration.State, migration.StateDescription)
+		}
+		images = append(images, imageStatus)
+	}
+
+	// start the next images while the batch has room
+	for _, image := range pending {
+		if active >= maxConcurrentImages {
+			// the images that wait for their clients or failed are checked again once the batch has room
+			if imageStatus, ok := tracked[image]; ok {
+				images = append(images, imageStatus)
+			}
+			continue
+		}
+		imageStatus := cephv1.PoolMigrationImageStatus{Name: image, State: cephv1.PoolMigrationImageMigrating}
+		rbdStatus, err := cephclient.GetRBDImageStatus(m.context, m.clusterInfo, m.poolName, image, "")
+		switch {
+		case err != nil:
+			imageStatus.State = cephv1.PoolMigrationImageFailed
+			imageStatus.Message = err.Error()
+		case len(rbdStatus.Watchers) > 0:
+			// the clients of the image would lose access to it during the migration
+			imageStatus.State = cephv1.PoolMigrationImageInUse
+			imageStatus.Message = fmt.Sprintf("image is mapped by %v", rbdStatus.GetWatchers())
+		default:
+			active++
+			err := cephclient.PrepareImageMigration(m.context, m.clusterInfo, m.poolName, image, status.TargetPool, m.pool.Spec.Migration.DataPool)
+			if err != nil {
+				imageStatus.State = cephv1.PoolMigrationImageFailed
+				imageStatus.Message = err.Error()
+			}
+		}
+		images = append(images, imageStatus)
+	}
+
+	status.Images = images
+	status.CompletedImages = completed
+	status.TotalImages = len(targetImages) + len(pending)
+	return checkAll && len(images) == 0, nil
+}
+
+// executeImageMigration copies the ceph-csi journal of the image to the target pool and starts
+// copying the data of the image
+func (m *poolMigrator) executeImageMigration(migration *cephclient.RBDMigrationStatus) error {
+	if err := copyCSIJournal(m.context, m.clusterInfo, migration); err != nil {
+		return err
+	}
+	return cephclient.ExecuteImageMigration(m.context, m.clusterInfo, migration.DestPoolName, migration.DestImageName)
+}
+
+// swapPools renames the pool to keep it for the admin and gives its name to the target pool. The
+// pools are identified by their ID so that an interrupted swap is resumed.
+func (m *poolMigrator) swapPools(status *cephv1.PoolMigrationStatus) error {
+	names, err := cephclient.GetPoolNamesByID(m.context, m.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get pool names")
+	}
+	if names[status.SourcePoolID] == m.poolName {
+		premigrationName := fmt.Sprintf("%s-premigration-%d", m.poolName, status.SourcePoolID)
+		if err := cephclient.RenamePool(m.context, m.clusterInfo, m.poolName, premigrationName); err != nil {
+			return err
+		}
+	}
+	targetName, ok := names[status.TargetPoolID]
+	if !ok {
+		return errors.Errorf("migration target pool with ID %d not found", status.TargetPoolID)
+	}
+	if targetName != m.poolName {
+		if err := cephclient.RenamePool(m.context, m.clusterInfo, targetName, m.poolName); err != nil {
+			return err
+		}
+	}
+	status.TargetPool = m.poolName
+	return nil
+}
+
+// copyCSIJournal copies the ceph-csi journal of a volume or snapshot image to the target pool with
+// the ID of the target image. Images that were not created by ceph-csi have no journal.
+func copyCSIJournal(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, migration *cephclient.RBDMigrationStatus) error {
+	image := migration.SourceImageName
+	if len(image) < csiUUIDLength {
+		return nil
+	}
+	uuid := image[len(image)-csiUUIDLength:]
+	for _, journal := range csiJournals {
+		object := journal.objectPrefix + uuid
+		values, err := cephclient.GetOmapValues(context, clusterInfo, migration.SourcePoolName, object)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the csi journal of image %q", image)
+		}
+		if values == nil {
+			continue
+		}
+		values[csiImageIDKey] = migration.DestImageID
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			if err := cephclient.SetOmapValue(context, clusterInfo, migration.DestPoolName, object, key, values[key]); err != nil {
+				return errors.Wrapf(err, "failed to copy the csi journal of image %q", image)
+			}
+		}
+		if name := values[journal.nameKey]; name != "" {
+			if err := cephclient.SetOmapValue(context, clusterInfo, migration.DestPoolName, journal.directory, journal.objectPrefix+name, uuid); err != nil {
+				return errors.Wrapf(err, "failed to copy the csi journal of image %q", image)
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// listImageNames returns the names of the images of the pool, once per image
+func listImageNames(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, poolName string) ([]string, error) {
+	images, err := cephclient.ListImagesInPool(context, clusterInfo, poolName)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, image := range images {
+		if !slices.Contains(names, image.Name) {
+			names = append(names, image.Name)
+		}
+	}
+	return names, nil
+}
+
+func findMigrationTask(tasks []cephclient.RBDTask, poolName, imageName string) *cephclient.RBDTask {
+	for i := range tasks {
+		if tasks[i].IsMigrationExecute(poolName, imageName) {
+			return &tasks[i]
+		}
+	}
+	return nil
+}
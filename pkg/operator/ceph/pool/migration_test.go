@@ -0,0 +1,308 @@
+/*
+Copyright 2026 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testCSIUUID = "0c4f3b0e-2f4b-11ee-8d1a-0242ac120002"
+
+// the hexdump of the journal of the volume csi-vol-<uuid> in the output of `rados listomapvals`
+const testCSIVolumeJournal = `csi.imageid
+value (12 bytes) :
+00000000  31 30 65 35 36 62 38 62  34 35 36 37              |10e56b8b4567|
+0000000c
+
+csi.volname
+value (5 bytes) :
+00000000  70 76 63 2d 31                                    |pvc-1|
+00000005
+
+`
+
+func migrationStatusJSON(state string) string {
+	if state == "" {
+		return `{"watchers":[]}`
+	}
+	return fmt.Sprintf(`{"watchers":[],"migration":{"source_pool_name":"replicapool","source_image_name":"csi-vol-%[2]s",
+"dest_pool_name":"replicapool-migration","dest_image_name":"csi-vol-%[2]s","dest_image_id":"2b3c4d","state":"%[1]s"}}`, state, testCSIUUID)
+}
+
+func newTestPoolMigrator(executor *exectest.MockExecutor, maxConcurrentImages int) *poolMigrator {
+	clusterInfo := cephclient.AdminTestClusterInfo("rook-ceph")
+	clusterInfo.Context = context.TODO()
+	return &poolMigrator{
+		context:     &clusterd.Context{Executor: executor},
+		clusterInfo: clusterInfo,
+		pool: &cephv1.CephBlockPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: "rook-ceph"},
+			Spec: cephv1.NamedBlockPoolSpec{
+				PoolSpec:  cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}},
+				Migration: &cephv1.PoolMigrationSpec{MaxConcurrentImages: maxConcurrentImages, DataPool: "ec-data"},
+			},
+		},
+		poolName: "replicapool",
+	}
+}
+
+func TestMigrateImages(t *testing.T) {
+	var commands []string
+	var checkedImages []string
+	targetImages := map[string]string{
+		"executing": cephclient.RBDMigrationExecuting,
+		"executed":  cephclient.RBDMigrationExecuted,
+		"completed": "",
+		"prepared":  cephclient.RBDMigrationPrepared,
+		"error":     cephclient.RBDMigrationError,
+	}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case command == "rbd" && args[0] == "ls":
+				if args[2] == "replicapool-migration" {
+					return `[{"image":"executing"},{"image":"executed"},{"image":"completed"},{"image":"completed","snapshot":"snap1"},{"image":"prepared"},{"image":"error"}]`, nil
+				}
+				return `[{"image":"executing"},{"image":"in-use"},{"image":"free"},{"image":"waiting"}]`, nil
+			case command == "rbd" && args[0] == "status":
+				checkedImages = append(checkedImages, args[1])
+				pool, image, _ := strings.Cut(args[1], "/")
+				if pool == "replicapool" {
+					if image == "in-use" {
+						return `{"watchers":[{"address":"10.0.0.1:0/1234"}]}`, nil
+					}
+					return `{"watchers":[]}`, nil
+				}
+				return migrationStatusJSON(targetImages[image]), nil
+			case command == "ceph" && args[0] == "rbd" && args[1] == "task" && args[2] == "list":
+				return `[{"id":"1","refs":{"action":"migrate execute","pool_name":"replicapool-migration","image_name":"executing"},"in_progress":true,"progress":0.25}]`, nil
+			case command == "rados" && args[2] == "listomapvals":
+				if args[3] == "csi.volume."+testCSIUUID {
+					return testCSIVolumeJournal, nil
+				}
+				return "", errors.New("No such file or directory")
+			case command == "rbd" && args[0] == "migration", command == "rados", command == "ceph" && args[0] == "rbd":
+				// ignore the connection flags
+				for i, arg := range args {
+					if strings.HasPrefix(arg, "--cluster") || strings.HasPrefix(arg, "--connect-timeout") {
+						args = args[:i]
+						break
+					}
+				}
+				commands = append(commands, strings.Join(append([]string{command}, args...), " "))
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %s %v", command, args)
+		},
+	}
+
+	m := newTestPoolMigrator(executor, 3)
+	// the images of the batch are tracked in the status, the image committed earlier is not
+	status := &cephv1.PoolMigrationStatus{Phase: cephv1.PoolMigrationMigrating, TargetPool: "replicapool-migration", Images: []cephv1.PoolMigrationImageStatus{
+		{Name: "executing", State: cephv1.PoolMigrationImageMigrating},
+		{Name: "executed", State: cephv1.PoolMigrationImageMigrating},
+		{Name: "prepared", State: cephv1.PoolMigrationImageMigrating},
+		{Name: "error", State: cephv1.PoolMigrationImageFailed},
+	}}
+	done, err := m.migrateImages(status)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, 2, status.CompletedImages)
+	assert.Equal(t, 8, status.TotalImages)
+	require.Len(t, status.Images, 5)
+	assert.Equal(t, cephv1.PoolMigrationImageStatus{Name: "executing", State: cephv1.PoolMigrationImageMigrating, Progress: 25}, status.Images[0])
+	assert.Equal(t, cephv1.PoolMigrationImageStatus{Name: "prepared", State: cephv1.PoolMigrationImageMigrating}, status.Images[1])
+	assert.Equal(t, "error", status.Images[2].Name)
+	assert.Equal(t, cephv1.PoolMigrationImageFailed, status.Images[2].State)
+	assert.Equal(t, "in-use", status.Images[3].Name)
+	assert.Equal(t, cephv1.PoolMigrationImageInUse, status.Images[3].State)
+	assert.Equal(t, cephv1.PoolMigrationImageStatus{Name: "free", State: cephv1.PoolMigrationImageMigrating}, status.Images[4])
+	// the committed image and the image waiting for its turn are not checked
+	assert.Equal(t, []string{
+		"replicapool-migration/executing",
+		"replicapool-migration/executed",
+		"replicapool-migration/prepared",
+		"replicapool-migration/error",
+		"replicapool/in-use",
+		"replicapool/free",
+	}, checkedImages)
+
+	volumeJournal := "csi.volume." + testCSIUUID
+	assert.Equal(t, []string{
+		"rbd migration commit replicapool-migration/executed",
+		"rados --pool replicapool-migration setomapval " + volumeJournal + " csi.imageid 2b3c4d",
+		"rados --pool replicapool-migration setomapval " + volumeJournal + " csi.volname pvc-1",
+		"rados --pool replicapool-migration setomapval csi.volumes.default csi.volume.pvc-1 " + testCSIUUID,
+		"ceph rbd task add migration execute replicapool-migration/csi-vol-" + testCSIUUID,
+		"rbd migration prepare replicapool/free replicapool-migration/free --data-pool ec-data",
+	}, commands)
+
+	t.Run("full batch", func(t *testing.T) {
+		checkedImages = nil
+		commands = nil
+		m := newTestPoolMigrator(executor, 1)
+		status := &cephv1.PoolMigrationStatus{Phase: cephv1.PoolMigrationMigrating, TargetPool: "replicapool-migration", Images: []cephv1.PoolMigrationImageStatus{
+			{Name: "executing", State: cephv1.PoolMigrationImageMigrating},
+			{Name: "in-use", State: cephv1.PoolMigrationImageInUse, Message: "image is mapped"},
+		}}
+		done, err := m.migrateImages(status)
+		assert.NoError(t, err)
+		assert.False(t, done)
+		assert.Equal(t, 4, status.CompletedImages)
+		assert.Equal(t, 8, status.TotalImages)
+		// the image in use keeps its state until the batch has room
+		assert.Equal(t, []cephv1.PoolMigrationImageStatus{
+			{Name: "executing", State: cephv1.PoolMigrationImageMigrating, Progress: 25},
+			{Name: "in-use", State: cephv1.PoolMigrationImageInUse, Message: "image is mapped"},
+		}, status.Images)
+		assert.Equal(t, []string{"replicapool-migration/executing"}, checkedImages)
+		assert.Empty(t, commands)
+	})
+
+	t.Run("all images migrated", func(t *testing.T) {
+		executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+			switch {
+			case command == "rbd" && args[0] == "ls":
+				if args[2] == "replicapool-migration" {
+					return `[{"image":"completed"}]`, nil
+				}
+				return `[]`, nil
+			case command == "rbd" && args[0] == "status":
+				return migrationStatusJSON(""), nil
+			case command == "ceph" && args[0] == "rbd":
+				return `[]`, nil
+			}
+			return "", errors.Errorf("unexpected command %s %v", command, args)
+		}
+		status := &cephv1.PoolMigrationStatus{Phase: cephv1.PoolMigrationMigrating, TargetPool: "replicapool-migration"}
+		done, err := m.migrateImages(status)
+		assert.NoError(t, err)
+		assert.True(t, done)
+		assert.Equal(t, 1, status.CompletedImages)
+		assert.Equal(t, 1, status.TotalImages)
+		assert.Empty(t, status.Images)
+	})
+
+	t.Run("untracked image found before completing", func(t *testing.T) {
+		executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+			switch {
+			case command == "rbd" && args[0] == "ls":
+				if args[2] == "replicapool-migration" {
+					return `[{"image":"completed"},{"image":"prepared"}]`, nil
+				}
+				return `[]`, nil
+			case command == "rbd" && args[0] == "status":
+				if strings.HasSuffix(args[1], "/prepared") {
+					return migrationStatusJSON(cephclient.RBDMigrationExecuting), nil
+				}
+				return migrationStatusJSON(""), nil
+			case command == "ceph" && args[0] == "rbd":
+				return `[{"id":"1","refs":{"action":"migrate execute","pool_name":"replicapool-migration","image_name":"prepared"},"in_progress":true,"progress":0.5}]`, nil
+			}
+			return "", errors.Errorf("unexpected command %s %v", command, args)
+		}
+		// the last image of the batch was committed
+		status := &cephv1.PoolMigrationStatus{Phase: cephv1.PoolMigrationMigrating, TargetPool: "replicapool-migration"}
+		done, err := m.migrateImages(status)
+		assert.NoError(t, err)
+		assert.False(t, done)
+		assert.Equal(t, 1, status.CompletedImages)
+		assert.Equal(t, 2, status.TotalImages)
+		assert.Equal(t, []cephv1.PoolMigrationImageStatus{{Name: "prepared", State: cephv1.PoolMigrationImageMigrating, Progress: 50}}, status.Images)
+	})
+}
+
+func TestSwapPools(t *testing.T) {
+	pools := map[int]string{1: ".mgr", 2: "replicapool", 3: "replicapool-migration"}
+	var renames []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "osd" && args[1] == "lspools":
+				var summaries []string
+				for id, name := range pools {
+					summaries = append(summaries, fmt.Sprintf(`{"poolnum":%d,"poolname":%q}`, id, name))
+				}
+				return "[" + strings.Join(summaries, ",") + "]", nil
+			case args[0] == "osd" && args[1] == "pool" && args[2] == "rename":
+				renames = append(renames, args[3]+" "+args[4])
+				for id, name := range pools {
+					if name == args[3] {
+						pools[id] = args[4]
+					}
+				}
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %v", args)
+		},
+	}
+	m := newTestPoolMigrator(executor, 1)
+	status := &cephv1.PoolMigrationStatus{Phase: cephv1.PoolMigrationSwapping, TargetPool: "replicapool-migration", SourcePoolID: 2, TargetPoolID: 3}
+
+	assert.NoError(t, m.migrate(status))
+	assert.Equal(t, cephv1.PoolMigrationCompleted, status.Phase)
+	assert.Equal(t, "replicapool", status.TargetPool)
+	assert.Equal(t, []string{"replicapool replicapool-premigration-2", "replicapool-migration replicapool"}, renames)
+	assert.Equal(t, map[int]string{1: ".mgr", 2: "replicapool-premigration-2", 3: "replicapool"}, pools)
+
+	// the swap is resumed after an interruption
+	renames = nil
+	pools = map[int]string{2: "replicapool-premigration-2", 3: "replicapool-migration"}
+	assert.NoError(t, m.swapPools(status))
+	assert.Equal(t, []string{"replicapool-migration replicapool"}, renames)
+
+	renames = nil
+	assert.NoError(t, m.swapPools(status))
+	assert.Empty(t, renames)
+}
+
+func TestDesiredPoolSpec(t *testing.T) {
+	target := &cephv1.PoolSpec{FailureDomain: "rack", Replicated: cephv1.ReplicatedSpec{Size: 2}}
+	p := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicapool"},
+		Spec: cephv1.NamedBlockPoolSpec{
+			PoolSpec:  cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}},
+			Migration: &cephv1.PoolMigrationSpec{Target: target},
+		},
+		Status: &cephv1.CephBlockPoolStatus{},
+	}
+	assert.True(t, migrationInProgress(p))
+	assert.Equal(t, uint(3), desiredPoolSpec(p).Replicated.Size)
+
+	p.Status.Migration = &cephv1.PoolMigrationStatus{Phase: cephv1.PoolMigrationSwapping}
+	assert.True(t, migrationInProgress(p))
+
+	p.Status.Migration.Phase = cephv1.PoolMigrationCompleted
+	assert.False(t, migrationInProgress(p))
+	assert.Equal(t, cephv1.NamedPoolSpec{Name: "replicapool", PoolSpec: *target}, desiredPoolSpec(p))
+
+	p.Spec.Migration = nil
+	assert.False(t, migrationInProgress(p))
+	assert.Equal(t, uint(3), desiredPoolSpec(p).Replicated.Size)
+}
@@ -63,6 +63,10 @@ func (r *ReconcileCephBlockPool) updateStatus(poolName types.NamespacedName, sta
 			pool.Status.Cephx.PeerToken = *cephx
 		}
 
+		if pool.Spec.StatusCheck.Capacity.Disabled {
+			pool.Status.Capacity = nil
+		}
+
 		if err := reporting.UpdateStatus(r.client, pool); err != nil {
 			log.NamedWarning(poolName, logger, "failed to set pool %q status to %q. %v", pool.Name, status, err)
 			return errors.Wrapf(err, "failed to set pool %q status to %q", pool.Name, status)
@@ -86,14 +90,15 @@ func updateStatusInfo(cephBlockPool *cephv1.CephBlockPool) {
 		}
 	}
 
-	if cephBlockPool.Spec.IsReplicated() {
+	poolSpec := desiredPoolSpec(cephBlockPool)
+	if poolSpec.IsReplicated() {
 		m["type"] = "Replicated"
 	} else {
 		m["type"] = "Erasure Coded"
 	}
 
-	if cephBlockPool.Spec.FailureDomain != "" {
-		m["failureDomain"] = cephBlockPool.Spec.FailureDomain
+	if poolSpec.FailureDomain != "" {
+		m["failureDomain"] = poolSpec.FailureDomain
 	} else {
 		m["failureDomain"] = cephv1.DefaultFailureDomain
 	}
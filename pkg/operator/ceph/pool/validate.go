@@ -73,11 +73,14 @@ func ValidatePoolSpec(context *clusterd.Context, clusterInfo *cephclient.Cluster
 		if p.IsErasureCoded() {
 			return errors.New("erasure coded pools are not supported in stretch clusters")
 		}
+		if p.CrushRule != "" {
+			return errors.New("pools in a stretch cluster must use the stretch crush rule")
+		}
 	}
 
 	var crush cephclient.CrushMap
 	var err error
-	if p.FailureDomain != "" || p.CrushRoot != "" {
+	if p.FailureDomain != "" || p.CrushRoot != "" || p.CrushRule != "" {
 		crush, err = cephclient.GetCrushMap(context, clusterInfo)
 		if err != nil {
 			return errors.Wrap(err, "failed to get crush map")
@@ -98,6 +101,20 @@ func ValidatePoolSpec(context *clusterd.Context, clusterInfo *cephclient.Cluster
 		}
 	}
 
+	// validate the crush rule if specified
+	if p.CrushRule != "" {
+		found := false
+		for _, rule := range crush.Rules {
+			if rule.Name == p.CrushRule {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("crush rule %q not found. it must be created by a CephCrushRule before the pool", p.CrushRule)
+		}
+	}
+
 	// validate the crush root if specified
 	if p.CrushRoot != "" {
 		found := false
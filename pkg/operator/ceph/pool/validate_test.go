@@ -215,7 +215,8 @@ func TestValidateCrushProperties(t *testing.T) {
 	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
 		logger.Infof("Command: %s %v", command, args)
 		if args[1] == "crush" && args[2] == "dump" {
-			return `{"types":[{"type_id": 0,"name": "osd"}],"buckets":[{"id": -1,"name":"default"},{"id": -2,"name":"good"}, {"id": -3,"name":"host"}]}`, nil
+			return `{"types":[{"type_id": 0,"name": "osd"}],"buckets":[{"id": -1,"name":"default"},{"id": -2,"name":"good"}, {"id": -3,"name":"host"}],
+"rules":[{"rule_id":0,"rule_name":"replicated_rule"},{"rule_id":1,"rule_name":"two-dc"}]}`, nil
 		}
 		return "", errors.Errorf("unexpected ceph command %q", args)
 	}
@@ -255,6 +256,20 @@ func TestValidateCrushProperties(t *testing.T) {
 	p.Spec.Replicated.ReplicasPerFailureDomain = 2
 	err = validatePool(context, clusterInfo, clusterSpec, p)
 	assert.NoError(t, err)
+
+	t.Run("crush rule", func(t *testing.T) {
+		p := &cephv1.CephBlockPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "mypool", Namespace: clusterInfo.Namespace},
+			Spec: cephv1.NamedBlockPoolSpec{
+				PoolSpec: cephv1.PoolSpec{CrushRule: "two-dc", Replicated: cephv1.ReplicatedSpec{Size: 3}},
+			},
+		}
+		assert.NoError(t, validatePool(context, clusterInfo, clusterSpec, p))
+
+		p.Spec.CrushRule = "missing"
+		err := validatePool(context, clusterInfo, clusterSpec, p)
+		assert.ErrorContains(t, err, `crush rule "missing" not found`)
+	})
 }
 
 func TestValidateDeviceClasses(t *testing.T) {
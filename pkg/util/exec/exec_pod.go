@@ -48,6 +48,8 @@ type ExecOptions struct {
 	Stdin         io.Reader
 	CaptureStdout bool
 	CaptureStderr bool
+	// If set, stdout is streamed to the writer instead of being captured and returned.
+	Stdout io.Writer
 	// If false, whitespace in std{err,out} will be removed.
 	PreserveWhitespace bool
 }
@@ -78,13 +80,17 @@ func (e *RemotePodCommandExecutor) ExecWithOptions(ctx context.Context, options
 		Container: options.ContainerName,
 		Command:   options.Command,
 		Stdin:     options.Stdin != nil,
-		Stdout:    options.CaptureStdout,
+		Stdout:    options.CaptureStdout || options.Stdout != nil,
 		Stderr:    options.CaptureStderr,
 		TTY:       tty,
 	}, scheme.ParameterCodec)
 
 	var stdout, stderr bytes.Buffer
-	err := execute(ctx, http.MethodPost, req.URL(), e.RestClient, options.Stdin, &stdout, &stderr, tty)
+	var stdoutWriter io.Writer = &stdout
+	if options.Stdout != nil {
+		stdoutWriter = options.Stdout
+	}
+	err := execute(ctx, http.MethodPost, req.URL(), e.RestClient, options.Stdin, stdoutWriter, &stderr, tty)
 
 	if options.PreserveWhitespace {
 		return stdout.String(), stderr.String(), err